@@ -31,6 +31,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/bmatcuk/doublestar/v4"
+	"github.com/cloudwego/eino-ext/adk/backend/patch"
 	"github.com/cloudwego/eino/adk"
 	"github.com/cloudwego/eino/adk/filesystem"
 	mfs "github.com/cloudwego/eino/adk/middlewares/filesystem"
@@ -689,6 +690,176 @@ func (b *Backend) editViaFS(ctx context.Context, req *filesystem.EditRequest) er
 	return nil
 }
 
+// MultiEdit applies req.Edits to one file in order, each to the result of the
+// previous one, and writes the file back with a single WriteTextFile call only
+// when every edit applies. A failed edit is reported as an *EditError.
+func (b *Backend) MultiEdit(ctx context.Context, req *MultiEditRequest) error {
+	if !(b.hasReadFS && b.hasWriteFS) {
+		return fmt.Errorf("%w: multi edit requires fs.ReadTextFile and fs.WriteTextFile", ErrCapabilityMissing)
+	}
+	content, err := b.readFull(ctx, req.FilePath)
+	if err != nil {
+		return fmt.Errorf("acp.multiEdit read %s: %w", req.FilePath, err)
+	}
+	if len(content) > maxEditFileSize {
+		return fmt.Errorf("acp.multiEdit %s: file is %d bytes: %w", req.FilePath, len(content), ErrFileTooLarge)
+	}
+	content, err = patch.ApplyEdits(req.FilePath, content, req.Edits)
+	if err != nil {
+		return fmt.Errorf("acp.multiEdit: %w", wrapEditError(err))
+	}
+	_, err = b.conn.WriteTextFile(ctx, acpproto.WriteTextFileRequest{
+		Path:      req.FilePath,
+		Content:   content,
+		SessionID: b.sessionID,
+	})
+	if err != nil {
+		return fmt.Errorf("acp.multiEdit write %s: %w", req.FilePath, err)
+	}
+	return nil
+}
+
+// wrapEditError wraps an edit failure of the patch package with the matching
+// sentinel of Edit, so MultiEdit failures are checked like Edit ones.
+func wrapEditError(err error) error {
+	switch {
+	case errors.Is(err, patch.ErrOldStringNotFound):
+		return fmt.Errorf("%w: %w", ErrOldStringNotFound, err)
+	case errors.Is(err, patch.ErrAmbiguousReplace):
+		return fmt.Errorf("%w: %w", ErrAmbiguousReplace, err)
+	default:
+		return err
+	}
+}
+
+// ApplyPatch applies a unified diff that may span several files. Every file is
+// read and patched in memory first, so a hunk that does not match (reported as
+// a *PatchError) leaves the client untouched. The results are then written one
+// by one; if a write fails, the files already written are restored.
+//
+// ACP has no way to tell a missing file from an unreadable one, so when a
+// file the patch creates cannot be read, the terminal checks that it does not
+// exist. The fs API cannot delete files either: deletions and renames go
+// through the terminal, and so does undoing a created file on rollback, so
+// patches that create, delete or rename files need UseTerminalForFileTools.
+func (b *Backend) ApplyPatch(ctx context.Context, req *ApplyPatchRequest) (*ApplyPatchResult, error) {
+	if !(b.hasReadFS && b.hasWriteFS) {
+		return nil, fmt.Errorf("%w: apply patch requires fs.ReadTextFile and fs.WriteTextFile", ErrCapabilityMissing)
+	}
+	files, err := patch.Parse(req.Patch)
+	if err != nil {
+		return nil, err
+	}
+	patch.ResolvePaths(files, func(p string) string { return joinPatchPath(req.BaseDir, p) })
+
+	creates := make(map[string]bool)
+	for _, fp := range files {
+		if fp.NewPath != "" && fp.NewPath != fp.OldPath {
+			creates[fp.NewPath] = true
+		}
+	}
+
+	report, writes, err := patch.Plan(files, func(p string) (patch.FileSnapshot, error) {
+		if !path.IsAbs(p) {
+			return patch.FileSnapshot{}, fmt.Errorf("acp.patch: path must be absolute (set BaseDir): %s", p)
+		}
+		content, err := b.readFull(ctx, p)
+		if err != nil {
+			if !creates[p] {
+				return patch.FileSnapshot{}, fmt.Errorf("acp.patch read: %w", err)
+			}
+			if exists, eErr := b.fileExists(ctx, p); eErr != nil {
+				return patch.FileSnapshot{}, fmt.Errorf("acp.patch read %s: %w", p, errors.Join(err, eErr))
+			} else if exists {
+				return patch.FileSnapshot{}, fmt.Errorf("acp.patch: %s already exists but cannot be read: %w", p, err)
+			}
+			return patch.FileSnapshot{}, nil
+		}
+		if len(content) > maxEditFileSize {
+			return patch.FileSnapshot{}, fmt.Errorf("acp.patch: file is %d bytes: %w", len(content), ErrFileTooLarge)
+		}
+		return patch.FileSnapshot{Content: content, Exists: true}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Removing a file needs the terminal, whether the patch deletes it or a
+	// rollback has to undo its creation.
+	for _, w := range writes {
+		if b.shell == nil && (!w.Before.Exists || !w.After.Exists) {
+			return nil, fmt.Errorf("%w: creating or deleting %s requires the terminal (UseTerminalForFileTools)", ErrCapabilityMissing, w.Path)
+		}
+	}
+
+	for i, w := range writes {
+		if err := b.applyFileWrite(ctx, w.Path, w.After); err != nil {
+			return nil, errors.Join(fmt.Errorf("acp.patch write %s: %w", w.Path, err), b.rollbackPatch(ctx, writes[:i]))
+		}
+	}
+	return &ApplyPatchResult{Files: report}, nil
+}
+
+// applyFileWrite makes filePath hold snap: written through the fs API, or
+// removed through the terminal when snap does not exist.
+func (b *Backend) applyFileWrite(ctx context.Context, filePath string, snap patch.FileSnapshot) error {
+	if snap.Exists {
+		_, err := b.conn.WriteTextFile(ctx, acpproto.WriteTextFileRequest{
+			Path:      filePath,
+			Content:   snap.Content,
+			SessionID: b.sessionID,
+		})
+		return err
+	}
+	if b.shell == nil {
+		return fmt.Errorf("%w: cannot remove %s without the terminal", ErrCapabilityMissing, filePath)
+	}
+	quoted, err := shellQuote(filePath)
+	if err != nil {
+		return err
+	}
+	_, err = b.runShell(ctx, "rm -f -- "+quoted)
+	return err
+}
+
+// fileExists reports whether filePath exists, using the terminal.
+func (b *Backend) fileExists(ctx context.Context, filePath string) (bool, error) {
+	if b.shell == nil {
+		return false, fmt.Errorf("%w: creating %s requires the terminal (UseTerminalForFileTools)", ErrCapabilityMissing, filePath)
+	}
+	quoted, err := shellQuote(filePath)
+	if err != nil {
+		return false, err
+	}
+	resp, err := b.shell.Execute(ctx, &filesystem.ExecuteRequest{Command: "test -e " + quoted})
+	if err != nil {
+		return false, err
+	}
+	// test exits with 1 when the file does not exist and with >1 on errors.
+	switch {
+	case resp.ExitCode != nil && *resp.ExitCode == 0:
+		return true, nil
+	case resp.ExitCode != nil && *resp.ExitCode == 1:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: test -e %s: %s", ErrShellNonZeroExit, quoted, truncateStr(resp.Output, maxLogCommandLen))
+	}
+}
+
+// rollbackPatch restores the original state of the files in writes. It uses a
+// context detached from ctx's cancellation, since a cancelled patch is one of
+// the failures it has to undo.
+func (b *Backend) rollbackPatch(ctx context.Context, writes []*patch.FileWrite) error {
+	ctx = context.WithoutCancel(ctx)
+	var errs []error
+	for i := len(writes) - 1; i >= 0; i-- {
+		if err := b.applyFileWrite(ctx, writes[i].Path, writes[i].Before); err != nil {
+			errs = append(errs, fmt.Errorf("acp.patch restore %s: %w", writes[i].Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // shellResult holds the output of a shell command execution along with
 // metadata about whether the output was truncated.
 type shellResult struct {
@@ -18,6 +18,7 @@ package einoacp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"iter"
@@ -304,20 +305,7 @@ func marshalInterruptInfo(info *adk.InterruptInfo) (text string, meta map[string
 		MetaKeyInterrupted: true,
 	}
 
-	// Convert Data to text
-	switch v := info.Data.(type) {
-	case string:
-		text = v
-	case nil:
-		text = ""
-	default:
-		b, jErr := json.Marshal(v)
-		if jErr != nil {
-			text = fmt.Sprintf("%v", v)
-		} else {
-			text = string(b)
-		}
-	}
+	text = interruptDataText(info.Data)
 
 	// Convert InterruptContexts to JSON-safe structure for meta
 	if len(info.InterruptContexts) > 0 {
@@ -332,6 +320,23 @@ func marshalInterruptInfo(info *adk.InterruptInfo) (text string, meta map[string
 	return text, meta
 }
 
+// interruptDataText renders interrupt data as text: strings are used as-is, anything else is
+// JSON-encoded, falling back to fmt formatting when it cannot be marshaled.
+func interruptDataText(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	}
+}
+
 func interruptCtxToMap(ic *adk.InterruptCtx) map[string]any {
 	m := map[string]any{
 		ctxKeyID:          ic.ID,
@@ -524,6 +529,251 @@ func inputPartToContentBlock(part schema.MessageInputPart) (acpproto.ContentBloc
 	}
 }
 
+// promptToMessage converts the content blocks of an ACP session/prompt into a single user
+// message. A prompt made only of text blocks becomes a plain-text message (blocks joined by
+// newlines); any other block switches the message to UserInputMultiContent so that images,
+// audio and resource links are preserved.
+func promptToMessage(blocks []acpproto.ContentBlock) (adk.Message, error) {
+	texts := make([]string, 0, len(blocks))
+	allText := true
+	for _, block := range blocks {
+		tc, ok := block.AsText()
+		if !ok {
+			allText = false
+			break
+		}
+		texts = append(texts, tc.Text)
+	}
+	if allText {
+		return schema.UserMessage(strings.Join(texts, "\n")), nil
+	}
+
+	parts := make([]schema.MessageInputPart, 0, len(blocks))
+	for _, block := range blocks {
+		part, err := contentBlockToInputPart(block)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return &schema.Message{Role: schema.User, UserInputMultiContent: parts}, nil
+}
+
+// contentBlockToInputPart is the inverse of inputPartToContentBlock.
+func contentBlockToInputPart(block acpproto.ContentBlock) (schema.MessageInputPart, error) {
+	if tc, ok := block.AsText(); ok {
+		return schema.MessageInputPart{Type: schema.ChatMessagePartTypeText, Text: tc.Text}, nil
+	}
+	if ic, ok := block.AsImage(); ok {
+		img := &schema.MessageInputImage{MessagePartCommon: schema.MessagePartCommon{MIMEType: ic.MimeType}}
+		switch {
+		case ic.Data != "":
+			data := ic.Data
+			img.Base64Data = &data
+		case ic.URI != "":
+			uri := ic.URI
+			img.URL = &uri
+		default:
+			return schema.MessageInputPart{}, fmt.Errorf("image content block has neither data nor uri")
+		}
+		return schema.MessageInputPart{Type: schema.ChatMessagePartTypeImageURL, Image: img}, nil
+	}
+	if ac, ok := block.AsAudio(); ok {
+		data := ac.Data
+		return schema.MessageInputPart{
+			Type: schema.ChatMessagePartTypeAudioURL,
+			Audio: &schema.MessageInputAudio{MessagePartCommon: schema.MessagePartCommon{
+				MIMEType:   ac.MimeType,
+				Base64Data: &data,
+			}},
+		}, nil
+	}
+	if rl, ok := block.AsResourceLink(); ok {
+		uri := rl.URI
+		return schema.MessageInputPart{
+			Type: schema.ChatMessagePartTypeFileURL,
+			File: &schema.MessageInputFile{
+				MessagePartCommon: schema.MessagePartCommon{MIMEType: rl.MimeType, URL: &uri},
+				Name:              rl.Name,
+			},
+		}, nil
+	}
+	return schema.MessageInputPart{}, fmt.Errorf("unsupported prompt content block")
+}
+
+// SessionUpdateToMessage is the reverse of AgentEventToSessionUpdate for a single update: it
+// converts a message chunk, thought chunk, tool call or tool call update into the equivalent
+// eino message chunk. Chunks of the same kind can be merged with schema.ConcatMessages.
+// It returns nil for updates that carry no message content, such as plans or mode changes.
+//
+// Tool results are returned as tool messages keyed by ToolCallID only; ACP does not repeat the
+// tool name on updates, so callers that need it should remember the title of the ToolCall.
+func SessionUpdateToMessage(su acpproto.SessionUpdate) (*schema.Message, error) {
+	if chunk, ok := su.AsUserMessageChunk(); ok {
+		if tc, ok := chunk.Content.AsText(); ok {
+			return schema.UserMessage(tc.Text), nil
+		}
+		part, err := contentBlockToInputPart(chunk.Content)
+		if err != nil {
+			return nil, err
+		}
+		return &schema.Message{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{part}}, nil
+	}
+	if chunk, ok := su.AsAgentMessageChunk(); ok {
+		if tc, ok := chunk.Content.AsText(); ok {
+			return schema.AssistantMessage(tc.Text, nil), nil
+		}
+		part, err := contentBlockToOutputPart(chunk.Content)
+		if err != nil {
+			return nil, err
+		}
+		return &schema.Message{Role: schema.Assistant, AssistantGenMultiContent: []schema.MessageOutputPart{part}}, nil
+	}
+	if chunk, ok := su.AsAgentThoughtChunk(); ok {
+		tc, ok := chunk.Content.AsText()
+		if !ok {
+			return nil, fmt.Errorf("unsupported agent thought content block")
+		}
+		return &schema.Message{Role: schema.Assistant, ReasoningContent: tc.Text}, nil
+	}
+	if tc, ok := su.AsToolCall(); ok {
+		args := string(tc.RawInput)
+		if args == "" {
+			args = "{}"
+		}
+		return schema.AssistantMessage("", []schema.ToolCall{{
+			ID:       string(tc.ToolCallID),
+			Type:     "function",
+			Function: schema.FunctionCall{Name: tc.Title, Arguments: args},
+		}}), nil
+	}
+	if tu, ok := su.AsToolCallUpdate(); ok {
+		return toolCallUpdateToMessage(tu)
+	}
+	return nil, nil
+}
+
+// toolCallUpdateToMessage converts the result carried by a ToolCallUpdate into a tool message.
+// Text-only content becomes the message content; any other block switches the message to
+// UserInputMultiContent, matching how yieldMessageUpdates renders tool messages. When the
+// update has no content, RawOutput is used as the content.
+func toolCallUpdateToMessage(tu acpproto.ToolCallUpdate) (*schema.Message, error) {
+	var (
+		texts   []string
+		parts   []schema.MessageInputPart
+		allText = true
+	)
+	for _, c := range tu.Content {
+		content, ok := c.AsContent()
+		if !ok {
+			continue
+		}
+		if tc, ok := content.Content.AsText(); ok {
+			texts = append(texts, tc.Text)
+		} else {
+			allText = false
+		}
+		part, err := contentBlockToInputPart(content.Content)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+
+	msg := schema.ToolMessage("", string(tu.ToolCallID))
+	switch {
+	case len(parts) == 0:
+		msg.Content = string(tu.RawOutput)
+	case allText:
+		msg.Content = strings.Join(texts, "")
+	default:
+		msg.UserInputMultiContent = parts
+	}
+	return msg, nil
+}
+
+// contentBlockToOutputPart converts an agent message content block into an assistant output part.
+func contentBlockToOutputPart(block acpproto.ContentBlock) (schema.MessageOutputPart, error) {
+	if tc, ok := block.AsText(); ok {
+		return schema.MessageOutputPart{Type: schema.ChatMessagePartTypeText, Text: tc.Text}, nil
+	}
+	if ic, ok := block.AsImage(); ok {
+		img := &schema.MessageOutputImage{MessagePartCommon: schema.MessagePartCommon{MIMEType: ic.MimeType}}
+		switch {
+		case ic.Data != "":
+			data := ic.Data
+			img.Base64Data = &data
+		case ic.URI != "":
+			uri := ic.URI
+			img.URL = &uri
+		default:
+			return schema.MessageOutputPart{}, fmt.Errorf("image content block has neither data nor uri")
+		}
+		return schema.MessageOutputPart{Type: schema.ChatMessagePartTypeImageURL, Image: img}, nil
+	}
+	if ac, ok := block.AsAudio(); ok {
+		data := ac.Data
+		return schema.MessageOutputPart{
+			Type: schema.ChatMessagePartTypeAudioURL,
+			Audio: &schema.MessageOutputAudio{MessagePartCommon: schema.MessagePartCommon{
+				MIMEType:   ac.MimeType,
+				Base64Data: &data,
+			}},
+		}, nil
+	}
+	return schema.MessageOutputPart{}, fmt.Errorf("unsupported agent message content block")
+}
+
+// messagesToPrompt renders an adk conversation as an ACP prompt. The last message becomes the
+// prompt itself. Earlier messages are folded into a leading text block, so an agent that starts
+// from a fresh session still sees the conversation so far.
+func messagesToPrompt(msgs []adk.Message) ([]acpproto.ContentBlock, error) {
+	if len(msgs) == 0 {
+		return nil, errors.New("no input messages")
+	}
+
+	var blocks []acpproto.ContentBlock
+	if history := renderTranscript(msgs[:len(msgs)-1]); history != "" {
+		blocks = append(blocks, acpproto.NewContentBlockText(acpproto.TextContent{Text: history}))
+	}
+
+	last := msgs[len(msgs)-1]
+	if last.Content != "" {
+		blocks = append(blocks, acpproto.NewContentBlockText(acpproto.TextContent{Text: last.Content}))
+	}
+	for _, part := range last.UserInputMultiContent {
+		cb, err := inputPartToContentBlock(part)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, cb)
+	}
+	if len(blocks) == 0 {
+		return nil, errors.New("input messages carry no content")
+	}
+	return blocks, nil
+}
+
+// renderTranscript renders msgs as a plain-text transcript, one "role: content" entry per
+// message. Non-text parts are omitted.
+func renderTranscript(msgs []adk.Message) string {
+	if len(msgs) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("Conversation so far:\n")
+	for _, m := range msgs {
+		sb.WriteString(string(m.Role))
+		sb.WriteString(": ")
+		sb.WriteString(m.Content)
+		for _, tc := range m.ToolCalls {
+			fmt.Fprintf(&sb, " [call %s(%s)]", tc.Function.Name, tc.Function.Arguments)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
 func outputPartToSessionUpdate(part schema.MessageOutputPart) (acpproto.SessionUpdate, error) {
 	switch part.Type {
 	case schema.ChatMessagePartTypeText:
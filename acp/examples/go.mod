@@ -2,7 +2,10 @@ module github.com/cloudwego/eino-ext/acp/examples
 
 go 1.24
 
-replace github.com/cloudwego/eino-ext/acp => ../
+replace (
+	github.com/cloudwego/eino-ext/acp => ../
+	github.com/cloudwego/eino-ext/adk/backend/patch => ../../adk/backend/patch
+)
 
 require (
 	github.com/cloudwego/eino v0.8.11
@@ -20,6 +23,7 @@ require (
 	github.com/bytedance/sonic v1.15.0 // indirect
 	github.com/bytedance/sonic/loader v0.5.0 // indirect
 	github.com/cloudwego/base64x v0.1.6 // indirect
+	github.com/cloudwego/eino-ext/adk/backend/patch v0.1.0 // indirect
 	github.com/cloudwego/gopkg v0.1.4 // indirect
 	github.com/cloudwego/netpoll v0.7.2 // indirect
 	github.com/dustin/go-humanize v1.0.1 // indirect
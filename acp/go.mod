@@ -2,10 +2,14 @@ module github.com/cloudwego/eino-ext/acp
 
 go 1.24
 
+replace github.com/cloudwego/eino-ext/adk/backend/patch => ../adk/backend/patch
+
 require (
 	github.com/bmatcuk/doublestar/v4 v4.10.0
 	github.com/cloudwego/eino v0.8.11
+	github.com/cloudwego/eino-ext/adk/backend/patch v0.1.0
 	github.com/eino-contrib/acp v0.0.1
+	github.com/google/uuid v1.6.0
 )
 
 require (
@@ -18,7 +22,6 @@ require (
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
 	github.com/dustin/go-humanize v1.0.1 // indirect
 	github.com/eino-contrib/jsonschema v1.0.3 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/goph/emperror v0.17.2 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/klauspost/cpuid/v2 v2.2.9 // indirect
@@ -0,0 +1,253 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoacp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	acpproto "github.com/eino-contrib/acp"
+
+	"github.com/cloudwego/eino-ext/adk/backend/patch"
+)
+
+// memFSConn returns a mockConn whose fs and terminal calls operate on files.
+// Writes to failPath fail; terminal commands are recorded in commands,
+// `rm -f -- '<path>'` removes the file and `test -e '<path>'` exits with 1
+// when the file does not exist.
+func memFSConn(files map[string]string, failPath string, commands *[]string) *mockConn {
+	mc := terminalMock("", 0, false)
+	var exitCode int64
+	mc.waitForTerminalExit = func(_ context.Context, _ acpproto.WaitForTerminalExitRequest) (acpproto.WaitForTerminalExitResponse, error) {
+		ec := exitCode
+		return acpproto.WaitForTerminalExitResponse{ExitCode: &ec}, nil
+	}
+	mc.terminalOutput = func(_ context.Context, _ acpproto.TerminalOutputRequest) (acpproto.TerminalOutputResponse, error) {
+		ec := exitCode
+		return acpproto.TerminalOutputResponse{ExitStatus: &acpproto.TerminalExitStatus{ExitCode: &ec}}, nil
+	}
+	mc.readTextFile = func(_ context.Context, req acpproto.ReadTextFileRequest) (acpproto.ReadTextFileResponse, error) {
+		content, ok := files[req.Path]
+		if !ok {
+			return acpproto.ReadTextFileResponse{}, errors.New("resource not found")
+		}
+		return acpproto.ReadTextFileResponse{Content: content}, nil
+	}
+	mc.writeTextFile = func(_ context.Context, req acpproto.WriteTextFileRequest) (acpproto.WriteTextFileResponse, error) {
+		if req.Path == failPath {
+			return acpproto.WriteTextFileResponse{}, errors.New("disk full")
+		}
+		files[req.Path] = req.Content
+		return acpproto.WriteTextFileResponse{}, nil
+	}
+	mc.createTerminal = func(_ context.Context, req acpproto.CreateTerminalRequest) (acpproto.CreateTerminalResponse, error) {
+		*commands = append(*commands, req.Command)
+		exitCode = 0
+		if p, ok := strings.CutPrefix(req.Command, "rm -f -- "); ok {
+			delete(files, strings.Trim(p, "'"))
+		}
+		if p, ok := strings.CutPrefix(req.Command, "test -e "); ok {
+			if _, exists := files[strings.Trim(p, "'")]; !exists {
+				exitCode = 1
+			}
+		}
+		return acpproto.CreateTerminalResponse{TerminalID: "t1"}, nil
+	}
+	return mc
+}
+
+func TestBackend_MultiEdit(t *testing.T) {
+	files := map[string]string{"/src/a.go": "func a() {}\nfunc b() { a() }\n"}
+	var commands []string
+	b := newMockBackend(memFSConn(files, "", &commands))
+
+	err := b.MultiEdit(context.Background(), &MultiEditRequest{FilePath: "/src/a.go", Edits: []EditOperation{
+		{OldString: "func b()", NewString: "func c()"},
+		{OldString: "a()", NewString: "first()", ReplaceAll: true},
+	}})
+	if err != nil {
+		t.Fatalf("MultiEdit: %v", err)
+	}
+	if want := "func first() {}\nfunc c() { first() }\n"; files["/src/a.go"] != want {
+		t.Fatalf("got %q, want %q", files["/src/a.go"], want)
+	}
+
+	before := files["/src/a.go"]
+	err = b.MultiEdit(context.Background(), &MultiEditRequest{FilePath: "/src/a.go", Edits: []EditOperation{
+		{OldString: "func c()", NewString: "func d()"},
+		{OldString: "func", NewString: "fn"},
+	}})
+	var eErr *EditError
+	if !errors.As(err, &eErr) || eErr.Index != 1 || !errors.Is(err, ErrAmbiguousReplace) {
+		t.Fatalf("expected ambiguous EditError for edit 1, got %v", err)
+	}
+	if !errors.Is(err, patch.ErrAmbiguousReplace) || !strings.Contains(err.Error(), ErrAmbiguousReplace.Error()) {
+		t.Fatalf("expected acp and patch ambiguous errors, got %v", err)
+	}
+	if files["/src/a.go"] != before {
+		t.Fatalf("file changed after a failed multi edit: %q", files["/src/a.go"])
+	}
+
+	err = newBackendNoShell(t).MultiEdit(context.Background(), &MultiEditRequest{FilePath: "/src/a.go", Edits: []EditOperation{{OldString: "a", NewString: "b"}}})
+	if !errors.Is(err, ErrCapabilityMissing) {
+		t.Fatalf("expected ErrCapabilityMissing, got %v", err)
+	}
+}
+
+func TestBackend_ApplyPatch(t *testing.T) {
+	patch := `--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ one
+-two
++2
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++created
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`
+
+	t.Run("success", func(t *testing.T) {
+		files := map[string]string{"/w/a.txt": "one\ntwo\n", "/w/gone.txt": "bye\n"}
+		var commands []string
+		b := newMockBackend(memFSConn(files, "", &commands))
+
+		res, err := b.ApplyPatch(context.Background(), &ApplyPatchRequest{Patch: patch, BaseDir: "/w"})
+		if err != nil {
+			t.Fatalf("ApplyPatch: %v", err)
+		}
+		want := map[string]string{"/w/a.txt": "one\n2\n", "/w/new.txt": "created\n"}
+		if !reflect.DeepEqual(files, want) {
+			t.Fatalf("files = %v, want %v", files, want)
+		}
+		if len(res.Files) != 3 || res.Files[1].Op != PatchFileCreated || res.Files[2].Op != PatchFileDeleted {
+			t.Fatalf("unexpected report: %+v", res.Files)
+		}
+		if want := []string{"test -e '/w/new.txt'", "rm -f -- '/w/gone.txt'"}; !reflect.DeepEqual(commands, want) {
+			t.Fatalf("terminal commands = %v, want %v", commands, want)
+		}
+	})
+
+	t.Run("failing hunk writes nothing", func(t *testing.T) {
+		files := map[string]string{"/w/a.txt": "one\nTWO\n", "/w/gone.txt": "bye\n"}
+		var commands []string
+		b := newMockBackend(memFSConn(files, "", &commands))
+
+		_, err := b.ApplyPatch(context.Background(), &ApplyPatchRequest{Patch: patch, BaseDir: "/w"})
+		var pErr *PatchError
+		if !errors.As(err, &pErr) || pErr.FilePath != "/w/a.txt" || pErr.Hunk != 1 || !errors.Is(err, ErrHunkMismatch) {
+			t.Fatalf("expected PatchError for hunk 1 of /w/a.txt, got %v", err)
+		}
+		if len(files) != 2 || files["/w/a.txt"] != "one\nTWO\n" || len(commands) != 0 {
+			t.Fatalf("client state changed: %v %v", files, commands)
+		}
+	})
+
+	t.Run("failed write rolls back", func(t *testing.T) {
+		files := map[string]string{"/w/a.txt": "one\ntwo\n", "/w/gone.txt": "bye\n"}
+		var commands []string
+		b := newMockBackend(memFSConn(files, "/w/new.txt", &commands))
+
+		_, err := b.ApplyPatch(context.Background(), &ApplyPatchRequest{Patch: patch, BaseDir: "/w"})
+		if err == nil || !strings.Contains(err.Error(), "disk full") {
+			t.Fatalf("expected write failure, got %v", err)
+		}
+		want := map[string]string{"/w/a.txt": "one\ntwo\n", "/w/gone.txt": "bye\n"}
+		if !reflect.DeepEqual(files, want) {
+			t.Fatalf("files = %v, want %v", files, want)
+		}
+	})
+
+	t.Run("deletion without terminal", func(t *testing.T) {
+		files := map[string]string{"/w/a.txt": "one\ntwo\n", "/w/gone.txt": "bye\n"}
+		var commands []string
+		b := newMockBackend(memFSConn(files, "", &commands))
+		b.shell = nil
+
+		_, err := b.ApplyPatch(context.Background(), &ApplyPatchRequest{Patch: patch, BaseDir: "/w"})
+		if !errors.Is(err, ErrCapabilityMissing) {
+			t.Fatalf("expected ErrCapabilityMissing, got %v", err)
+		}
+		if files["/w/a.txt"] != "one\ntwo\n" {
+			t.Fatalf("a.txt was written: %q", files["/w/a.txt"])
+		}
+	})
+
+	t.Run("creation without terminal", func(t *testing.T) {
+		files := map[string]string{"/w/a.txt": "one\ntwo\n"}
+		var commands []string
+		b := newMockBackend(memFSConn(files, "", &commands))
+		b.shell = nil
+
+		created := "--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1 @@\n+created\n"
+		_, err := b.ApplyPatch(context.Background(), &ApplyPatchRequest{Patch: created, BaseDir: "/w"})
+		if !errors.Is(err, ErrCapabilityMissing) {
+			t.Fatalf("expected ErrCapabilityMissing, got %v", err)
+		}
+		if _, ok := files["/w/new.txt"]; ok {
+			t.Fatalf("new.txt was written")
+		}
+	})
+
+	t.Run("created file exists but cannot be read", func(t *testing.T) {
+		files := map[string]string{"/w/a.txt": "one\ntwo\n", "/w/gone.txt": "bye\n", "/w/new.txt": "keep\n"}
+		var commands []string
+		mc := memFSConn(files, "", &commands)
+		read := mc.readTextFile
+		mc.readTextFile = func(ctx context.Context, req acpproto.ReadTextFileRequest) (acpproto.ReadTextFileResponse, error) {
+			if req.Path == "/w/new.txt" {
+				return acpproto.ReadTextFileResponse{}, errors.New("permission denied")
+			}
+			return read(ctx, req)
+		}
+		b := newMockBackend(mc)
+
+		_, err := b.ApplyPatch(context.Background(), &ApplyPatchRequest{Patch: patch, BaseDir: "/w"})
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Fatalf("expected already exists error, got %v", err)
+		}
+		want := map[string]string{"/w/a.txt": "one\ntwo\n", "/w/gone.txt": "bye\n", "/w/new.txt": "keep\n"}
+		if !reflect.DeepEqual(files, want) {
+			t.Fatalf("files = %v, want %v", files, want)
+		}
+	})
+
+	t.Run("relative paths need a base dir", func(t *testing.T) {
+		var commands []string
+		b := newMockBackend(memFSConn(map[string]string{}, "", &commands))
+		_, err := b.ApplyPatch(context.Background(), &ApplyPatchRequest{Patch: patch})
+		if err == nil || !strings.Contains(err.Error(), "path must be absolute") {
+			t.Fatalf("expected absolute path error, got %v", err)
+		}
+	})
+
+	t.Run("invalid patch", func(t *testing.T) {
+		var commands []string
+		b := newMockBackend(memFSConn(map[string]string{}, "", &commands))
+		_, err := b.ApplyPatch(context.Background(), &ApplyPatchRequest{Patch: "not a diff"})
+		if !errors.Is(err, ErrInvalidPatch) {
+			t.Fatalf("expected ErrInvalidPatch, got %v", err)
+		}
+	})
+}
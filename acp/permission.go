@@ -0,0 +1,239 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoacp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/cloudwego/eino/adk"
+	acpproto "github.com/eino-contrib/acp"
+)
+
+// ErrPermissionCancelled is returned by RequestPermissions when the client answers a
+// session/request_permission call with the cancelled outcome, which it does when the
+// prompt turn is being cancelled.
+var ErrPermissionCancelled = errors.New("acp.requestPermission: cancelled by client")
+
+const (
+	// PermissionOptionAllowOnce is the ID of the default "Allow" option.
+	PermissionOptionAllowOnce acpproto.PermissionOptionID = "allow_once"
+	// PermissionOptionRejectOnce is the ID of the default "Reject" option.
+	PermissionOptionRejectOnce acpproto.PermissionOptionID = "reject_once"
+)
+
+// PermissionRequester is the part of the agent-side connection used to ask the client for
+// permission. *acpconn.AgentConnection satisfies it.
+type PermissionRequester interface {
+	RequestPermission(ctx context.Context, req acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error)
+}
+
+// PermissionDecision is the default resume data handed to an interrupted tool once the
+// client has answered the permission request. Tools read it on resume with
+// tool.GetResumeContext[*einoacp.PermissionDecision](ctx).
+type PermissionDecision struct {
+	// OptionID is the option the client selected.
+	OptionID acpproto.PermissionOptionID
+	// Kind is the kind of the selected option, e.g. allow_once or reject_always.
+	Kind acpproto.PermissionOptionKind
+	// Approved reports whether the selected option is one of the allow kinds.
+	Approved bool
+}
+
+// PermissionConfig controls how tool-approval interrupts are bridged to ACP
+// session/request_permission calls.
+type PermissionConfig struct {
+	// Options are the choices offered to the user. Defaults to DefaultPermissionOptions().
+	Options []acpproto.PermissionOption
+
+	// Match reports whether an interrupt context is a tool approval that should be turned
+	// into a permission request. Defaults to root-cause interrupts raised by a tool.
+	Match func(ic *adk.InterruptCtx) bool
+
+	// ToolCall builds the tool call shown to the user for a matched interrupt. By default the
+	// tool call ID is taken from the tool segment of the interrupt address, and the
+	// interrupt info is rendered as text content.
+	ToolCall func(ic *adk.InterruptCtx) acpproto.ToolCallUpdate
+
+	// ResumeData maps the client's decision to the resume data for ic. Defaults to passing
+	// the *PermissionDecision itself.
+	ResumeData func(ic *adk.InterruptCtx, decision *PermissionDecision) (any, error)
+}
+
+// DefaultPermissionOptions returns the options offered when PermissionConfig.Options is empty:
+// a single allow_once and a single reject_once option.
+func DefaultPermissionOptions() []acpproto.PermissionOption {
+	return []acpproto.PermissionOption{
+		{OptionID: PermissionOptionAllowOnce, Name: "Allow", Kind: acpproto.PermissionOptionKindAllowOnce},
+		{OptionID: PermissionOptionRejectOnce, Name: "Reject", Kind: acpproto.PermissionOptionKindRejectOnce},
+	}
+}
+
+// RequestPermissions asks the client, through session/request_permission, to approve every
+// interrupt context in info that cfg matches, and returns the resulting resume targets keyed
+// by interrupt ID, ready for adk.ResumeParams.Targets. It returns an empty map when nothing
+// matched, and ErrPermissionCancelled when the client cancelled any of the requests.
+func RequestPermissions(ctx context.Context, conn PermissionRequester, sessionID acpproto.SessionID,
+	info *adk.InterruptInfo, cfg *PermissionConfig) (map[string]any, error) {
+
+	if conn == nil {
+		return nil, errors.New("acp.RequestPermissions: conn is required")
+	}
+	if cfg == nil {
+		cfg = &PermissionConfig{}
+	}
+
+	targets := make(map[string]any)
+	if info == nil {
+		return targets, nil
+	}
+
+	options := cfg.options()
+	for _, ic := range info.InterruptContexts {
+		if !cfg.match(ic) {
+			continue
+		}
+
+		resp, err := conn.RequestPermission(ctx, acpproto.RequestPermissionRequest{
+			SessionID: sessionID,
+			ToolCall:  cfg.toolCall(ic),
+			Options:   options,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("acp.requestPermission session=%s interrupt=%s: %w", sessionID, ic.ID, err)
+		}
+		if _, ok := resp.Outcome.AsCancelled(); ok {
+			return nil, ErrPermissionCancelled
+		}
+		selected, ok := resp.Outcome.AsSelected()
+		if !ok {
+			return nil, fmt.Errorf("acp.requestPermission session=%s interrupt=%s: empty outcome", sessionID, ic.ID)
+		}
+
+		decision, err := newPermissionDecision(options, selected.OptionID)
+		if err != nil {
+			return nil, fmt.Errorf("acp.requestPermission session=%s interrupt=%s: %w", sessionID, ic.ID, err)
+		}
+
+		var data any = decision
+		if cfg.ResumeData != nil {
+			if data, err = cfg.ResumeData(ic, decision); err != nil {
+				return nil, fmt.Errorf("acp.requestPermission session=%s interrupt=%s: build resume data: %w",
+					sessionID, ic.ID, err)
+			}
+		}
+		targets[ic.ID] = data
+	}
+	return targets, nil
+}
+
+// NewPermissionInterruptConverter returns an InterruptConverter for use together with
+// RequestPermissions. Interrupt contexts that cfg will turn into permission requests produce
+// no session update, since the permission request is what the user sees; the other contexts
+// are passed on to next, or to the default converter when next is nil. An interrupt whose
+// contexts are all turned into permission requests produces no update at all.
+func NewPermissionInterruptConverter(cfg *PermissionConfig, next InterruptConverter) InterruptConverter {
+	if cfg == nil {
+		cfg = &PermissionConfig{}
+	}
+	if next == nil {
+		next = defaultInterruptConverter
+	}
+	return func(info *adk.InterruptInfo) iter.Seq2[acpproto.SessionUpdate, error] {
+		var rest []*adk.InterruptCtx
+		for _, ic := range info.InterruptContexts {
+			if !cfg.match(ic) {
+				rest = append(rest, ic)
+			}
+		}
+		switch len(rest) {
+		case len(info.InterruptContexts):
+			return next(info)
+		case 0:
+			return func(func(acpproto.SessionUpdate, error) bool) {}
+		}
+		filtered := *info
+		filtered.InterruptContexts = rest
+		return next(&filtered)
+	}
+}
+
+func (c *PermissionConfig) options() []acpproto.PermissionOption {
+	if len(c.Options) > 0 {
+		return c.Options
+	}
+	return DefaultPermissionOptions()
+}
+
+func (c *PermissionConfig) match(ic *adk.InterruptCtx) bool {
+	if ic == nil {
+		return false
+	}
+	if c.Match != nil {
+		return c.Match(ic)
+	}
+	return ic.IsRootCause && toolCallIDOf(ic) != ""
+}
+
+func (c *PermissionConfig) toolCall(ic *adk.InterruptCtx) acpproto.ToolCallUpdate {
+	if c.ToolCall != nil {
+		return c.ToolCall(ic)
+	}
+	tc := acpproto.ToolCallUpdate{
+		Meta: map[string]any{
+			MetaKeyInterruptContexts: []map[string]any{interruptCtxToMap(ic)},
+		},
+		ToolCallID: acpproto.ToolCallID(toolCallIDOf(ic)),
+	}
+	if text := interruptDataText(ic.Info); text != "" {
+		tc.Content = []acpproto.ToolCallContent{acpproto.NewToolCallContentContent(acpproto.Content{
+			Content: acpproto.NewContentBlockText(acpproto.TextContent{Text: text}),
+		})}
+	}
+	return tc
+}
+
+// toolCallIDOf returns the tool call ID recorded in the innermost tool segment of the
+// interrupt address, or "" when the interrupt was not raised by a tool. Tool segments carry
+// the tool name as ID and the call ID as SubID; the name is used when no call ID is set.
+func toolCallIDOf(ic *adk.InterruptCtx) string {
+	for i := len(ic.Address) - 1; i >= 0; i-- {
+		seg := ic.Address[i]
+		if seg.Type != adk.AddressSegmentTool {
+			continue
+		}
+		if seg.SubID != "" {
+			return seg.SubID
+		}
+		return seg.ID
+	}
+	return ""
+}
+
+func newPermissionDecision(options []acpproto.PermissionOption, id acpproto.PermissionOptionID) (*PermissionDecision, error) {
+	for _, opt := range options {
+		if opt.OptionID != id {
+			continue
+		}
+		approved := opt.Kind == acpproto.PermissionOptionKindAllowOnce ||
+			opt.Kind == acpproto.PermissionOptionKindAllowAlways
+		return &PermissionDecision{OptionID: id, Kind: opt.Kind, Approved: approved}, nil
+	}
+	return nil, fmt.Errorf("unknown option %q", id)
+}
@@ -0,0 +1,360 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoacp
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	acpproto "github.com/eino-contrib/acp"
+)
+
+type permissionRequesterFunc func(ctx context.Context, req acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error)
+
+func (f permissionRequesterFunc) RequestPermission(ctx context.Context, req acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+	return f(ctx, req)
+}
+
+func selectOption(id acpproto.PermissionOptionID) acpproto.RequestPermissionResponse {
+	return acpproto.RequestPermissionResponse{
+		Outcome: acpproto.NewRequestPermissionOutcomeSelected(acpproto.SelectedPermissionOutcome{OptionID: id}),
+	}
+}
+
+func toolInterrupt(callID string) *adk.InterruptCtx {
+	return &adk.InterruptCtx{
+		ID: "agent:a;tool:rm:" + callID,
+		Address: adk.Address{
+			{Type: adk.AddressSegmentAgent, ID: "a"},
+			{Type: adk.AddressSegmentTool, ID: "rm", SubID: callID},
+		},
+		Info:        "run rm -rf?",
+		IsRootCause: true,
+	}
+}
+
+func TestRequestPermissions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("approve tool interrupt", func(t *testing.T) {
+		var got []acpproto.RequestPermissionRequest
+		conn := permissionRequesterFunc(func(_ context.Context, req acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+			got = append(got, req)
+			return selectOption(PermissionOptionAllowOnce), nil
+		})
+		info := &adk.InterruptInfo{InterruptContexts: []*adk.InterruptCtx{
+			toolInterrupt("call_1"),
+			{ID: "agent:a", Address: adk.Address{{Type: adk.AddressSegmentAgent, ID: "a"}}, IsRootCause: true},
+		}}
+
+		targets, err := RequestPermissions(ctx, conn, "s1", info, nil)
+		if err != nil {
+			t.Fatalf("RequestPermissions: %v", err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("expected 1 permission request, got %d", len(got))
+		}
+		if got[0].SessionID != "s1" || got[0].ToolCall.ToolCallID != "call_1" {
+			t.Fatalf("unexpected request: %+v", got[0])
+		}
+		if len(got[0].Options) != 2 {
+			t.Fatalf("expected default options, got %+v", got[0].Options)
+		}
+		if len(got[0].ToolCall.Content) != 1 {
+			t.Fatalf("expected interrupt info as content, got %+v", got[0].ToolCall.Content)
+		}
+		d, ok := targets["agent:a;tool:rm:call_1"].(*PermissionDecision)
+		if !ok || len(targets) != 1 {
+			t.Fatalf("unexpected targets: %+v", targets)
+		}
+		if !d.Approved || d.OptionID != PermissionOptionAllowOnce || d.Kind != acpproto.PermissionOptionKindAllowOnce {
+			t.Fatalf("unexpected decision: %+v", d)
+		}
+	})
+
+	t.Run("reject with custom resume data", func(t *testing.T) {
+		conn := permissionRequesterFunc(func(context.Context, acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+			return selectOption(PermissionOptionRejectOnce), nil
+		})
+		cfg := &PermissionConfig{
+			ResumeData: func(_ *adk.InterruptCtx, d *PermissionDecision) (any, error) {
+				return d.Approved, nil
+			},
+		}
+		info := &adk.InterruptInfo{InterruptContexts: []*adk.InterruptCtx{toolInterrupt("call_1")}}
+		targets, err := RequestPermissions(ctx, conn, "s1", info, cfg)
+		if err != nil {
+			t.Fatalf("RequestPermissions: %v", err)
+		}
+		if v, ok := targets["agent:a;tool:rm:call_1"].(bool); !ok || v {
+			t.Fatalf("unexpected targets: %+v", targets)
+		}
+	})
+
+	t.Run("no matching interrupt", func(t *testing.T) {
+		conn := permissionRequesterFunc(func(context.Context, acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+			t.Fatal("unexpected permission request")
+			return acpproto.RequestPermissionResponse{}, nil
+		})
+		ic := toolInterrupt("call_1")
+		ic.IsRootCause = false
+		targets, err := RequestPermissions(ctx, conn, "s1", &adk.InterruptInfo{InterruptContexts: []*adk.InterruptCtx{ic}}, nil)
+		if err != nil || len(targets) != 0 {
+			t.Fatalf("expected no targets, got %+v, %v", targets, err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		conn := permissionRequesterFunc(func(context.Context, acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+			return acpproto.RequestPermissionResponse{
+				Outcome: acpproto.NewRequestPermissionOutcomeCancelled(acpproto.CancelledPermissionOutcome{}),
+			}, nil
+		})
+		info := &adk.InterruptInfo{InterruptContexts: []*adk.InterruptCtx{toolInterrupt("call_1")}}
+		if _, err := RequestPermissions(ctx, conn, "s1", info, nil); !errors.Is(err, ErrPermissionCancelled) {
+			t.Fatalf("expected ErrPermissionCancelled, got %v", err)
+		}
+	})
+
+	t.Run("unknown option", func(t *testing.T) {
+		conn := permissionRequesterFunc(func(context.Context, acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+			return selectOption("bogus"), nil
+		})
+		info := &adk.InterruptInfo{InterruptContexts: []*adk.InterruptCtx{toolInterrupt("call_1")}}
+		if _, err := RequestPermissions(ctx, conn, "s1", info, nil); err == nil || !strings.Contains(err.Error(), "unknown option") {
+			t.Fatalf("expected unknown option error, got %v", err)
+		}
+	})
+}
+
+func TestNewPermissionInterruptConverter(t *testing.T) {
+	conv := NewPermissionInterruptConverter(nil, nil)
+
+	var n int
+	for range conv(&adk.InterruptInfo{InterruptContexts: []*adk.InterruptCtx{toolInterrupt("call_1")}}) {
+		n++
+	}
+	if n != 0 {
+		t.Fatalf("expected bridged interrupt to produce no updates, got %d", n)
+	}
+
+	n = 0
+	for su, err := range conv(&adk.InterruptInfo{Data: "need input"}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		requireTextChunk(t, su, "need input")
+		n++
+	}
+	if n != 1 {
+		t.Fatalf("expected fallback rendering, got %d updates", n)
+	}
+
+	// Only the bridged contexts are dropped; the others still reach next.
+	other := &adk.InterruptCtx{ID: "agent:a", Info: "pick a branch", IsRootCause: true}
+	var passed []*adk.InterruptCtx
+	conv = NewPermissionInterruptConverter(nil, func(info *adk.InterruptInfo) iter.Seq2[acpproto.SessionUpdate, error] {
+		passed = info.InterruptContexts
+		return defaultInterruptConverter(info)
+	})
+	info := &adk.InterruptInfo{InterruptContexts: []*adk.InterruptCtx{toolInterrupt("call_1"), other}}
+	n = 0
+	for range conv(info) {
+		n++
+	}
+	if n != 1 || len(passed) != 1 || passed[0] != other {
+		t.Fatalf("expected only the unbridged context to be rendered, got %d updates for %v", n, passed)
+	}
+	if len(info.InterruptContexts) != 2 {
+		t.Fatal("the caller's InterruptInfo was modified")
+	}
+}
+
+// approvalModel calls the "rm" tool once and then reports the tool result.
+type approvalModel struct{}
+
+func (m *approvalModel) Generate(_ context.Context, msgs []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	last := msgs[len(msgs)-1]
+	if last.Role == schema.Tool {
+		return schema.AssistantMessage("tool said: "+last.Content, nil), nil
+	}
+	return schema.AssistantMessage("", []schema.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: schema.FunctionCall{Name: "rm", Arguments: "{}"},
+	}}), nil
+}
+
+func (m *approvalModel) Stream(ctx context.Context, msgs []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, msgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (m *approvalModel) WithTools([]*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return m, nil
+}
+
+// approvalTool interrupts until it is resumed with a PermissionDecision.
+type approvalTool struct{}
+
+func (approvalTool) Info(context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "rm", Desc: "removes files"}, nil
+}
+
+func (approvalTool) InvokableRun(ctx context.Context, _ string, _ ...tool.Option) (string, error) {
+	wasInterrupted, _, _ := tool.GetInterruptState[any](ctx)
+	if !wasInterrupted {
+		return "", tool.Interrupt(ctx, "remove all files?")
+	}
+	isTarget, hasData, d := tool.GetResumeContext[*PermissionDecision](ctx)
+	if !isTarget || !hasData {
+		return "", tool.Interrupt(ctx, "remove all files?")
+	}
+	if !d.Approved {
+		return "rejected", nil
+	}
+	return "removed", nil
+}
+
+func TestServer_PermissionBridge(t *testing.T) {
+	cases := []struct {
+		name   string
+		option acpproto.PermissionOptionID
+		want   string
+	}{
+		{name: "approved", option: PermissionOptionAllowOnce, want: "tool said: removed"},
+		{name: "rejected", option: PermissionOptionRejectOnce, want: "tool said: rejected"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			srv, err := NewServer(&ServerConfig{
+				NewRunner: func(ctx context.Context, req *RunnerRequest) (*adk.Runner, error) {
+					agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+						Name:        "approver",
+						Description: "agent with a guarded tool",
+						Model:       &approvalModel{},
+						ToolsConfig: adk.ToolsConfig{ToolsNodeConfig: compose.ToolsNodeConfig{
+							Tools: []tool.BaseTool{approvalTool{}},
+						}},
+					})
+					if err != nil {
+						return nil, err
+					}
+					return adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent, CheckPointStore: req.CheckPointStore}), nil
+				},
+				Permission: &PermissionConfig{},
+			})
+			if err != nil {
+				t.Fatalf("NewServer: %v", err)
+			}
+
+			var mu sync.Mutex
+			var requests []acpproto.RequestPermissionRequest
+			client := &recordingClient{permission: func(req acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				requests = append(requests, req)
+				return selectOption(tc.option), nil
+			}}
+			conn := connectPipe(t, srv, client)
+
+			sessResp, err := conn.NewSession(ctx, acpproto.NewSessionRequest{})
+			if err != nil {
+				t.Fatalf("NewSession: %v", err)
+			}
+			resp, err := conn.Prompt(ctx, textPrompt(sessResp.SessionID, "clean up"))
+			if err != nil {
+				t.Fatalf("Prompt: %v", err)
+			}
+			if resp.StopReason != acpproto.StopReasonEndTurn {
+				t.Fatalf("StopReason = %v, want end_turn", resp.StopReason)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(requests) != 1 || requests[0].ToolCall.ToolCallID != "call_1" {
+				t.Fatalf("unexpected permission requests: %+v", requests)
+			}
+
+			texts := client.agentTexts()
+			if len(texts) == 0 || texts[len(texts)-1] != tc.want {
+				t.Fatalf("agent texts = %v, want last %q", texts, tc.want)
+			}
+			for _, text := range texts {
+				if strings.Contains(text, "remove all files?") {
+					t.Fatalf("bridged interrupt should not be rendered as a message, got %v", texts)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_PermissionCancelled(t *testing.T) {
+	ctx := context.Background()
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(ctx context.Context, req *RunnerRequest) (*adk.Runner, error) {
+			agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+				Name:        "approver",
+				Description: "agent with a guarded tool",
+				Model:       &approvalModel{},
+				ToolsConfig: adk.ToolsConfig{ToolsNodeConfig: compose.ToolsNodeConfig{
+					Tools: []tool.BaseTool{approvalTool{}},
+				}},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent, CheckPointStore: req.CheckPointStore}), nil
+		},
+		Permission: &PermissionConfig{},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	client := &recordingClient{permission: func(acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+		return acpproto.RequestPermissionResponse{
+			Outcome: acpproto.NewRequestPermissionOutcomeCancelled(acpproto.CancelledPermissionOutcome{}),
+		}, nil
+	}}
+	conn := connectPipe(t, srv, client)
+
+	sessResp, err := conn.NewSession(ctx, acpproto.NewSessionRequest{})
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	resp, err := conn.Prompt(ctx, textPrompt(sessResp.SessionID, "clean up"))
+	if err != nil {
+		t.Fatalf("Prompt: %v", err)
+	}
+	if resp.StopReason != acpproto.StopReasonCancelled {
+		t.Fatalf("StopReason = %v, want cancelled", resp.StopReason)
+	}
+}
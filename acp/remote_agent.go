@@ -0,0 +1,606 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoacp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/schema"
+	acpproto "github.com/eino-contrib/acp"
+	acpconn "github.com/eino-contrib/acp/conn"
+	"github.com/eino-contrib/acp/transport/stdio"
+)
+
+const (
+	// remoteStreamBuffer is the capacity of the message streams handed out in streaming mode.
+	remoteStreamBuffer = 64
+	// remoteCloseGrace is how long Close waits for the subprocess to exit before killing it.
+	remoteCloseGrace = 3 * time.Second
+)
+
+// RemoteAgentConfig configures NewRemoteAgent.
+type RemoteAgentConfig struct {
+	// Name and Description identify the agent inside eino. Name is required.
+	Name        string
+	Description string
+
+	// Command launches the ACP agent as a subprocess that speaks ACP over its stdin/stdout.
+	// Args, Env and Dir are applied to the subprocess; Env entries are appended to the
+	// current environment. Stderr receives the subprocess's stderr and defaults to discarding it.
+	Command string
+	Args    []string
+	Env     []string
+	Dir     string
+	Stderr  io.Writer
+
+	// Reader and Writer connect to an agent that is already running, as an alternative to
+	// Command: Reader carries the agent's output and Writer its input.
+	Reader io.Reader
+	Writer io.Writer
+
+	// Cwd is the working directory sent with every session/new. Defaults to Dir, then to the
+	// current working directory.
+	Cwd string
+
+	// Backend serves the agent's fs/read_text_file and fs/write_text_file requests. When it
+	// also implements filesystem.StreamingShell or filesystem.Shell, terminal requests are
+	// served too. The capabilities advertised at initialize follow what Backend supports;
+	// with a nil Backend the agent is told the client has neither fs nor terminal support.
+	Backend filesystem.Backend
+
+	// RequestPermission answers the agent's session/request_permission calls. When nil, every
+	// request is rejected. AutoApprovePermissions approves them instead, which suits a
+	// sub-agent whose file and shell access is already confined by Backend.
+	RequestPermission func(ctx context.Context, req acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error)
+
+	// Logger is an optional structured logger; defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+func (c *RemoteAgentConfig) validate() error {
+	if c == nil {
+		return errors.New("acp.NewRemoteAgent: cfg is required")
+	}
+	if c.Name == "" {
+		return errors.New("acp.NewRemoteAgent: cfg.Name is required")
+	}
+	hasStream := c.Reader != nil || c.Writer != nil
+	switch {
+	case c.Command == "" && !hasStream:
+		return errors.New("acp.NewRemoteAgent: one of cfg.Command or cfg.Reader/cfg.Writer is required")
+	case c.Command != "" && hasStream:
+		return errors.New("acp.NewRemoteAgent: cfg.Command and cfg.Reader/cfg.Writer are mutually exclusive")
+	case hasStream && (c.Reader == nil || c.Writer == nil):
+		return errors.New("acp.NewRemoteAgent: cfg.Reader and cfg.Writer must be set together")
+	}
+	return nil
+}
+
+// RemoteAgent is an adk.Agent backed by an external ACP agent. Every Run opens a new ACP
+// session, sends the input as a prompt, and translates the agent's session/update
+// notifications back into AgentEvents: message and thought chunks become assistant
+// messages (streamed when the run enables streaming), tool calls become assistant tool-call
+// messages, and tool call results become tool messages. Cancelling the Run context sends
+// session/cancel.
+//
+// RemoteAgent is also the ACP client of that agent: it serves the agent's fs and terminal
+// requests from RemoteAgentConfig.Backend.
+type RemoteAgent struct {
+	acpproto.BaseClient
+
+	cfg    *RemoteAgentConfig
+	logger *slog.Logger
+	cwd    string
+
+	cmd    *exec.Cmd
+	stdin  io.Closer
+	conn   *acpconn.ClientConnection
+	cancel context.CancelFunc
+
+	agentInfo *acpproto.Implementation
+
+	mu   sync.Mutex
+	runs map[acpproto.SessionID]*remoteRun
+
+	terminals *terminalManager
+}
+
+// NewRemoteAgent starts (or connects to) the ACP agent described by cfg and performs the
+// initialize handshake. Call Close to release the connection and stop the subprocess.
+func NewRemoteAgent(ctx context.Context, cfg *RemoteAgentConfig) (*RemoteAgent, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	cwd := cfg.Cwd
+	if cwd == "" {
+		cwd = cfg.Dir
+	}
+	if cwd == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("acp.NewRemoteAgent: resolve cwd: %w", err)
+		}
+		cwd = wd
+	}
+
+	r := &RemoteAgent{
+		cfg:    cfg,
+		logger: logger,
+		cwd:    cwd,
+		runs:   make(map[acpproto.SessionID]*remoteRun),
+	}
+	if sh := shellOf(cfg.Backend); sh != nil {
+		r.terminals = newTerminalManager(sh)
+	}
+
+	reader, writer := cfg.Reader, cfg.Writer
+	if cfg.Command != "" {
+		var err error
+		if reader, writer, err = r.startProcess(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The connection outlives ctx, which only bounds the handshake.
+	connCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.conn = acpconn.NewClientConnectionFromTransport(r, stdio.NewTransport(reader, writer))
+	if err := r.conn.Start(connCtx); err != nil {
+		_ = r.Close()
+		return nil, fmt.Errorf("acp.NewRemoteAgent: start connection: %w", err)
+	}
+
+	resp, err := r.conn.Initialize(ctx, acpproto.InitializeRequest{
+		ProtocolVersion:    acpproto.ProtocolVersion(acpproto.CurrentProtocolVersion),
+		ClientCapabilities: r.clientCapabilities(),
+	})
+	if err != nil {
+		_ = r.Close()
+		return nil, fmt.Errorf("acp.NewRemoteAgent: initialize: %w", err)
+	}
+	r.agentInfo = resp.AgentInfo
+	return r, nil
+}
+
+func (r *RemoteAgent) startProcess() (io.Reader, io.Writer, error) {
+	cmd := exec.Command(r.cfg.Command, r.cfg.Args...)
+	cmd.Dir = r.cfg.Dir
+	if len(r.cfg.Env) > 0 {
+		cmd.Env = append(os.Environ(), r.cfg.Env...)
+	}
+	cmd.Stderr = r.cfg.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("acp.NewRemoteAgent: stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("acp.NewRemoteAgent: stdout pipe: %w", err)
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("acp.NewRemoteAgent: start %s: %w", r.cfg.Command, err)
+	}
+	r.cmd = cmd
+	r.stdin = stdin
+	return stdout, stdin, nil
+}
+
+func (r *RemoteAgent) clientCapabilities() *acpproto.ClientCapabilities {
+	if r.cfg.Backend == nil {
+		return &acpproto.ClientCapabilities{}
+	}
+	return &acpproto.ClientCapabilities{
+		FS:       &acpproto.FileSystemCapabilities{ReadTextFile: true, WriteTextFile: true},
+		Terminal: r.terminals != nil,
+	}
+}
+
+// AgentInfo returns the implementation info the agent reported during initialize, if any.
+func (r *RemoteAgent) AgentInfo() *acpproto.Implementation {
+	return r.agentInfo
+}
+
+// Close kills any terminals still running, closes the connection and, when the agent was
+// launched from Command, waits briefly for the subprocess to exit before killing it.
+func (r *RemoteAgent) Close() error {
+	if r.terminals != nil {
+		r.terminals.killAll()
+	}
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if r.cmd == nil {
+		return nil
+	}
+	_ = r.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- r.cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(remoteCloseGrace):
+		_ = r.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+func (r *RemoteAgent) Name(_ context.Context) string {
+	return r.cfg.Name
+}
+
+func (r *RemoteAgent) Description(_ context.Context) string {
+	return r.cfg.Description
+}
+
+// Run opens a new ACP session and prompts it with input. See RemoteAgent for how the
+// agent's updates are translated.
+func (r *RemoteAgent) Run(ctx context.Context, input *adk.AgentInput, _ ...adk.AgentRunOption) *adk.AsyncIterator[*adk.AgentEvent] {
+	iter, gen := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				gen.Send(&adk.AgentEvent{Err: fmt.Errorf("acp.remoteAgent: panic: %v", p)})
+			}
+			gen.Close()
+		}()
+		if err := r.run(ctx, input, gen); err != nil {
+			gen.Send(&adk.AgentEvent{Err: err})
+		}
+	}()
+	return iter
+}
+
+func (r *RemoteAgent) run(ctx context.Context, input *adk.AgentInput, gen *adk.AsyncGenerator[*adk.AgentEvent]) error {
+	prompt, err := messagesToPrompt(input.Messages)
+	if err != nil {
+		return fmt.Errorf("acp.remoteAgent: convert input: %w", err)
+	}
+
+	sess, err := r.conn.NewSession(ctx, acpproto.NewSessionRequest{Cwd: r.cwd, McpServers: []acpproto.McpServer{}})
+	if err != nil {
+		return fmt.Errorf("acp.remoteAgent: new session: %w", err)
+	}
+
+	run := &remoteRun{
+		gen:        gen,
+		streaming:  input.EnableStreaming,
+		toolTitles: make(map[acpproto.ToolCallID]string),
+		logger:     r.logger,
+	}
+	r.mu.Lock()
+	r.runs[sess.SessionID] = run
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.runs, sess.SessionID)
+		r.mu.Unlock()
+		run.finish()
+	}()
+
+	stop := context.AfterFunc(ctx, func() {
+		// session/cancel is a notification; the agent answers the pending prompt with
+		// StopReasonCancelled.
+		if cErr := r.conn.Cancel(context.Background(), acpproto.CancelNotification{SessionID: sess.SessionID}); cErr != nil {
+			r.logger.Warn("acp.remoteAgent: send cancel failed", "session", sess.SessionID, "err", cErr)
+		}
+	})
+	defer stop()
+
+	resp, err := r.conn.Prompt(ctx, acpproto.PromptRequest{SessionID: sess.SessionID, Prompt: prompt})
+	if err != nil {
+		return fmt.Errorf("acp.remoteAgent: prompt session=%s: %w", sess.SessionID, err)
+	}
+	if resp.StopReason == acpproto.StopReasonCancelled {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("acp.remoteAgent: prompt session=%s: cancelled by agent", sess.SessionID)
+	}
+	return nil
+}
+
+// SessionUpdate routes an update to the run that owns the session.
+func (r *RemoteAgent) SessionUpdate(_ context.Context, n acpproto.SessionNotification) error {
+	r.mu.Lock()
+	run, ok := r.runs[n.SessionID]
+	r.mu.Unlock()
+	if !ok {
+		r.logger.Debug("acp.remoteAgent: update for unknown session", "session", n.SessionID)
+		return nil
+	}
+	run.handle(n.Update)
+	return nil
+}
+
+func (r *RemoteAgent) RequestPermission(ctx context.Context, req acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+	if r.cfg.RequestPermission != nil {
+		return r.cfg.RequestPermission(ctx, req)
+	}
+	// Without a handler, nothing is approved: the reject_once option is selected, or the
+	// request is cancelled if there is none.
+	for _, opt := range req.Options {
+		if opt.Kind == acpproto.PermissionOptionKindRejectOnce {
+			return acpproto.RequestPermissionResponse{
+				Outcome: acpproto.NewRequestPermissionOutcomeSelected(acpproto.SelectedPermissionOutcome{OptionID: opt.OptionID}),
+			}, nil
+		}
+	}
+	return acpproto.RequestPermissionResponse{
+		Outcome: acpproto.NewRequestPermissionOutcomeCancelled(acpproto.CancelledPermissionOutcome{}),
+	}, nil
+}
+
+// AutoApprovePermissions is a RemoteAgentConfig.RequestPermission handler that approves every
+// request: it selects the first allow_once option, or the first option if none is
+// allow_once. Use it only when the agent's file and shell access is already confined by
+// RemoteAgentConfig.Backend.
+func AutoApprovePermissions(_ context.Context, req acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+	if len(req.Options) == 0 {
+		return acpproto.RequestPermissionResponse{
+			Outcome: acpproto.NewRequestPermissionOutcomeCancelled(acpproto.CancelledPermissionOutcome{}),
+		}, nil
+	}
+	selected := req.Options[0].OptionID
+	for _, opt := range req.Options {
+		if opt.Kind == acpproto.PermissionOptionKindAllowOnce {
+			selected = opt.OptionID
+			break
+		}
+	}
+	return acpproto.RequestPermissionResponse{
+		Outcome: acpproto.NewRequestPermissionOutcomeSelected(acpproto.SelectedPermissionOutcome{OptionID: selected}),
+	}, nil
+}
+
+func (r *RemoteAgent) ReadTextFile(ctx context.Context, req acpproto.ReadTextFileRequest) (acpproto.ReadTextFileResponse, error) {
+	if r.cfg.Backend == nil {
+		return acpproto.ReadTextFileResponse{}, fmt.Errorf("%w: fs/read_text_file", ErrCapabilityMissing)
+	}
+	rr := &filesystem.ReadRequest{FilePath: req.Path}
+	if req.Line != nil {
+		rr.Offset = int(*req.Line)
+	}
+	if req.Limit != nil {
+		rr.Limit = int(*req.Limit)
+	}
+	fc, err := r.cfg.Backend.Read(ctx, rr)
+	if err != nil {
+		return acpproto.ReadTextFileResponse{}, fmt.Errorf("acp.remoteAgent: read %s: %w", req.Path, err)
+	}
+	return acpproto.ReadTextFileResponse{Content: fc.Content}, nil
+}
+
+func (r *RemoteAgent) WriteTextFile(ctx context.Context, req acpproto.WriteTextFileRequest) (acpproto.WriteTextFileResponse, error) {
+	if r.cfg.Backend == nil {
+		return acpproto.WriteTextFileResponse{}, fmt.Errorf("%w: fs/write_text_file", ErrCapabilityMissing)
+	}
+	if err := r.cfg.Backend.Write(ctx, &filesystem.WriteRequest{FilePath: req.Path, Content: req.Content}); err != nil {
+		return acpproto.WriteTextFileResponse{}, fmt.Errorf("acp.remoteAgent: write %s: %w", req.Path, err)
+	}
+	return acpproto.WriteTextFileResponse{}, nil
+}
+
+func (r *RemoteAgent) CreateTerminal(_ context.Context, req acpproto.CreateTerminalRequest) (acpproto.CreateTerminalResponse, error) {
+	if r.terminals == nil {
+		return acpproto.CreateTerminalResponse{}, fmt.Errorf("%w: terminal/create", ErrCapabilityMissing)
+	}
+	id, err := r.terminals.create(req)
+	if err != nil {
+		return acpproto.CreateTerminalResponse{}, err
+	}
+	return acpproto.CreateTerminalResponse{TerminalID: id}, nil
+}
+
+func (r *RemoteAgent) TerminalOutput(_ context.Context, req acpproto.TerminalOutputRequest) (acpproto.TerminalOutputResponse, error) {
+	if r.terminals == nil {
+		return acpproto.TerminalOutputResponse{}, fmt.Errorf("%w: terminal/output", ErrCapabilityMissing)
+	}
+	return r.terminals.output(req.TerminalID)
+}
+
+func (r *RemoteAgent) WaitForTerminalExit(ctx context.Context, req acpproto.WaitForTerminalExitRequest) (acpproto.WaitForTerminalExitResponse, error) {
+	if r.terminals == nil {
+		return acpproto.WaitForTerminalExitResponse{}, fmt.Errorf("%w: terminal/wait_for_exit", ErrCapabilityMissing)
+	}
+	return r.terminals.wait(ctx, req.TerminalID)
+}
+
+func (r *RemoteAgent) KillTerminalCommand(_ context.Context, req acpproto.KillTerminalCommandRequest) (acpproto.KillTerminalCommandResponse, error) {
+	if r.terminals == nil {
+		return acpproto.KillTerminalCommandResponse{}, fmt.Errorf("%w: terminal/kill", ErrCapabilityMissing)
+	}
+	return acpproto.KillTerminalCommandResponse{}, r.terminals.kill(req.TerminalID)
+}
+
+func (r *RemoteAgent) ReleaseTerminal(_ context.Context, req acpproto.ReleaseTerminalRequest) (acpproto.ReleaseTerminalResponse, error) {
+	if r.terminals == nil {
+		return acpproto.ReleaseTerminalResponse{}, fmt.Errorf("%w: terminal/release", ErrCapabilityMissing)
+	}
+	return acpproto.ReleaseTerminalResponse{}, r.terminals.release(req.TerminalID)
+}
+
+// remoteRun translates the updates of one prompt turn into AgentEvents. Consecutive chunks of
+// the same kind (agent text or thought) are grouped into one message: in streaming mode the
+// message is a stream that stays open until a chunk of another kind arrives; otherwise the
+// chunks are concatenated and emitted as a single message.
+//
+// Writing to a stream blocks once its buffer is full, so stream chunks are not sent under mu:
+// they are queued in order and delivered by sendLoop, and a slow stream consumer never
+// holds up the connection's notifications.
+type remoteRun struct {
+	gen       *adk.AsyncGenerator[*adk.AgentEvent]
+	streaming bool
+	logger    *slog.Logger
+
+	mu         sync.Mutex
+	toolTitles map[acpproto.ToolCallID]string
+
+	kind    chunkKind
+	pending []*schema.Message
+	writer  *schema.StreamWriter[*schema.Message]
+
+	sends   []streamSend
+	sending bool
+}
+
+// streamSend is a chunk to send to writer, or the end of writer's stream when msg is nil.
+type streamSend struct {
+	writer *schema.StreamWriter[*schema.Message]
+	msg    *schema.Message
+}
+
+type chunkKind int
+
+const (
+	chunkNone chunkKind = iota
+	chunkMessage
+	chunkThought
+)
+
+func (rr *remoteRun) handle(su acpproto.SessionUpdate) {
+	msg, err := SessionUpdateToMessage(su)
+	if err != nil {
+		rr.logger.Warn("acp.remoteAgent: skip unconvertible update", "err", err)
+		return
+	}
+	if msg == nil {
+		return
+	}
+
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	switch {
+	case msg.Role == schema.User:
+		// Echoed user input; the caller already has it.
+		return
+	case msg.Role == schema.Assistant && len(msg.ToolCalls) > 0:
+		rr.flushLocked()
+		for _, tc := range msg.ToolCalls {
+			rr.toolTitles[acpproto.ToolCallID(tc.ID)] = tc.Function.Name
+		}
+		rr.gen.Send(adk.EventFromMessage(msg, nil, schema.Assistant, ""))
+	case msg.Role == schema.Tool:
+		if msg.Content == "" && len(msg.UserInputMultiContent) == 0 {
+			// A status-only update, e.g. in_progress.
+			return
+		}
+		rr.flushLocked()
+		name := rr.toolTitles[acpproto.ToolCallID(msg.ToolCallID)]
+		msg.ToolName = name
+		rr.gen.Send(adk.EventFromMessage(msg, nil, schema.Tool, name))
+	default:
+		kind := chunkMessage
+		if msg.ReasoningContent != "" {
+			kind = chunkThought
+		}
+		if kind != rr.kind {
+			rr.flushLocked()
+			rr.kind = kind
+		}
+		rr.appendLocked(msg)
+	}
+}
+
+func (rr *remoteRun) appendLocked(msg *schema.Message) {
+	if !rr.streaming {
+		rr.pending = append(rr.pending, msg)
+		return
+	}
+	if rr.writer == nil {
+		sr, sw := schema.Pipe[*schema.Message](remoteStreamBuffer)
+		rr.writer = sw
+		rr.gen.Send(adk.EventFromMessage(nil, sr, schema.Assistant, ""))
+	}
+	rr.queueLocked(streamSend{writer: rr.writer, msg: msg})
+}
+
+// flushLocked ends the current group of chunks.
+func (rr *remoteRun) flushLocked() {
+	defer func() { rr.kind = chunkNone }()
+	if rr.writer != nil {
+		rr.queueLocked(streamSend{writer: rr.writer})
+		rr.writer = nil
+		return
+	}
+	if len(rr.pending) == 0 {
+		return
+	}
+	msg, err := schema.ConcatMessages(rr.pending)
+	rr.pending = nil
+	if err != nil {
+		rr.gen.Send(&adk.AgentEvent{Err: fmt.Errorf("acp.remoteAgent: concat chunks: %w", err)})
+		return
+	}
+	rr.gen.Send(adk.EventFromMessage(msg, nil, schema.Assistant, ""))
+}
+
+// queueLocked queues s for sendLoop, starting it if it is not running.
+func (rr *remoteRun) queueLocked(s streamSend) {
+	rr.sends = append(rr.sends, s)
+	if !rr.sending {
+		rr.sending = true
+		go rr.sendLoop()
+	}
+}
+
+// sendLoop delivers the queued stream sends in order, without holding mu, and exits once
+// the queue is empty.
+func (rr *remoteRun) sendLoop() {
+	for {
+		rr.mu.Lock()
+		sends := rr.sends
+		rr.sends = nil
+		if len(sends) == 0 {
+			rr.sending = false
+		}
+		rr.mu.Unlock()
+		if len(sends) == 0 {
+			return
+		}
+
+		for _, s := range sends {
+			if s.msg == nil {
+				s.writer.Close()
+			} else {
+				s.writer.Send(s.msg, nil)
+			}
+		}
+	}
+}
+
+func (rr *remoteRun) finish() {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.flushLocked()
+}
@@ -0,0 +1,551 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoacp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/schema"
+	acpproto "github.com/eino-contrib/acp"
+	acpconn "github.com/eino-contrib/acp/conn"
+	"github.com/eino-contrib/acp/transport/stdio"
+)
+
+// helperAgentEnv makes the test binary act as an ACP agent over stdio, so RemoteAgent can be
+// exercised against a real subprocess.
+const helperAgentEnv = "EINOACP_HELPER_AGENT"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperAgentEnv) == "1" {
+		runHelperAgent()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func runHelperAgent() {
+	var mu sync.Mutex
+	var seen []int
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(ctx context.Context, _ *RunnerRequest) (*adk.Runner, error) {
+			return adk.NewRunner(ctx, adk.RunnerConfig{Agent: echoAgent(&seen, &mu)}), nil
+		},
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	_ = srv.Serve(context.Background(), os.Stdin, os.Stdout)
+}
+
+// pipePair returns the two ends of an in-memory bidirectional stream.
+func pipePair(t *testing.T) (agentR io.Reader, agentW io.Writer, clientR io.Reader, clientW io.Writer) {
+	t.Helper()
+	c2aR, c2aW := io.Pipe()
+	a2cR, a2cW := io.Pipe()
+	t.Cleanup(func() {
+		_ = c2aW.Close()
+		_ = a2cW.Close()
+	})
+	return c2aR, a2cW, a2cR, c2aW
+}
+
+// newRemoteForServer serves srv over a pipe pair and connects a RemoteAgent to it.
+func newRemoteForServer(t *testing.T, srv *Server, cfg *RemoteAgentConfig) *RemoteAgent {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	agentR, agentW, clientR, clientW := pipePair(t)
+	go func() { _ = srv.Serve(ctx, agentR, agentW) }()
+
+	cfg.Reader, cfg.Writer = clientR, clientW
+	remote, err := NewRemoteAgent(ctx, cfg)
+	if err != nil {
+		cancel()
+		t.Fatalf("NewRemoteAgent: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = remote.Close()
+		cancel()
+	})
+	return remote
+}
+
+func scriptedServer(t *testing.T, events ...*adk.AgentEvent) *Server {
+	t.Helper()
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(ctx context.Context, _ *RunnerRequest) (*adk.Runner, error) {
+			return adk.NewRunner(ctx, adk.RunnerConfig{Agent: &scriptedAgent{
+				run: func(_ context.Context, _ *adk.AgentInput, gen *adk.AsyncGenerator[*adk.AgentEvent]) {
+					for _, e := range events {
+						gen.Send(e)
+					}
+				},
+			}}), nil
+		},
+		AgentInfo: &acpproto.Implementation{Name: "scripted-acp", Version: "1.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	return srv
+}
+
+func collectMessages(t *testing.T, iter *adk.AsyncIterator[*adk.AgentEvent]) []*schema.Message {
+	t.Helper()
+	var msgs []*schema.Message
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			return msgs
+		}
+		if event.Err != nil {
+			t.Fatalf("unexpected event error: %v", event.Err)
+		}
+		msg, err := event.Output.MessageOutput.GetMessage()
+		if err != nil {
+			t.Fatalf("GetMessage: %v", err)
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+func TestNewRemoteAgent_Validate(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name string
+		cfg  *RemoteAgentConfig
+		want string
+	}{
+		{name: "nil", cfg: nil, want: "cfg is required"},
+		{name: "no name", cfg: &RemoteAgentConfig{Command: "agent"}, want: "cfg.Name is required"},
+		{name: "no transport", cfg: &RemoteAgentConfig{Name: "a"}, want: "one of cfg.Command"},
+		{name: "both", cfg: &RemoteAgentConfig{Name: "a", Command: "agent", Reader: strings.NewReader("")}, want: "mutually exclusive"},
+		{name: "half stream", cfg: &RemoteAgentConfig{Name: "a", Reader: strings.NewReader("")}, want: "must be set together"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewRemoteAgent(ctx, tc.cfg); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestRemoteAgent_TranslatesUpdates(t *testing.T) {
+	srv := scriptedServer(t,
+		adk.EventFromMessage(&schema.Message{Role: schema.Assistant, ReasoningContent: "thinking"}, nil, schema.Assistant, ""),
+		adk.EventFromMessage(schema.AssistantMessage("let me look", nil), nil, schema.Assistant, ""),
+		adk.EventFromMessage(schema.AssistantMessage("", []schema.ToolCall{{
+			ID:       "call_1",
+			Function: schema.FunctionCall{Name: "ls", Arguments: `{"path":"/"}`},
+		}}), nil, schema.Assistant, ""),
+		adk.EventFromMessage(schema.ToolMessage("a.txt", "call_1"), nil, schema.Tool, "ls"),
+		adk.EventFromMessage(schema.AssistantMessage("found a.txt", nil), nil, schema.Assistant, ""),
+	)
+	remote := newRemoteForServer(t, srv, &RemoteAgentConfig{Name: "remote", Description: "remote agent"})
+
+	if info := remote.AgentInfo(); info == nil || info.Name != "scripted-acp" {
+		t.Fatalf("unexpected agent info: %+v", info)
+	}
+	if remote.Name(context.Background()) != "remote" {
+		t.Fatalf("unexpected name")
+	}
+
+	msgs := collectMessages(t, remote.Run(context.Background(), &adk.AgentInput{
+		Messages: []adk.Message{schema.UserMessage("what's in /?")},
+	}))
+	if len(msgs) != 5 {
+		t.Fatalf("expected 5 messages, got %d: %+v", len(msgs), msgs)
+	}
+	if msgs[0].ReasoningContent != "thinking" {
+		t.Fatalf("msgs[0] = %+v, want thought", msgs[0])
+	}
+	if msgs[1].Role != schema.Assistant || msgs[1].Content != "let me look" {
+		t.Fatalf("msgs[1] = %+v", msgs[1])
+	}
+	if len(msgs[2].ToolCalls) != 1 || msgs[2].ToolCalls[0].ID != "call_1" ||
+		msgs[2].ToolCalls[0].Function.Name != "ls" || msgs[2].ToolCalls[0].Function.Arguments != `{"path":"/"}` {
+		t.Fatalf("msgs[2] = %+v", msgs[2])
+	}
+	if msgs[3].Role != schema.Tool || msgs[3].Content != "a.txt" || msgs[3].ToolCallID != "call_1" || msgs[3].ToolName != "ls" {
+		t.Fatalf("msgs[3] = %+v", msgs[3])
+	}
+	if msgs[4].Content != "found a.txt" {
+		t.Fatalf("msgs[4] = %+v", msgs[4])
+	}
+}
+
+func TestRemoteAgent_Streaming(t *testing.T) {
+	stream := schema.StreamReaderFromArray([]*schema.Message{
+		schema.AssistantMessage("hel", nil),
+		schema.AssistantMessage("lo", nil),
+	})
+	srv := scriptedServer(t, adk.EventFromMessage(nil, stream, schema.Assistant, ""))
+	remote := newRemoteForServer(t, srv, &RemoteAgentConfig{Name: "remote"})
+
+	iter := remote.Run(context.Background(), &adk.AgentInput{
+		Messages:        []adk.Message{schema.UserMessage("hi")},
+		EnableStreaming: true,
+	})
+	event, ok := iter.Next()
+	if !ok {
+		t.Fatal("expected an event")
+	}
+	if event.Err != nil {
+		t.Fatalf("unexpected error: %v", event.Err)
+	}
+	if !event.Output.MessageOutput.IsStreaming {
+		t.Fatal("expected a streaming message output")
+	}
+	msg, err := event.Output.MessageOutput.GetMessage()
+	if err != nil {
+		t.Fatalf("GetMessage: %v", err)
+	}
+	if msg.Content != "hello" {
+		t.Fatalf("content = %q, want hello", msg.Content)
+	}
+	if _, ok = iter.Next(); ok {
+		t.Fatal("expected no more events")
+	}
+}
+
+func TestRemoteAgent_Subprocess(t *testing.T) {
+	ctx := context.Background()
+	remote, err := NewRemoteAgent(ctx, &RemoteAgentConfig{
+		Name:    "echo",
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^$"},
+		Env:     []string{helperAgentEnv + "=1"},
+		Stderr:  os.Stderr,
+	})
+	if err != nil {
+		t.Fatalf("NewRemoteAgent: %v", err)
+	}
+	defer remote.Close()
+
+	msgs := collectMessages(t, remote.Run(ctx, &adk.AgentInput{Messages: []adk.Message{schema.UserMessage("ping")}}))
+	if len(msgs) != 1 || msgs[0].Content != "echo: ping" {
+		t.Fatalf("unexpected messages: %+v", msgs)
+	}
+}
+
+func TestRemoteAgent_RunError(t *testing.T) {
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(context.Context, *RunnerRequest) (*adk.Runner, error) {
+			return nil, errors.New("no runner for you")
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	remote := newRemoteForServer(t, srv, &RemoteAgentConfig{Name: "remote"})
+
+	iter := remote.Run(context.Background(), &adk.AgentInput{Messages: []adk.Message{schema.UserMessage("hi")}})
+	event, ok := iter.Next()
+	if !ok || event.Err == nil || !strings.Contains(event.Err.Error(), "no runner for you") {
+		t.Fatalf("expected new session error, got %+v", event)
+	}
+}
+
+func TestRemoteAgent_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(ctx context.Context, _ *RunnerRequest) (*adk.Runner, error) {
+			return adk.NewRunner(ctx, adk.RunnerConfig{Agent: &scriptedAgent{
+				run: func(ctx context.Context, _ *adk.AgentInput, gen *adk.AsyncGenerator[*adk.AgentEvent]) {
+					close(started)
+					<-ctx.Done()
+					gen.Send(&adk.AgentEvent{Err: ctx.Err()})
+				},
+			}}), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	remote := newRemoteForServer(t, srv, &RemoteAgentConfig{Name: "remote"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iter := remote.Run(ctx, &adk.AgentInput{Messages: []adk.Message{schema.UserMessage("hi")}})
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("remote agent did not start")
+	}
+	cancel()
+
+	event, ok := iter.Next()
+	if !ok || !errors.Is(event.Err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %+v", event)
+	}
+}
+
+// shellBackend is an in-memory backend whose shell echoes the command it was given.
+type shellBackend struct {
+	*filesystem.InMemoryBackend
+	commands []string
+}
+
+func (b *shellBackend) Execute(_ context.Context, req *filesystem.ExecuteRequest) (*filesystem.ExecuteResponse, error) {
+	b.commands = append(b.commands, req.Command)
+	code := 0
+	return &filesystem.ExecuteResponse{Output: "ran: " + req.Command, ExitCode: &code}, nil
+}
+
+// fsAgent is a raw ACP agent that exercises the client's fs and terminal methods on
+// every prompt and reports what it saw as a message chunk.
+type fsAgent struct {
+	acpproto.BaseAgent
+	conn *acpconn.AgentConnection
+	caps *acpproto.ClientCapabilities
+}
+
+func (a *fsAgent) Initialize(_ context.Context, req acpproto.InitializeRequest) (acpproto.InitializeResponse, error) {
+	a.caps = req.ClientCapabilities
+	return acpproto.InitializeResponse{ProtocolVersion: acpproto.ProtocolVersion(acpproto.CurrentProtocolVersion)}, nil
+}
+
+func (a *fsAgent) NewSession(context.Context, acpproto.NewSessionRequest) (acpproto.NewSessionResponse, error) {
+	return acpproto.NewSessionResponse{SessionID: "fs-session"}, nil
+}
+
+func (a *fsAgent) Prompt(ctx context.Context, req acpproto.PromptRequest) (acpproto.PromptResponse, error) {
+	if _, err := a.conn.WriteTextFile(ctx, acpproto.WriteTextFileRequest{
+		SessionID: req.SessionID, Path: "/notes.txt", Content: "remember the milk",
+	}); err != nil {
+		return acpproto.PromptResponse{}, err
+	}
+	read, err := a.conn.ReadTextFile(ctx, acpproto.ReadTextFileRequest{SessionID: req.SessionID, Path: "/notes.txt"})
+	if err != nil {
+		return acpproto.PromptResponse{}, err
+	}
+
+	cwd := "/work"
+	term, err := a.conn.CreateTerminal(ctx, acpproto.CreateTerminalRequest{
+		SessionID: req.SessionID, Command: "echo", Args: []string{"it's"}, Cwd: &cwd,
+	})
+	if err != nil {
+		return acpproto.PromptResponse{}, err
+	}
+	exit, err := a.conn.WaitForTerminalExit(ctx, acpproto.WaitForTerminalExitRequest{SessionID: req.SessionID, TerminalID: term.TerminalID})
+	if err != nil {
+		return acpproto.PromptResponse{}, err
+	}
+	out, err := a.conn.TerminalOutput(ctx, acpproto.TerminalOutputRequest{SessionID: req.SessionID, TerminalID: term.TerminalID})
+	if err != nil {
+		return acpproto.PromptResponse{}, err
+	}
+	if _, err = a.conn.ReleaseTerminal(ctx, acpproto.ReleaseTerminalRequest{SessionID: req.SessionID, TerminalID: term.TerminalID}); err != nil {
+		return acpproto.PromptResponse{}, err
+	}
+
+	report := fmt.Sprintf("read=%q exit=%d out=%q", read.Content, *exit.ExitCode, out.Output)
+	if err = a.conn.SessionUpdate(ctx, acpproto.SessionNotification{
+		SessionID: req.SessionID,
+		Update: acpproto.NewSessionUpdateAgentMessageChunk(acpproto.ContentChunk{
+			Content: acpproto.NewContentBlockText(acpproto.TextContent{Text: report}),
+		}),
+	}); err != nil {
+		return acpproto.PromptResponse{}, err
+	}
+	return acpproto.PromptResponse{StopReason: acpproto.StopReasonEndTurn}, nil
+}
+
+func TestRemoteAgent_ServesFSAndTerminalFromBackend(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	agentR, agentW, clientR, clientW := pipePair(t)
+	agent := &fsAgent{}
+	agent.conn = acpconn.NewAgentConnectionFromTransport(agent, stdio.NewTransport(agentR, agentW))
+	if err := agent.conn.Start(ctx); err != nil {
+		t.Fatalf("start agent connection: %v", err)
+	}
+
+	backend := &shellBackend{InMemoryBackend: filesystem.NewInMemoryBackend()}
+	remote, err := NewRemoteAgent(ctx, &RemoteAgentConfig{
+		Name:    "fs",
+		Reader:  clientR,
+		Writer:  clientW,
+		Backend: backend,
+	})
+	if err != nil {
+		t.Fatalf("NewRemoteAgent: %v", err)
+	}
+	defer remote.Close()
+
+	if agent.caps == nil || agent.caps.FS == nil || !agent.caps.FS.ReadTextFile || !agent.caps.FS.WriteTextFile || !agent.caps.Terminal {
+		t.Fatalf("unexpected advertised capabilities: %+v", agent.caps)
+	}
+
+	msgs := collectMessages(t, remote.Run(ctx, &adk.AgentInput{Messages: []adk.Message{schema.UserMessage("go")}}))
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(msgs))
+	}
+	wantCmd := `cd '/work' && 'echo' 'it'\''s'`
+	want := fmt.Sprintf("read=%q exit=0 out=%q", "remember the milk", "ran: "+wantCmd)
+	if msgs[0].Content != want {
+		t.Fatalf("report = %s\nwant     %s", msgs[0].Content, want)
+	}
+
+	content, err := backend.Read(ctx, &filesystem.ReadRequest{FilePath: "/notes.txt"})
+	if err != nil || content.Content != "remember the milk" {
+		t.Fatalf("backend content = %+v, %v", content, err)
+	}
+}
+
+func TestRemoteAgent_NoBackendAdvertisesNothing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	agentR, agentW, clientR, clientW := pipePair(t)
+	agent := &fsAgent{}
+	agent.conn = acpconn.NewAgentConnectionFromTransport(agent, stdio.NewTransport(agentR, agentW))
+	if err := agent.conn.Start(ctx); err != nil {
+		t.Fatalf("start agent connection: %v", err)
+	}
+	remote, err := NewRemoteAgent(ctx, &RemoteAgentConfig{Name: "fs", Reader: clientR, Writer: clientW})
+	if err != nil {
+		t.Fatalf("NewRemoteAgent: %v", err)
+	}
+	defer remote.Close()
+
+	if agent.caps == nil || agent.caps.FS != nil || agent.caps.Terminal {
+		t.Fatalf("unexpected advertised capabilities: %+v", agent.caps)
+	}
+	if _, err = remote.ReadTextFile(ctx, acpproto.ReadTextFileRequest{Path: "/x"}); !errors.Is(err, ErrCapabilityMissing) {
+		t.Fatalf("expected ErrCapabilityMissing, got %v", err)
+	}
+}
+
+func TestTerminalOutputByteLimit(t *testing.T) {
+	term := &terminal{limit: 4}
+	term.appendOutput("hello ")
+	term.appendOutput("wörld")
+	// The last 4 bytes start inside the two-byte "ö", so the cut moves past it.
+	if got := term.output.String(); got != "rld" || !term.truncated {
+		t.Fatalf("output = %q truncated=%v", got, term.truncated)
+	}
+}
+
+func TestMessagesToPrompt(t *testing.T) {
+	blocks, err := messagesToPrompt([]adk.Message{
+		schema.UserMessage("list files"),
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "c1", Function: schema.FunctionCall{Name: "ls", Arguments: "{}"}}}),
+		schema.ToolMessage("a.txt", "c1"),
+		schema.UserMessage("now read it"),
+	})
+	if err != nil {
+		t.Fatalf("messagesToPrompt: %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("expected transcript + prompt blocks, got %d", len(blocks))
+	}
+	history, _ := blocks[0].AsText()
+	if !strings.Contains(history.Text, "user: list files") || !strings.Contains(history.Text, "[call ls({})]") ||
+		!strings.Contains(history.Text, "tool: a.txt") {
+		t.Fatalf("unexpected transcript: %q", history.Text)
+	}
+	last, _ := blocks[1].AsText()
+	if last.Text != "now read it" {
+		t.Fatalf("prompt = %q", last.Text)
+	}
+
+	if _, err = messagesToPrompt(nil); err == nil {
+		t.Fatal("expected error for empty input")
+	}
+}
+
+func TestRemoteAgent_RequestPermission(t *testing.T) {
+	ctx := context.Background()
+	req := acpproto.RequestPermissionRequest{Options: []acpproto.PermissionOption{
+		{OptionID: "always", Kind: acpproto.PermissionOptionKindAllowAlways},
+		{OptionID: "once", Kind: acpproto.PermissionOptionKindAllowOnce},
+		{OptionID: "reject", Kind: acpproto.PermissionOptionKindRejectOnce},
+	}}
+	selected := func(resp acpproto.RequestPermissionResponse, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("RequestPermission: %v", err)
+		}
+		if _, ok := resp.Outcome.AsCancelled(); ok {
+			return "cancelled"
+		}
+		s, _ := resp.Outcome.AsSelected()
+		return string(s.OptionID)
+	}
+
+	// Nothing is approved by default.
+	remote := &RemoteAgent{cfg: &RemoteAgentConfig{}}
+	if got := selected(remote.RequestPermission(ctx, req)); got != "reject" {
+		t.Fatalf("default selected %q, want reject", got)
+	}
+	if got := selected(remote.RequestPermission(ctx, acpproto.RequestPermissionRequest{Options: req.Options[:2]})); got != "cancelled" {
+		t.Fatalf("default without reject option: %q, want cancelled", got)
+	}
+
+	remote = &RemoteAgent{cfg: &RemoteAgentConfig{RequestPermission: AutoApprovePermissions}}
+	if got := selected(remote.RequestPermission(ctx, req)); got != "once" {
+		t.Fatalf("AutoApprovePermissions selected %q, want once", got)
+	}
+}
+
+func TestRemoteRun_SlowStreamConsumer(t *testing.T) {
+	iter, gen := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	run := &remoteRun{gen: gen, streaming: true, toolTitles: map[acpproto.ToolCallID]string{}, logger: slog.Default()}
+
+	// Nobody reads the stream while the chunks arrive, so more chunks than the stream buffer
+	// holds must not block the updates.
+	const n = remoteStreamBuffer * 3
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < n; i++ {
+			run.handle(textUpdate("x"))
+		}
+		run.finish()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("updates blocked on the stream consumer")
+	}
+	gen.Close()
+
+	event, ok := iter.Next()
+	if !ok || event.Err != nil {
+		t.Fatalf("expected a stream event, got %+v", event)
+	}
+	msg, err := event.Output.MessageOutput.GetMessage()
+	if err != nil {
+		t.Fatalf("GetMessage: %v", err)
+	}
+	if msg.Content != strings.Repeat("x", n) {
+		t.Fatalf("content has %d chunks, want %d", len(msg.Content), n)
+	}
+}
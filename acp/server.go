@@ -0,0 +1,617 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoacp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+	acpproto "github.com/eino-contrib/acp"
+	acpconn "github.com/eino-contrib/acp/conn"
+	"github.com/eino-contrib/acp/transport/stdio"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSessionNotFound is returned when a request references a session the server does not know.
+	ErrSessionNotFound = errors.New("acp.server: session not found")
+	// ErrNoConnection is returned when the server is asked to serve a session before a client
+	// connection has been attached via SetClientConnection or Serve.
+	ErrNoConnection = errors.New("acp.server: client connection is not set")
+
+	// errPromptCancelled is the cancellation cause installed by Server.Cancel. It lets Prompt
+	// distinguish a client-initiated session/cancel from the caller's own context ending.
+	errPromptCancelled = errors.New("acp.server: prompt cancelled by client")
+)
+
+// ServerConn is the agent-side connection used by Server to talk back to the client.
+// *acpconn.AgentConnection satisfies it; tests and proxies may supply their own.
+type ServerConn interface {
+	ACPConn
+	PermissionRequester
+	SessionUpdate(ctx context.Context, n acpproto.SessionNotification) error
+}
+
+// RunnerFactory builds the adk.Runner that serves a single ACP session. It is called once
+// per session/new (and per session/load of a session that is not live in this process).
+type RunnerFactory func(ctx context.Context, req *RunnerRequest) (*adk.Runner, error)
+
+// RunnerRequest carries the per-session context handed to a RunnerFactory.
+type RunnerRequest struct {
+	// SessionID is the ID the server assigned to the session.
+	SessionID acpproto.SessionID
+	// Cwd is the working directory the client asked the session to operate in.
+	Cwd string
+	// ClientCapabilities is the capability set negotiated during initialize. It may be nil
+	// when the client skipped initialize or advertised nothing.
+	ClientCapabilities *acpproto.ClientCapabilities
+	// Handlers holds the client tools middleware built from the negotiated capabilities
+	// (see NewClientToolsMiddleware). It is empty when the client advertised neither fs nor
+	// terminal support, or when ServerConfig.DisableClientTools is set. Factories building a
+	// ChatModelAgent should append these to ChatModelAgentConfig.Handlers.
+	Handlers []adk.ChatModelAgentMiddleware
+	// CheckPointStore is the store the server resumes interrupted runs from. Factories should
+	// set it as adk.RunnerConfig.CheckPointStore; without it, tool-approval interrupts cannot
+	// be resumed after a permission request.
+	CheckPointStore adk.CheckPointStore
+}
+
+// ServerConfig configures NewServer.
+type ServerConfig struct {
+	// NewRunner creates the runner for each session. Required.
+	NewRunner RunnerFactory
+
+	// AgentInfo is reported to the client in the initialize response. Optional.
+	AgentInfo *acpproto.Implementation
+
+	// DisableClientTools prevents the server from building the client tools middleware,
+	// even when the client advertises fs or terminal capabilities.
+	DisableClientTools bool
+	// UseTerminalForFileTools is forwarded to Config.UseTerminalForFileTools when the
+	// client tools middleware is built.
+	UseTerminalForFileTools bool
+
+	// EventConverterOption is passed to AgentEventToSessionUpdate for every event the
+	// runner emits. Optional.
+	EventConverterOption *EventConverterOption
+
+	// Permission, when set, turns tool-approval interrupts into session/request_permission
+	// calls: the server asks the client, then resumes the runner with the resume data built
+	// from the selected option. Interrupts that are bridged this way are not rendered through
+	// the InterruptConverter. When nil, interrupts simply end the turn.
+	Permission *PermissionConfig
+	// CheckPointStore is where runner checkpoints are kept; it is handed to the runner
+	// factory through RunnerRequest.CheckPointStore. Checkpoints are always recorded in the
+	// SessionStore as well, so that session/load can rehydrate them. Defaults to reading
+	// checkpoints back from the SessionStore.
+	CheckPointStore adk.CheckPointStore
+
+	// SessionStore records the session/update stream, the conversation history and the
+	// latest checkpoint of every session, so that session/load can restore a session that
+	// is no longer live, e.g. after a restart. Defaults to a MemorySessionStore.
+	SessionStore SessionStore
+
+	// Logger is an optional structured logger; defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+func (c *ServerConfig) validate() error {
+	if c == nil {
+		return errors.New("acp.NewServer: cfg is required")
+	}
+	if c.NewRunner == nil {
+		return errors.New("acp.NewServer: cfg.NewRunner is required")
+	}
+	return nil
+}
+
+// Server is a ready-made ACP agent that serves adk runners. It handles initialize,
+// session/new, session/load, session/prompt and session/cancel: every session gets its own
+// runner from ServerConfig.NewRunner, prompts are serialized per session, the conversation
+// history is fed back into the runner on each turn, and every emitted AgentEvent is
+// streamed to the client through AgentEventToSessionUpdate.
+//
+// Server implements acpproto.Agent and the ACP server's ConnectionAwareAgent, so it can be
+// mounted on any ACP transport; Serve is a shortcut for a single stream-based connection
+// such as stdio.
+type Server struct {
+	acpproto.BaseAgent
+
+	cfg     *ServerConfig
+	logger  *slog.Logger
+	convOpt *EventConverterOption
+
+	sessionStore SessionStore
+	checkPoints  adk.CheckPointStore
+
+	mu         sync.Mutex
+	conn       ServerConn
+	clientCaps *acpproto.ClientCapabilities
+	sessions   map[acpproto.SessionID]*serverSession
+}
+
+// serverSession is the runtime state of one ACP session.
+type serverSession struct {
+	id     acpproto.SessionID
+	cwd    string
+	runner *adk.Runner
+
+	// turnMu serializes prompts within the session so that history appends never interleave.
+	turnMu sync.Mutex
+	// history is only read and written while turnMu is held.
+	history []adk.Message
+
+	// cancelMu guards cancel, which is set for the duration of an in-flight prompt.
+	cancelMu sync.Mutex
+	cancel   context.CancelCauseFunc
+}
+
+// NewServer creates a Server from cfg.
+func NewServer(cfg *ServerConfig) (*Server, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	sessionStore := cfg.SessionStore
+	if sessionStore == nil {
+		sessionStore = NewMemorySessionStore()
+	}
+	convOpt := cfg.EventConverterOption
+	if cfg.Permission != nil {
+		var next InterruptConverter
+		if convOpt != nil {
+			next = convOpt.InterruptConverter
+			cp := *convOpt
+			convOpt = &cp
+		} else {
+			convOpt = &EventConverterOption{}
+		}
+		convOpt.InterruptConverter = NewPermissionInterruptConverter(cfg.Permission, next)
+	}
+	return &Server{
+		cfg:      cfg,
+		logger:   logger,
+		convOpt:  convOpt,
+		sessions: make(map[acpproto.SessionID]*serverSession),
+
+		sessionStore: sessionStore,
+		checkPoints:  &sessionCheckPointStore{sessions: sessionStore, next: cfg.CheckPointStore},
+	}, nil
+}
+
+// SetClientConnection attaches the agent-side connection. It is called by the ACP server
+// framework for every connection it creates.
+func (s *Server) SetClientConnection(conn *acpconn.AgentConnection) {
+	s.setConn(conn)
+}
+
+func (s *Server) setConn(conn ServerConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conn = conn
+}
+
+// Serve runs the server over a single stream-based connection (e.g. stdin/stdout, or an
+// io.Pipe pair in tests) and blocks until the peer closes the connection.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	conn := acpconn.NewAgentConnectionFromTransport(s, stdio.NewTransport(r, w))
+	s.setConn(conn)
+	if err := conn.Start(ctx); err != nil {
+		return fmt.Errorf("acp.server: start connection: %w", err)
+	}
+	select {
+	case <-conn.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Server) Initialize(_ context.Context, req acpproto.InitializeRequest) (acpproto.InitializeResponse, error) {
+	s.mu.Lock()
+	s.clientCaps = req.ClientCapabilities
+	s.mu.Unlock()
+
+	return acpproto.InitializeResponse{
+		ProtocolVersion: acpproto.ProtocolVersion(acpproto.CurrentProtocolVersion),
+		AgentCapabilities: &acpproto.AgentCapabilities{
+			LoadSession: true,
+		},
+		AgentInfo: s.cfg.AgentInfo,
+	}, nil
+}
+
+func (s *Server) NewSession(ctx context.Context, req acpproto.NewSessionRequest) (acpproto.NewSessionResponse, error) {
+	sessionID := acpproto.SessionID(uuid.NewString())
+	// The record is created first: a runner cannot be released, so it is only built once
+	// the session can be kept.
+	if err := s.sessionStore.Create(ctx, sessionID, req.Cwd); err != nil {
+		return acpproto.NewSessionResponse{}, fmt.Errorf("acp.server: record session=%s: %w", sessionID, err)
+	}
+	sess, err := s.newSession(ctx, sessionID, req.Cwd)
+	if err != nil {
+		if dErr := s.sessionStore.Delete(ctx, sessionID); dErr != nil {
+			s.logger.WarnContext(ctx, "acp.server: failed to delete session record", "session", sessionID, "err", dErr)
+		}
+		return acpproto.NewSessionResponse{}, err
+	}
+
+	s.mu.Lock()
+	s.sessions[sessionID] = sess
+	s.mu.Unlock()
+
+	return acpproto.NewSessionResponse{SessionID: sessionID}, nil
+}
+
+// newSession builds the runner for a session, wiring the client tools middleware when the
+// negotiated capabilities allow it.
+func (s *Server) newSession(ctx context.Context, sessionID acpproto.SessionID, cwd string) (*serverSession, error) {
+	s.mu.Lock()
+	conn, caps := s.conn, s.clientCaps
+	s.mu.Unlock()
+	if conn == nil {
+		return nil, ErrNoConnection
+	}
+
+	var handlers []adk.ChatModelAgentMiddleware
+	if !s.cfg.DisableClientTools && hasClientTools(caps) {
+		m, err := NewClientToolsMiddleware(ctx, &Config{
+			SessionID:               sessionID,
+			Conn:                    conn,
+			Capabilities:            caps,
+			UseTerminalForFileTools: s.cfg.UseTerminalForFileTools,
+			Logger:                  s.logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("acp.server: create client tools middleware session=%s: %w", sessionID, err)
+		}
+		handlers = append(handlers, m)
+	}
+
+	runner, err := s.cfg.NewRunner(ctx, &RunnerRequest{
+		SessionID:          sessionID,
+		Cwd:                cwd,
+		ClientCapabilities: caps,
+		Handlers:           handlers,
+		CheckPointStore:    s.checkPoints,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("acp.server: create runner session=%s: %w", sessionID, err)
+	}
+	if runner == nil {
+		return nil, fmt.Errorf("acp.server: create runner session=%s: factory returned nil runner", sessionID)
+	}
+
+	return &serverSession{id: sessionID, cwd: cwd, runner: runner}, nil
+}
+
+// hasClientTools reports whether caps advertise anything the client tools middleware can use.
+func hasClientTools(caps *acpproto.ClientCapabilities) bool {
+	if caps == nil {
+		return false
+	}
+	if caps.Terminal {
+		return true
+	}
+	return caps.FS != nil && (caps.FS.ReadTextFile || caps.FS.WriteTextFile)
+}
+
+// LoadSession restores a session from the SessionStore and replays its recorded
+// session/update stream to the client. A session that is not live in this server (e.g.
+// after a restart) gets a new runner from the factory, its conversation history back, and
+// its last checkpoint rehydrated, so the conversation continues where it stopped.
+func (s *Server) LoadSession(ctx context.Context, req acpproto.LoadSessionRequest) (acpproto.LoadSessionResponse, error) {
+	rec, err := s.sessionStore.Load(ctx, req.SessionID)
+	if err != nil {
+		return acpproto.LoadSessionResponse{}, fmt.Errorf("acp.server: load session=%s: %w", req.SessionID, err)
+	}
+
+	sess, err := s.getSession(req.SessionID)
+	if errors.Is(err, ErrSessionNotFound) {
+		sess, err = s.restoreSession(ctx, rec, req.Cwd)
+	}
+	if err != nil {
+		return acpproto.LoadSessionResponse{}, err
+	}
+
+	sess.turnMu.Lock()
+	defer sess.turnMu.Unlock()
+
+	conn, err := s.getConn()
+	if err != nil {
+		return acpproto.LoadSessionResponse{}, err
+	}
+	// The default checkpoint store reads straight from the SessionStore; only a
+	// user-supplied one needs the checkpoint copied back.
+	if err = ReplaySession(ctx, conn, rec, s.cfg.CheckPointStore); err != nil {
+		return acpproto.LoadSessionResponse{}, err
+	}
+	return acpproto.LoadSessionResponse{}, nil
+}
+
+// restoreSession makes a stored session live again. If another request restored it
+// concurrently, that session wins.
+func (s *Server) restoreSession(ctx context.Context, rec *SessionRecord, cwd string) (*serverSession, error) {
+	if cwd == "" {
+		cwd = rec.Cwd
+	}
+	sess, err := s.newSession(ctx, rec.SessionID, cwd)
+	if err != nil {
+		return nil, err
+	}
+	sess.history = rec.Messages
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.sessions[rec.SessionID]; ok {
+		return existing, nil
+	}
+	s.sessions[rec.SessionID] = sess
+	return sess, nil
+}
+
+// Prompt runs one turn of the session's runner. Prompts on the same session are serialized.
+// A session/cancel for the session ends the turn with StopReasonCancelled. When
+// ServerConfig.Permission is set, tool-approval interrupts are turned into permission
+// requests and the runner is resumed within the same turn.
+func (s *Server) Prompt(ctx context.Context, req acpproto.PromptRequest) (acpproto.PromptResponse, error) {
+	sess, err := s.getSession(req.SessionID)
+	if err != nil {
+		return acpproto.PromptResponse{}, err
+	}
+
+	userMsg, err := promptToMessage(req.Prompt)
+	if err != nil {
+		return acpproto.PromptResponse{}, fmt.Errorf("acp.server: convert prompt session=%s: %w", sess.id, err)
+	}
+
+	sess.turnMu.Lock()
+	defer sess.turnMu.Unlock()
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	sess.setCancel(cancel)
+	defer func() {
+		sess.setCancel(nil)
+		cancel(nil)
+	}()
+
+	input := make([]adk.Message, 0, len(sess.history)+1)
+	input = append(input, sess.history...)
+	input = append(input, userMsg)
+
+	userUpdates := make([]acpproto.SessionUpdate, 0, len(req.Prompt))
+	for _, block := range req.Prompt {
+		userUpdates = append(userUpdates, acpproto.NewSessionUpdateUserMessageChunk(acpproto.ContentChunk{Content: block}))
+	}
+	s.recordUpdates(ctx, sess.id, userUpdates)
+
+	produced, err := s.runTurn(ctx, sess, input)
+	if errors.Is(context.Cause(ctx), errPromptCancelled) || errors.Is(err, ErrPermissionCancelled) {
+		// Keep whatever completed before the cancellation so the next turn has context.
+		s.appendHistory(ctx, sess, userMsg, produced)
+		return acpproto.PromptResponse{StopReason: acpproto.StopReasonCancelled}, nil
+	}
+	if err != nil {
+		return acpproto.PromptResponse{}, err
+	}
+
+	s.appendHistory(ctx, sess, userMsg, produced)
+	return acpproto.PromptResponse{StopReason: acpproto.StopReasonEndTurn}, nil
+}
+
+// appendHistory records a finished turn both in memory and in the SessionStore. Must be
+// called with sess.turnMu held.
+func (s *Server) appendHistory(ctx context.Context, sess *serverSession, userMsg adk.Message, produced []adk.Message) {
+	turn := make([]adk.Message, 0, len(produced)+1)
+	turn = append(turn, userMsg)
+	turn = append(turn, produced...)
+	sess.history = append(sess.history, turn...)
+
+	// The turn already happened; a store failure only affects future session/loads.
+	if err := s.sessionStore.AppendMessages(context.WithoutCancel(ctx), sess.id, turn); err != nil {
+		s.logger.WarnContext(ctx, "acp.server: failed to record messages", "session", sess.id, "err", err)
+	}
+}
+
+func (s *Server) recordUpdates(ctx context.Context, sessionID acpproto.SessionID, updates []acpproto.SessionUpdate) {
+	if len(updates) == 0 {
+		return
+	}
+	if err := s.sessionStore.AppendUpdates(context.WithoutCancel(ctx), sessionID, updates); err != nil {
+		s.logger.WarnContext(ctx, "acp.server: failed to record updates", "session", sessionID, "err", err)
+	}
+}
+
+// Cancel aborts the in-flight prompt of the session, if any. Cancelling an idle or unknown
+// session is a no-op, as session/cancel is a notification.
+func (s *Server) Cancel(_ context.Context, req acpproto.CancelNotification) error {
+	s.mu.Lock()
+	sess, ok := s.sessions[req.SessionID]
+	s.mu.Unlock()
+	if !ok {
+		s.logger.Debug("acp.server: cancel for unknown session", "session", req.SessionID)
+		return nil
+	}
+	sess.cancelMu.Lock()
+	defer sess.cancelMu.Unlock()
+	if sess.cancel != nil {
+		sess.cancel(errPromptCancelled)
+	}
+	return nil
+}
+
+// runTurn runs the session's runner on input and streams the result to the client. Every
+// tool-approval interrupt is bridged to the client and the run resumed from its checkpoint,
+// until the run completes or ends on an interrupt that is not bridged.
+func (s *Server) runTurn(ctx context.Context, sess *serverSession, input []adk.Message) ([]adk.Message, error) {
+	// One checkpoint per session: each turn overwrites the previous one, so the store does
+	// not grow with the length of the conversation.
+	checkPointID := string(sess.id)
+	iter := sess.runner.Run(ctx, input, adk.WithCheckPointID(checkPointID))
+
+	var produced []adk.Message
+	for {
+		msgs, interrupted, err := s.streamTurn(ctx, sess.id, iter)
+		produced = append(produced, msgs...)
+		if err != nil || interrupted == nil || s.cfg.Permission == nil {
+			return produced, err
+		}
+
+		conn, err := s.getConn()
+		if err != nil {
+			return produced, err
+		}
+		targets, err := RequestPermissions(ctx, conn, sess.id, interrupted, s.cfg.Permission)
+		if err != nil {
+			return produced, err
+		}
+		if len(targets) == 0 {
+			return produced, nil
+		}
+
+		iter, err = sess.runner.ResumeWithParams(ctx, checkPointID, &adk.ResumeParams{Targets: targets})
+		if err != nil {
+			return produced, fmt.Errorf("acp.server: resume session=%s: %w", sess.id, err)
+		}
+	}
+}
+
+func (s *Server) getConn() (ServerConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil, ErrNoConnection
+	}
+	return s.conn, nil
+}
+
+func (s *Server) getSession(id acpproto.SessionID) (*serverSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	return sess, nil
+}
+
+func (ss *serverSession) setCancel(cancel context.CancelCauseFunc) {
+	ss.cancelMu.Lock()
+	defer ss.cancelMu.Unlock()
+	ss.cancel = cancel
+}
+
+// streamTurn forwards every event of iter to the client and returns the messages produced,
+// in order, for history recording, together with the interrupt the run ended on, if any.
+func (s *Server) streamTurn(ctx context.Context, sessionID acpproto.SessionID,
+	iter *adk.AsyncIterator[*adk.AgentEvent]) ([]adk.Message, *adk.InterruptInfo, error) {
+
+	var (
+		produced    []adk.Message
+		interrupted *adk.InterruptInfo
+	)
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			return produced, interrupted, nil
+		}
+		if event.Err != nil {
+			return produced, nil, event.Err
+		}
+		if event.Action != nil && event.Action.Interrupted != nil {
+			interrupted = event.Action.Interrupted
+		}
+
+		// AgentEventToSessionUpdate consumes (and closes) streaming outputs. Tee the stream
+		// so the same chunks can be concatenated into a full message for history.
+		var historyCopy adk.MessageStream
+		if mo := event.Output; mo != nil && mo.MessageOutput != nil && mo.MessageOutput.IsStreaming {
+			copies := mo.MessageOutput.MessageStream.Copy(2)
+			mo.MessageOutput.MessageStream = copies[0]
+			historyCopy = copies[1]
+		}
+
+		if err := s.sendEvent(ctx, sessionID, event); err != nil {
+			if historyCopy != nil {
+				historyCopy.Close()
+			}
+			return produced, nil, err
+		}
+
+		msg, err := capturedMessage(event, historyCopy)
+		if err != nil {
+			s.logger.WarnContext(ctx, "acp.server: failed to capture message for history",
+				"session", sessionID, "err", err)
+			continue
+		}
+		if msg != nil {
+			produced = append(produced, msg)
+		}
+	}
+}
+
+// sendEvent converts event into SessionUpdates and sends them to the client.
+func (s *Server) sendEvent(ctx context.Context, sessionID acpproto.SessionID, event *adk.AgentEvent) error {
+	conn, err := s.getConn()
+	if err != nil {
+		return err
+	}
+
+	var sent []acpproto.SessionUpdate
+	defer func() { s.recordUpdates(ctx, sessionID, sent) }()
+
+	for su, err := range AgentEventToSessionUpdate(event, s.convOpt) {
+		if err != nil {
+			return err
+		}
+		if err = conn.SessionUpdate(ctx, acpproto.SessionNotification{
+			SessionID: sessionID,
+			Update:    su,
+		}); err != nil {
+			return fmt.Errorf("acp.sessionUpdate session=%s: %w", sessionID, err)
+		}
+		sent = append(sent, su)
+	}
+	return nil
+}
+
+// capturedMessage returns the message carried by event. Non-streaming outputs are returned
+// as-is; streaming outputs are rebuilt by draining historyCopy, the second branch of the
+// original stream. It returns nil when the event carries no message.
+func capturedMessage(event *adk.AgentEvent, historyCopy adk.MessageStream) (adk.Message, error) {
+	if event.Output == nil || event.Output.MessageOutput == nil {
+		return nil, nil
+	}
+	mo := event.Output.MessageOutput
+	if !mo.IsStreaming {
+		return mo.Message, nil
+	}
+	if historyCopy == nil {
+		return nil, nil
+	}
+	// ConcatMessageStream drains and closes the stream.
+	return schema.ConcatMessageStream(historyCopy)
+}
@@ -0,0 +1,474 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoacp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+	acpproto "github.com/eino-contrib/acp"
+	acpconn "github.com/eino-contrib/acp/conn"
+	"github.com/eino-contrib/acp/transport/stdio"
+)
+
+// scriptedAgent is an adk.Agent whose behavior is supplied by the test.
+type scriptedAgent struct {
+	run func(ctx context.Context, input *adk.AgentInput, gen *adk.AsyncGenerator[*adk.AgentEvent])
+}
+
+func (a *scriptedAgent) Name(_ context.Context) string        { return "scripted" }
+func (a *scriptedAgent) Description(_ context.Context) string { return "scripted test agent" }
+
+func (a *scriptedAgent) Run(ctx context.Context, input *adk.AgentInput, _ ...adk.AgentRunOption) *adk.AsyncIterator[*adk.AgentEvent] {
+	iter, gen := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	go func() {
+		defer gen.Close()
+		a.run(ctx, input, gen)
+	}()
+	return iter
+}
+
+// echoAgent replies with "echo: <last user message>" and reports how many input messages it saw.
+func echoAgent(seen *[]int, mu *sync.Mutex) *scriptedAgent {
+	return &scriptedAgent{run: func(_ context.Context, input *adk.AgentInput, gen *adk.AsyncGenerator[*adk.AgentEvent]) {
+		mu.Lock()
+		*seen = append(*seen, len(input.Messages))
+		mu.Unlock()
+		last := input.Messages[len(input.Messages)-1]
+		gen.Send(adk.EventFromMessage(schema.AssistantMessage("echo: "+last.Content, nil), nil, schema.Assistant, ""))
+	}}
+}
+
+// recordingClient is an ACP client that records every session/update it receives.
+type recordingClient struct {
+	acpproto.BaseClient
+
+	mu      sync.Mutex
+	updates []acpproto.SessionNotification
+
+	// permission, if set, answers session/request_permission.
+	permission func(req acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error)
+}
+
+func (c *recordingClient) RequestPermission(_ context.Context, req acpproto.RequestPermissionRequest) (acpproto.RequestPermissionResponse, error) {
+	if c.permission == nil {
+		return acpproto.RequestPermissionResponse{}, errors.New("unexpected permission request")
+	}
+	return c.permission(req)
+}
+
+func (c *recordingClient) SessionUpdate(_ context.Context, n acpproto.SessionNotification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updates = append(c.updates, n)
+	return nil
+}
+
+func (c *recordingClient) agentTexts() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var texts []string
+	for _, n := range c.updates {
+		chunk, ok := n.Update.AsAgentMessageChunk()
+		if !ok {
+			continue
+		}
+		if tc, ok := chunk.Content.AsText(); ok {
+			texts = append(texts, tc.Text)
+		}
+	}
+	return texts
+}
+
+func (c *recordingClient) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updates = nil
+}
+
+// connectPipe serves srv over an in-memory pipe pair and returns the client side of the
+// connection. Both ends are torn down when the test finishes.
+func connectPipe(t *testing.T, srv *Server, client acpproto.Client) *acpconn.ClientConnection {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	clientToAgentR, clientToAgentW := io.Pipe()
+	agentToClientR, agentToClientW := io.Pipe()
+
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		_ = srv.Serve(ctx, clientToAgentR, agentToClientW)
+	}()
+
+	conn := acpconn.NewClientConnectionFromTransport(client, stdio.NewTransport(agentToClientR, clientToAgentW))
+	if err := conn.Start(ctx); err != nil {
+		t.Fatalf("start client connection: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cancel()
+		_ = clientToAgentW.Close()
+		_ = agentToClientW.Close()
+		<-served
+	})
+	return conn
+}
+
+func textPrompt(sessionID acpproto.SessionID, text string) acpproto.PromptRequest {
+	return acpproto.PromptRequest{
+		SessionID: sessionID,
+		Prompt:    []acpproto.ContentBlock{acpproto.NewContentBlockText(acpproto.TextContent{Text: text})},
+	}
+}
+
+func TestNewServer_Validate(t *testing.T) {
+	if _, err := NewServer(nil); err == nil || !strings.Contains(err.Error(), "cfg is required") {
+		t.Fatalf("expected cfg is required error, got %v", err)
+	}
+	if _, err := NewServer(&ServerConfig{}); err == nil || !strings.Contains(err.Error(), "cfg.NewRunner is required") {
+		t.Fatalf("expected NewRunner is required error, got %v", err)
+	}
+}
+
+func TestServer_PromptStreamsAndKeepsHistory(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var seen []int
+
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(ctx context.Context, req *RunnerRequest) (*adk.Runner, error) {
+			return adk.NewRunner(ctx, adk.RunnerConfig{Agent: echoAgent(&seen, &mu)}), nil
+		},
+		AgentInfo: &acpproto.Implementation{Name: "test-agent", Version: "0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	client := &recordingClient{}
+	conn := connectPipe(t, srv, client)
+
+	initResp, err := conn.Initialize(ctx, acpproto.InitializeRequest{
+		ProtocolVersion: acpproto.ProtocolVersion(acpproto.CurrentProtocolVersion),
+	})
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if initResp.AgentInfo == nil || initResp.AgentInfo.Name != "test-agent" {
+		t.Fatalf("unexpected agent info: %+v", initResp.AgentInfo)
+	}
+	if initResp.AgentCapabilities == nil || !initResp.AgentCapabilities.LoadSession {
+		t.Fatalf("expected loadSession capability to be advertised")
+	}
+
+	sessResp, err := conn.NewSession(ctx, acpproto.NewSessionRequest{Cwd: "/tmp"})
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if sessResp.SessionID == "" {
+		t.Fatal("expected non-empty session id")
+	}
+
+	resp, err := conn.Prompt(ctx, textPrompt(sessResp.SessionID, "hello"))
+	if err != nil {
+		t.Fatalf("Prompt: %v", err)
+	}
+	if resp.StopReason != acpproto.StopReasonEndTurn {
+		t.Fatalf("StopReason = %v, want end_turn", resp.StopReason)
+	}
+	if got := client.agentTexts(); len(got) != 1 || got[0] != "echo: hello" {
+		t.Fatalf("agent texts = %v, want [echo: hello]", got)
+	}
+
+	if _, err = conn.Prompt(ctx, textPrompt(sessResp.SessionID, "again")); err != nil {
+		t.Fatalf("second Prompt: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// First turn: [user]; second turn: [user, assistant, user].
+	if len(seen) != 2 || seen[0] != 1 || seen[1] != 3 {
+		t.Fatalf("input sizes = %v, want [1 3]", seen)
+	}
+}
+
+func TestServer_WiresClientToolsFromCapabilities(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name         string
+		caps         *acpproto.ClientCapabilities
+		disable      bool
+		wantHandlers int
+	}{
+		{name: "no capabilities", caps: nil, wantHandlers: 0},
+		{name: "empty capabilities", caps: &acpproto.ClientCapabilities{}, wantHandlers: 0},
+		{
+			name:         "fs capabilities",
+			caps:         &acpproto.ClientCapabilities{FS: &acpproto.FileSystemCapabilities{ReadTextFile: true, WriteTextFile: true}},
+			wantHandlers: 1,
+		},
+		{name: "terminal capability", caps: &acpproto.ClientCapabilities{Terminal: true}, wantHandlers: 1},
+		{name: "disabled", caps: &acpproto.ClientCapabilities{Terminal: true}, disable: true, wantHandlers: 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *RunnerRequest
+			srv, err := NewServer(&ServerConfig{
+				NewRunner: func(ctx context.Context, req *RunnerRequest) (*adk.Runner, error) {
+					got = req
+					return adk.NewRunner(ctx, adk.RunnerConfig{Agent: &scriptedAgent{run: func(context.Context, *adk.AgentInput, *adk.AsyncGenerator[*adk.AgentEvent]) {}}}), nil
+				},
+				DisableClientTools: tc.disable,
+			})
+			if err != nil {
+				t.Fatalf("NewServer: %v", err)
+			}
+			conn := connectPipe(t, srv, &recordingClient{})
+			if _, err = conn.Initialize(ctx, acpproto.InitializeRequest{ClientCapabilities: tc.caps}); err != nil {
+				t.Fatalf("Initialize: %v", err)
+			}
+			sessResp, err := conn.NewSession(ctx, acpproto.NewSessionRequest{Cwd: "/work"})
+			if err != nil {
+				t.Fatalf("NewSession: %v", err)
+			}
+			if got == nil {
+				t.Fatal("runner factory was not called")
+			}
+			if got.SessionID != sessResp.SessionID || got.Cwd != "/work" {
+				t.Fatalf("unexpected runner request: %+v", got)
+			}
+			if len(got.Handlers) != tc.wantHandlers {
+				t.Fatalf("len(Handlers) = %d, want %d", len(got.Handlers), tc.wantHandlers)
+			}
+		})
+	}
+}
+
+func TestServer_RunnerFactoryError(t *testing.T) {
+	ctx := context.Background()
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(context.Context, *RunnerRequest) (*adk.Runner, error) {
+			return nil, errors.New("boom")
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	conn := connectPipe(t, srv, &recordingClient{})
+	if _, err = conn.NewSession(ctx, acpproto.NewSessionRequest{}); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected factory error, got %v", err)
+	}
+	store := srv.sessionStore.(*MemorySessionStore)
+	if n := len(store.sessions); n != 0 {
+		t.Fatalf("the failed session is still recorded: %d records", n)
+	}
+}
+
+// failingCreateStore is a SessionStore whose Create always fails.
+type failingCreateStore struct {
+	*MemorySessionStore
+}
+
+func (failingCreateStore) Create(context.Context, acpproto.SessionID, string) error {
+	return errors.New("disk full")
+}
+
+func TestServer_SessionStoreCreateError(t *testing.T) {
+	ctx := context.Background()
+	var runners int
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(context.Context, *RunnerRequest) (*adk.Runner, error) {
+			runners++
+			return nil, errors.New("unexpected runner")
+		},
+		SessionStore: failingCreateStore{NewMemorySessionStore()},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	conn := connectPipe(t, srv, &recordingClient{})
+	if _, err = conn.NewSession(ctx, acpproto.NewSessionRequest{}); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected store error, got %v", err)
+	}
+	if runners != 0 {
+		t.Fatalf("runner built for a session that could not be recorded")
+	}
+}
+
+func TestServer_Cancel(t *testing.T) {
+	ctx := context.Background()
+	started := make(chan struct{})
+
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(ctx context.Context, _ *RunnerRequest) (*adk.Runner, error) {
+			return adk.NewRunner(ctx, adk.RunnerConfig{Agent: &scriptedAgent{
+				run: func(ctx context.Context, _ *adk.AgentInput, gen *adk.AsyncGenerator[*adk.AgentEvent]) {
+					gen.Send(adk.EventFromMessage(schema.AssistantMessage("working", nil), nil, schema.Assistant, ""))
+					close(started)
+					<-ctx.Done()
+					gen.Send(&adk.AgentEvent{Err: ctx.Err()})
+				},
+			}}), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	client := &recordingClient{}
+	conn := connectPipe(t, srv, client)
+	sessResp, err := conn.NewSession(ctx, acpproto.NewSessionRequest{})
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+
+	type result struct {
+		resp acpproto.PromptResponse
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := conn.Prompt(ctx, textPrompt(sessResp.SessionID, "long task"))
+		done <- result{resp, err}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent did not start")
+	}
+	if err = conn.Cancel(ctx, acpproto.CancelNotification{SessionID: sessResp.SessionID}); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatalf("Prompt: %v", r.err)
+		}
+		if r.resp.StopReason != acpproto.StopReasonCancelled {
+			t.Fatalf("StopReason = %v, want cancelled", r.resp.StopReason)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("prompt did not return after cancel")
+	}
+}
+
+func TestServer_LoadSessionReplaysHistory(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var seen []int
+
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(ctx context.Context, _ *RunnerRequest) (*adk.Runner, error) {
+			return adk.NewRunner(ctx, adk.RunnerConfig{Agent: echoAgent(&seen, &mu)}), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	client := &recordingClient{}
+	conn := connectPipe(t, srv, client)
+
+	sessResp, err := conn.NewSession(ctx, acpproto.NewSessionRequest{})
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if _, err = conn.Prompt(ctx, textPrompt(sessResp.SessionID, "hi")); err != nil {
+		t.Fatalf("Prompt: %v", err)
+	}
+
+	client.reset()
+	if _, err = conn.LoadSession(ctx, acpproto.LoadSessionRequest{SessionID: sessResp.SessionID}); err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+
+	client.mu.Lock()
+	updates := client.updates
+	client.mu.Unlock()
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 replayed updates, got %d", len(updates))
+	}
+	if chunk, ok := updates[0].Update.AsUserMessageChunk(); !ok {
+		t.Fatal("expected first replayed update to be a user message chunk")
+	} else if tc, _ := chunk.Content.AsText(); tc.Text != "hi" {
+		t.Fatalf("replayed user text = %q, want hi", tc.Text)
+	}
+	requireTextChunk(t, updates[1].Update, "echo: hi")
+
+	if _, err = conn.LoadSession(ctx, acpproto.LoadSessionRequest{SessionID: "missing"}); err == nil {
+		t.Fatal("expected error loading unknown session")
+	}
+}
+
+func TestServer_PromptUnknownSession(t *testing.T) {
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(context.Context, *RunnerRequest) (*adk.Runner, error) { return nil, nil },
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	_, err = srv.Prompt(context.Background(), textPrompt("missing", "hi"))
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestPromptToMessage(t *testing.T) {
+	t.Run("text only", func(t *testing.T) {
+		msg, err := promptToMessage([]acpproto.ContentBlock{
+			acpproto.NewContentBlockText(acpproto.TextContent{Text: "a"}),
+			acpproto.NewContentBlockText(acpproto.TextContent{Text: "b"}),
+		})
+		if err != nil {
+			t.Fatalf("promptToMessage: %v", err)
+		}
+		if msg.Role != schema.User || msg.Content != "a\nb" {
+			t.Fatalf("unexpected message: %+v", msg)
+		}
+	})
+
+	t.Run("mixed content", func(t *testing.T) {
+		msg, err := promptToMessage([]acpproto.ContentBlock{
+			acpproto.NewContentBlockText(acpproto.TextContent{Text: "look"}),
+			acpproto.NewContentBlockImage(acpproto.ImageContent{MimeType: "image/png", Data: "AAAA"}),
+			acpproto.NewContentBlockResourceLink(acpproto.ResourceLink{Name: "a.go", URI: "file:///a.go"}),
+		})
+		if err != nil {
+			t.Fatalf("promptToMessage: %v", err)
+		}
+		if len(msg.UserInputMultiContent) != 3 {
+			t.Fatalf("expected 3 parts, got %d", len(msg.UserInputMultiContent))
+		}
+		if p := msg.UserInputMultiContent[1]; p.Type != schema.ChatMessagePartTypeImageURL ||
+			p.Image == nil || p.Image.Base64Data == nil || *p.Image.Base64Data != "AAAA" {
+			t.Fatalf("unexpected image part: %+v", p)
+		}
+		if p := msg.UserInputMultiContent[2]; p.Type != schema.ChatMessagePartTypeFileURL ||
+			p.File == nil || p.File.Name != "a.go" || *p.File.URL != "file:///a.go" {
+			t.Fatalf("unexpected file part: %+v", p)
+		}
+	})
+}
@@ -0,0 +1,448 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoacp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+	acpproto "github.com/eino-contrib/acp"
+)
+
+// SessionRecord is everything a SessionStore keeps for one ACP session.
+type SessionRecord struct {
+	SessionID acpproto.SessionID
+	// Cwd is the working directory the session was created with.
+	Cwd string
+	// Updates is the session/update stream sent to the client, in order, including the
+	// user message chunks of every prompt. It is what session/load replays.
+	Updates []acpproto.SessionUpdate
+	// Messages is the adk conversation history fed back to the runner on every turn.
+	Messages []adk.Message
+	// CheckPoint is the latest runner checkpoint of the session, if any.
+	CheckPoint []byte
+}
+
+// SessionStore persists ACP sessions so that session/load can restore them, possibly in a
+// different process. Implementations must be safe for concurrent use.
+type SessionStore interface {
+	// Create starts an empty record for id, replacing any existing one.
+	Create(ctx context.Context, id acpproto.SessionID, cwd string) error
+	// AppendUpdates appends updates to the recorded session/update stream.
+	AppendUpdates(ctx context.Context, id acpproto.SessionID, updates []acpproto.SessionUpdate) error
+	// AppendMessages appends msgs to the recorded conversation history.
+	AppendMessages(ctx context.Context, id acpproto.SessionID, msgs []adk.Message) error
+	// SetCheckPoint replaces the recorded runner checkpoint.
+	SetCheckPoint(ctx context.Context, id acpproto.SessionID, checkPoint []byte) error
+	// GetCheckPoint returns the recorded runner checkpoint, or nil if there is none. It
+	// returns an error wrapping ErrSessionNotFound when the session was never created.
+	GetCheckPoint(ctx context.Context, id acpproto.SessionID) ([]byte, error)
+	// Load returns the record of id. It returns an error wrapping ErrSessionNotFound when the
+	// session was never created.
+	Load(ctx context.Context, id acpproto.SessionID) (*SessionRecord, error)
+	// Delete removes the record of id. Deleting an unknown session is not an error.
+	Delete(ctx context.Context, id acpproto.SessionID) error
+}
+
+// SessionNotifier sends session/update notifications. *acpconn.AgentConnection and every
+// ServerConn satisfy it.
+type SessionNotifier interface {
+	SessionUpdate(ctx context.Context, n acpproto.SessionNotification) error
+}
+
+// ReplaySession re-emits the recorded session/update stream of rec to the client, as
+// session/load requires. Records without updates (e.g. written by a store that only kept
+// messages) fall back to converting the recorded messages. When checkPoints is non-nil and
+// rec holds a checkpoint, the checkpoint is written back to it under the session ID first,
+// so a runner using that store can resume the conversation.
+func ReplaySession(ctx context.Context, conn SessionNotifier, rec *SessionRecord, checkPoints adk.CheckPointStore) error {
+	if checkPoints != nil && len(rec.CheckPoint) > 0 {
+		if err := checkPoints.Set(ctx, string(rec.SessionID), rec.CheckPoint); err != nil {
+			return fmt.Errorf("acp.replaySession session=%s: restore checkpoint: %w", rec.SessionID, err)
+		}
+	}
+
+	send := func(su acpproto.SessionUpdate) error {
+		if err := conn.SessionUpdate(ctx, acpproto.SessionNotification{SessionID: rec.SessionID, Update: su}); err != nil {
+			return fmt.Errorf("acp.replaySession session=%s: %w", rec.SessionID, err)
+		}
+		return nil
+	}
+
+	if len(rec.Updates) > 0 {
+		for _, su := range rec.Updates {
+			if err := send(su); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, msg := range rec.Messages {
+		for su, err := range AgentEventToSessionUpdate(adk.EventFromMessage(msg, nil, msg.Role, ""), nil) {
+			if err != nil {
+				return fmt.Errorf("acp.replaySession session=%s: %w", rec.SessionID, err)
+			}
+			if err = send(su); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sessionCheckPointStore is the adk.CheckPointStore handed to runners by Server. Every
+// checkpoint is recorded in the SessionStore, keyed by session ID (which Server uses as the
+// checkpoint ID); reads go to next when the user supplied a store, or to the SessionStore.
+type sessionCheckPointStore struct {
+	sessions SessionStore
+	next     adk.CheckPointStore
+}
+
+func (s *sessionCheckPointStore) Get(ctx context.Context, checkPointID string) ([]byte, bool, error) {
+	if s.next != nil {
+		return s.next.Get(ctx, checkPointID)
+	}
+	checkPoint, err := s.sessions.GetCheckPoint(ctx, acpproto.SessionID(checkPointID))
+	if errors.Is(err, ErrSessionNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return checkPoint, len(checkPoint) > 0, nil
+}
+
+func (s *sessionCheckPointStore) Set(ctx context.Context, checkPointID string, checkPoint []byte) error {
+	if s.next != nil {
+		if err := s.next.Set(ctx, checkPointID, checkPoint); err != nil {
+			return err
+		}
+	}
+	return s.sessions.SetCheckPoint(ctx, acpproto.SessionID(checkPointID), checkPoint)
+}
+
+// MemorySessionStore is a SessionStore that keeps sessions in process memory.
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[acpproto.SessionID]*SessionRecord
+}
+
+// NewMemorySessionStore creates an empty MemorySessionStore.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[acpproto.SessionID]*SessionRecord)}
+}
+
+func (m *MemorySessionStore) Create(_ context.Context, id acpproto.SessionID, cwd string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[id] = &SessionRecord{SessionID: id, Cwd: cwd}
+	return nil
+}
+
+func (m *MemorySessionStore) AppendUpdates(_ context.Context, id acpproto.SessionID, updates []acpproto.SessionUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.sessions[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	rec.Updates = append(rec.Updates, updates...)
+	return nil
+}
+
+func (m *MemorySessionStore) AppendMessages(_ context.Context, id acpproto.SessionID, msgs []adk.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.sessions[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	rec.Messages = append(rec.Messages, msgs...)
+	return nil
+}
+
+func (m *MemorySessionStore) SetCheckPoint(_ context.Context, id acpproto.SessionID, checkPoint []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.sessions[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	rec.CheckPoint = append([]byte(nil), checkPoint...)
+	return nil
+}
+
+func (m *MemorySessionStore) GetCheckPoint(_ context.Context, id acpproto.SessionID) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	return append([]byte(nil), rec.CheckPoint...), nil
+}
+
+func (m *MemorySessionStore) Load(_ context.Context, id acpproto.SessionID) (*SessionRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	// Copy the slices so callers can't observe later appends.
+	return &SessionRecord{
+		SessionID:  rec.SessionID,
+		Cwd:        rec.Cwd,
+		Updates:    append([]acpproto.SessionUpdate(nil), rec.Updates...),
+		Messages:   append([]adk.Message(nil), rec.Messages...),
+		CheckPoint: append([]byte(nil), rec.CheckPoint...),
+	}, nil
+}
+
+func (m *MemorySessionStore) Delete(_ context.Context, id acpproto.SessionID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+// FileSessionStore is a SessionStore that keeps every session in a directory. Each session
+// has an append-only JSON Lines log (<id>.jsonl) holding its metadata, updates and messages,
+// and a checkpoint file (<id>.ckpt) that is replaced atomically on every write.
+type FileSessionStore struct {
+	dir string
+
+	// mu serializes writers; appends to a log are small and rare enough that a single
+	// lock for the whole store keeps things simple.
+	mu sync.Mutex
+}
+
+// maxSessionRecordSize is the longest session log line FileSessionStore reads back. A line
+// holds a single update or message, so this bounds the size of one message, not of a session.
+const maxSessionRecordSize = 64 * 1024 * 1024 // 64 MiB
+
+// fileSessionEntry is one line of a session log. Exactly one of the fields is set.
+type fileSessionEntry struct {
+	Cwd     *string                 `json:"cwd,omitempty"`
+	Update  *acpproto.SessionUpdate `json:"update,omitempty"`
+	Message *schema.Message         `json:"message,omitempty"`
+}
+
+// NewFileSessionStore creates a FileSessionStore rooted at dir, creating the directory if
+// needed.
+func NewFileSessionStore(dir string) (*FileSessionStore, error) {
+	if dir == "" {
+		return nil, errors.New("acp.NewFileSessionStore: dir is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("acp.NewFileSessionStore: create %s: %w", dir, err)
+	}
+	return &FileSessionStore{dir: dir}, nil
+}
+
+// paths returns the log and checkpoint paths of id. Session IDs come from clients on
+// session/load, so anything that could escape the store directory is rejected.
+func (f *FileSessionStore) paths(id acpproto.SessionID) (logPath, ckptPath string, err error) {
+	s := string(id)
+	if s == "" || s == "." || s == ".." || strings.ContainsAny(s, `/\`) || strings.ContainsRune(s, 0) {
+		return "", "", fmt.Errorf("acp.fileSessionStore: invalid session id %q", s)
+	}
+	return filepath.Join(f.dir, s+".jsonl"), filepath.Join(f.dir, s+".ckpt"), nil
+}
+
+func (f *FileSessionStore) Create(_ context.Context, id acpproto.SessionID, cwd string) error {
+	logPath, ckptPath, err := f.paths(id)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(fileSessionEntry{Cwd: &cwd})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err = os.Remove(ckptPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("acp.fileSessionStore: reset checkpoint session=%s: %w", id, err)
+	}
+	if err = os.WriteFile(logPath, append(line, '\n'), 0o644); err != nil {
+		return fmt.Errorf("acp.fileSessionStore: create session=%s: %w", id, err)
+	}
+	return nil
+}
+
+func (f *FileSessionStore) AppendUpdates(_ context.Context, id acpproto.SessionID, updates []acpproto.SessionUpdate) error {
+	entries := make([]fileSessionEntry, len(updates))
+	for i := range updates {
+		entries[i] = fileSessionEntry{Update: &updates[i]}
+	}
+	return f.append(id, entries)
+}
+
+func (f *FileSessionStore) AppendMessages(_ context.Context, id acpproto.SessionID, msgs []adk.Message) error {
+	entries := make([]fileSessionEntry, len(msgs))
+	for i := range msgs {
+		entries[i] = fileSessionEntry{Message: msgs[i]}
+	}
+	return f.append(id, entries)
+}
+
+func (f *FileSessionStore) append(id acpproto.SessionID, entries []fileSessionEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	logPath, _, err := f.paths(id)
+	if err != nil {
+		return err
+	}
+	var buf []byte
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("acp.fileSessionStore: encode session=%s: %w", id, err)
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// Opening without O_CREATE makes appends to a session that was never created fail.
+	file, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("acp.fileSessionStore: open session=%s: %w", id, err)
+	}
+	if _, err = file.Write(buf); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("acp.fileSessionStore: append session=%s: %w", id, err)
+	}
+	return file.Close()
+}
+
+func (f *FileSessionStore) SetCheckPoint(_ context.Context, id acpproto.SessionID, checkPoint []byte) error {
+	logPath, ckptPath, err := f.paths(id)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err = os.Stat(logPath); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	tmp := ckptPath + ".tmp"
+	if err = os.WriteFile(tmp, checkPoint, 0o644); err != nil {
+		return fmt.Errorf("acp.fileSessionStore: write checkpoint session=%s: %w", id, err)
+	}
+	if err = os.Rename(tmp, ckptPath); err != nil {
+		return fmt.Errorf("acp.fileSessionStore: write checkpoint session=%s: %w", id, err)
+	}
+	return nil
+}
+
+// GetCheckPoint reads only the checkpoint file, not the session log.
+func (f *FileSessionStore) GetCheckPoint(_ context.Context, id acpproto.SessionID) ([]byte, error) {
+	logPath, ckptPath, err := f.paths(id)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err = os.Stat(logPath); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	checkPoint, err := os.ReadFile(ckptPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("acp.fileSessionStore: read checkpoint session=%s: %w", id, err)
+	}
+	return checkPoint, nil
+}
+
+func (f *FileSessionStore) Load(_ context.Context, id acpproto.SessionID) (*SessionRecord, error) {
+	logPath, ckptPath, err := f.paths(id)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.Open(logPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("acp.fileSessionStore: open session=%s: %w", id, err)
+	}
+	defer file.Close()
+
+	rec := &SessionRecord{SessionID: id}
+	sc := bufio.NewScanner(file)
+	sc.Buffer(make([]byte, 0, 64*1024), maxSessionRecordSize)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		var e fileSessionEntry
+		if err = json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("acp.fileSessionStore: decode session=%s line=%d: %w", id, lineNo, err)
+		}
+		switch {
+		case e.Cwd != nil:
+			rec.Cwd = *e.Cwd
+		case e.Update != nil:
+			rec.Updates = append(rec.Updates, *e.Update)
+		case e.Message != nil:
+			rec.Messages = append(rec.Messages, e.Message)
+		}
+	}
+	if err = sc.Err(); err != nil {
+		return nil, fmt.Errorf("acp.fileSessionStore: read session=%s: %w", id, err)
+	}
+
+	rec.CheckPoint, err = os.ReadFile(ckptPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("acp.fileSessionStore: read checkpoint session=%s: %w", id, err)
+	}
+	return rec, nil
+}
+
+func (f *FileSessionStore) Delete(_ context.Context, id acpproto.SessionID) error {
+	logPath, ckptPath, err := f.paths(id)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range []string{logPath, ckptPath} {
+		if err = os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("acp.fileSessionStore: delete session=%s: %w", id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoacp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+	acpproto "github.com/eino-contrib/acp"
+)
+
+func textUpdate(text string) acpproto.SessionUpdate {
+	return acpproto.NewSessionUpdateAgentMessageChunk(acpproto.ContentChunk{
+		Content: acpproto.NewContentBlockText(acpproto.TextContent{Text: text}),
+	})
+}
+
+func TestSessionStores_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	fileStore, err := NewFileSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSessionStore: %v", err)
+	}
+
+	stores := map[string]SessionStore{
+		"memory": NewMemorySessionStore(),
+		"file":   fileStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			if _, err := store.Load(ctx, "missing"); !errors.Is(err, ErrSessionNotFound) {
+				t.Fatalf("Load(missing) err = %v, want ErrSessionNotFound", err)
+			}
+			if _, err := store.GetCheckPoint(ctx, "missing"); !errors.Is(err, ErrSessionNotFound) {
+				t.Fatalf("GetCheckPoint(missing) err = %v, want ErrSessionNotFound", err)
+			}
+			if err := store.AppendUpdates(ctx, "missing", []acpproto.SessionUpdate{textUpdate("x")}); !errors.Is(err, ErrSessionNotFound) {
+				t.Fatalf("AppendUpdates(missing) err = %v, want ErrSessionNotFound", err)
+			}
+
+			if err := store.Create(ctx, "s1", "/work"); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if err := store.AppendUpdates(ctx, "s1", []acpproto.SessionUpdate{textUpdate("a"), textUpdate("b")}); err != nil {
+				t.Fatalf("AppendUpdates: %v", err)
+			}
+			if err := store.AppendMessages(ctx, "s1", []adk.Message{schema.UserMessage("hi"), schema.AssistantMessage("a", nil)}); err != nil {
+				t.Fatalf("AppendMessages: %v", err)
+			}
+			if ckpt, err := store.GetCheckPoint(ctx, "s1"); err != nil || ckpt != nil {
+				t.Fatalf("GetCheckPoint before SetCheckPoint = %q, %v, want none", ckpt, err)
+			}
+			if err := store.SetCheckPoint(ctx, "s1", []byte("ckpt-1")); err != nil {
+				t.Fatalf("SetCheckPoint: %v", err)
+			}
+			if err := store.SetCheckPoint(ctx, "s1", []byte("ckpt-2")); err != nil {
+				t.Fatalf("SetCheckPoint: %v", err)
+			}
+
+			rec, err := store.Load(ctx, "s1")
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if rec.SessionID != "s1" || rec.Cwd != "/work" {
+				t.Fatalf("record = %s %q, want s1 /work", rec.SessionID, rec.Cwd)
+			}
+			if len(rec.Updates) != 2 {
+				t.Fatalf("got %d updates, want 2", len(rec.Updates))
+			}
+			if chunk, ok := rec.Updates[1].AsAgentMessageChunk(); !ok {
+				t.Fatal("expected an agent message chunk")
+			} else if tc, _ := chunk.Content.AsText(); tc.Text != "b" {
+				t.Fatalf("second update text = %q, want b", tc.Text)
+			}
+			if len(rec.Messages) != 2 || rec.Messages[0].Content != "hi" || rec.Messages[1].Role != schema.Assistant {
+				t.Fatalf("unexpected messages %v", rec.Messages)
+			}
+			if string(rec.CheckPoint) != "ckpt-2" {
+				t.Fatalf("checkpoint = %q, want ckpt-2", rec.CheckPoint)
+			}
+			if ckpt, err := store.GetCheckPoint(ctx, "s1"); err != nil || string(ckpt) != "ckpt-2" {
+				t.Fatalf("GetCheckPoint = %q, %v, want ckpt-2", ckpt, err)
+			}
+
+			// Create replaces the previous record.
+			if err := store.Create(ctx, "s1", "/other"); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if rec, err = store.Load(ctx, "s1"); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if rec.Cwd != "/other" || len(rec.Updates) != 0 || len(rec.Messages) != 0 || len(rec.CheckPoint) != 0 {
+				t.Fatalf("expected an empty record after Create, got %+v", rec)
+			}
+
+			if err := store.Delete(ctx, "s1"); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, err := store.Load(ctx, "s1"); !errors.Is(err, ErrSessionNotFound) {
+				t.Fatalf("Load after Delete err = %v, want ErrSessionNotFound", err)
+			}
+			if err := store.Delete(ctx, "s1"); err != nil {
+				t.Fatalf("Delete of an unknown session: %v", err)
+			}
+		})
+	}
+}
+
+func TestFileSessionStore_RejectsUnsafeIDs(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSessionStore: %v", err)
+	}
+	for _, id := range []acpproto.SessionID{"", ".", "..", "../escape", `a\b`, "a\x00b"} {
+		if err := store.Create(ctx, id, ""); err == nil {
+			t.Errorf("Create(%q) succeeded, want error", id)
+		}
+		if _, err := store.Load(ctx, id); err == nil || errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Load(%q) err = %v, want invalid id error", id, err)
+		}
+	}
+	if _, err = NewFileSessionStore(""); err == nil {
+		t.Fatal("expected error for empty dir")
+	}
+}
+
+func TestReplaySession(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("updates and checkpoint", func(t *testing.T) {
+		client := &recordingClient{}
+		checkPoints := &sessionCheckPointStore{sessions: NewMemorySessionStore(), next: nil}
+		_ = checkPoints.sessions.Create(ctx, "s1", "")
+
+		rec := &SessionRecord{
+			SessionID:  "s1",
+			Updates:    []acpproto.SessionUpdate{textUpdate("one"), textUpdate("two")},
+			Messages:   []adk.Message{schema.AssistantMessage("ignored", nil)},
+			CheckPoint: []byte("ckpt"),
+		}
+		if err := ReplaySession(ctx, client, rec, checkPoints); err != nil {
+			t.Fatalf("ReplaySession: %v", err)
+		}
+		if got := client.agentTexts(); len(got) != 2 || got[0] != "one" || got[1] != "two" {
+			t.Fatalf("replayed texts = %v, want [one two]", got)
+		}
+		data, ok, err := checkPoints.Get(ctx, "s1")
+		if err != nil || !ok || string(data) != "ckpt" {
+			t.Fatalf("Get = %q, %v, %v; want rehydrated checkpoint", data, ok, err)
+		}
+	})
+
+	t.Run("falls back to messages", func(t *testing.T) {
+		client := &recordingClient{}
+		rec := &SessionRecord{
+			SessionID: "s1",
+			Messages:  []adk.Message{schema.UserMessage("hi"), schema.AssistantMessage("hello", nil)},
+		}
+		if err := ReplaySession(ctx, client, rec, nil); err != nil {
+			t.Fatalf("ReplaySession: %v", err)
+		}
+		if got := client.agentTexts(); len(got) != 1 || got[0] != "hello" {
+			t.Fatalf("replayed agent texts = %v, want [hello]", got)
+		}
+		if len(client.updates) != 2 {
+			t.Fatalf("got %d updates, want 2", len(client.updates))
+		}
+	})
+}
+
+func TestSessionCheckPointStore_AlwaysRecords(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewMemorySessionStore()
+	_ = sessions.Create(ctx, "s1", "")
+	next := NewMemorySessionStore()
+	_ = next.Create(ctx, "s1", "")
+
+	store := &sessionCheckPointStore{sessions: sessions, next: &sessionCheckPointStore{sessions: next}}
+	if err := store.Set(ctx, "s1", []byte("ckpt")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	for name, s := range map[string]SessionStore{"sessions": sessions, "next": next} {
+		rec, _ := s.Load(ctx, "s1")
+		if string(rec.CheckPoint) != "ckpt" {
+			t.Fatalf("%s checkpoint = %q, want ckpt", name, rec.CheckPoint)
+		}
+	}
+
+	if _, ok, err := (&sessionCheckPointStore{sessions: sessions}).Get(ctx, "unknown"); ok || err != nil {
+		t.Fatalf("Get(unknown) = %v, %v; want not found without error", ok, err)
+	}
+}
+
+func TestSessionCheckPointStore_ReadsOnlyTheCheckPointFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	sessions, err := NewFileSessionStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileSessionStore: %v", err)
+	}
+	_ = sessions.Create(ctx, "s1", "")
+	_ = sessions.SetCheckPoint(ctx, "s1", []byte("ckpt"))
+
+	// A log that cannot be parsed does not get in the way of the checkpoint.
+	if err = os.WriteFile(filepath.Join(dir, "s1.jsonl"), []byte("not json\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	ckpt, ok, err := (&sessionCheckPointStore{sessions: sessions}).Get(ctx, "s1")
+	if err != nil || !ok || string(ckpt) != "ckpt" {
+		t.Fatalf("Get = %q, %v, %v; want ckpt", ckpt, ok, err)
+	}
+}
+
+func TestServer_LoadSessionAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSessionStore: %v", err)
+	}
+
+	var mu sync.Mutex
+	var seen []int
+	var cwds []string
+	newServer := func() *Server {
+		srv, err := NewServer(&ServerConfig{
+			NewRunner: func(ctx context.Context, req *RunnerRequest) (*adk.Runner, error) {
+				mu.Lock()
+				cwds = append(cwds, req.Cwd)
+				mu.Unlock()
+				return adk.NewRunner(ctx, adk.RunnerConfig{Agent: echoAgent(&seen, &mu)}), nil
+			},
+			SessionStore: store,
+		})
+		if err != nil {
+			t.Fatalf("NewServer: %v", err)
+		}
+		return srv
+	}
+
+	first := connectPipe(t, newServer(), &recordingClient{})
+	sessResp, err := first.NewSession(ctx, acpproto.NewSessionRequest{Cwd: "/work"})
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if _, err = first.Prompt(ctx, textPrompt(sessResp.SessionID, "hi")); err != nil {
+		t.Fatalf("Prompt: %v", err)
+	}
+
+	// A fresh server sharing only the store stands in for a restarted process.
+	client := &recordingClient{}
+	second := connectPipe(t, newServer(), client)
+	if _, err = second.Prompt(ctx, textPrompt(sessResp.SessionID, "lost")); err == nil {
+		t.Fatal("expected Prompt on a session that was not loaded to fail")
+	}
+	if _, err = second.LoadSession(ctx, acpproto.LoadSessionRequest{SessionID: sessResp.SessionID}); err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+
+	client.mu.Lock()
+	updates := client.updates
+	client.mu.Unlock()
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 replayed updates, got %d", len(updates))
+	}
+	if chunk, ok := updates[0].Update.AsUserMessageChunk(); !ok {
+		t.Fatal("expected first replayed update to be a user message chunk")
+	} else if tc, _ := chunk.Content.AsText(); tc.Text != "hi" {
+		t.Fatalf("replayed user text = %q, want hi", tc.Text)
+	}
+	if got := client.agentTexts(); len(got) != 1 || got[0] != "echo: hi" {
+		t.Fatalf("replayed agent texts = %v, want [echo: hi]", got)
+	}
+
+	if _, err = second.Prompt(ctx, textPrompt(sessResp.SessionID, "again")); err != nil {
+		t.Fatalf("Prompt after LoadSession: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The restored runner sees the stored history: [user, assistant, user].
+	if len(seen) != 2 || seen[1] != 3 {
+		t.Fatalf("input sizes = %v, want [1 3]", seen)
+	}
+	if len(cwds) != 2 || cwds[1] != "/work" {
+		t.Fatalf("runner cwds = %v, want the stored cwd on restore", cwds)
+	}
+
+	rec, err := store.Load(ctx, sessResp.SessionID)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(rec.Messages) != 4 || len(rec.Updates) != 4 {
+		t.Fatalf("stored %d messages and %d updates, want 4 and 4", len(rec.Messages), len(rec.Updates))
+	}
+}
+
+func TestServer_LoadSessionUnknown(t *testing.T) {
+	ctx := context.Background()
+	srv, err := NewServer(&ServerConfig{
+		NewRunner: func(ctx context.Context, _ *RunnerRequest) (*adk.Runner, error) {
+			return adk.NewRunner(ctx, adk.RunnerConfig{Agent: &scriptedAgent{}}), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	if _, err = srv.LoadSession(ctx, acpproto.LoadSessionRequest{SessionID: "nope"}); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("LoadSession err = %v, want ErrSessionNotFound", err)
+	}
+}
@@ -0,0 +1,295 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoacp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/schema"
+	acpproto "github.com/eino-contrib/acp"
+	"github.com/google/uuid"
+)
+
+// ErrTerminalNotFound is returned for terminal requests that reference an unknown or
+// released terminal.
+var ErrTerminalNotFound = errors.New("acp.terminal: terminal not found")
+
+// shellOf returns the command runner of b, preferring streaming execution so that
+// terminal/output can report output while the command is still running.
+func shellOf(b filesystem.Backend) filesystem.StreamingShell {
+	switch sh := b.(type) {
+	case filesystem.StreamingShell:
+		return sh
+	case filesystem.Shell:
+		return blockingShell{sh}
+	default:
+		return nil
+	}
+}
+
+// blockingShell adapts a filesystem.Shell to filesystem.StreamingShell by emitting its whole
+// result as a single chunk.
+type blockingShell struct {
+	sh filesystem.Shell
+}
+
+func (b blockingShell) ExecuteStreaming(ctx context.Context, req *filesystem.ExecuteRequest) (*schema.StreamReader[*filesystem.ExecuteResponse], error) {
+	resp, err := b.sh.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*filesystem.ExecuteResponse{resp}), nil
+}
+
+// terminalManager serves the ACP terminal/* methods on top of a backend shell.
+type terminalManager struct {
+	shell filesystem.StreamingShell
+
+	mu        sync.Mutex
+	terminals map[string]*terminal
+}
+
+// terminal is one command started through terminal/create.
+type terminal struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu        sync.Mutex
+	output    strings.Builder
+	limit     int64
+	truncated bool
+	exit      *acpproto.TerminalExitStatus
+}
+
+func newTerminalManager(sh filesystem.StreamingShell) *terminalManager {
+	return &terminalManager{shell: sh, terminals: make(map[string]*terminal)}
+}
+
+// create starts req in the background and returns its terminal ID.
+func (m *terminalManager) create(req acpproto.CreateTerminalRequest) (string, error) {
+	command, err := terminalCommand(req)
+	if err != nil {
+		return "", err
+	}
+
+	// The command outlives the terminal/create request; it ends on its own, on
+	// terminal/kill or terminal/release.
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &terminal{cancel: cancel, done: make(chan struct{})}
+	if req.OutputByteLimit != nil {
+		t.limit = *req.OutputByteLimit
+	}
+
+	id := "term_" + uuid.NewString()
+	m.mu.Lock()
+	m.terminals[id] = t
+	m.mu.Unlock()
+
+	go t.run(ctx, m.shell, command)
+	return id, nil
+}
+
+// terminalCommand renders a terminal/create request as a single sh command line.
+func terminalCommand(req acpproto.CreateTerminalRequest) (string, error) {
+	var sb strings.Builder
+	if req.Cwd != nil && *req.Cwd != "" {
+		q, err := shellQuote(*req.Cwd)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString("cd " + q + " && ")
+	}
+	if len(req.Env) > 0 {
+		sb.WriteString("env")
+		for _, e := range req.Env {
+			q, err := shellQuote(e.Name + "=" + e.Value)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(" " + q)
+		}
+		sb.WriteString(" ")
+	}
+	if len(req.Args) == 0 {
+		// A bare command may be a full shell command line; run it as-is.
+		sb.WriteString(req.Command)
+		return sb.String(), nil
+	}
+	args, err := joinShellArgs(append([]string{req.Command}, req.Args...))
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(args)
+	return sb.String(), nil
+}
+
+func (t *terminal) run(ctx context.Context, sh filesystem.StreamingShell, command string) {
+	defer close(t.done)
+
+	sr, err := sh.ExecuteStreaming(ctx, &filesystem.ExecuteRequest{Command: command})
+	if err != nil {
+		t.finish(nil, err)
+		return
+	}
+	defer sr.Close()
+
+	var exitCode *int
+	for {
+		chunk, rErr := sr.Recv()
+		if rErr == io.EOF {
+			break
+		}
+		if rErr != nil {
+			t.finish(exitCode, rErr)
+			return
+		}
+		if chunk == nil {
+			continue
+		}
+		t.appendOutput(chunk.Output)
+		if chunk.ExitCode != nil {
+			exitCode = chunk.ExitCode
+		}
+	}
+	if ctx.Err() != nil {
+		t.finish(exitCode, ctx.Err())
+		return
+	}
+	t.finish(exitCode, nil)
+}
+
+// appendOutput records s, keeping only the last limit bytes when a limit is set. Truncation
+// happens on a character boundary, as the protocol requires.
+func (t *terminal) appendOutput(s string) {
+	if s == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.output.WriteString(s)
+	if t.limit <= 0 || int64(t.output.Len()) <= t.limit {
+		return
+	}
+	out := t.output.String()
+	cut := len(out) - int(t.limit)
+	for cut < len(out) && !utf8.RuneStart(out[cut]) {
+		cut++
+	}
+	t.output.Reset()
+	t.output.WriteString(out[cut:])
+	t.truncated = true
+}
+
+func (t *terminal) finish(exitCode *int, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status := &acpproto.TerminalExitStatus{}
+	switch {
+	case exitCode != nil:
+		code := int64(*exitCode)
+		status.ExitCode = &code
+	case errors.Is(err, context.Canceled):
+		status.Signal = "SIGKILL"
+	case err != nil:
+		t.output.WriteString(err.Error())
+		code := int64(-1)
+		status.ExitCode = &code
+	default:
+		code := int64(0)
+		status.ExitCode = &code
+	}
+	t.exit = status
+}
+
+func (m *terminalManager) get(id string) (*terminal, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.terminals[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTerminalNotFound, id)
+	}
+	return t, nil
+}
+
+func (m *terminalManager) output(id string) (acpproto.TerminalOutputResponse, error) {
+	t, err := m.get(id)
+	if err != nil {
+		return acpproto.TerminalOutputResponse{}, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return acpproto.TerminalOutputResponse{
+		Output:     t.output.String(),
+		Truncated:  t.truncated,
+		ExitStatus: t.exit,
+	}, nil
+}
+
+func (m *terminalManager) wait(ctx context.Context, id string) (acpproto.WaitForTerminalExitResponse, error) {
+	t, err := m.get(id)
+	if err != nil {
+		return acpproto.WaitForTerminalExitResponse{}, err
+	}
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return acpproto.WaitForTerminalExitResponse{}, ctx.Err()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return acpproto.WaitForTerminalExitResponse{ExitCode: t.exit.ExitCode, Signal: t.exit.Signal}, nil
+}
+
+// kill stops the command but keeps the terminal, so its output can still be read.
+func (m *terminalManager) kill(id string) error {
+	t, err := m.get(id)
+	if err != nil {
+		return err
+	}
+	t.cancel()
+	return nil
+}
+
+// release kills the command if it is still running and forgets the terminal.
+func (m *terminalManager) release(id string) error {
+	if err := m.kill(id); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	delete(m.terminals, id)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *terminalManager) killAll() {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.terminals))
+	for id := range m.terminals {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+	for _, id := range ids {
+		_ = m.release(id)
+	}
+}
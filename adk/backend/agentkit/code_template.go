@@ -294,5 +294,86 @@ try:
 except Exception as e:
     print(f"Error executing command script: {{e}}", file=sys.stderr)
     sys.exit(1)
+`
+	readFilesPythonCodeTemplate = `
+import os
+import json
+import base64
+
+paths = json.loads(base64.b64decode('{paths_b64}').decode('utf-8'))
+
+results = []
+for path in paths:
+    if not os.path.isfile(path):
+        results.append({{'exists': False, 'content_b64': ''}})
+        continue
+    with open(path, 'rb') as f:
+        results.append({{'exists': True, 'content_b64': base64.b64encode(f.read()).decode('ascii')}})
+print(json.dumps(results), end="")
+`
+	commitFilesPythonCodeTemplate = `
+import os
+import sys
+import json
+import base64
+import tempfile
+
+# Each change is {{'path': ..., 'content_b64': ...}}; a null content deletes the file.
+changes = json.loads(base64.b64decode('{changes_b64}').decode('utf-8'))
+
+staged = []
+
+def cleanup():
+    for tmp in staged:
+        if tmp and os.path.exists(tmp):
+            os.remove(tmp)
+
+# Stage every new content next to its target before touching any target.
+try:
+    for change in changes:
+        if change['content_b64'] is None:
+            staged.append(None)
+            continue
+        path = change['path']
+        parent_dir = os.path.dirname(path) or '.'
+        os.makedirs(parent_dir, exist_ok=True)
+        fd, tmp = tempfile.mkstemp(dir=parent_dir, prefix='.' + os.path.basename(path) + '.tmp-')
+        staged.append(tmp)
+        with os.fdopen(fd, 'wb') as f:
+            f.write(base64.b64decode(change['content_b64']))
+        mode = os.stat(path).st_mode & 0o777 if os.path.exists(path) else 0o644
+        os.chmod(tmp, mode)
+except Exception as e:
+    cleanup()
+    print(f"Error: failed to stage {{change['path']}}: {{e}}", end="")
+    sys.exit(1)
+
+# Move the staged files into place, restoring the originals if any step fails.
+done = []
+try:
+    for change, tmp in zip(changes, staged):
+        path = change['path']
+        backup = None
+        if os.path.isfile(path):
+            with open(path, 'rb') as f:
+                backup = f.read()
+        if tmp is None:
+            os.remove(path)
+        else:
+            os.replace(tmp, path)
+        done.append((path, backup))
+except Exception as e:
+    for path, backup in reversed(done):
+        try:
+            if backup is None:
+                os.remove(path)
+            else:
+                with open(path, 'wb') as f:
+                    f.write(backup)
+        except Exception:
+            pass
+    cleanup()
+    print(f"Error: failed to apply patch to {{path}}: {{e}}", end="")
+    sys.exit(1)
 `
 )
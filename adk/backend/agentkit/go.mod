@@ -2,9 +2,12 @@ module github.com/cloudwego/eino-ext/adk/backend/agentkit
 
 go 1.25.0
 
+replace github.com/cloudwego/eino-ext/adk/backend/patch => ../patch
+
 require (
 	github.com/bytedance/sonic v1.15.0
 	github.com/cloudwego/eino v0.9.1
+	github.com/cloudwego/eino-ext/adk/backend/patch v0.1.0
 	github.com/klippa-app/go-pdfium v1.19.3
 	github.com/slongfield/pyfmt v0.0.0-20220222012616-ea85ff4c361f
 	github.com/stretchr/testify v1.11.1
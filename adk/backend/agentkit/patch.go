@@ -0,0 +1,64 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agentkit
+
+import (
+	"path/filepath"
+
+	"github.com/cloudwego/eino-ext/adk/backend/patch"
+)
+
+var (
+	// ErrOldStringNotFound is patch.ErrOldStringNotFound.
+	ErrOldStringNotFound = patch.ErrOldStringNotFound
+	// ErrAmbiguousReplace is patch.ErrAmbiguousReplace.
+	ErrAmbiguousReplace = patch.ErrAmbiguousReplace
+	// ErrInvalidPatch is patch.ErrInvalidPatch.
+	ErrInvalidPatch = patch.ErrInvalidPatch
+	// ErrHunkMismatch is patch.ErrHunkMismatch.
+	ErrHunkMismatch = patch.ErrHunkMismatch
+)
+
+// The request, result and error types of MultiEdit and ApplyPatch.
+type (
+	EditOperation     = patch.EditOperation
+	MultiEditRequest  = patch.MultiEditRequest
+	ApplyPatchRequest = patch.ApplyPatchRequest
+	PatchFileOp       = patch.PatchFileOp
+	PatchedFile       = patch.PatchedFile
+	ApplyPatchResult  = patch.ApplyPatchResult
+	MultiEditor       = patch.MultiEditor
+	Patcher           = patch.Patcher
+	EditError         = patch.EditError
+	PatchError        = patch.PatchError
+)
+
+// The file operations of a PatchedFile, see patch.PatchFileOp.
+const (
+	PatchFileModified = patch.PatchFileModified
+	PatchFileCreated  = patch.PatchFileCreated
+	PatchFileDeleted  = patch.PatchFileDeleted
+	PatchFileRenamed  = patch.PatchFileRenamed
+)
+
+// joinPatchPath resolves a path named by a patch against baseDir.
+func joinPatchPath(baseDir, p string) string {
+	if baseDir == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(baseDir, p)
+}
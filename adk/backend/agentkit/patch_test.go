@@ -0,0 +1,182 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agentkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/adk/backend/patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pythonSandboxHandler runs every submitted script with the local python3,
+// standing in for the remote sandbox kernel. It returns a pointer to the
+// number of scripts run.
+func pythonSandboxHandler(t *testing.T) *int {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is required to run sandbox scripts locally")
+	}
+	calls := new(int)
+	mockAPIHandler = func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req invokeToolRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		var payload struct {
+			Code string `json:"code"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(req.OperationPayload), &payload))
+
+		out, err := exec.Command(python, "-c", payload.Code).Output()
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(createMockResponse(t, err == nil, string(out), "", ""))
+	}
+	t.Cleanup(func() { mockAPIHandler = nil })
+	return calls
+}
+
+func TestSandbox_MultiEdit(t *testing.T) {
+	s, server := setupTest(t)
+	defer server.Close()
+	ctx := context.Background()
+	calls := pythonSandboxHandler(t)
+
+	path := filepath.Join(t.TempDir(), "main.py")
+	require.NoError(t, os.WriteFile(path, []byte("def a():\n    return b()\n\ndef b():\n    return 1\n"), 0644))
+
+	err := s.MultiEdit(ctx, &MultiEditRequest{FilePath: path, Edits: []EditOperation{
+		{OldString: "def a()", NewString: "def first()"},
+		{OldString: "b()", NewString: "second()", ReplaceAll: true},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, *calls)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "def first():\n    return second()\n\ndef second():\n    return 1\n", string(data))
+
+	t.Run("a failing edit writes nothing", func(t *testing.T) {
+		*calls = 0
+		err := s.MultiEdit(ctx, &MultiEditRequest{FilePath: path, Edits: []EditOperation{
+			{OldString: "first", NewString: "one"},
+			{OldString: "missing", NewString: "x"},
+		}})
+		var eErr *EditError
+		require.True(t, errors.As(err, &eErr))
+		assert.Equal(t, 1, eErr.Index)
+		assert.ErrorIs(t, err, ErrOldStringNotFound)
+		assert.Equal(t, 1, *calls, "only the read should reach the sandbox")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := s.MultiEdit(ctx, &MultiEditRequest{FilePath: path + ".missing", Edits: []EditOperation{{OldString: "a", NewString: "b"}}})
+		assert.ErrorContains(t, err, "file not found")
+	})
+}
+
+func TestSandbox_ApplyPatch(t *testing.T) {
+	s, server := setupTest(t)
+	defer server.Close()
+	ctx := context.Background()
+	calls := pythonSandboxHandler(t)
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("multi-file patch", func(t *testing.T) {
+		write("a.txt", "one\ntwo\n")
+		write("gone.txt", "bye\n")
+		*calls = 0
+
+		res, err := s.ApplyPatch(ctx, &ApplyPatchRequest{BaseDir: dir, Patch: `--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ one
+-two
++2
+--- /dev/null
++++ b/sub/new.txt
+@@ -0,0 +1 @@
++created
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`})
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls, "one read and one commit")
+		assert.Equal(t, []PatchedFile{
+			{Path: filepath.Join(dir, "a.txt"), Op: PatchFileModified, Hunks: 1},
+			{Path: filepath.Join(dir, "sub/new.txt"), Op: PatchFileCreated, Hunks: 1},
+			{Path: filepath.Join(dir, "gone.txt"), Op: PatchFileDeleted, Hunks: 1},
+		}, res.Files)
+		assert.Equal(t, "one\n2\n", read("a.txt"))
+		assert.Equal(t, "created\n", read("sub/new.txt"))
+		assert.NoFileExists(t, filepath.Join(dir, "gone.txt"))
+	})
+
+	t.Run("a failing hunk writes nothing", func(t *testing.T) {
+		write("x.txt", "x\n")
+		write("y.txt", "y\n")
+		*calls = 0
+
+		_, err := s.ApplyPatch(ctx, &ApplyPatchRequest{BaseDir: dir, Patch: "--- x.txt\n+++ x.txt\n@@ -1 +1 @@\n-x\n+X\n--- y.txt\n+++ y.txt\n@@ -1 +1 @@\n-nope\n+Y\n"})
+		var pErr *PatchError
+		require.True(t, errors.As(err, &pErr))
+		assert.Equal(t, filepath.Join(dir, "y.txt"), pErr.FilePath)
+		assert.Equal(t, 1, pErr.Hunk)
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, "x\n", read("x.txt"))
+	})
+
+	t.Run("commit failure restores the originals", func(t *testing.T) {
+		write("first.txt", "first\n")
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "busy", "child"), 0755))
+
+		// Moving the staged file onto a non-empty directory fails after
+		// first.txt was already replaced.
+		err := s.commitFiles(ctx, []*patch.FileWrite{
+			{Path: filepath.Join(dir, "first.txt"), After: patch.FileSnapshot{Content: "changed\n", Exists: true}},
+			{Path: filepath.Join(dir, "busy"), After: patch.FileSnapshot{Content: "x", Exists: true}},
+		})
+		assert.ErrorContains(t, err, "commitFiles script exited with non-zero code")
+		assert.Equal(t, "first\n", read("first.txt"))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.NotContains(t, e.Name(), ".tmp-", "temporary files must be cleaned up")
+		}
+	})
+}
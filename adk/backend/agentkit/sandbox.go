@@ -39,6 +39,7 @@ import (
 	"time"
 
 	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino-ext/adk/backend/patch"
 	"github.com/cloudwego/eino/adk/filesystem"
 	"github.com/klippa-app/go-pdfium"
 	"github.com/klippa-app/go-pdfium/references"
@@ -615,6 +616,155 @@ func (s *SandboxTool) Edit(ctx context.Context, req *filesystem.EditRequest) err
 	return nil
 }
 
+// MultiEdit applies req.Edits to one file in order, each to the result of the
+// previous one. The edits are computed locally and the file is written back in
+// a single sandbox call only when every edit applies; otherwise an *EditError
+// names the failed edit and the file is left untouched.
+func (s *SandboxTool) MultiEdit(ctx context.Context, req *MultiEditRequest) error {
+	path := filepath.Clean(req.FilePath)
+
+	snapshots, err := s.readFiles(ctx, []string{path})
+	if err != nil {
+		return err
+	}
+	if !snapshots[0].Exists {
+		return fmt.Errorf("file not found: %s", path)
+	}
+
+	newText, err := patch.ApplyEdits(req.FilePath, snapshots[0].Content, req.Edits)
+	if err != nil {
+		return err
+	}
+
+	return s.commitFiles(ctx, []*patch.FileWrite{{Path: path, Before: snapshots[0], After: patch.FileSnapshot{Content: newText, Exists: true}}})
+}
+
+// ApplyPatch applies a unified diff that may span several files. The touched
+// files are fetched in one sandbox call and every hunk is applied locally;
+// a hunk that does not match is reported as a *PatchError before anything is
+// written. The result is then committed by a script that stages all files
+// first and restores the originals if moving them into place fails.
+func (s *SandboxTool) ApplyPatch(ctx context.Context, req *ApplyPatchRequest) (*ApplyPatchResult, error) {
+	files, err := patch.Parse(req.Patch)
+	if err != nil {
+		return nil, err
+	}
+	patch.ResolvePaths(files, func(p string) string { return joinPatchPath(req.BaseDir, p) })
+
+	targets := patch.Targets(files)
+	snapshots, err := s.readFiles(ctx, targets)
+	if err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]patch.FileSnapshot, len(targets))
+	for i, p := range targets {
+		byPath[p] = snapshots[i]
+	}
+
+	report, writes, err := patch.Plan(files, func(p string) (patch.FileSnapshot, error) {
+		return byPath[p], nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(writes) > 0 {
+		if err := s.commitFiles(ctx, writes); err != nil {
+			return nil, err
+		}
+	}
+	return &ApplyPatchResult{Files: report}, nil
+}
+
+// readFiles returns the contents of paths, in order, from a single script run.
+func (s *SandboxTool) readFiles(ctx context.Context, paths []string) ([]patch.FileSnapshot, error) {
+	cleaned := make([]string, len(paths))
+	for i, p := range paths {
+		cleaned[i] = filepath.Clean(p)
+	}
+	pathsJSON, err := json.Marshal(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal paths: %w", err)
+	}
+
+	params := map[string]any{
+		"paths_b64": base64.StdEncoding.EncodeToString(pathsJSON),
+	}
+
+	script, err := pyfmt.Fmt(readFilesPythonCodeTemplate, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render readFiles template: %w", err)
+	}
+
+	output, exitCode, err := s.execute(ctx, script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute readFiles script: %w", err)
+	}
+	if exitCode != nil && *exitCode != 0 {
+		return nil, fmt.Errorf("readFiles script exited with non-zero code %d: %s", *exitCode, output)
+	}
+
+	var results []struct {
+		Exists     bool   `json:"exists"`
+		ContentB64 string `json:"content_b64"`
+	}
+	if err := json.Unmarshal([]byte(output), &results); err != nil {
+		return nil, fmt.Errorf("failed to parse readFiles output: %w", err)
+	}
+	if len(results) != len(paths) {
+		return nil, fmt.Errorf("readFiles returned %d results for %d paths", len(results), len(paths))
+	}
+
+	snapshots := make([]patch.FileSnapshot, len(results))
+	for i, r := range results {
+		content, err := base64.StdEncoding.DecodeString(r.ContentB64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 content of %s: %w", paths[i], err)
+		}
+		snapshots[i] = patch.FileSnapshot{Content: string(content), Exists: r.Exists}
+	}
+	return snapshots, nil
+}
+
+// commitFiles writes or deletes the files of writes in a single script run.
+func (s *SandboxTool) commitFiles(ctx context.Context, writes []*patch.FileWrite) error {
+	type change struct {
+		Path       string  `json:"path"`
+		ContentB64 *string `json:"content_b64"`
+	}
+	changes := make([]change, len(writes))
+	for i, w := range writes {
+		changes[i].Path = filepath.Clean(w.Path)
+		if !w.Deleted {
+			content := base64.StdEncoding.EncodeToString([]byte(w.After.Content))
+			changes[i].ContentB64 = &content
+		}
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal changes: %w", err)
+	}
+
+	params := map[string]any{
+		"changes_b64": base64.StdEncoding.EncodeToString(changesJSON),
+	}
+
+	script, err := pyfmt.Fmt(commitFilesPythonCodeTemplate, params)
+	if err != nil {
+		return fmt.Errorf("failed to render commitFiles template: %w", err)
+	}
+
+	output, exitCode, err := s.execute(ctx, script)
+	if err != nil {
+		return fmt.Errorf("failed to execute commitFiles script: %w", err)
+	}
+	if exitCode != nil && *exitCode != 0 {
+		return fmt.Errorf("commitFiles script exited with non-zero code %d: %s", *exitCode, output)
+	}
+
+	return nil
+}
+
 // MultiModalRead reads file content with multimodal support for images and PDFs.
 // For non-image/non-PDF files, it delegates to the standard Read method.
 //
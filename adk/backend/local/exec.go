@@ -0,0 +1,499 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// defaultBackgroundOutputBytes caps the output kept for a background
+	// command when ExecConfig.MaxOutputBytes is not set.
+	defaultBackgroundOutputBytes = 1 << 20
+	// maxFinishedBackgroundProcesses bounds how many exited background
+	// commands are remembered; the oldest are forgotten first.
+	maxFinishedBackgroundProcesses = 64
+	// cmdWaitDelay bounds how long Wait keeps reading output after the command
+	// was killed, in case a process outside its group still holds the pipes.
+	cmdWaitDelay = 2 * time.Second
+)
+
+// ErrBackgroundProcessNotFound is returned for background process IDs that
+// were never issued or have been forgotten.
+var ErrBackgroundProcessNotFound = errors.New("background process not found")
+
+// ExecConfig controls how Execute and ExecuteStreaming run commands. Config.Exec
+// holds the backend defaults; WithExecConfig overrides them for a single call.
+type ExecConfig struct {
+	// WorkDir is the working directory of commands. It is subject to
+	// Config.RootDirs and Config.DenyGlobs like any other path. Defaults to the
+	// first root, or to the working directory of the process without roots.
+	WorkDir string
+
+	// EnvAllowlist lists the parent environment variables commands inherit. A
+	// trailing "*" matches a prefix, e.g. "LC_*". nil inherits the whole parent
+	// environment; an empty non-nil list inherits nothing.
+	EnvAllowlist []string
+
+	// Env sets environment variables for commands, taking precedence over
+	// inherited ones.
+	Env map[string]string
+
+	// Timeout is the wall-clock limit of a foreground command. When it expires
+	// the command and every process it started are killed and the response
+	// reports the timeout. Background commands are not subject to it. Zero
+	// means no limit.
+	Timeout time.Duration
+
+	// MaxOutputBytes caps the stdout and the stderr returned for a command,
+	// each; anything beyond is dropped and replaced by a truncation marker, and
+	// the response has Truncated set. Zero means no limit for foreground
+	// commands and 1 MiB for the output kept for background commands.
+	MaxOutputBytes int
+
+	// RLimits are resource limits applied to commands on Linux. Ignored on
+	// other platforms.
+	RLimits *RLimits
+}
+
+// RLimits are per-process resource limits. Zero fields are left unlimited.
+type RLimits struct {
+	// CPUSeconds limits CPU time (RLIMIT_CPU).
+	CPUSeconds uint64
+	// MemoryBytes limits the virtual address space (RLIMIT_AS).
+	MemoryBytes uint64
+	// OpenFiles limits the number of open file descriptors (RLIMIT_NOFILE).
+	OpenFiles uint64
+}
+
+type execConfigKey struct{}
+
+// WithExecConfig returns a context under which Execute and ExecuteStreaming use
+// cfg on top of Config.Exec: non-zero fields of cfg replace the backend
+// defaults, and Env is merged into the default Env.
+func WithExecConfig(ctx context.Context, cfg ExecConfig) context.Context {
+	return context.WithValue(ctx, execConfigKey{}, cfg)
+}
+
+// execConfig returns the backend defaults merged with the overrides in ctx.
+func (s *Local) execConfig(ctx context.Context) ExecConfig {
+	cfg := s.execCfg
+	o, ok := ctx.Value(execConfigKey{}).(ExecConfig)
+	if !ok {
+		return cfg
+	}
+	if o.WorkDir != "" {
+		cfg.WorkDir = o.WorkDir
+	}
+	if o.EnvAllowlist != nil {
+		cfg.EnvAllowlist = o.EnvAllowlist
+	}
+	if len(o.Env) > 0 {
+		env := maps.Clone(cfg.Env)
+		if env == nil {
+			env = make(map[string]string, len(o.Env))
+		}
+		maps.Copy(env, o.Env)
+		cfg.Env = env
+	}
+	if o.Timeout > 0 {
+		cfg.Timeout = o.Timeout
+	}
+	if o.MaxOutputBytes > 0 {
+		cfg.MaxOutputBytes = o.MaxOutputBytes
+	}
+	if o.RLimits != nil {
+		cfg.RLimits = o.RLimits
+	}
+	return cfg
+}
+
+// newCmd builds the shell command for command under cfg: working directory,
+// environment, resource limits and process-group handling, so that
+// cancellation kills everything the command started.
+func (s *Local) newCmd(ctx context.Context, command string, cfg *ExecConfig) (*exec.Cmd, error) {
+	dir := s.policy.workDir()
+	if cfg.WorkDir != "" {
+		var err error
+		if dir, err = s.policy.resolve(cfg.WorkDir); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", rlimitPrefix(cfg.RLimits)+command)
+	cmd.Dir = dir
+	cmd.Env = cfg.environ()
+	setProcessGroup(cmd)
+	cmd.WaitDelay = cmdWaitDelay
+	return cmd, nil
+}
+
+// environ returns the environment for commands, or nil to inherit the parent
+// environment unchanged.
+func (c *ExecConfig) environ() []string {
+	if c.EnvAllowlist == nil && len(c.Env) == 0 {
+		return nil
+	}
+	env := make([]string, 0, len(c.Env))
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if _, ok := c.Env[name]; ok {
+			continue
+		}
+		if c.EnvAllowlist != nil && !envAllowed(c.EnvAllowlist, name) {
+			continue
+		}
+		env = append(env, kv)
+	}
+	names := make([]string, 0, len(c.Env))
+	for name := range c.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, name+"="+c.Env[name])
+	}
+	return env
+}
+
+func envAllowed(allowlist []string, name string) bool {
+	for _, allowed := range allowlist {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rlimitPrefix returns a shell snippet that applies r before the command runs,
+// or "" when there is nothing to apply. Limits set with ulimit are inherited by
+// every process the shell starts. If a limit cannot be applied the command is
+// not run.
+func rlimitPrefix(r *RLimits) string {
+	if r == nil || runtime.GOOS != "linux" {
+		return ""
+	}
+	var limits []string
+	if r.CPUSeconds > 0 {
+		limits = append(limits, "ulimit -t "+strconv.FormatUint(r.CPUSeconds, 10))
+	}
+	if r.MemoryBytes > 0 {
+		// ulimit -v takes KiB.
+		kib := r.MemoryBytes / 1024
+		if kib == 0 {
+			kib = 1
+		}
+		limits = append(limits, "ulimit -v "+strconv.FormatUint(kib, 10))
+	}
+	if r.OpenFiles > 0 {
+		limits = append(limits, "ulimit -n "+strconv.FormatUint(r.OpenFiles, 10))
+	}
+	if len(limits) == 0 {
+		return ""
+	}
+	return strings.Join(limits, " && ") + " || exit 126\n"
+}
+
+// cappedBuffer is an io.Writer that keeps the first limit bytes written to it
+// and counts the rest. A non-positive limit keeps everything. It is safe for
+// concurrent writers, so stdout and stderr can share one.
+type cappedBuffer struct {
+	limit int
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	dropped int64
+}
+
+func newCappedBuffer(limit int) *cappedBuffer {
+	return &cappedBuffer{limit: limit}
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.limit <= 0 {
+		return b.buf.Write(p)
+	}
+	keep := b.limit - b.buf.Len()
+	if b.dropped > 0 || keep <= 0 {
+		b.dropped += int64(len(p))
+		return len(p), nil
+	}
+	if keep >= len(p) {
+		return b.buf.Write(p)
+	}
+	keep = runeBoundary(p, keep)
+	b.buf.Write(p[:keep])
+	b.dropped += int64(len(p) - keep)
+	return len(p), nil
+}
+
+// String returns the kept output, followed by a truncation marker when
+// anything was dropped.
+func (b *cappedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.dropped == 0 {
+		return b.buf.String()
+	}
+	return b.buf.String() + truncationMarker(b.dropped)
+}
+
+func (b *cappedBuffer) truncated() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped > 0
+}
+
+// runeBoundary returns the largest n' <= n such that p[:n'] does not end in
+// the middle of a UTF-8 encoded rune.
+func runeBoundary(p []byte, n int) int {
+	if n >= len(p) {
+		return len(p)
+	}
+	for n > 0 && !utf8.RuneStart(p[n]) {
+		n--
+	}
+	return n
+}
+
+func truncationMarker(dropped int64) string {
+	return fmt.Sprintf("\n... [output truncated: %d bytes omitted]\n", dropped)
+}
+
+// BackgroundProcess describes a command started with RunInBackendGround.
+type BackgroundProcess struct {
+	ID        string
+	Command   string
+	PID       int
+	StartedAt time.Time
+	// Running is false once the command has exited or was killed.
+	Running bool
+	// ExitCode is set once the command has exited; it is -1 when the command
+	// was killed by a signal.
+	ExitCode *int
+	// Output is the combined stdout and stderr of the command so far, capped
+	// at ExecConfig.MaxOutputBytes.
+	Output string
+	// Truncated reports whether output beyond the cap was dropped.
+	Truncated bool
+}
+
+// backgroundProcess is the live state behind a BackgroundProcess.
+type backgroundProcess struct {
+	id        string
+	command   string
+	cmd       *exec.Cmd
+	startedAt time.Time
+	output    *cappedBuffer
+	done      chan struct{}
+
+	mu       sync.Mutex
+	exitCode *int
+}
+
+func (p *backgroundProcess) snapshot() BackgroundProcess {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	bp := BackgroundProcess{
+		ID:        p.id,
+		Command:   p.command,
+		PID:       p.cmd.Process.Pid,
+		StartedAt: p.startedAt,
+		Running:   p.exitCode == nil,
+		Output:    p.output.String(),
+		Truncated: p.output.truncated(),
+	}
+	if p.exitCode != nil {
+		code := *p.exitCode
+		bp.ExitCode = &code
+	}
+	return bp
+}
+
+// backgroundRegistry tracks the background commands of one Local.
+type backgroundRegistry struct {
+	mu        sync.Mutex
+	seq       int
+	processes map[string]*backgroundProcess
+	finished  []string
+}
+
+func newBackgroundRegistry() *backgroundRegistry {
+	return &backgroundRegistry{processes: make(map[string]*backgroundProcess)}
+}
+
+func (r *backgroundRegistry) add(p *backgroundProcess) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	p.id = "bg-" + strconv.Itoa(r.seq)
+	r.processes[p.id] = p
+}
+
+// exited records that p has exited and forgets the oldest exited processes
+// beyond maxFinishedBackgroundProcesses.
+func (r *backgroundRegistry) exited(p *backgroundProcess) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished = append(r.finished, p.id)
+	for len(r.finished) > maxFinishedBackgroundProcesses {
+		delete(r.processes, r.finished[0])
+		r.finished = r.finished[1:]
+	}
+}
+
+func (r *backgroundRegistry) get(id string) (*backgroundProcess, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.processes[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBackgroundProcessNotFound, id)
+	}
+	return p, nil
+}
+
+// startBackground starts command without waiting for it and registers it. The
+// command is killed when ctx is done.
+func (s *Local) startBackground(ctx context.Context, command string, cfg *ExecConfig) (*backgroundProcess, error) {
+	limit := cfg.MaxOutputBytes
+	if limit <= 0 {
+		limit = defaultBackgroundOutputBytes
+	}
+
+	// ctx is watched below rather than handed to exec, which would only kill
+	// the shell and not the processes it started.
+	cmd, err := s.newCmd(context.WithoutCancel(ctx), command, cfg)
+	if err != nil {
+		return nil, err
+	}
+	output := newCappedBuffer(limit)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start command: %w", err)
+	}
+
+	p := &backgroundProcess{
+		command:   command,
+		cmd:       cmd,
+		startedAt: time.Now(),
+		output:    output,
+		done:      make(chan struct{}),
+	}
+	s.background.add(p)
+
+	go func() {
+		err := cmd.Wait()
+		code := 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		} else if err != nil {
+			code = -1
+		}
+		p.mu.Lock()
+		p.exitCode = &code
+		p.mu.Unlock()
+		close(p.done)
+		s.background.exited(p)
+	}()
+	go func() {
+		select {
+		case <-p.done:
+		case <-ctx.Done():
+			_ = killProcessGroup(cmd)
+		}
+	}()
+	return p, nil
+}
+
+// BackgroundProcesses returns the background commands started by this backend
+// that are still running or exited recently, oldest first.
+func (s *Local) BackgroundProcesses() []BackgroundProcess {
+	s.background.mu.Lock()
+	processes := make([]*backgroundProcess, 0, len(s.background.processes))
+	for _, p := range s.background.processes {
+		processes = append(processes, p)
+	}
+	s.background.mu.Unlock()
+
+	result := make([]BackgroundProcess, 0, len(processes))
+	for _, p := range processes {
+		result = append(result, p.snapshot())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].StartedAt.Equal(result[j].StartedAt) {
+			return result[i].StartedAt.Before(result[j].StartedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// BackgroundProcess returns the state of the background command with the given
+// ID, as reported when it was started.
+func (s *Local) BackgroundProcess(id string) (*BackgroundProcess, error) {
+	p, err := s.background.get(id)
+	if err != nil {
+		return nil, err
+	}
+	bp := p.snapshot()
+	return &bp, nil
+}
+
+// KillBackgroundProcess kills the background command with the given ID and
+// every process it started, and waits for it to exit. Killing a command that
+// has already exited is not an error.
+func (s *Local) KillBackgroundProcess(ctx context.Context, id string) error {
+	p, err := s.background.get(id)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-p.done:
+		return nil
+	default:
+	}
+	if err = killProcessGroup(p.cmd); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to kill background process %s: %w", id, err)
+	}
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
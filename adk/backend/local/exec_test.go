@@ -0,0 +1,257 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectStream drains sr and returns the concatenated output and the last
+// response carrying an exit code.
+func collectStream(t *testing.T, sr *schema.StreamReader[*filesystem.ExecuteResponse]) (string, *filesystem.ExecuteResponse, bool) {
+	t.Helper()
+	var sb strings.Builder
+	var last *filesystem.ExecuteResponse
+	truncated := false
+	for {
+		resp, err := sr.Recv()
+		if err != nil {
+			break
+		}
+		if resp == nil {
+			continue
+		}
+		sb.WriteString(resp.Output)
+		truncated = truncated || resp.Truncated
+		if resp.ExitCode != nil {
+			last = resp
+		}
+	}
+	return sb.String(), last, truncated
+}
+
+func TestExecConfig_WorkDirAndEnv(t *testing.T) {
+	ctx := context.Background()
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.Mkdir(filepath.Join(root, "sub"), 0755))
+
+	t.Setenv("LOCAL_EXEC_TEST_SECRET", "secret")
+	t.Setenv("LOCAL_EXEC_TEST_KEEP", "keep")
+
+	s, err := NewBackend(ctx, &Config{
+		RootDirs: []string{root},
+		Exec: ExecConfig{
+			EnvAllowlist: []string{"PATH", "LOCAL_EXEC_TEST_K*"},
+			Env:          map[string]string{"FOO": "default", "BAR": "bar"},
+		},
+	})
+	require.NoError(t, err)
+
+	cmd := `echo "$(pwd)|$LOCAL_EXEC_TEST_SECRET|$LOCAL_EXEC_TEST_KEEP|$FOO|$BAR"`
+
+	resp, err := s.Execute(ctx, &filesystem.ExecuteRequest{Command: cmd})
+	require.NoError(t, err)
+	assert.Equal(t, root+"||keep|default|bar\n", resp.Output)
+
+	overrideCtx := WithExecConfig(ctx, ExecConfig{WorkDir: "sub", Env: map[string]string{"FOO": "override"}})
+	resp, err = s.Execute(overrideCtx, &filesystem.ExecuteRequest{Command: cmd})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "sub")+"||keep|override|bar\n", resp.Output)
+
+	sr, err := s.ExecuteStreaming(overrideCtx, &filesystem.ExecuteRequest{Command: cmd})
+	require.NoError(t, err)
+	out, _, _ := collectStream(t, sr)
+	assert.Equal(t, filepath.Join(root, "sub")+"||keep|override|bar\n", out)
+
+	t.Run("empty allowlist inherits nothing", func(t *testing.T) {
+		emptyCtx := WithExecConfig(ctx, ExecConfig{EnvAllowlist: []string{}})
+		resp, err := s.Execute(emptyCtx, &filesystem.ExecuteRequest{Command: `echo "$LOCAL_EXEC_TEST_KEEP|$FOO"`})
+		require.NoError(t, err)
+		assert.Equal(t, "|default\n", resp.Output)
+	})
+
+	t.Run("work dir is subject to the path policy", func(t *testing.T) {
+		_, err := s.Execute(WithExecConfig(ctx, ExecConfig{WorkDir: "/"}), &filesystem.ExecuteRequest{Command: "pwd"})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+	})
+}
+
+func TestExecConfig_Timeout(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "leaked")
+
+	s, err := NewBackend(ctx, &Config{Exec: ExecConfig{Timeout: 200 * time.Millisecond}})
+	require.NoError(t, err)
+
+	t.Run("Execute kills the process group", func(t *testing.T) {
+		start := time.Now()
+		resp, err := s.Execute(ctx, &filesystem.ExecuteRequest{
+			Command: "echo started; (sleep 1; echo leaked > " + marker + ") & sleep 30",
+		})
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.Contains(t, resp.Output, "command timed out after 200ms")
+		assert.Contains(t, resp.Output, "started")
+		require.NotNil(t, resp.ExitCode)
+		assert.NotEqual(t, 0, *resp.ExitCode)
+
+		// The subshell was in the same process group and must not survive.
+		time.Sleep(1500 * time.Millisecond)
+		assert.NoFileExists(t, marker)
+	})
+
+	t.Run("ExecuteStreaming reports the timeout", func(t *testing.T) {
+		sr, err := s.ExecuteStreaming(ctx, &filesystem.ExecuteRequest{Command: "echo started; sleep 30"})
+		require.NoError(t, err)
+		out, last, _ := collectStream(t, sr)
+		assert.Contains(t, out, "started\n")
+		assert.Contains(t, out, "command timed out after 200ms")
+		require.NotNil(t, last)
+		assert.NotEqual(t, 0, *last.ExitCode)
+	})
+
+	t.Run("per-call timeout overrides the default", func(t *testing.T) {
+		resp, err := s.Execute(WithExecConfig(ctx, ExecConfig{Timeout: 5 * time.Second}), &filesystem.ExecuteRequest{Command: "sleep 0.5; echo done"})
+		require.NoError(t, err)
+		assert.Equal(t, "done\n", resp.Output)
+	})
+}
+
+func TestExecConfig_MaxOutputBytes(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewBackend(ctx, &Config{Exec: ExecConfig{MaxOutputBytes: 100}})
+	require.NoError(t, err)
+
+	resp, err := s.Execute(ctx, &filesystem.ExecuteRequest{Command: "head -c 10000 /dev/zero | tr '\\0' a"})
+	require.NoError(t, err)
+	assert.True(t, resp.Truncated)
+	assert.True(t, strings.HasPrefix(resp.Output, strings.Repeat("a", 100)+"\n... [output truncated: 9900 bytes omitted]"))
+
+	resp, err = s.Execute(ctx, &filesystem.ExecuteRequest{Command: "echo short"})
+	require.NoError(t, err)
+	assert.False(t, resp.Truncated)
+	assert.Equal(t, "short\n", resp.Output)
+
+	sr, err := s.ExecuteStreaming(ctx, &filesystem.ExecuteRequest{Command: "seq 1 1000"})
+	require.NoError(t, err)
+	out, _, truncated := collectStream(t, sr)
+	assert.True(t, truncated)
+	assert.Regexp(t, regexp.MustCompile(`^1\n2\n(.|\n)*\.\.\. \[output truncated: \d+ bytes omitted\]\n$`), out)
+	assert.Less(t, len(out), 200)
+}
+
+func TestCappedBuffer(t *testing.T) {
+	b := newCappedBuffer(4)
+	_, _ = b.Write([]byte("ab"))
+	_, _ = b.Write([]byte("cé"))
+	_, _ = b.Write([]byte("xyz"))
+	assert.True(t, b.truncated())
+	// "é" is two bytes and would straddle the limit, so it is dropped whole.
+	assert.Equal(t, "abc"+truncationMarker(5), b.String())
+
+	unlimited := newCappedBuffer(0)
+	_, _ = unlimited.Write([]byte(strings.Repeat("x", 1000)))
+	assert.False(t, unlimited.truncated())
+	assert.Len(t, unlimited.String(), 1000)
+}
+
+func TestExecConfig_RLimits(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("rlimits are only applied on Linux")
+	}
+	ctx := context.Background()
+	s, err := NewBackend(ctx, &Config{Exec: ExecConfig{RLimits: &RLimits{CPUSeconds: 7, OpenFiles: 64, MemoryBytes: 1 << 30}}})
+	require.NoError(t, err)
+
+	resp, err := s.Execute(ctx, &filesystem.ExecuteRequest{Command: "ulimit -t; ulimit -n; ulimit -v"})
+	require.NoError(t, err)
+	assert.Equal(t, "7\n64\n1048576\n", resp.Output)
+}
+
+func TestBackgroundProcesses(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewBackend(ctx, &Config{})
+	require.NoError(t, err)
+
+	startBackground := func(command string) string {
+		sr, err := s.ExecuteStreaming(ctx, &filesystem.ExecuteRequest{Command: command, RunInBackendGround: true})
+		require.NoError(t, err)
+		out, _, _ := collectStream(t, sr)
+		m := regexp.MustCompile(`with id (bg-\d+) \(pid \d+\)`).FindStringSubmatch(out)
+		require.NotNil(t, m, out)
+		return m[1]
+	}
+
+	longID := startBackground("echo hello; sleep 30")
+	shortID := startBackground("echo done")
+
+	require.Eventually(t, func() bool {
+		p, err := s.BackgroundProcess(shortID)
+		return err == nil && !p.Running
+	}, 5*time.Second, 20*time.Millisecond)
+	short, err := s.BackgroundProcess(shortID)
+	require.NoError(t, err)
+	require.NotNil(t, short.ExitCode)
+	assert.Equal(t, 0, *short.ExitCode)
+	assert.Equal(t, "done\n", short.Output)
+
+	require.Eventually(t, func() bool {
+		p, err := s.BackgroundProcess(longID)
+		return err == nil && p.Output == "hello\n"
+	}, 5*time.Second, 20*time.Millisecond)
+	long, err := s.BackgroundProcess(longID)
+	require.NoError(t, err)
+	assert.True(t, long.Running)
+	assert.Nil(t, long.ExitCode)
+	assert.Equal(t, "echo hello; sleep 30", long.Command)
+	assert.NotZero(t, long.PID)
+
+	procs := s.BackgroundProcesses()
+	require.Len(t, procs, 2)
+	assert.Equal(t, longID, procs[0].ID)
+	assert.Equal(t, shortID, procs[1].ID)
+
+	killCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, s.KillBackgroundProcess(killCtx, longID))
+	long, err = s.BackgroundProcess(longID)
+	require.NoError(t, err)
+	assert.False(t, long.Running)
+	require.NotNil(t, long.ExitCode)
+	assert.Equal(t, -1, *long.ExitCode)
+
+	// Killing an exited process is a no-op.
+	assert.NoError(t, s.KillBackgroundProcess(killCtx, shortID))
+
+	_, err = s.BackgroundProcess("bg-999")
+	assert.ErrorIs(t, err, ErrBackgroundProcessNotFound)
+	assert.ErrorIs(t, s.KillBackgroundProcess(killCtx, "bg-999"), ErrBackgroundProcessNotFound)
+}
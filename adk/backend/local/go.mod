@@ -2,9 +2,12 @@ module github.com/cloudwego/eino-ext/adk/backend/local
 
 go 1.25.0
 
+replace github.com/cloudwego/eino-ext/adk/backend/patch => ../patch
+
 require (
 	github.com/bmatcuk/doublestar/v4 v4.10.0
 	github.com/cloudwego/eino v0.9.1
+	github.com/cloudwego/eino-ext/adk/backend/patch v0.1.0
 	github.com/klippa-app/go-pdfium v1.19.3
 	github.com/stretchr/testify v1.11.1
 )
@@ -0,0 +1,509 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino-ext/adk/backend/patch"
+)
+
+const (
+	defaultJournalMaxBytes = 256 << 20
+
+	journalIndexFile = "journal.json"
+	journalBlobDir   = "blobs"
+)
+
+var (
+	// ErrJournalDisabled is returned by the journal methods when
+	// Config.Journal is not set.
+	ErrJournalDisabled = errors.New("journal is not enabled")
+	// ErrNothingToUndo is returned by Undo when the run has no journaled
+	// changes left.
+	ErrNothingToUndo = errors.New("no journaled changes to undo")
+	// ErrJournalConflict is returned when a file was modified after the change
+	// being undone, so restoring the snapshot would discard that modification.
+	ErrJournalConflict = errors.New("file changed since it was journaled")
+)
+
+// JournalConfig enables the file-change journal of Local.
+type JournalConfig struct {
+	// Dir is where the journal index and file snapshots are stored. It is
+	// created if missing, and an existing journal in it is loaded, so undo
+	// history survives restarts. Required.
+	Dir string
+	// MaxBytes caps the total size of stored snapshots. Only the content of
+	// files before each change is stored and counted; the content written is
+	// recorded as a hash. When a new change exceeds the cap, the oldest
+	// entries (of any run) are dropped first.
+	// Optional; default 256 MiB.
+	MaxBytes int64
+}
+
+// JournalEntry is one journaled change: a Write, Edit, MultiEdit or
+// ApplyPatch call. Undoing it restores every file to its state before the
+// call.
+type JournalEntry struct {
+	// Seq orders entries across runs; it only grows.
+	Seq   int64
+	RunID string
+	// Op is "write", "edit", "multi_edit" or "apply_patch".
+	Op    string
+	Time  time.Time
+	Files []JournalFile
+}
+
+// JournalFile describes the snapshot of one file in a JournalEntry.
+type JournalFile struct {
+	Path string
+	// Existed is false when the change created the file; undoing the change
+	// removes it again.
+	Existed bool
+	// Size is the size of the snapshot.
+	Size int64
+}
+
+type journalRunKey struct{}
+
+// WithJournalRunID returns a context whose Write, Edit, MultiEdit and
+// ApplyPatch calls are journaled under runID, typically the ID of the agent
+// run issuing them. Changes made without a run ID are journaled under "".
+func WithJournalRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, journalRunKey{}, runID)
+}
+
+func journalRunID(ctx context.Context) string {
+	runID, _ := ctx.Value(journalRunKey{}).(string)
+	return runID
+}
+
+// journalRecord is the persisted form of a JournalEntry.
+type journalRecord struct {
+	Seq   int64               `json:"seq"`
+	RunID string              `json:"run_id"`
+	Op    string              `json:"op"`
+	Time  time.Time           `json:"time"`
+	Files []journalFileRecord `json:"files"`
+}
+
+type journalFileRecord struct {
+	Path    string `json:"path"`
+	Existed bool   `json:"existed"`
+	// Blob is the snapshot's file name below the blob directory.
+	Blob string `json:"blob,omitempty"`
+	Size int64  `json:"size"`
+	// After is the SHA-256 of the content the change wrote, or "" when the
+	// change deleted the file. Undo refuses to run when the file no longer
+	// matches it.
+	After string `json:"after"`
+}
+
+type journalIndex struct {
+	LastSeq int64            `json:"last_seq"`
+	Entries []*journalRecord `json:"entries"`
+}
+
+// journalChange is a change about to be made to one file.
+type journalChange struct {
+	path   string
+	before patch.FileSnapshot
+	after  patch.FileSnapshot
+}
+
+// journal stores snapshots of files before they are changed, so that changes
+// can be undone per run.
+type journal struct {
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	index journalIndex
+}
+
+func newJournal(cfg *JournalConfig) (*journal, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("journal dir is required")
+	}
+	j := &journal{dir: cfg.Dir, maxBytes: cfg.MaxBytes}
+	if j.maxBytes <= 0 {
+		j.maxBytes = defaultJournalMaxBytes
+	}
+	if err := os.MkdirAll(filepath.Join(j.dir, journalBlobDir), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create journal dir: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(j.dir, journalIndexFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	default:
+		if err := json.Unmarshal(data, &j.index); err != nil {
+			return nil, fmt.Errorf("failed to parse journal: %w", err)
+		}
+	}
+	return j, nil
+}
+
+// prepare stores the snapshots of changes before they are applied. The
+// returned record must be passed to commit once the changes succeeded, or to
+// discard otherwise.
+func (j *journal) prepare(ctx context.Context, op string, changes []journalChange) (*journalRecord, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.index.LastSeq++
+	rec := &journalRecord{Seq: j.index.LastSeq, RunID: journalRunID(ctx), Op: op, Time: time.Now()}
+	for i, c := range changes {
+		f := journalFileRecord{Path: c.path, Existed: c.before.Exists, Size: int64(len(c.before.Content))}
+		if c.after.Exists {
+			f.After = contentHash(c.after.Content)
+		}
+		if c.before.Exists {
+			f.Blob = strconv.FormatInt(rec.Seq, 10) + "-" + strconv.Itoa(i)
+			if err := os.WriteFile(j.blobPath(f.Blob), []byte(c.before.Content), 0600); err != nil {
+				j.removeBlobs(rec)
+				return nil, fmt.Errorf("failed to write journal snapshot: %w", err)
+			}
+		}
+		rec.Files = append(rec.Files, f)
+	}
+	return rec, nil
+}
+
+// commit adds rec to the journal and drops the oldest entries while the
+// journal exceeds its size cap.
+func (j *journal) commit(rec *journalRecord) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.index.Entries = append(j.index.Entries, rec)
+	var total int64
+	for _, e := range j.index.Entries {
+		total += e.size()
+	}
+	for total > j.maxBytes && len(j.index.Entries) > 0 {
+		oldest := j.index.Entries[0]
+		if oldest == rec {
+			log.Printf("[WARN] local: journal snapshot of %s change (%d bytes) exceeds MaxBytes (%d); it cannot be undone", rec.Op, rec.size(), j.maxBytes)
+		}
+		total -= oldest.size()
+		j.removeBlobs(oldest)
+		j.index.Entries = j.index.Entries[1:]
+	}
+	if err := j.save(); err != nil {
+		log.Printf("[WARN] local: failed to save journal: %v", err)
+	}
+}
+
+// discard drops the snapshots of a record whose changes were not applied.
+func (j *journal) discard(rec *journalRecord) {
+	j.removeBlobs(rec)
+}
+
+func (j *journal) entries(runID string) []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var entries []JournalEntry
+	for _, rec := range j.index.Entries {
+		if rec.RunID == runID {
+			entries = append(entries, rec.entry())
+		}
+	}
+	return entries
+}
+
+func (j *journal) lastSeq() int64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.index.LastSeq
+}
+
+// undo restores the files of the newest entries of runID while keep returns
+// false for them, newest first, and returns the entries undone. It stops at
+// the first entry that cannot be undone or when ctx is done. resolve
+// re-validates each path against the current path policy.
+func (j *journal) undo(ctx context.Context, runID string, keep func(*journalRecord) bool, resolve func(string) (string, error)) ([]JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var undone []JournalEntry
+	for i := len(j.index.Entries) - 1; i >= 0; i-- {
+		rec := j.index.Entries[i]
+		if rec.RunID != runID {
+			continue
+		}
+		if keep(rec) {
+			break
+		}
+		if err := j.restore(ctx, rec, resolve); err != nil {
+			return undone, errors.Join(err, j.save())
+		}
+		j.removeBlobs(rec)
+		j.index.Entries = append(j.index.Entries[:i], j.index.Entries[i+1:]...)
+		undone = append(undone, rec.entry())
+	}
+	return undone, j.save()
+}
+
+// restore puts the files of rec back into their state before rec. All files
+// are checked for conflicts before any of them is touched, and the files
+// already restored are put back to their current content if a later one
+// fails or ctx is done in between.
+func (j *journal) restore(ctx context.Context, rec *journalRecord, resolve func(string) (string, error)) error {
+	paths := make([]string, len(rec.Files))
+	currents := make([]patch.FileSnapshot, len(rec.Files))
+	for i, f := range rec.Files {
+		path, err := resolve(f.Path)
+		if err != nil {
+			return err
+		}
+		current, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			if f.After != "" {
+				return fmt.Errorf("%w: %s was deleted", ErrJournalConflict, f.Path)
+			}
+		case err != nil:
+			return fmt.Errorf("failed to read file: %w", err)
+		case f.After == "" || contentHash(string(current)) != f.After:
+			return fmt.Errorf("%w: %s", ErrJournalConflict, f.Path)
+		default:
+			currents[i] = patch.FileSnapshot{Content: string(current), Exists: true}
+		}
+		paths[i] = path
+	}
+
+	for i, f := range rec.Files {
+		err := ctx.Err()
+		if err == nil {
+			err = j.restoreFile(paths[i], f)
+		}
+		if err != nil {
+			for k := i - 1; k >= 0; k-- {
+				if rbErr := putSnapshot(paths[k], currents[k]); rbErr != nil {
+					err = errors.Join(err, fmt.Errorf("failed to roll back %s: %w", rec.Files[k].Path, rbErr))
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreFile puts the file at path back into its state before the change
+// recorded by f.
+func (j *journal) restoreFile(path string, f journalFileRecord) error {
+	if !f.Existed {
+		return putSnapshot(path, patch.FileSnapshot{})
+	}
+	content, err := os.ReadFile(j.blobPath(f.Blob))
+	if err != nil {
+		return fmt.Errorf("failed to read journal snapshot of %s: %w", f.Path, err)
+	}
+	if err := putSnapshot(path, patch.FileSnapshot{Content: string(content), Exists: true}); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", f.Path, err)
+	}
+	return nil
+}
+
+// putSnapshot writes snapshot to path, or removes path when the snapshot does
+// not exist.
+func putSnapshot(path string, snapshot patch.FileSnapshot) error {
+	if !snapshot.Exists {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove file: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	return writeFileAtomic(path, []byte(snapshot.Content))
+}
+
+func (j *journal) clear(runID string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	kept := j.index.Entries[:0]
+	for _, rec := range j.index.Entries {
+		if rec.RunID == runID {
+			j.removeBlobs(rec)
+			continue
+		}
+		kept = append(kept, rec)
+	}
+	j.index.Entries = kept
+	return j.save()
+}
+
+func (j *journal) save() error {
+	data, err := json.Marshal(&j.index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(j.dir, journalIndexFile), data)
+}
+
+func (j *journal) blobPath(name string) string {
+	return filepath.Join(j.dir, journalBlobDir, name)
+}
+
+func (j *journal) removeBlobs(rec *journalRecord) {
+	for _, f := range rec.Files {
+		if f.Blob != "" {
+			_ = os.Remove(j.blobPath(f.Blob))
+		}
+	}
+}
+
+func (rec *journalRecord) size() int64 {
+	var n int64
+	for _, f := range rec.Files {
+		n += f.Size
+	}
+	return n
+}
+
+func (rec *journalRecord) entry() JournalEntry {
+	e := JournalEntry{Seq: rec.Seq, RunID: rec.RunID, Op: rec.Op, Time: rec.Time}
+	for _, f := range rec.Files {
+		e.Files = append(e.Files, JournalFile{Path: f.Path, Existed: f.Existed, Size: f.Size})
+	}
+	return e
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// journaled runs apply, which makes changes, with their snapshots stored in
+// the journal first. It is a no-op wrapper when the journal is disabled.
+func (s *Local) journaled(ctx context.Context, op string, changes []journalChange, apply func() error) error {
+	if s.journal == nil {
+		return apply()
+	}
+	rec, err := s.journal.prepare(ctx, op, changes)
+	if err != nil {
+		return err
+	}
+	if err := apply(); err != nil {
+		s.journal.discard(rec)
+		return err
+	}
+	s.journal.commit(rec)
+	return nil
+}
+
+// readSnapshot returns the current content of path for the journal; a missing
+// file is a snapshot that does not exist.
+func readSnapshot(path string) (patch.FileSnapshot, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return patch.FileSnapshot{}, nil
+	}
+	if err != nil {
+		return patch.FileSnapshot{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	return patch.FileSnapshot{Content: string(content), Exists: true}, nil
+}
+
+// JournalEntries lists the journaled changes of runID, oldest first.
+func (s *Local) JournalEntries(runID string) ([]JournalEntry, error) {
+	if s.journal == nil {
+		return nil, ErrJournalDisabled
+	}
+	return s.journal.entries(runID), nil
+}
+
+// Checkpoint returns a marker for the current state of the journal. Passing
+// it to RestoreCheckpoint later undoes every change a run made after it.
+func (s *Local) Checkpoint() (int64, error) {
+	if s.journal == nil {
+		return 0, ErrJournalDisabled
+	}
+	return s.journal.lastSeq(), nil
+}
+
+// Undo reverts the newest journaled change of runID and removes it from the
+// journal. It fails with ErrJournalConflict, changing nothing, when one of
+// the files was modified after that change, e.g. by a command or another run.
+// If restoring a file fails or ctx is done before every file is restored, the
+// files already restored are put back, so the change is undone whole or not
+// at all.
+func (s *Local) Undo(ctx context.Context, runID string) (*JournalEntry, error) {
+	if s.journal == nil {
+		return nil, ErrJournalDisabled
+	}
+	if s.policy.readOnly {
+		return nil, fmt.Errorf("%w: cannot undo changes", ErrReadOnly)
+	}
+	first := true
+	undone, err := s.journal.undo(ctx, runID, func(*journalRecord) bool {
+		keep := !first
+		first = false
+		return keep
+	}, s.policy.resolveWritable)
+	if err != nil {
+		return nil, err
+	}
+	if len(undone) == 0 {
+		return nil, ErrNothingToUndo
+	}
+	return &undone[0], nil
+}
+
+// RestoreCheckpoint undoes, newest first, every journaled change runID made
+// after checkpoint was taken and returns the changes undone. Each change is
+// undone whole or not at all (see Undo), but the restore as a whole can be
+// partial: it stops at the first change that cannot be undone, or when ctx is
+// done, leaving that change and older ones in place.
+func (s *Local) RestoreCheckpoint(ctx context.Context, runID string, checkpoint int64) ([]JournalEntry, error) {
+	if s.journal == nil {
+		return nil, ErrJournalDisabled
+	}
+	if s.policy.readOnly {
+		return nil, fmt.Errorf("%w: cannot undo changes", ErrReadOnly)
+	}
+	return s.journal.undo(ctx, runID, func(rec *journalRecord) bool {
+		return rec.Seq <= checkpoint
+	}, s.policy.resolveWritable)
+}
+
+// ClearJournal drops the journaled changes of runID, e.g. once they have been
+// reviewed and accepted, and frees their snapshots.
+func (s *Local) ClearJournal(runID string) error {
+	if s.journal == nil {
+		return ErrJournalDisabled
+	}
+	return s.journal.clear(runID)
+}
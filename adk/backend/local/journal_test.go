@@ -0,0 +1,225 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJournaledBackend(t *testing.T, maxBytes int64) (s *Local, root, journalDir string) {
+	t.Helper()
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	root = filepath.Join(base, "repo")
+	journalDir = filepath.Join(base, "journal")
+	require.NoError(t, os.MkdirAll(root, 0755))
+	s, err = NewBackend(context.Background(), &Config{
+		RootDirs: []string{root},
+		Journal:  &JournalConfig{Dir: journalDir, MaxBytes: maxBytes},
+	})
+	require.NoError(t, err)
+	return s, root, journalDir
+}
+
+func readString(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestJournal_Undo(t *testing.T) {
+	s, root, _ := newJournaledBackend(t, 0)
+	ctx := WithJournalRunID(context.Background(), "run-1")
+	path := filepath.Join(root, "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n"), 0644))
+
+	require.NoError(t, s.Write(ctx, &filesystem.WriteRequest{FilePath: "main.go", Content: "package main\n\nfunc main() {}\n"}))
+	require.NoError(t, s.Edit(ctx, &filesystem.EditRequest{FilePath: "main.go", OldString: "main() {}", NewString: "main() { run() }"}))
+	require.NoError(t, s.Write(ctx, &filesystem.WriteRequest{FilePath: "new.go", Content: "package main\n"}))
+
+	entries, err := s.JournalEntries("run-1")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{"write", "edit", "write"}, []string{entries[0].Op, entries[1].Op, entries[2].Op})
+	assert.Equal(t, []JournalFile{{Path: filepath.Join(root, "new.go"), Existed: false, Size: 0}}, entries[2].Files)
+	assert.Equal(t, int64(len("package main\n")), entries[0].Files[0].Size)
+
+	undone, err := s.Undo(ctx, "run-1")
+	require.NoError(t, err)
+	assert.Equal(t, entries[2].Seq, undone.Seq)
+	assert.NoFileExists(t, filepath.Join(root, "new.go"))
+
+	_, err = s.Undo(ctx, "run-1")
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc main() {}\n", readString(t, path))
+
+	_, err = s.Undo(ctx, "run-1")
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", readString(t, path))
+
+	_, err = s.Undo(ctx, "run-1")
+	assert.ErrorIs(t, err, ErrNothingToUndo)
+}
+
+func TestJournal_RestoreCheckpointPerRun(t *testing.T) {
+	s, root, _ := newJournaledBackend(t, 0)
+	run1 := WithJournalRunID(context.Background(), "run-1")
+	run2 := WithJournalRunID(context.Background(), "run-2")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("a0\n"), 0644))
+
+	require.NoError(t, s.Write(run1, &filesystem.WriteRequest{FilePath: "a.txt", Content: "a1\n"}))
+	cp, err := s.Checkpoint()
+	require.NoError(t, err)
+
+	require.NoError(t, s.Write(run1, &filesystem.WriteRequest{FilePath: "a.txt", Content: "a2\n"}))
+	require.NoError(t, s.Write(run2, &filesystem.WriteRequest{FilePath: "b.txt", Content: "b1\n"}))
+	require.NoError(t, s.MultiEdit(run1, &MultiEditRequest{FilePath: "a.txt", Edits: []EditOperation{{OldString: "a2", NewString: "a3"}}}))
+	_, err = s.ApplyPatch(run1, &ApplyPatchRequest{Patch: "--- /dev/null\n+++ c.txt\n@@ -0,0 +1 @@\n+c1\n"})
+	require.NoError(t, err)
+
+	undone, err := s.RestoreCheckpoint(run1, "run-1", cp)
+	require.NoError(t, err)
+	require.Len(t, undone, 3)
+	assert.Equal(t, []string{"apply_patch", "multi_edit", "write"}, []string{undone[0].Op, undone[1].Op, undone[2].Op})
+
+	assert.Equal(t, "a1\n", readString(t, filepath.Join(root, "a.txt")))
+	assert.NoFileExists(t, filepath.Join(root, "c.txt"))
+	// run-2's change is untouched.
+	assert.Equal(t, "b1\n", readString(t, filepath.Join(root, "b.txt")))
+
+	entries, err := s.JournalEntries("run-1")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	entries, err = s.JournalEntries("run-2")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, s.ClearJournal("run-2"))
+	entries, err = s.JournalEntries("run-2")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestJournal_Conflict(t *testing.T) {
+	s, root, _ := newJournaledBackend(t, 0)
+	ctx := context.Background()
+	path := filepath.Join(root, "a.txt")
+
+	require.NoError(t, s.Write(ctx, &filesystem.WriteRequest{FilePath: "a.txt", Content: "agent\n"}))
+	// Someone else changes the file afterwards.
+	require.NoError(t, os.WriteFile(path, []byte("human\n"), 0644))
+
+	_, err := s.Undo(ctx, "")
+	assert.ErrorIs(t, err, ErrJournalConflict)
+	assert.Equal(t, "human\n", readString(t, path))
+
+	entries, err := s.JournalEntries("")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "a conflicting entry stays in the journal")
+}
+
+func TestJournal_UndoRollback(t *testing.T) {
+	s, root, journalDir := newJournaledBackend(t, 0)
+	ctx := context.Background()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("a0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "b.txt"), []byte("b0\n"), 0644))
+	_, err := s.ApplyPatch(ctx, &ApplyPatchRequest{Patch: "--- a.txt\n+++ a.txt\n@@ -1 +1 @@\n-a0\n+a1\n" +
+		"--- b.txt\n+++ b.txt\n@@ -1 +1 @@\n-b0\n+b1\n"})
+	require.NoError(t, err)
+
+	t.Run("context done", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := s.Undo(cancelled, "")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, "a1\n", readString(t, filepath.Join(root, "a.txt")))
+		assert.Equal(t, "b1\n", readString(t, filepath.Join(root, "b.txt")))
+	})
+
+	t.Run("restored files are rolled back", func(t *testing.T) {
+		entries, err := s.JournalEntries("")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		// Losing the snapshot of b.txt makes its restore fail after a.txt was restored.
+		blob := filepath.Join(journalDir, journalBlobDir, strconv.FormatInt(entries[0].Seq, 10)+"-1")
+		require.NoError(t, os.Remove(blob))
+
+		_, err = s.Undo(ctx, "")
+		assert.ErrorContains(t, err, "failed to read journal snapshot")
+		assert.Equal(t, "a1\n", readString(t, filepath.Join(root, "a.txt")))
+		assert.Equal(t, "b1\n", readString(t, filepath.Join(root, "b.txt")))
+
+		entries, err = s.JournalEntries("")
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "an entry that was not undone stays in the journal")
+	})
+}
+
+func TestJournal_SizeCapAndPersistence(t *testing.T) {
+	s, root, journalDir := newJournaledBackend(t, 10)
+	ctx := context.Background()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("12345678"), 0644))
+
+	require.NoError(t, s.Write(ctx, &filesystem.WriteRequest{FilePath: "a.txt", Content: "abcdefgh"}))
+	require.NoError(t, s.Write(ctx, &filesystem.WriteRequest{FilePath: "a.txt", Content: "ABCDEFGH"}))
+
+	// Each snapshot is 8 bytes, so only the newest fits under the 10-byte cap.
+	entries, err := s.JournalEntries("")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	blobs, err := os.ReadDir(filepath.Join(journalDir, journalBlobDir))
+	require.NoError(t, err)
+	assert.Len(t, blobs, 1)
+
+	reopened, err := NewBackend(ctx, &Config{RootDirs: []string{root}, Journal: &JournalConfig{Dir: journalDir, MaxBytes: 10}})
+	require.NoError(t, err)
+	entries, err = reopened.JournalEntries("")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	_, err = reopened.Undo(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefgh", readString(t, filepath.Join(root, "a.txt")))
+
+	// Sequence numbers keep growing across restarts.
+	require.NoError(t, reopened.Write(ctx, &filesystem.WriteRequest{FilePath: "a.txt", Content: "x"}))
+	entries, err = reopened.JournalEntries("")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(3), entries[0].Seq)
+}
+
+func TestJournal_Disabled(t *testing.T) {
+	s, err := NewBackend(context.Background(), &Config{})
+	require.NoError(t, err)
+	_, err = s.JournalEntries("")
+	assert.ErrorIs(t, err, ErrJournalDisabled)
+	_, err = s.Undo(context.Background(), "")
+	assert.ErrorIs(t, err, ErrJournalDisabled)
+
+	_, err = NewBackend(context.Background(), &Config{Journal: &JournalConfig{}})
+	assert.ErrorContains(t, err, "journal dir is required")
+}
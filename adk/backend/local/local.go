@@ -38,6 +38,7 @@ import (
 	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
+	"github.com/cloudwego/eino-ext/adk/backend/patch"
 	"github.com/cloudwego/eino/adk/filesystem"
 	"github.com/cloudwego/eino/schema"
 	"github.com/klippa-app/go-pdfium"
@@ -246,11 +247,51 @@ type Config struct {
 	// Local.MultiModalRead. Optional; zero-value fields fall back to
 	// package defaults (see MultiModalReadConfig field comments).
 	MultiModalRead MultiModalReadConfig
+
+	// RootDirs confines every operation to these directories. Paths are
+	// resolved through symlinks, so a link inside a root cannot reach files
+	// outside of it; relative paths are taken relative to the first root,
+	// which is also the working directory of Execute and ExecuteStreaming.
+	// Optional; when empty, any path on the host is accessible.
+	RootDirs []string
+
+	// DenyGlobs lists doublestar patterns for paths that must never be read,
+	// listed or modified, e.g. ".env", "*.pem" or ".git/". A pattern without a
+	// slash matches a file or directory name at any depth; a pattern with a
+	// slash is matched against the path relative to its root. Denying a
+	// directory denies everything below it. Optional.
+	DenyGlobs []string
+
+	// Exec holds the default working directory, environment, timeout, output
+	// cap and resource limits of Execute and ExecuteStreaming. Use
+	// WithExecConfig to override them for a single call. Optional.
+	Exec ExecConfig
+
+	// ReadOnly rejects Write, Edit, MultiEdit, ApplyPatch, Undo, Execute and
+	// ExecuteStreaming with ErrReadOnly. Commands are rejected as well because a shell command can
+	// modify files regardless of the path policy.
+	ReadOnly bool
+
+	// Journal enables snapshots of files before Write, Edit, MultiEdit and
+	// ApplyPatch change them, so the changes can be listed and undone per
+	// agent run (see WithJournalRunID, Undo and RestoreCheckpoint). Changes
+	// made by commands are not journaled. Optional.
+	Journal *JournalConfig
 }
 
 type Local struct {
 	validateCommand func(string) error
 
+	// policy enforces RootDirs, DenyGlobs and ReadOnly on every operation.
+	policy *pathPolicy
+
+	// execCfg holds the command defaults from Config.Exec.
+	execCfg ExecConfig
+	// background tracks commands started with RunInBackendGround.
+	background *backgroundRegistry
+	// journal records file changes for undo; nil when Config.Journal is unset.
+	journal *journal
+
 	// multiModalReadCfg carries already-resolved (defaults applied, hard-caps
 	// enforced) limits used by MultiModalRead. Every field is guaranteed > 0.
 	multiModalReadCfg MultiModalReadConfig
@@ -277,14 +318,33 @@ func NewBackend(_ context.Context, cfg *Config) (*Local, error) {
 		validateCommand = cfg.ValidateCommand
 	}
 
+	policy, err := newPathPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var j *journal
+	if cfg.Journal != nil {
+		if j, err = newJournal(cfg.Journal); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Local{
 		validateCommand:   validateCommand,
+		policy:            policy,
+		execCfg:           cfg.Exec,
+		background:        newBackgroundRegistry(),
+		journal:           j,
 		multiModalReadCfg: resolveMultiModalReadConfig(cfg.MultiModalRead),
 	}, nil
 }
 
 func (s *Local) LsInfo(ctx context.Context, req *filesystem.LsInfoRequest) ([]filesystem.FileInfo, error) {
-	path := filepath.Clean(req.Path)
+	path, err := s.policy.resolve(req.Path)
+	if err != nil {
+		return nil, err
+	}
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -298,6 +358,9 @@ func (s *Local) LsInfo(ctx context.Context, req *filesystem.LsInfoRequest) ([]fi
 
 	var files []filesystem.FileInfo
 	for _, entry := range entries {
+		if s.policy.denied(filepath.Join(path, entry.Name())) {
+			continue
+		}
 		files = append(files, filesystem.FileInfo{
 			Path: entry.Name(),
 		})
@@ -307,7 +370,10 @@ func (s *Local) LsInfo(ctx context.Context, req *filesystem.LsInfoRequest) ([]fi
 }
 
 func (s *Local) Read(ctx context.Context, req *filesystem.ReadRequest) (*filesystem.FileContent, error) {
-	path := filepath.Clean(req.FilePath)
+	path, err := s.policy.resolve(req.FilePath)
+	if err != nil {
+		return nil, err
+	}
 
 	file, err := os.Open(path)
 	if err != nil {
@@ -388,7 +454,10 @@ func (s *Local) Read(ctx context.Context, req *filesystem.ReadRequest) (*filesys
 // PDF rendering uses github.com/klippa-app/go-pdfium with a WebAssembly backend
 // (no CGO required). The worker pool is initialized lazily on first paged read.
 func (s *Local) MultiModalRead(ctx context.Context, req *filesystem.MultiModalReadRequest) (*filesystem.MultiFileContent, error) {
-	path := filepath.Clean(req.FilePath)
+	path, err := s.policy.resolve(req.FilePath)
+	if err != nil {
+		return nil, err
+	}
 	ext := strings.ToLower(filepath.Ext(path))
 
 	// If the file is not an image or PDF, delegate to the standard Read method.
@@ -424,7 +493,6 @@ func (s *Local) MultiModalRead(ctx context.Context, req *filesystem.MultiModalRe
 	paged := req.Pages != ""
 	var pagedStart, pagedEnd int
 	if paged {
-		var err error
 		pagedStart, pagedEnd, err = parsePagesParam(req.Pages, s.multiModalReadCfg.MaxPDFPagesPerRequest)
 		if err != nil {
 			return nil, err
@@ -759,7 +827,10 @@ func (s *Local) GrepRaw(ctx context.Context, req *filesystem.GrepRequest) ([]fil
 	if req.Pattern == "" {
 		return nil, fmt.Errorf("pattern is required")
 	}
-	path := filepath.Clean(req.Path)
+	path, err := s.policy.resolve(req.Path)
+	if err != nil {
+		return nil, err
+	}
 
 	cmd := []string{"rg", "--json"}
 	if req.CaseInsensitive {
@@ -812,6 +883,9 @@ func (s *Local) GrepRaw(ctx context.Context, req *filesystem.GrepRequest) ([]fil
 		}
 		if data.Type == "match" || data.Type == "context" {
 			matchPath := data.Data.Path.Text
+			if s.policy.denied(matchPath) {
+				continue
+			}
 			if req.FileType != "" && req.Glob != "" {
 				matched, _ := doublestar.Match(req.Glob, matchPath)
 				if !matched {
@@ -833,13 +907,17 @@ func (s *Local) GrepRaw(ctx context.Context, req *filesystem.GrepRequest) ([]fil
 }
 
 func (s *Local) GlobInfo(ctx context.Context, req *filesystem.GlobInfoRequest) ([]filesystem.FileInfo, error) {
-	if req.Path == "" {
-		req.Path = defaultRootPath
+	reqPath := req.Path
+	if reqPath == "" && s.policy.workDir() == "" {
+		reqPath = defaultRootPath
+	}
+	path, err := s.policy.resolve(reqPath)
+	if err != nil {
+		return nil, err
 	}
-	path := filepath.Clean(req.Path)
 
 	var matches []string
-	err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+	err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -864,6 +942,13 @@ func (s *Local) GlobInfo(ctx context.Context, req *filesystem.GlobInfoRequest) (
 			return nil
 		}
 
+		if s.policy.denied(p) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		matched, _ := doublestar.Match(req.Pattern, relPath)
 		if matched {
 			matches = append(matches, relPath)
@@ -889,29 +974,46 @@ func (s *Local) GlobInfo(ctx context.Context, req *filesystem.GlobInfoRequest) (
 }
 
 func (s *Local) Write(ctx context.Context, req *filesystem.WriteRequest) error {
-	path := filepath.Clean(req.FilePath)
-
-	parentDir := filepath.Dir(path)
-	if err := os.MkdirAll(parentDir, 0755); err != nil {
-		return fmt.Errorf("failed to create parent directory: %w", err)
-	}
-
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	path, err := s.policy.resolveWritable(req.FilePath)
 	if err != nil {
-		return fmt.Errorf("failed to open file for writing: %w", err)
+		return err
 	}
-	defer file.Close()
 
-	_, err = file.Write([]byte(req.Content))
-	if err != nil {
-		return fmt.Errorf("failed to write to file: %w", err)
+	var changes []journalChange
+	if s.journal != nil {
+		before, err := readSnapshot(path)
+		if err != nil {
+			return err
+		}
+		changes = []journalChange{{path: path, before: before, after: patch.FileSnapshot{Content: req.Content, Exists: true}}}
 	}
 
-	return nil
+	return s.journaled(ctx, "write", changes, func() error {
+		parentDir := filepath.Dir(path)
+		if err := os.MkdirAll(parentDir, 0755); err != nil {
+			return fmt.Errorf("failed to create parent directory: %w", err)
+		}
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open file for writing: %w", err)
+		}
+		defer file.Close()
+
+		_, err = file.Write([]byte(req.Content))
+		if err != nil {
+			return fmt.Errorf("failed to write to file: %w", err)
+		}
+
+		return nil
+	})
 }
 
 func (s *Local) Edit(ctx context.Context, req *filesystem.EditRequest) error {
-	path := filepath.Clean(req.FilePath)
+	path, err := s.policy.resolveWritable(req.FilePath)
+	if err != nil {
+		return err
+	}
 	if req.OldString == "" {
 		return fmt.Errorf("old string is required")
 	}
@@ -942,7 +1044,188 @@ func (s *Local) Edit(ctx context.Context, req *filesystem.EditRequest) error {
 		newText = strings.Replace(text, req.OldString, req.NewString, 1)
 	}
 
-	return os.WriteFile(path, []byte(newText), 0644)
+	changes := []journalChange{{path: path, before: patch.FileSnapshot{Content: text, Exists: true}, after: patch.FileSnapshot{Content: newText, Exists: true}}}
+	return s.journaled(ctx, "edit", changes, func() error {
+		return os.WriteFile(path, []byte(newText), 0644)
+	})
+}
+
+// MultiEdit applies req.Edits to one file in order, each to the result of the
+// previous one. The file is replaced atomically and only when every edit
+// applies; otherwise an *EditError names the failed edit and the file is left
+// untouched.
+func (s *Local) MultiEdit(ctx context.Context, req *MultiEditRequest) error {
+	path, err := s.policy.resolveWritable(req.FilePath)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	newText, err := patch.ApplyEdits(req.FilePath, string(content), req.Edits)
+	if err != nil {
+		return err
+	}
+
+	changes := []journalChange{{path: path, before: patch.FileSnapshot{Content: string(content), Exists: true}, after: patch.FileSnapshot{Content: newText, Exists: true}}}
+	return s.journaled(ctx, "multi_edit", changes, func() error {
+		return writeFileAtomic(path, []byte(newText))
+	})
+}
+
+// ApplyPatch applies a unified diff that may span several files. Every hunk is
+// applied in memory first; files are only written when all of them match, and
+// if writing fails part-way the files already replaced are restored. A hunk
+// that does not match is reported as a *PatchError.
+func (s *Local) ApplyPatch(ctx context.Context, req *ApplyPatchRequest) (*ApplyPatchResult, error) {
+	if s.policy.readOnly {
+		return nil, fmt.Errorf("%w: cannot apply patch", ErrReadOnly)
+	}
+
+	files, err := patch.Parse(req.Patch)
+	if err != nil {
+		return nil, err
+	}
+	patch.ResolvePaths(files, func(p string) string { return joinPatchPath(req.BaseDir, p) })
+
+	resolved := make(map[string]string)
+	report, writes, err := patch.Plan(files, func(p string) (patch.FileSnapshot, error) {
+		path, err := s.policy.resolveWritable(p)
+		if err != nil {
+			return patch.FileSnapshot{}, err
+		}
+		resolved[p] = path
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return patch.FileSnapshot{}, nil
+		}
+		if err != nil {
+			return patch.FileSnapshot{}, fmt.Errorf("failed to read file: %w", err)
+		}
+		return patch.FileSnapshot{Content: string(content), Exists: true}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]journalChange, len(writes))
+	for i, w := range writes {
+		changes[i] = journalChange{path: resolved[w.Path], before: w.Before, after: w.After}
+	}
+	err = s.journaled(ctx, "apply_patch", changes, func() error {
+		return commitPatch(writes, resolved)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ApplyPatchResult{Files: report}, nil
+}
+
+// commitPatch stages every new file content in a temporary file next to its
+// target, then renames the staged files into place and removes deleted files.
+// If a step fails, the targets already changed are restored to their original
+// content.
+func commitPatch(writes []*patch.FileWrite, resolved map[string]string) (err error) {
+	staged := make([]string, len(writes))
+	defer func() {
+		for _, tmp := range staged {
+			if tmp != "" {
+				_ = os.Remove(tmp)
+			}
+		}
+	}()
+
+	for i, w := range writes {
+		if !w.After.Exists {
+			continue
+		}
+		path := resolved[w.Path]
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create parent directory: %w", err)
+		}
+		tmp, err := stageFile(path, []byte(w.After.Content))
+		if err != nil {
+			return err
+		}
+		staged[i] = tmp
+	}
+
+	for i, w := range writes {
+		path := resolved[w.Path]
+		var opErr error
+		if w.Deleted {
+			opErr = os.Remove(path)
+		} else {
+			opErr = os.Rename(staged[i], path)
+			staged[i] = ""
+		}
+		if opErr != nil {
+			return errors.Join(fmt.Errorf("failed to apply patch to %s: %w", w.Path, opErr), rollbackPatch(writes[:i], resolved))
+		}
+	}
+	return nil
+}
+
+// rollbackPatch restores the original state of the targets in writes.
+func rollbackPatch(writes []*patch.FileWrite, resolved map[string]string) error {
+	var errs []error
+	for i := len(writes) - 1; i >= 0; i-- {
+		w := writes[i]
+		path := resolved[w.Path]
+		var err error
+		if w.Before.Exists {
+			err = writeFileAtomic(path, []byte(w.Before.Content))
+		} else {
+			err = os.Remove(path)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", w.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeFileAtomic replaces path with data through a temporary file and a
+// rename, so readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := stageFile(path, data)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
+
+// stageFile writes data to a temporary file in the directory of path, with the
+// permissions of path when it exists, and returns the temporary file's name.
+func stageFile(path string, data []byte) (string, error) {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	_, err = f.Write(data)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), mode)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	return f.Name(), nil
 }
 
 func (s *Local) ExecuteStreaming(ctx context.Context, input *filesystem.ExecuteRequest) (result *schema.StreamReader[*filesystem.ExecuteResponse], err error) {
@@ -950,30 +1233,53 @@ func (s *Local) ExecuteStreaming(ctx context.Context, input *filesystem.ExecuteR
 		return nil, fmt.Errorf("command is required")
 	}
 
+	if s.policy.readOnly {
+		return nil, fmt.Errorf("%w: cannot execute commands", ErrReadOnly)
+	}
+
 	if err := s.validateCommand(input.Command); err != nil {
 		return nil, err
 	}
 
-	cmd, stdout, stderr, err := s.initStreamingCmd(ctx, input.Command)
+	cfg := s.execConfig(ctx)
+	sr, w := schema.Pipe[*filesystem.ExecuteResponse](100)
+
+	if input.RunInBackendGround {
+		p, err := s.startBackground(ctx, input.Command, &cfg)
+		if err != nil {
+			go sendErrorAndClose(w, err)
+			return sr, nil
+		}
+		go func() {
+			defer w.Close()
+			w.Send(&filesystem.ExecuteResponse{
+				Output:   fmt.Sprintf("command started in background with id %s (pid %d)\n", p.id, p.cmd.Process.Pid),
+				ExitCode: new(int),
+			}, nil)
+		}()
+		return sr, nil
+	}
+
+	runCtx, cancel := withExecTimeout(ctx, cfg.Timeout)
+	cmd, stdout, stderr, err := s.initStreamingCmd(runCtx, input.Command, &cfg)
 	if err != nil {
+		cancel()
+		w.Close()
 		return nil, err
 	}
 
-	sr, w := schema.Pipe[*filesystem.ExecuteResponse](100)
-
 	if err := cmd.Start(); err != nil {
+		cancel()
 		_ = stdout.Close()
 		_ = stderr.Close()
 		go sendErrorAndClose(w, fmt.Errorf("failed to start command: %w", err))
 		return sr, nil
 	}
 
-	if input.RunInBackendGround {
-		s.runCmdInBackground(ctx, cmd, stdout, stderr, w)
-		return sr, nil
-	}
-
-	go s.streamCmdOutput(ctx, cmd, stdout, stderr, w)
+	go func() {
+		defer cancel()
+		s.streamCmdOutput(ctx, runCtx, cmd, stdout, stderr, &cfg, w)
+	}()
 
 	return sr, nil
 }
@@ -983,20 +1289,53 @@ func (s *Local) Execute(ctx context.Context, input *filesystem.ExecuteRequest) (
 		return nil, fmt.Errorf("command is required")
 	}
 
+	if s.policy.readOnly {
+		return nil, fmt.Errorf("%w: cannot execute commands", ErrReadOnly)
+	}
+
 	if err := s.validateCommand(input.Command); err != nil {
 		return nil, err
 	}
 
-	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", input.Command)
+	cfg := s.execConfig(ctx)
+	runCtx, cancel := withExecTimeout(ctx, cfg.Timeout)
+	defer cancel()
 
-	var stdoutBuf, stderrBuf strings.Builder
-	cmd.Stdout = &stdoutBuf
-	cmd.Stderr = &stderrBuf
+	cmd, err := s.newCmd(runCtx, input.Command, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	stdoutBuf := newCappedBuffer(cfg.MaxOutputBytes)
+	stderrBuf := newCappedBuffer(cfg.MaxOutputBytes)
+	cmd.Stdout = stdoutBuf
+	cmd.Stderr = stderrBuf
+	truncated := func() bool { return stdoutBuf.truncated() || stderrBuf.truncated() }
 
 	exitCode := 0
-	if err := cmd.Run(); err != nil {
+	runErr := cmd.Run()
+	if timedOut(ctx, runCtx) {
+		exitCode = -1
 		var exitError *exec.ExitError
-		if errors.As(err, &exitError) {
+		if errors.As(runErr, &exitError) {
+			exitCode = exitError.ExitCode()
+		}
+		parts := []string{fmt.Sprintf("command timed out after %s", cfg.Timeout)}
+		if stdoutStr := stdoutBuf.String(); stdoutStr != "" {
+			parts = append(parts, "[stdout]:\n"+stdoutStr)
+		}
+		if stderrStr := stderrBuf.String(); stderrStr != "" {
+			parts = append(parts, "[stderr]:\n"+stderrStr)
+		}
+		return &filesystem.ExecuteResponse{
+			Output:    strings.Join(parts, "\n"),
+			ExitCode:  &exitCode,
+			Truncated: truncated(),
+		}, nil
+	}
+	if runErr != nil {
+		var exitError *exec.ExitError
+		if errors.As(runErr, &exitError) {
 			exitCode = exitError.ExitCode()
 			stdoutStr := stdoutBuf.String()
 			stderrStr := stderrBuf.String()
@@ -1008,22 +1347,41 @@ func (s *Local) Execute(ctx context.Context, input *filesystem.ExecuteRequest) (
 				parts = append(parts, "[stderr]:\n"+strings.TrimSuffix(stderrStr, ""))
 			}
 			return &filesystem.ExecuteResponse{
-				Output:   strings.Join(parts, "\n"),
-				ExitCode: &exitCode,
+				Output:    strings.Join(parts, "\n"),
+				ExitCode:  &exitCode,
+				Truncated: truncated(),
 			}, nil
 		}
-		return nil, fmt.Errorf("failed to execute command: %w", err)
+		return nil, fmt.Errorf("failed to execute command: %w", runErr)
 	}
 
 	return &filesystem.ExecuteResponse{
-		Output:   stdoutBuf.String(),
-		ExitCode: &exitCode,
+		Output:    stdoutBuf.String(),
+		ExitCode:  &exitCode,
+		Truncated: stdoutBuf.truncated(),
 	}, nil
 }
 
+// withExecTimeout derives the context a foreground command runs under.
+func withExecTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timedOut reports whether runCtx, derived by withExecTimeout, ended because
+// of the command timeout rather than because ctx was cancelled.
+func timedOut(ctx, runCtx context.Context) bool {
+	return ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded)
+}
+
 // initStreamingCmd creates command with stdout and stderr pipes.
-func (s *Local) initStreamingCmd(ctx context.Context, command string) (*exec.Cmd, io.ReadCloser, io.ReadCloser, error) {
-	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+func (s *Local) initStreamingCmd(ctx context.Context, command string, cfg *ExecConfig) (*exec.Cmd, io.ReadCloser, io.ReadCloser, error) {
+	cmd, err := s.newCmd(ctx, command, cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -1039,55 +1397,9 @@ func (s *Local) initStreamingCmd(ctx context.Context, command string) (*exec.Cmd
 	return cmd, stdout, stderr, nil
 }
 
-// runCmdInBackground executes command in background without waiting for completion.
-// The caller controls timeout/cancellation via ctx.Done().
-func (s *Local) runCmdInBackground(ctx context.Context, cmd *exec.Cmd, stdout, stderr io.ReadCloser, w *schema.StreamWriter[*filesystem.ExecuteResponse]) {
-	go func() {
-		defer func() {
-			if pe := recover(); pe != nil {
-				_ = cmd.Process.Kill()
-			}
-			_ = stdout.Close()
-			_ = stderr.Close()
-		}()
-
-		done := make(chan struct{})
-		go func() {
-			drainPipesConcurrently(stdout, stderr)
-			_ = cmd.Wait()
-			close(done)
-		}()
-
-		select {
-		case <-done:
-		case <-ctx.Done():
-			_ = cmd.Process.Kill()
-		}
-	}()
-
-	go func() {
-		defer w.Close()
-		w.Send(&filesystem.ExecuteResponse{Output: "command started in background\n", ExitCode: new(int)}, nil)
-	}()
-}
-
-// drainPipesConcurrently consumes stdout and stderr concurrently to prevent pipe blocking.
-func drainPipesConcurrently(stdout, stderr io.Reader) {
-	var wg sync.WaitGroup
-	wg.Add(2)
-	go func() {
-		defer wg.Done()
-		_, _ = io.Copy(io.Discard, stdout)
-	}()
-	go func() {
-		defer wg.Done()
-		_, _ = io.Copy(io.Discard, stderr)
-	}()
-	wg.Wait()
-}
-
-// streamCmdOutput handles streaming command output to the writer.
-func (s *Local) streamCmdOutput(ctx context.Context, cmd *exec.Cmd, stdout, stderr io.ReadCloser, w *schema.StreamWriter[*filesystem.ExecuteResponse]) {
+// streamCmdOutput handles streaming command output to the writer. ctx is the
+// caller's context and runCtx the one carrying the command timeout.
+func (s *Local) streamCmdOutput(ctx, runCtx context.Context, cmd *exec.Cmd, stdout, stderr io.ReadCloser, cfg *ExecConfig, w *schema.StreamWriter[*filesystem.ExecuteResponse]) {
 	defer func() {
 		if pe := recover(); pe != nil {
 			w.Send(nil, newPanicErr(pe, debug.Stack()))
@@ -1096,9 +1408,9 @@ func (s *Local) streamCmdOutput(ctx context.Context, cmd *exec.Cmd, stdout, stde
 		w.Close()
 	}()
 
-	stderrData, stderrErr := s.readStderrAsync(stderr)
+	stderrData, stderrErr := s.readStderrAsync(stderr, cfg.MaxOutputBytes)
 
-	hasOutput, err := s.streamStdout(ctx, cmd, stdout, w)
+	hasOutput, err := s.streamStdout(ctx, cmd, stdout, cfg.MaxOutputBytes, w)
 	if err != nil {
 		w.Send(nil, err)
 		return
@@ -1109,12 +1421,23 @@ func (s *Local) streamCmdOutput(ctx context.Context, cmd *exec.Cmd, stdout, stde
 		return
 	}
 
-	s.handleCmdCompletion(cmd, stderrData, hasOutput, w)
+	s.handleCmdCompletion(cmd, stderrData, hasOutput, w, timeoutOf(ctx, runCtx, cfg.Timeout))
+}
+
+// timeoutOf returns timeout if the command run under runCtx timed out, else 0.
+func timeoutOf(ctx, runCtx context.Context, timeout time.Duration) func() time.Duration {
+	return func() time.Duration {
+		if timedOut(ctx, runCtx) {
+			return timeout
+		}
+		return 0
+	}
 }
 
-// readStderrAsync reads stderr in a separate goroutine.
-func (s *Local) readStderrAsync(stderr io.Reader) (*[]byte, <-chan error) {
-	stderrData := new([]byte)
+// readStderrAsync reads stderr in a separate goroutine, keeping at most
+// maxBytes of it when maxBytes is positive.
+func (s *Local) readStderrAsync(stderr io.Reader, maxBytes int) (*cappedBuffer, <-chan error) {
+	stderrData := newCappedBuffer(maxBytes)
 	stderrErr := make(chan error, 1)
 
 	go func() {
@@ -1125,9 +1448,7 @@ func (s *Local) readStderrAsync(stderr io.Reader) (*[]byte, <-chan error) {
 			}
 			close(stderrErr)
 		}()
-		var err error
-		*stderrData, err = io.ReadAll(stderr)
-		if err != nil {
+		if _, err := io.Copy(stderrData, stderr); err != nil {
 			stderrErr <- fmt.Errorf("failed to read stderr: %w", err)
 		}
 	}()
@@ -1135,21 +1456,33 @@ func (s *Local) readStderrAsync(stderr io.Reader) (*[]byte, <-chan error) {
 	return stderrData, stderrErr
 }
 
-// streamStdout streams stdout line by line to the writer.
-func (s *Local) streamStdout(ctx context.Context, cmd *exec.Cmd, stdout io.Reader, w *schema.StreamWriter[*filesystem.ExecuteResponse]) (bool, error) {
+// streamStdout streams stdout line by line to the writer. Once maxBytes (when
+// positive) have been sent, the rest is drained and reported by a single
+// truncation marker.
+func (s *Local) streamStdout(ctx context.Context, cmd *exec.Cmd, stdout io.Reader, maxBytes int, w *schema.StreamWriter[*filesystem.ExecuteResponse]) (bool, error) {
 	reader := bufio.NewReader(stdout)
 	hasOutput := false
+	sent := 0
+	var dropped int64
 
 	for {
 		line, err := reader.ReadString('\n')
 		if line != "" {
 			hasOutput = true
-			select {
-			case <-ctx.Done():
-				_ = cmd.Process.Kill()
-				return hasOutput, ctx.Err()
-			default:
-				w.Send(&filesystem.ExecuteResponse{Output: line}, nil)
+			if maxBytes > 0 && sent+len(line) > maxBytes {
+				keep := runeBoundary([]byte(line), maxBytes-sent)
+				dropped += int64(len(line) - keep)
+				line = line[:keep]
+			}
+			if line != "" {
+				sent += len(line)
+				select {
+				case <-ctx.Done():
+					_ = killProcessGroup(cmd)
+					return hasOutput, ctx.Err()
+				default:
+					w.Send(&filesystem.ExecuteResponse{Output: line}, nil)
+				}
 			}
 		}
 		if err != nil {
@@ -1160,22 +1493,44 @@ func (s *Local) streamStdout(ctx context.Context, cmd *exec.Cmd, stdout io.Reade
 		}
 	}
 
+	if dropped > 0 {
+		w.Send(&filesystem.ExecuteResponse{Output: truncationMarker(dropped), Truncated: true}, nil)
+	}
 	return hasOutput, nil
 }
 
 // handleCmdCompletion handles command completion and sends final response.
-func (s *Local) handleCmdCompletion(cmd *exec.Cmd, stderrData *[]byte, hasOutput bool, w *schema.StreamWriter[*filesystem.ExecuteResponse]) {
-	if err := cmd.Wait(); err != nil {
+func (s *Local) handleCmdCompletion(cmd *exec.Cmd, stderrData *cappedBuffer, hasOutput bool, w *schema.StreamWriter[*filesystem.ExecuteResponse], timeout func() time.Duration) {
+	err := cmd.Wait()
+	if d := timeout(); d > 0 {
+		exitCode := -1
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			exitCode = exitError.ExitCode()
+		}
+		parts := []string{fmt.Sprintf("command timed out after %s", d)}
+		if stderrStr := stderrData.String(); stderrStr != "" {
+			parts = append(parts, "[stderr]:\n"+stderrStr)
+		}
+		w.Send(&filesystem.ExecuteResponse{
+			Output:    strings.Join(parts, "\n"),
+			ExitCode:  &exitCode,
+			Truncated: stderrData.truncated(),
+		}, nil)
+		return
+	}
+	if err != nil {
 		var exitError *exec.ExitError
 		if errors.As(err, &exitError) {
 			exitCode := exitError.ExitCode()
 			parts := []string{fmt.Sprintf("command exited with non-zero code %d", exitCode)}
-			if stderrStr := string(*stderrData); stderrStr != "" {
+			if stderrStr := stderrData.String(); stderrStr != "" {
 				parts = append(parts, "[stderr]:\n"+stderrStr)
 			}
 			w.Send(&filesystem.ExecuteResponse{
-				Output:   strings.Join(parts, "\n"),
-				ExitCode: &exitCode,
+				Output:    strings.Join(parts, "\n"),
+				ExitCode:  &exitCode,
+				Truncated: stderrData.truncated(),
 			}, nil)
 			return
 		}
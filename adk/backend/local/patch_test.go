@@ -0,0 +1,230 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/adk/backend/patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiEdit(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	original := "package main\n\nfunc a() {}\nfunc b() {}\n"
+	require.NoError(t, os.WriteFile(path, []byte(original), 0600))
+
+	s, err := NewBackend(ctx, &Config{})
+	require.NoError(t, err)
+
+	err = s.MultiEdit(ctx, &MultiEditRequest{FilePath: path, Edits: []EditOperation{
+		{OldString: "func a()", NewString: "func alpha()"},
+		{OldString: "func b() {}", NewString: "func beta() { alpha() }"},
+		{OldString: "alpha", NewString: "first", ReplaceAll: true},
+	}})
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc first() {}\nfunc beta() { first() }\n", string(data))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	t.Run("a failing edit leaves the file untouched", func(t *testing.T) {
+		before, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		err = s.MultiEdit(ctx, &MultiEditRequest{FilePath: path, Edits: []EditOperation{
+			{OldString: "beta", NewString: "second"},
+			{OldString: "missing", NewString: "x"},
+		}})
+		var eErr *EditError
+		require.True(t, errors.As(err, &eErr))
+		assert.Equal(t, 1, eErr.Index)
+		assert.ErrorIs(t, err, ErrOldStringNotFound)
+
+		err = s.MultiEdit(ctx, &MultiEditRequest{FilePath: path, Edits: []EditOperation{{OldString: "func", NewString: "fn"}}})
+		assert.ErrorIs(t, err, ErrAmbiguousReplace)
+
+		after, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+
+	t.Run("respects the path policy", func(t *testing.T) {
+		ro, err := NewBackend(ctx, &Config{ReadOnly: true})
+		require.NoError(t, err)
+		err = ro.MultiEdit(ctx, &MultiEditRequest{FilePath: path, Edits: []EditOperation{{OldString: "beta", NewString: "x"}}})
+		assert.ErrorIs(t, err, ErrReadOnly)
+	})
+}
+
+func TestApplyPatch(t *testing.T) {
+	ctx := context.Background()
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	s, err := NewBackend(ctx, &Config{RootDirs: []string{root}})
+	require.NoError(t, err)
+
+	t.Run("multi-file patch", func(t *testing.T) {
+		write("a.txt", "one\ntwo\nthree\n")
+		write("b.txt", "alpha\nbeta\n")
+		write("gone.txt", "bye\n")
+		write("from.txt", "moved\n")
+
+		res, err := s.ApplyPatch(ctx, &ApplyPatchRequest{Patch: `--- a/a.txt
++++ b/a.txt
+@@ -2 +2 @@
+-two
++2
+--- a/b.txt
++++ b/b.txt
+@@ -1,2 +1,3 @@
+ alpha
+ beta
++gamma
+--- /dev/null
++++ b/dir/new.txt
+@@ -0,0 +1 @@
++created
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+--- a/from.txt
++++ b/to.txt
+@@ -1 +1 @@
+-moved
++renamed
+`})
+		require.NoError(t, err)
+		assert.Equal(t, []PatchedFile{
+			{Path: "a.txt", Op: PatchFileModified, Hunks: 1},
+			{Path: "b.txt", Op: PatchFileModified, Hunks: 1},
+			{Path: "dir/new.txt", Op: PatchFileCreated, Hunks: 1},
+			{Path: "gone.txt", Op: PatchFileDeleted, Hunks: 1},
+			{Path: "to.txt", OldPath: "from.txt", Op: PatchFileRenamed, Hunks: 1},
+		}, res.Files)
+
+		assert.Equal(t, "one\n2\nthree\n", read("a.txt"))
+		assert.Equal(t, "alpha\nbeta\ngamma\n", read("b.txt"))
+		assert.Equal(t, "created\n", read("dir/new.txt"))
+		assert.Equal(t, "renamed\n", read("to.txt"))
+		assert.NoFileExists(t, filepath.Join(root, "gone.txt"))
+		assert.NoFileExists(t, filepath.Join(root, "from.txt"))
+	})
+
+	t.Run("a failing hunk changes nothing", func(t *testing.T) {
+		write("x.txt", "x1\nx2\n")
+		write("y.txt", "y1\ny2\n")
+
+		_, err := s.ApplyPatch(ctx, &ApplyPatchRequest{Patch: `--- x.txt
++++ x.txt
+@@ -1 +1 @@
+-x1
++X1
+--- y.txt
++++ y.txt
+@@ -1 +1 @@
+-y1
++Y1
+@@ -2 +2 @@
+-nope
++NOPE
+`})
+		var pErr *PatchError
+		require.True(t, errors.As(err, &pErr))
+		assert.Equal(t, "y.txt", pErr.FilePath)
+		assert.Equal(t, 2, pErr.Hunk)
+		assert.ErrorIs(t, err, ErrHunkMismatch)
+
+		assert.Equal(t, "x1\nx2\n", read("x.txt"))
+		assert.Equal(t, "y1\ny2\n", read("y.txt"))
+	})
+
+	t.Run("file-level errors", func(t *testing.T) {
+		write("exists.txt", "e\n")
+		_, err := s.ApplyPatch(ctx, &ApplyPatchRequest{Patch: "--- /dev/null\n+++ exists.txt\n@@ -0,0 +1 @@\n+e\n"})
+		assert.ErrorContains(t, err, "already exists")
+
+		_, err = s.ApplyPatch(ctx, &ApplyPatchRequest{Patch: "--- missing.txt\n+++ missing.txt\n@@ -1 +1 @@\n-a\n+b\n"})
+		assert.ErrorContains(t, err, "does not exist")
+
+		_, err = s.ApplyPatch(ctx, &ApplyPatchRequest{Patch: "--- /etc/passwd\n+++ /etc/passwd\n@@ -1 +1 @@\n-a\n+b\n"})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+	})
+
+	t.Run("base dir", func(t *testing.T) {
+		write("sub/c.txt", "c\n")
+		_, err := s.ApplyPatch(ctx, &ApplyPatchRequest{BaseDir: "sub", Patch: "--- c.txt\n+++ c.txt\n@@ -1 +1 @@\n-c\n+C\n"})
+		require.NoError(t, err)
+		assert.Equal(t, "C\n", read("sub/c.txt"))
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		ro, err := NewBackend(ctx, &Config{RootDirs: []string{root}, ReadOnly: true})
+		require.NoError(t, err)
+		_, err = ro.ApplyPatch(ctx, &ApplyPatchRequest{Patch: "--- a.txt\n+++ a.txt\n@@ -1 +1 @@\n-one\n+1\n"})
+		assert.ErrorIs(t, err, ErrReadOnly)
+	})
+}
+
+func TestCommitPatch_RollsBackOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	modified := filepath.Join(dir, "modified.txt")
+	created := filepath.Join(dir, "created.txt")
+	busy := filepath.Join(dir, "busy")
+	require.NoError(t, os.WriteFile(modified, []byte("before\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(busy, "child"), 0755))
+
+	// Removing a non-empty directory fails after the first two writes landed.
+	writes := []*patch.FileWrite{
+		{Path: modified, Before: patch.FileSnapshot{Content: "before\n", Exists: true}, After: patch.FileSnapshot{Content: "after\n", Exists: true}},
+		{Path: created, After: patch.FileSnapshot{Content: "new\n", Exists: true}},
+		{Path: busy, Before: patch.FileSnapshot{Exists: true}, Deleted: true},
+	}
+	resolved := map[string]string{modified: modified, created: created, busy: busy}
+
+	err := commitPatch(writes, resolved)
+	require.Error(t, err)
+
+	data, err := os.ReadFile(modified)
+	require.NoError(t, err)
+	assert.Equal(t, "before\n", string(data))
+	assert.NoFileExists(t, created)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "temporary files must be cleaned up")
+}
@@ -0,0 +1,249 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+var (
+	// ErrPathOutsideRoot is returned when a path resolves, after following
+	// symlinks, to a location outside every Config.RootDirs entry.
+	ErrPathOutsideRoot = errors.New("path is outside the allowed root directories")
+	// ErrPathDenied is returned when a path matches one of Config.DenyGlobs.
+	ErrPathDenied = errors.New("path is denied by policy")
+	// ErrReadOnly is returned by every operation that modifies files or runs
+	// commands when Config.ReadOnly is set.
+	ErrReadOnly = errors.New("backend is read-only")
+)
+
+// root is one allowed root directory, kept both as configured (made absolute)
+// and with symlinks resolved, so that deny globs can be matched against either
+// spelling of a path.
+type root struct {
+	path string
+	real string
+}
+
+// pathPolicy enforces Config.RootDirs, Config.DenyGlobs and Config.ReadOnly.
+// The zero value allows everything, which keeps the historical behavior.
+type pathPolicy struct {
+	roots     []root
+	denyGlobs []string
+	readOnly  bool
+}
+
+func newPathPolicy(cfg *Config) (*pathPolicy, error) {
+	p := &pathPolicy{readOnly: cfg.ReadOnly}
+	for _, dir := range cfg.RootDirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root dir %q: %w", dir, err)
+		}
+		real, err := filepath.EvalSymlinks(abs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root dir %q: %w", dir, err)
+		}
+		info, err := os.Stat(real)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root dir %q: %w", dir, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("invalid root dir %q: not a directory", dir)
+		}
+		p.roots = append(p.roots, root{path: abs, real: real})
+	}
+	for _, pattern := range cfg.DenyGlobs {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if pattern == "" || !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("invalid deny glob %q", pattern)
+		}
+		p.denyGlobs = append(p.denyGlobs, pattern)
+	}
+	return p, nil
+}
+
+// confined reports whether the policy restricts paths at all. When it does
+// not, paths are only cleaned, exactly as before the policy existed.
+func (p *pathPolicy) confined() bool {
+	return len(p.roots) > 0 || len(p.denyGlobs) > 0
+}
+
+// resolve validates path and returns the path to operate on. Relative paths
+// (and the empty path) are taken relative to the first root. Symlinks are
+// resolved, also for paths whose trailing components do not exist yet, and
+// the result must lie inside a root; both the requested and the resolved path
+// are checked against the deny globs, so a symlink can neither escape a root
+// nor expose a denied file under another name.
+func (p *pathPolicy) resolve(path string) (string, error) {
+	if !p.confined() {
+		return filepath.Clean(path), nil
+	}
+
+	clean := path
+	if !filepath.IsAbs(clean) {
+		base := "."
+		if len(p.roots) > 0 {
+			base = p.roots[0].path
+		}
+		clean = filepath.Join(base, clean)
+	}
+	clean, err := filepath.Abs(clean)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %w", path, err)
+	}
+
+	real, err := evalSymlinksAllowMissing(clean)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	if len(p.roots) > 0 && p.relToRoot(real, true) == "" {
+		return "", fmt.Errorf("%w: %s", ErrPathOutsideRoot, path)
+	}
+	if p.denied(clean) || p.denied(real) {
+		return "", fmt.Errorf("%w: %s", ErrPathDenied, path)
+	}
+	return real, nil
+}
+
+// resolveWritable is resolve for operations that modify the filesystem.
+func (p *pathPolicy) resolveWritable(path string) (string, error) {
+	if p.readOnly {
+		return "", fmt.Errorf("%w: cannot modify %s", ErrReadOnly, path)
+	}
+	return p.resolve(path)
+}
+
+// workDir returns the working directory for commands: the first root, or ""
+// (the process working directory) when no roots are configured.
+func (p *pathPolicy) workDir() string {
+	if len(p.roots) == 0 {
+		return ""
+	}
+	return p.roots[0].real
+}
+
+// denied reports whether the absolute path abs, or any of its ancestors below
+// its root, matches a deny glob. A glob without a slash matches a single name
+// at any depth (".env", "*.pem"); a glob with a slash is matched against the
+// slash-separated path relative to the root ("config/prod/**"). Without roots,
+// paths are taken relative to the filesystem root.
+func (p *pathPolicy) denied(abs string) bool {
+	if len(p.denyGlobs) == 0 {
+		return false
+	}
+	rel := p.relToRoot(abs, false)
+	if rel == "" {
+		rel = strings.TrimPrefix(filepath.ToSlash(abs), "/")
+	}
+	if rel == "." || rel == "" {
+		return false
+	}
+
+	elems := strings.Split(rel, "/")
+	for _, pattern := range p.denyGlobs {
+		anchored := strings.Contains(pattern, "/")
+		for i := range elems {
+			subject := elems[i]
+			if anchored {
+				subject = strings.Join(elems[:i+1], "/")
+			}
+			if ok, _ := doublestar.Match(pattern, subject); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// relToRoot returns abs relative to the first root containing it, as a
+// slash-separated path ("." for the root itself), or "" when no root contains
+// it. With realOnly set, only the symlink-resolved roots are considered.
+func (p *pathPolicy) relToRoot(abs string, realOnly bool) string {
+	for _, r := range p.roots {
+		candidates := []string{r.real}
+		if !realOnly && r.path != r.real {
+			candidates = append(candidates, r.path)
+		}
+		for _, base := range candidates {
+			rel, err := filepath.Rel(base, abs)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			return filepath.ToSlash(rel)
+		}
+	}
+	return ""
+}
+
+// maxDanglingLinks bounds how many dangling symlinks evalSymlinksAllowMissing
+// follows by hand before giving up.
+const maxDanglingLinks = 255
+
+// evalSymlinksAllowMissing is filepath.EvalSymlinks for paths whose trailing
+// components may not exist yet (e.g. the target of Write). The longest
+// existing prefix is resolved and the missing components are appended. A
+// dangling symlink is followed to its (missing) target, since creating a file
+// through it would create the target.
+func evalSymlinksAllowMissing(path string) (string, error) {
+	return evalSymlinksDepth(path, 0)
+}
+
+func evalSymlinksDepth(path string, depth int) (string, error) {
+	var missing []string
+	cur := path
+	for {
+		real, err := filepath.EvalSymlinks(cur)
+		if err == nil {
+			for i := len(missing) - 1; i >= 0; i-- {
+				real = filepath.Join(real, missing[i])
+			}
+			return real, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		if info, lErr := os.Lstat(cur); lErr == nil && info.Mode()&os.ModeSymlink != 0 {
+			if depth >= maxDanglingLinks {
+				return "", fmt.Errorf("too many links: %s", path)
+			}
+			target, err := os.Readlink(cur)
+			if err != nil {
+				return "", err
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(cur), target)
+			}
+			for i := len(missing) - 1; i >= 0; i-- {
+				target = filepath.Join(target, missing[i])
+			}
+			return evalSymlinksDepth(target, depth+1)
+		}
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return path, nil
+		}
+		missing = append(missing, filepath.Base(cur))
+		cur = parent
+	}
+}
@@ -0,0 +1,241 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPolicyDirs creates a root with a few files and an unrelated directory
+// outside of it. Both paths are returned with symlinks resolved.
+func setupPolicyDirs(t *testing.T) (root, outside string) {
+	t.Helper()
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	root = filepath.Join(base, "root")
+	outside = filepath.Join(base, "outside")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(outside, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".env"), []byte("TOKEN=secret\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".git", "config"), []byte("[core]\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "passwd"), []byte("root:x:0:0\n"), 0644))
+	return root, outside
+}
+
+func TestNewBackend_InvalidPolicy(t *testing.T) {
+	ctx := context.Background()
+	root, _ := setupPolicyDirs(t)
+
+	_, err := NewBackend(ctx, &Config{RootDirs: []string{filepath.Join(root, "missing")}})
+	assert.Error(t, err)
+
+	_, err = NewBackend(ctx, &Config{RootDirs: []string{filepath.Join(root, ".env")}})
+	assert.ErrorContains(t, err, "not a directory")
+
+	_, err = NewBackend(ctx, &Config{DenyGlobs: []string{"[unclosed"}})
+	assert.ErrorContains(t, err, "invalid deny glob")
+}
+
+func TestRootDirs(t *testing.T) {
+	ctx := context.Background()
+	root, outside := setupPolicyDirs(t)
+	s, err := NewBackend(ctx, &Config{RootDirs: []string{root}})
+	require.NoError(t, err)
+
+	t.Run("paths inside the root are allowed", func(t *testing.T) {
+		content, err := s.Read(ctx, &filesystem.ReadRequest{FilePath: filepath.Join(root, "src", "main.go")})
+		require.NoError(t, err)
+		assert.Equal(t, "package main", content.Content)
+	})
+
+	t.Run("relative paths resolve against the first root", func(t *testing.T) {
+		content, err := s.Read(ctx, &filesystem.ReadRequest{FilePath: "src/main.go"})
+		require.NoError(t, err)
+		assert.Equal(t, "package main", content.Content)
+
+		require.NoError(t, s.Write(ctx, &filesystem.WriteRequest{FilePath: "new/file.txt", Content: "hi"}))
+		data, err := os.ReadFile(filepath.Join(root, "new", "file.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "hi", string(data))
+	})
+
+	t.Run("paths outside the root are rejected", func(t *testing.T) {
+		_, err := s.Read(ctx, &filesystem.ReadRequest{FilePath: filepath.Join(outside, "passwd")})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+
+		_, err = s.Read(ctx, &filesystem.ReadRequest{FilePath: "../outside/passwd"})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+
+		_, err = s.LsInfo(ctx, &filesystem.LsInfoRequest{Path: outside})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+
+		_, err = s.GlobInfo(ctx, &filesystem.GlobInfoRequest{Path: "/", Pattern: "**"})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+
+		_, err = s.GrepRaw(ctx, &filesystem.GrepRequest{Path: outside, Pattern: "root"})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+
+		err = s.Write(ctx, &filesystem.WriteRequest{FilePath: filepath.Join(outside, "x.txt"), Content: "x"})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+		assert.NoFileExists(t, filepath.Join(outside, "x.txt"))
+
+		err = s.Edit(ctx, &filesystem.EditRequest{FilePath: filepath.Join(outside, "passwd"), OldString: "root", NewString: "toor"})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+
+		_, err = s.MultiModalRead(ctx, &filesystem.MultiModalReadRequest{
+			ReadRequest: filesystem.ReadRequest{FilePath: filepath.Join(outside, "image.png")},
+		})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+	})
+
+	t.Run("symlinks cannot escape the root", func(t *testing.T) {
+		require.NoError(t, os.Symlink(outside, filepath.Join(root, "escape")))
+		require.NoError(t, os.Symlink(filepath.Join(outside, "created.txt"), filepath.Join(root, "dangling")))
+
+		_, err := s.Read(ctx, &filesystem.ReadRequest{FilePath: filepath.Join(root, "escape", "passwd")})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+
+		err = s.Write(ctx, &filesystem.WriteRequest{FilePath: filepath.Join(root, "escape", "new.txt"), Content: "x"})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+
+		err = s.Write(ctx, &filesystem.WriteRequest{FilePath: filepath.Join(root, "dangling"), Content: "x"})
+		assert.ErrorIs(t, err, ErrPathOutsideRoot)
+		assert.NoFileExists(t, filepath.Join(outside, "created.txt"))
+	})
+
+	t.Run("multiple roots", func(t *testing.T) {
+		multi, err := NewBackend(ctx, &Config{RootDirs: []string{root, outside}})
+		require.NoError(t, err)
+		content, err := multi.Read(ctx, &filesystem.ReadRequest{FilePath: filepath.Join(outside, "passwd")})
+		require.NoError(t, err)
+		assert.Equal(t, "root:x:0:0", content.Content)
+	})
+
+	t.Run("commands run in the first root", func(t *testing.T) {
+		resp, err := s.Execute(ctx, &filesystem.ExecuteRequest{Command: "pwd"})
+		require.NoError(t, err)
+		assert.Equal(t, root, strings.TrimSpace(resp.Output))
+	})
+}
+
+func TestDenyGlobs(t *testing.T) {
+	ctx := context.Background()
+	root, _ := setupPolicyDirs(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "config", "prod"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "config", "prod", "db.yaml"), []byte("pw: x\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "key.pem"), []byte("KEY\n"), 0644))
+
+	s, err := NewBackend(ctx, &Config{
+		RootDirs:  []string{root},
+		DenyGlobs: []string{".env", ".git/", "*.pem", "config/prod/**"},
+	})
+	require.NoError(t, err)
+
+	for _, p := range []string{
+		".env",
+		".git/config",
+		"src/key.pem",
+		"config/prod/db.yaml",
+	} {
+		_, err := s.Read(ctx, &filesystem.ReadRequest{FilePath: filepath.Join(root, p)})
+		assert.ErrorIs(t, err, ErrPathDenied, p)
+	}
+
+	t.Run("denied entries are hidden from listings", func(t *testing.T) {
+		files, err := s.LsInfo(ctx, &filesystem.LsInfoRequest{Path: root})
+		require.NoError(t, err)
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Path)
+		}
+		assert.ElementsMatch(t, []string{"config", "src"}, names)
+
+		files, err = s.GlobInfo(ctx, &filesystem.GlobInfoRequest{Path: root, Pattern: "**"})
+		require.NoError(t, err)
+		var paths []string
+		for _, f := range files {
+			paths = append(paths, f.Path)
+		}
+		assert.Equal(t, []string{"config", "src", "src/main.go"}, paths)
+	})
+
+	t.Run("denied grep matches are dropped", func(t *testing.T) {
+		mockOutput := `{"type":"match","data":{"path":{"text":"` + filepath.Join(root, ".env") + `"},"line_number":1,"lines":{"text":"TOKEN\n"}}}
+{"type":"match","data":{"path":{"text":"` + filepath.Join(root, "src", "main.go") + `"},"line_number":1,"lines":{"text":"TOKEN\n"}}}`
+		t.Setenv("PATH", createMockRg(t, mockOutput, 0)+":"+os.Getenv("PATH"))
+
+		matches, err := s.GrepRaw(ctx, &filesystem.GrepRequest{Path: root, Pattern: "TOKEN"})
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, filepath.Join(root, "src", "main.go"), matches[0].Path)
+	})
+
+	t.Run("writes to denied paths are rejected", func(t *testing.T) {
+		err := s.Write(ctx, &filesystem.WriteRequest{FilePath: filepath.Join(root, ".git", "hooks", "pre-commit"), Content: "x"})
+		assert.ErrorIs(t, err, ErrPathDenied)
+		err = s.Edit(ctx, &filesystem.EditRequest{FilePath: filepath.Join(root, ".env"), OldString: "secret", NewString: "x"})
+		assert.ErrorIs(t, err, ErrPathDenied)
+	})
+
+	t.Run("symlinks to denied files are denied", func(t *testing.T) {
+		require.NoError(t, os.Symlink(filepath.Join(root, ".env"), filepath.Join(root, "src", "settings")))
+		_, err := s.Read(ctx, &filesystem.ReadRequest{FilePath: filepath.Join(root, "src", "settings")})
+		assert.ErrorIs(t, err, ErrPathDenied)
+	})
+
+	t.Run("deny globs apply without roots", func(t *testing.T) {
+		unrooted, err := NewBackend(ctx, &Config{DenyGlobs: []string{".env"}})
+		require.NoError(t, err)
+		_, err = unrooted.Read(ctx, &filesystem.ReadRequest{FilePath: filepath.Join(root, ".env")})
+		assert.ErrorIs(t, err, ErrPathDenied)
+		_, err = unrooted.Read(ctx, &filesystem.ReadRequest{FilePath: filepath.Join(root, "src", "main.go")})
+		assert.NoError(t, err)
+	})
+}
+
+func TestReadOnly(t *testing.T) {
+	ctx := context.Background()
+	root, _ := setupPolicyDirs(t)
+	s, err := NewBackend(ctx, &Config{RootDirs: []string{root}, ReadOnly: true})
+	require.NoError(t, err)
+
+	_, err = s.Read(ctx, &filesystem.ReadRequest{FilePath: "src/main.go"})
+	assert.NoError(t, err)
+
+	err = s.Write(ctx, &filesystem.WriteRequest{FilePath: "src/new.go", Content: "x"})
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.NoFileExists(t, filepath.Join(root, "src", "new.go"))
+
+	err = s.Edit(ctx, &filesystem.EditRequest{FilePath: "src/main.go", OldString: "main", NewString: "other"})
+	assert.ErrorIs(t, err, ErrReadOnly)
+
+	_, err = s.Execute(ctx, &filesystem.ExecuteRequest{Command: "touch x"})
+	assert.ErrorIs(t, err, ErrReadOnly)
+
+	_, err = s.ExecuteStreaming(ctx, &filesystem.ExecuteRequest{Command: "touch x"})
+	assert.ErrorIs(t, err, ErrReadOnly)
+}
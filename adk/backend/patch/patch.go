@@ -0,0 +1,425 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package patch holds the multi-edit and unified diff engine shared by the
+// filesystem backends. Edits and patches are applied in memory; reading the
+// original files and committing the result is left to each backend.
+//
+// The local, agentkit and acp backends re-export the request, result and
+// error types of this package as aliases, so a caller can type-assert any of
+// them to MultiEditor or Patcher and match errors with errors.Is and
+// errors.As regardless of the backend. Backends may wrap the errors with
+// their own sentinels, as acp does for its Edit errors.
+package patch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrOldStringNotFound is returned (wrapped in an *EditError) when an edit's
+	// old string does not occur in the file.
+	ErrOldStringNotFound = errors.New("old string not found")
+	// ErrAmbiguousReplace is returned (wrapped in an *EditError) when an edit's
+	// old string occurs more than once and ReplaceAll is not set.
+	ErrAmbiguousReplace = errors.New("old string is not unique (set ReplaceAll or add context)")
+	// ErrInvalidPatch is returned when a patch is not a well-formed unified diff.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrHunkMismatch is returned (wrapped in a *PatchError) when a hunk's
+	// context and removed lines cannot be found in the file.
+	ErrHunkMismatch = errors.New("hunk does not match file content")
+)
+
+// EditOperation is one string replacement of a MultiEditRequest.
+type EditOperation struct {
+	OldString  string
+	NewString  string
+	ReplaceAll bool
+}
+
+// MultiEditRequest asks for several replacements in one file. Edits are applied
+// in order, each to the result of the previous one, and the file is only
+// written when all of them succeed.
+type MultiEditRequest struct {
+	FilePath string
+	Edits    []EditOperation
+}
+
+// ApplyPatchRequest asks for a unified diff to be applied.
+type ApplyPatchRequest struct {
+	// Patch is a unified diff covering one or more files, as produced by
+	// `diff -u` or `git diff`. "a/" and "b/" path prefixes are stripped, and
+	// /dev/null marks created and deleted files.
+	Patch string
+	// BaseDir is the directory relative paths in the patch are resolved
+	// against. Optional, unless the backend only accepts absolute paths and
+	// the patch names relative ones.
+	BaseDir string
+}
+
+// PatchFileOp is what ApplyPatch did to a file.
+type PatchFileOp string
+
+const (
+	PatchFileModified PatchFileOp = "modified"
+	PatchFileCreated  PatchFileOp = "created"
+	PatchFileDeleted  PatchFileOp = "deleted"
+	PatchFileRenamed  PatchFileOp = "renamed"
+)
+
+// PatchedFile reports the outcome for one file of a patch.
+type PatchedFile struct {
+	Path string
+	// OldPath is the source path of a rename.
+	OldPath string
+	Op      PatchFileOp
+	// Hunks is the number of hunks applied to the file.
+	Hunks int
+}
+
+// ApplyPatchResult lists the files changed by ApplyPatch, in patch order.
+type ApplyPatchResult struct {
+	Files []PatchedFile
+}
+
+// MultiEditor is implemented by backends that can apply several edits to one
+// file atomically.
+type MultiEditor interface {
+	MultiEdit(ctx context.Context, req *MultiEditRequest) error
+}
+
+// Patcher is implemented by backends that can apply a multi-file unified diff
+// with all-or-nothing semantics.
+type Patcher interface {
+	ApplyPatch(ctx context.Context, req *ApplyPatchRequest) (*ApplyPatchResult, error)
+}
+
+// EditError reports which edit of a MultiEditRequest failed.
+type EditError struct {
+	FilePath string
+	// Index is the 0-based position of the failed edit in Edits.
+	Index int
+	Err   error
+}
+
+func (e *EditError) Error() string {
+	return fmt.Sprintf("edit %d of %s failed: %v", e.Index+1, e.FilePath, e.Err)
+}
+
+func (e *EditError) Unwrap() error { return e.Err }
+
+// PatchError reports which file, and which hunk of it, a patch failed on.
+type PatchError struct {
+	FilePath string
+	// Hunk is the 1-based index of the failed hunk within the file, or 0 when
+	// the failure concerns the file as a whole (e.g. it does not exist).
+	Hunk int
+	// Header is the "@@ -l,s +l,s @@" line of the failed hunk.
+	Header string
+	Err    error
+}
+
+func (e *PatchError) Error() string {
+	if e.Hunk == 0 {
+		return fmt.Sprintf("patch of %s failed: %v", e.FilePath, e.Err)
+	}
+	return fmt.Sprintf("hunk %d (%s) of %s failed: %v", e.Hunk, e.Header, e.FilePath, e.Err)
+}
+
+func (e *PatchError) Unwrap() error { return e.Err }
+
+// ApplyEdits applies edits to content in order and returns the result. A
+// failed edit is reported as an *EditError.
+func ApplyEdits(path, content string, edits []EditOperation) (string, error) {
+	if len(edits) == 0 {
+		return "", fmt.Errorf("at least one edit is required for %s", path)
+	}
+	for i, e := range edits {
+		if e.OldString == "" {
+			return "", &EditError{FilePath: path, Index: i, Err: errors.New("old string is required")}
+		}
+		if e.OldString == e.NewString {
+			return "", &EditError{FilePath: path, Index: i, Err: errors.New("new string must be different from old string")}
+		}
+		switch n := strings.Count(content, e.OldString); {
+		case n == 0:
+			return "", &EditError{FilePath: path, Index: i, Err: ErrOldStringNotFound}
+		case n > 1 && !e.ReplaceAll:
+			return "", &EditError{FilePath: path, Index: i, Err: fmt.Errorf("%w: %d occurrences", ErrAmbiguousReplace, n)}
+		}
+		if e.ReplaceAll {
+			content = strings.ReplaceAll(content, e.OldString, e.NewString)
+		} else {
+			content = strings.Replace(content, e.OldString, e.NewString, 1)
+		}
+	}
+	return content, nil
+}
+
+// FilePatch is the part of a unified diff that concerns one file. An empty
+// path stands for /dev/null.
+type FilePatch struct {
+	OldPath string
+	NewPath string
+	Hunks   []*Hunk
+}
+
+// Hunk is one "@@" section of a FilePatch.
+type Hunk struct {
+	header   string
+	oldStart int
+	oldLines int
+	// old and new are the hunk's lines without their leading marker: context
+	// and removed lines for old, context and added lines for new.
+	old []string
+	new []string
+	// oldNoEOL and newNoEOL record "\ No newline at end of file" markers.
+	oldNoEOL bool
+	newNoEOL bool
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Parse splits a unified diff into per-file patches. Lines outside file
+// headers and hunks (e.g. "diff --git" or "index" lines) are ignored. Paths are
+// returned as written in the diff, minus git's "a/" and "b/" prefixes.
+func Parse(patch string) ([]*FilePatch, error) {
+	lines := strings.Split(patch, "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+
+	var files []*FilePatch
+	var cur *FilePatch
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			cur = &FilePatch{OldPath: parsePatchPath(line[4:]), NewPath: parsePatchPath(lines[i+1][4:])}
+			if cur.OldPath == "" && cur.NewPath == "" {
+				return nil, fmt.Errorf("%w: line %d: both sides are /dev/null", ErrInvalidPatch, i+1)
+			}
+			stripGitPrefixes(cur)
+			files = append(files, cur)
+			i += 2
+		case strings.HasPrefix(line, "@@ "):
+			if cur == nil {
+				return nil, fmt.Errorf("%w: line %d: hunk without a file header", ErrInvalidPatch, i+1)
+			}
+			h, next, err := parseHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			cur.Hunks = append(cur.Hunks, h)
+			i = next
+		default:
+			i++
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no file headers found", ErrInvalidPatch)
+	}
+	for _, fp := range files {
+		if len(fp.Hunks) == 0 {
+			return nil, fmt.Errorf("%w: no hunks for %s", ErrInvalidPatch, fp.displayPath())
+		}
+	}
+	return files, nil
+}
+
+// parseHunk parses the hunk whose header is lines[start] and returns it along
+// with the index of the first line after it.
+func parseHunk(lines []string, start int) (*Hunk, int, error) {
+	m := hunkHeaderRe.FindStringSubmatch(lines[start])
+	if m == nil {
+		return nil, 0, fmt.Errorf("%w: line %d: malformed hunk header %q", ErrInvalidPatch, start+1, lines[start])
+	}
+	count := func(s string) int {
+		if s == "" {
+			return 1
+		}
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	h := &Hunk{header: m[0], oldLines: count(m[2])}
+	h.oldStart, _ = strconv.Atoi(m[1])
+	newLines := count(m[4])
+
+	i := start + 1
+	var last byte
+	for ; i < len(lines) && (len(h.old) < h.oldLines || len(h.new) < newLines); i++ {
+		line := lines[i]
+		if line == "" {
+			// Some tools strip the single space of empty context lines.
+			line = " "
+		}
+		switch line[0] {
+		case ' ':
+			h.old = append(h.old, line[1:])
+			h.new = append(h.new, line[1:])
+		case '-':
+			h.old = append(h.old, line[1:])
+		case '+':
+			h.new = append(h.new, line[1:])
+		case '\\':
+			h.markNoEOL(last)
+			continue
+		default:
+			return nil, 0, fmt.Errorf("%w: line %d: unexpected line in hunk %s", ErrInvalidPatch, i+1, h.header)
+		}
+		last = line[0]
+	}
+	if len(h.old) != h.oldLines || len(h.new) != newLines {
+		return nil, 0, fmt.Errorf("%w: hunk %s at line %d is truncated", ErrInvalidPatch, h.header, start+1)
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], `\`) {
+		h.markNoEOL(last)
+		i++
+	}
+	return h, i, nil
+}
+
+func (h *Hunk) markNoEOL(last byte) {
+	switch last {
+	case ' ':
+		h.oldNoEOL, h.newNoEOL = true, true
+	case '-':
+		h.oldNoEOL = true
+	case '+':
+		h.newNoEOL = true
+	}
+}
+
+// parsePatchPath extracts the path from a "---" or "+++" header, dropping a
+// trailing timestamp. /dev/null becomes "".
+func parsePatchPath(s string) string {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	return s
+}
+
+// stripGitPrefixes removes the "a/" and "b/" prefixes git puts on paths.
+func stripGitPrefixes(fp *FilePatch) {
+	if (fp.OldPath == "" || strings.HasPrefix(fp.OldPath, "a/")) &&
+		(fp.NewPath == "" || strings.HasPrefix(fp.NewPath, "b/")) {
+		fp.OldPath = strings.TrimPrefix(fp.OldPath, "a/")
+		fp.NewPath = strings.TrimPrefix(fp.NewPath, "b/")
+	}
+}
+
+func (fp *FilePatch) displayPath() string {
+	if fp.NewPath != "" {
+		return fp.NewPath
+	}
+	return fp.OldPath
+}
+
+// splitLines splits content into lines without their terminating newline and
+// reports whether the last line was terminated.
+func splitLines(content string) ([]string, bool) {
+	if content == "" {
+		return nil, true
+	}
+	lines := strings.Split(content, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1], true
+	}
+	return lines, false
+}
+
+func joinLines(lines []string, eol bool) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	s := strings.Join(lines, "\n")
+	if eol {
+		s += "\n"
+	}
+	return s
+}
+
+// applyHunks applies the hunks of one file to content. Hunks must appear in
+// file order; each is looked up at its stated position first and then at
+// increasing distances from it, as patch(1) does without fuzz.
+func applyHunks(path, content string, hunks []*Hunk) (string, error) {
+	lines, eol := splitLines(content)
+	out := make([]string, 0, len(lines))
+	pos, offset := 0, 0
+	for i, h := range hunks {
+		want := h.oldStart - 1
+		if h.oldLines == 0 {
+			// A pure insertion names the line after which to insert.
+			want = h.oldStart
+		}
+		at := findHunk(lines, h.old, want+offset, pos)
+		if at < 0 {
+			return "", &PatchError{FilePath: path, Hunk: i + 1, Header: h.header, Err: ErrHunkMismatch}
+		}
+		out = append(out, lines[pos:at]...)
+		out = append(out, h.new...)
+		pos = at + len(h.old)
+		offset = at - want
+		if pos == len(lines) {
+			eol = !h.newNoEOL
+		}
+	}
+	out = append(out, lines[pos:]...)
+	return joinLines(out, eol), nil
+}
+
+// findHunk returns the index at which old occurs in lines, searching from want
+// outwards but never before min, or -1.
+func findHunk(lines, old []string, want, min int) int {
+	last := len(lines) - len(old)
+	if last < min {
+		return -1
+	}
+	if want < min {
+		want = min
+	}
+	if want > last {
+		want = last
+	}
+	for d := 0; want-d >= min || want+d <= last; d++ {
+		if at := want - d; at >= min && linesEqual(lines[at:at+len(old)], old) {
+			return at
+		}
+		if at := want + d; d > 0 && at <= last && linesEqual(lines[at:at+len(old)], old) {
+			return at
+		}
+	}
+	return -1
+}
+
+func linesEqual(a, b []string) bool {
+	for i := range b {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	patch := `diff --git a/a.txt b/a.txt
+index 0000001..0000002 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1,3 +1,3 @@
+ one
+-two
++TWO
+ three
+@@ -10 +10,2 @@
+ ten
++eleven
+\ No newline at end of file
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++hello
+--- old.txt	2026-01-01 00:00:00
++++ /dev/null	2026-01-01 00:00:00
+@@ -1 +0,0 @@
+-bye
+`
+	files, err := Parse(patch)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	assert.Equal(t, "a.txt", files[0].OldPath)
+	assert.Equal(t, "a.txt", files[0].NewPath)
+	require.Len(t, files[0].Hunks, 2)
+	assert.Equal(t, []string{"one", "two", "three"}, files[0].Hunks[0].old)
+	assert.Equal(t, []string{"one", "TWO", "three"}, files[0].Hunks[0].new)
+	assert.True(t, files[0].Hunks[1].newNoEOL)
+	assert.False(t, files[0].Hunks[1].oldNoEOL)
+
+	assert.Equal(t, "", files[1].OldPath)
+	assert.Equal(t, "new.txt", files[1].NewPath)
+	assert.Equal(t, "old.txt", files[2].OldPath)
+	assert.Equal(t, "", files[2].NewPath)
+
+	for name, bad := range map[string]string{
+		"empty":          "",
+		"no hunks":       "--- a\n+++ b\n",
+		"orphan hunk":    "@@ -1 +1 @@\n-a\n+b\n",
+		"truncated hunk": "--- a\n+++ b\n@@ -1,2 +1,2 @@\n-a\n+b\n",
+		"bad line":       "--- a\n+++ b\n@@ -1 +1 @@\n*a\n",
+	} {
+		_, err := Parse(bad)
+		assert.ErrorIs(t, err, ErrInvalidPatch, name)
+	}
+}
+
+func TestApplyHunks(t *testing.T) {
+	apply := func(content, patch string) (string, error) {
+		files, err := Parse(patch)
+		require.NoError(t, err)
+		return applyHunks("f", content, files[0].Hunks)
+	}
+
+	t.Run("hunks found at an offset", func(t *testing.T) {
+		out, err := apply("x\ny\na\nb\nc\n", "--- f\n+++ f\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n")
+		require.NoError(t, err)
+		assert.Equal(t, "x\ny\na\nB\nc\n", out)
+	})
+
+	t.Run("pure insertion", func(t *testing.T) {
+		out, err := apply("a\nb\n", "--- f\n+++ f\n@@ -1,0 +2 @@\n+inserted\n")
+		require.NoError(t, err)
+		assert.Equal(t, "a\ninserted\nb\n", out)
+	})
+
+	t.Run("newline at end of file", func(t *testing.T) {
+		out, err := apply("a\nb", "--- f\n+++ f\n@@ -2 +2 @@\n-b\n\\ No newline at end of file\n+b\n")
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\n", out)
+
+		out, err = apply("a\nb\n", "--- f\n+++ f\n@@ -2 +2 @@\n-b\n+c\n\\ No newline at end of file\n")
+		require.NoError(t, err)
+		assert.Equal(t, "a\nc", out)
+	})
+
+	t.Run("mismatch names the hunk", func(t *testing.T) {
+		_, err := apply("a\nb\nc\n", "--- f\n+++ f\n@@ -1 +1 @@\n-a\n+A\n@@ -3 +3 @@\n-x\n+X\n")
+		var pErr *PatchError
+		require.True(t, errors.As(err, &pErr))
+		assert.Equal(t, 2, pErr.Hunk)
+		assert.Equal(t, "@@ -3 +3 @@", pErr.Header)
+		assert.ErrorIs(t, err, ErrHunkMismatch)
+	})
+}
+
+func TestApplyEdits(t *testing.T) {
+	out, err := ApplyEdits("f", "a b a", []EditOperation{
+		{OldString: "b", NewString: "c"},
+		{OldString: "a", NewString: "x", ReplaceAll: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "x c x", out)
+
+	_, err = ApplyEdits("f", "a a", []EditOperation{{OldString: "b", NewString: "c"}, {OldString: "a", NewString: "x"}})
+	var eErr *EditError
+	require.True(t, errors.As(err, &eErr))
+	assert.Equal(t, 0, eErr.Index)
+	assert.ErrorIs(t, err, ErrOldStringNotFound)
+
+	_, err = ApplyEdits("f", "a a", []EditOperation{{OldString: "a", NewString: "x"}})
+	assert.ErrorIs(t, err, ErrAmbiguousReplace)
+
+	_, err = ApplyEdits("f", "a", nil)
+	assert.Error(t, err)
+}
+
+func TestPlan(t *testing.T) {
+	disk := map[string]FileSnapshot{
+		"/w/mod.txt":  {Content: "one\ntwo\n", Exists: true},
+		"/w/old.txt":  {Content: "moved\n", Exists: true},
+		"/w/gone.txt": {Content: "bye\n", Exists: true},
+	}
+	var reads []string
+	read := func(p string) (FileSnapshot, error) {
+		reads = append(reads, p)
+		return disk[p], nil
+	}
+	parse := func(patch string) []*FilePatch {
+		files, err := Parse(patch)
+		require.NoError(t, err)
+		ResolvePaths(files, func(p string) string { return "/w/" + p })
+		return files
+	}
+
+	t.Run("report and writes", func(t *testing.T) {
+		reads = nil
+		files := parse(`--- a/mod.txt
++++ b/mod.txt
+@@ -2 +2 @@
+-two
++TWO
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++hello
+--- a/old.txt
++++ b/moved.txt
+@@ -1 +1 @@
+ moved
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+--- a/mod.txt
++++ b/mod.txt
+@@ -1 +1 @@
+-one
++ONE
+`)
+		assert.Equal(t, []string{"/w/mod.txt", "/w/new.txt", "/w/old.txt", "/w/moved.txt", "/w/gone.txt"}, Targets(files))
+
+		report, writes, err := Plan(files, read)
+		require.NoError(t, err)
+		assert.Equal(t, []PatchedFile{
+			{Path: "/w/mod.txt", Op: PatchFileModified, Hunks: 1},
+			{Path: "/w/new.txt", Op: PatchFileCreated, Hunks: 1},
+			{Path: "/w/moved.txt", OldPath: "/w/old.txt", Op: PatchFileRenamed, Hunks: 1},
+			{Path: "/w/gone.txt", Op: PatchFileDeleted, Hunks: 1},
+			{Path: "/w/mod.txt", Op: PatchFileModified, Hunks: 1},
+		}, report)
+		// Each path is read once, even when patched twice.
+		assert.Equal(t, []string{"/w/mod.txt", "/w/new.txt", "/w/old.txt", "/w/moved.txt", "/w/gone.txt"}, reads)
+		assert.Equal(t, []*FileWrite{
+			{Path: "/w/mod.txt", Before: disk["/w/mod.txt"], After: FileSnapshot{Content: "ONE\nTWO\n", Exists: true}},
+			{Path: "/w/new.txt", After: FileSnapshot{Content: "hello\n", Exists: true}},
+			{Path: "/w/old.txt", Before: disk["/w/old.txt"], Deleted: true},
+			{Path: "/w/moved.txt", After: FileSnapshot{Content: "moved\n", Exists: true}},
+			{Path: "/w/gone.txt", Before: disk["/w/gone.txt"], Deleted: true},
+		}, writes)
+	})
+
+	t.Run("file-level errors", func(t *testing.T) {
+		for name, patch := range map[string]string{
+			"missing file":   "--- a/missing.txt\n+++ b/missing.txt\n@@ -1 +1 @@\n-a\n+b\n",
+			"existing file":  "--- /dev/null\n+++ b/mod.txt\n@@ -0,0 +1 @@\n+a\n",
+			"partial delete": "--- a/mod.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-one\n",
+		} {
+			_, _, err := Plan(parse(patch), read)
+			var pErr *PatchError
+			require.True(t, errors.As(err, &pErr), name)
+			assert.Equal(t, 0, pErr.Hunk, name)
+		}
+	})
+
+	t.Run("read errors", func(t *testing.T) {
+		boom := errors.New("boom")
+		_, _, err := Plan(parse("--- a/mod.txt\n+++ b/mod.txt\n@@ -1 +1 @@\n-one\n+ONE\n"), func(string) (FileSnapshot, error) {
+			return FileSnapshot{}, boom
+		})
+		var pErr *PatchError
+		require.True(t, errors.As(err, &pErr))
+		assert.Equal(t, "/w/mod.txt", pErr.FilePath)
+		assert.ErrorIs(t, err, boom)
+	})
+}
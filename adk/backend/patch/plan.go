@@ -0,0 +1,155 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package patch
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FileSnapshot is the content of a file before or after a patch.
+type FileSnapshot struct {
+	Content string
+	Exists  bool
+}
+
+// FileWrite is one change a patch makes to the filesystem, together with the
+// original state needed to roll it back.
+type FileWrite struct {
+	Path   string
+	Before FileSnapshot
+	After  FileSnapshot
+	// Deleted is set when the file existed before the patch and does not
+	// after it.
+	Deleted bool
+}
+
+// ResolvePaths replaces every non-empty path of files with resolve(path),
+// typically to join relative paths with ApplyPatchRequest.BaseDir.
+func ResolvePaths(files []*FilePatch, resolve func(path string) string) {
+	for _, fp := range files {
+		if fp.OldPath != "" {
+			fp.OldPath = resolve(fp.OldPath)
+		}
+		if fp.NewPath != "" {
+			fp.NewPath = resolve(fp.NewPath)
+		}
+	}
+}
+
+// Targets returns every path files touch, without duplicates.
+func Targets(files []*FilePatch) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, fp := range files {
+		for _, p := range []string{fp.OldPath, fp.NewPath} {
+			if p != "" && !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
+// Plan applies files in memory. read returns the current content of a path
+// and is called at most once per path. Plan returns the report for the caller
+// and the writes to commit, in first-touched order; nothing is written, so a
+// failure (reported as a *PatchError) leaves the filesystem untouched.
+func Plan(files []*FilePatch, read func(path string) (FileSnapshot, error)) ([]PatchedFile, []*FileWrite, error) {
+	original := make(map[string]FileSnapshot)
+	current := make(map[string]FileSnapshot)
+	var order []string
+	get := func(p string) (FileSnapshot, error) {
+		if s, ok := current[p]; ok {
+			return s, nil
+		}
+		s, err := read(p)
+		if err != nil {
+			return FileSnapshot{}, &PatchError{FilePath: p, Err: err}
+		}
+		original[p], current[p] = s, s
+		order = append(order, p)
+		return s, nil
+	}
+
+	report := make([]PatchedFile, 0, len(files))
+	for _, fp := range files {
+		oldPath, newPath := fp.OldPath, fp.NewPath
+		target := newPath
+		if target == "" {
+			target = oldPath
+		}
+
+		var src FileSnapshot
+		if oldPath != "" {
+			s, err := get(oldPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !s.Exists {
+				return nil, nil, &PatchError{FilePath: oldPath, Err: errors.New("file does not exist")}
+			}
+			src = s
+		}
+		if newPath != "" && newPath != oldPath {
+			s, err := get(newPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			if s.Exists {
+				return nil, nil, &PatchError{FilePath: newPath, Err: errors.New("file already exists")}
+			}
+		}
+
+		content, err := applyHunks(target, src.Content, fp.Hunks)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pf := PatchedFile{Path: target, Hunks: len(fp.Hunks)}
+		switch {
+		case oldPath == "":
+			pf.Op = PatchFileCreated
+			current[newPath] = FileSnapshot{Content: content, Exists: true}
+		case newPath == "":
+			if content != "" {
+				return nil, nil, &PatchError{FilePath: oldPath, Err: fmt.Errorf("%w: deleted file has lines not covered by the patch", ErrHunkMismatch)}
+			}
+			pf.Op = PatchFileDeleted
+			current[oldPath] = FileSnapshot{}
+		case oldPath != newPath:
+			pf.Op, pf.OldPath = PatchFileRenamed, oldPath
+			current[oldPath] = FileSnapshot{}
+			current[newPath] = FileSnapshot{Content: content, Exists: true}
+		default:
+			pf.Op = PatchFileModified
+			current[newPath] = FileSnapshot{Content: content, Exists: true}
+		}
+		report = append(report, pf)
+	}
+
+	var writes []*FileWrite
+	for _, p := range order {
+		before, after := original[p], current[p]
+		if before == after {
+			continue
+		}
+		writes = append(writes, &FileWrite{Path: p, Before: before, After: after, Deleted: before.Exists && !after.Exists})
+	}
+	return report, writes, nil
+}
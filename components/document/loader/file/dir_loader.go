@@ -0,0 +1,447 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	MetaKeyRelPath     = "_rel_path"
+	MetaKeyContentHash = "_content_hash"
+	MetaKeyChange      = "_change"
+)
+
+// ChangeType is how a file changed since the last load.
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeModified ChangeType = "modified"
+	ChangeDeleted  ChangeType = "deleted"
+)
+
+const manifestVersion = 1
+
+type DirLoaderConfig struct {
+	// Parser parses the files, by default an ExtParser with a TextParser
+	// fallback, like FileLoader.
+	Parser parser.Parser
+	// UsePathAsID uses the slash separated path relative to the root as the
+	// document ID, suffixed with the index of the document if a file is parsed
+	// into several documents.
+	UsePathAsID bool
+	// Include loads only the files which match one of the glob patterns. A
+	// pattern without a slash matches the file name, such as *.md, and a
+	// pattern with a slash matches the path relative to the root, such as
+	// docs/**/*.md. By default, all files are loaded.
+	Include []string
+	// Exclude skips the files and directories which match one of the glob
+	// patterns, which are matched like Include.
+	Exclude []string
+	// UseGitignore skips the files and directories ignored by the .gitignore
+	// files of the tree. The .git directory is always skipped.
+	UseGitignore bool
+	// ManifestPath is the file of the content hashes of the files committed
+	// last time. If set, only the files added or modified since then are
+	// loaded, and the manifest is only updated by Commit. If empty, all files
+	// are loaded every time.
+	ManifestPath string
+}
+
+// DirLoader loads the files of a directory tree. With a manifest, it only
+// loads the files added or modified since the last commit, and reports the
+// deleted ones.
+type DirLoader struct {
+	DirLoaderConfig
+
+	include []*glob
+	exclude []*glob
+}
+
+// NewDirLoader creates a new DirLoader.
+func NewDirLoader(ctx context.Context, config *DirLoaderConfig) (*DirLoader, error) {
+	if config == nil {
+		config = &DirLoaderConfig{}
+	}
+	conf := *config
+	if conf.Parser == nil {
+		p, err := parser.NewExtParser(ctx,
+			&parser.ExtParserConfig{
+				FallbackParser: parser.TextParser{},
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("new file parser fail: %w", err)
+		}
+		conf.Parser = p
+	}
+
+	include, err := newGlobs(conf.Include)
+	if err != nil {
+		return nil, fmt.Errorf("new dir loader, include: %w", err)
+	}
+	exclude, err := newGlobs(conf.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("new dir loader, exclude: %w", err)
+	}
+
+	return &DirLoader{
+		DirLoaderConfig: conf,
+		include:         include,
+		exclude:         exclude,
+	}, nil
+}
+
+// SyncResult is the result of loading a directory.
+type SyncResult struct {
+	// Docs are the documents of the added and modified files, with the change
+	// type in the metadata.
+	Docs []*schema.Document
+	// Added, Modified and Deleted are the slash separated paths, relative to
+	// the root, of the files which changed since the last load, sorted.
+	// Without a manifest, all files are added.
+	Added    []string
+	Modified []string
+	Deleted  []string
+	// Unchanged is the number of files which did not change.
+	Unchanged int
+
+	// manifest is the state of the files after the changes, written by Commit.
+	manifest *manifest
+}
+
+// Load loads the added and modified files of the directory src.URI. It does
+// not update the manifest; use Sync and Commit to load incrementally and get
+// the deleted files as well.
+func (d *DirLoader) Load(ctx context.Context, src document.Source, opts ...document.LoaderOption) (docs []*schema.Document, err error) {
+	ctx = callbacks.EnsureRunInfo(ctx, d.GetType(), components.ComponentOfLoader)
+
+	ctx = callbacks.OnStart(ctx, &document.LoaderCallbackInput{
+		Source: src,
+	})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	result, err := d.sync(ctx, src.URI, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = callbacks.OnEnd(ctx, &document.LoaderCallbackOutput{
+		Source: src,
+		Docs:   result.Docs,
+	})
+
+	return result.Docs, nil
+}
+
+// Sync loads the added and modified files of the directory root, and reports
+// the files added, modified and deleted since the last commit, to update an
+// index with. The manifest is not updated until the result is committed.
+func (d *DirLoader) Sync(ctx context.Context, root string, opts ...document.LoaderOption) (*SyncResult, error) {
+	return d.sync(ctx, root, opts...)
+}
+
+// Commit records the files of result, returned by Sync, in the manifest, so
+// that the next Sync only reports the changes made after it. Call it once the
+// changes are applied to the index; if that fails, the next Sync reports them
+// again.
+func (d *DirLoader) Commit(result *SyncResult) error {
+	if result == nil || result.manifest == nil {
+		return errors.New("dir loader commit, result is not returned by Sync")
+	}
+	return d.writeManifest(result.manifest)
+}
+
+func (d *DirLoader) sync(ctx context.Context, root string, opts ...document.LoaderOption) (*SyncResult, error) {
+	if len(root) == 0 {
+		return nil, errors.New("dir loader, root is empty")
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("dir loader, error while checking root stat: %w, root= %s", err, root)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("dir loader can only accept dir path, actual= %s", root)
+	}
+
+	old, err := d.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := d.walk(root)
+	if err != nil {
+		return nil, err
+	}
+
+	o := document.GetLoaderCommonOptions(&document.LoaderOptions{}, opts...)
+	result := &SyncResult{}
+	current := &manifest{Version: manifestVersion, Files: make(map[string]manifestEntry, len(paths))}
+	for _, rel := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("dir loader stat file failed with err: %w, path= %s", err, path)
+		}
+		prev, seen := old.Files[rel]
+		// Files of the same size and modification time are not read again,
+		// like git does.
+		if seen && prev.Size == info.Size() && prev.ModTime.Equal(info.ModTime()) {
+			current.Files[rel] = prev
+			result.Unchanged++
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("dir loader read file failed with err: %w, path= %s", err, path)
+		}
+		sum := sha256.Sum256(data)
+		entry := manifestEntry{
+			Hash:    hex.EncodeToString(sum[:]),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		current.Files[rel] = entry
+		if seen && prev.Hash == entry.Hash {
+			result.Unchanged++
+			continue
+		}
+
+		change := ChangeAdded
+		if seen {
+			change = ChangeModified
+			result.Modified = append(result.Modified, rel)
+		} else {
+			result.Added = append(result.Added, rel)
+		}
+		docs, err := d.parse(ctx, path, rel, data, entry.Hash, change, o)
+		if err != nil {
+			return nil, err
+		}
+		result.Docs = append(result.Docs, docs...)
+	}
+
+	for rel := range old.Files {
+		if _, ok := current.Files[rel]; !ok {
+			result.Deleted = append(result.Deleted, rel)
+		}
+	}
+	sort.Strings(result.Deleted)
+
+	result.manifest = current
+	return result, nil
+}
+
+func (d *DirLoader) parse(ctx context.Context, path, rel string, data []byte, hash string, change ChangeType, o *document.LoaderOptions) ([]*schema.Document, error) {
+	meta := map[string]any{
+		MetaKeyExtension:   filepath.Ext(path),
+		MetaKeyFileName:    filepath.Base(path),
+		MetaKeySource:      path,
+		MetaKeyRelPath:     rel,
+		MetaKeyContentHash: hash,
+		MetaKeyChange:      string(change),
+	}
+
+	docs, err := d.Parser.Parse(ctx, bytes.NewReader(data), append([]parser.Option{parser.WithURI(path), parser.WithExtraMeta(meta)}, o.ParserOptions...)...)
+	if err != nil {
+		return nil, fmt.Errorf("file parse err of [%s]: %w", path, err)
+	}
+
+	if d.UsePathAsID {
+		if len(docs) == 1 {
+			docs[0].ID = rel
+		} else {
+			for idx, doc := range docs {
+				doc.ID = fmt.Sprintf("%s_%d", rel, idx)
+			}
+		}
+	}
+	return docs, nil
+}
+
+// walk returns the slash separated paths, relative to root, of the files to
+// load, in lexical order.
+func (d *DirLoader) walk(root string) ([]string, error) {
+	var manifestPath string
+	if d.ManifestPath != "" {
+		manifestPath, _ = filepath.Abs(d.ManifestPath)
+	}
+
+	ignore := &gitignore{}
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if entry.IsDir() {
+			if rel == "." {
+				rel = ""
+			} else if entry.Name() == ".git" || matchAny(d.exclude, rel) || d.UseGitignore && ignore.ignored(rel, true) {
+				return filepath.SkipDir
+			}
+			if d.UseGitignore {
+				return d.addGitignore(ignore, path, rel)
+			}
+			return nil
+		}
+
+		// Symbolic links and other special files are skipped.
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if manifestPath != "" {
+			if abs, _ := filepath.Abs(path); abs == manifestPath {
+				return nil
+			}
+		}
+		if len(d.include) > 0 && !matchAny(d.include, rel) ||
+			matchAny(d.exclude, rel) ||
+			d.UseGitignore && ignore.ignored(rel, false) {
+			return nil
+		}
+		paths = append(paths, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dir loader walk dir failed with err: %w, root= %s", err, root)
+	}
+	return paths, nil
+}
+
+func (d *DirLoader) addGitignore(ignore *gitignore, dir, rel string) error {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := ignore.add(rel, f); err != nil {
+		return fmt.Errorf("parse %s: %w", f.Name(), err)
+	}
+	return nil
+}
+
+// manifest records the files loaded last time, by their slash separated
+// paths relative to the root.
+type manifest struct {
+	Version int                      `json:"version"`
+	Files   map[string]manifestEntry `json:"files"`
+}
+
+type manifestEntry struct {
+	// Hash is the hex encoded SHA-256 of the content.
+	Hash    string    `json:"hash"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+func (d *DirLoader) readManifest() (*manifest, error) {
+	m := &manifest{Files: map[string]manifestEntry{}}
+	if d.ManifestPath == "" {
+		return m, nil
+	}
+	data, err := os.ReadFile(d.ManifestPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dir loader read manifest failed with err: %w, path= %s", err, d.ManifestPath)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("dir loader decode manifest failed with err: %w, path= %s", err, d.ManifestPath)
+	}
+	if m.Version != manifestVersion {
+		return nil, fmt.Errorf("dir loader unsupported manifest version %d, path= %s", m.Version, d.ManifestPath)
+	}
+	if m.Files == nil {
+		m.Files = map[string]manifestEntry{}
+	}
+	return m, nil
+}
+
+// writeManifest replaces the manifest atomically, so that an interrupted
+// write does not lose the previous one.
+func (d *DirLoader) writeManifest(m *manifest) error {
+	if d.ManifestPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("dir loader encode manifest failed with err: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(d.ManifestPath), filepath.Base(d.ManifestPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("dir loader write manifest failed with err: %w, path= %s", err, d.ManifestPath)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Close()
+	} else {
+		_ = tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), d.ManifestPath)
+	}
+	if err != nil {
+		return fmt.Errorf("dir loader write manifest failed with err: %w, path= %s", err, d.ManifestPath)
+	}
+	return nil
+}
+
+func (d *DirLoader) GetType() string {
+	return "DirLoader"
+}
+
+func (d *DirLoader) IsCallbacksEnabled() bool {
+	return true
+}
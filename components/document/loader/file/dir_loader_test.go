@@ -0,0 +1,185 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/document"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestDirLoader_Load(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".gitignore":         "*.log\nbuild/\n!keep.log\n",
+		"a.md":               "alpha",
+		"b.txt":              "bravo",
+		"debug.log":          "ignored",
+		"keep.log":           "kept",
+		"build/out.md":       "ignored",
+		".git/HEAD":          "ref",
+		"docs/c.md":          "charlie",
+		"docs/.gitignore":    "/draft.md\n",
+		"docs/draft.md":      "ignored",
+		"docs/deep/draft.md": "delta",
+		"vendor/x.md":        "excluded",
+	})
+
+	loader, err := NewDirLoader(ctx, &DirLoaderConfig{
+		UsePathAsID:  true,
+		Include:      []string{"*.md", "*.log"},
+		Exclude:      []string{"vendor"},
+		UseGitignore: true,
+	})
+	assert.NoError(t, err)
+
+	docs, err := loader.Load(ctx, document.Source{URI: root})
+	assert.NoError(t, err)
+	var ids []string
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	assert.Equal(t, []string{"a.md", "docs/c.md", "docs/deep/draft.md", "keep.log"}, ids)
+	assert.Equal(t, "alpha", docs[0].Content)
+	assert.Equal(t, "a.md", docs[0].MetaData[MetaKeyFileName])
+	assert.Equal(t, ".md", docs[0].MetaData[MetaKeyExtension])
+	assert.Equal(t, filepath.Join(root, "a.md"), docs[0].MetaData[MetaKeySource])
+	assert.Equal(t, "a.md", docs[0].MetaData[MetaKeyRelPath])
+	assert.Equal(t, "8ed3f6ad685b959ead7022518e1af76cd816f8e8ec7ccdda1ed4018e8f2223f8", docs[0].MetaData[MetaKeyContentHash])
+	assert.Equal(t, "added", docs[0].MetaData[MetaKeyChange])
+
+	_, err = loader.Load(ctx, document.Source{URI: filepath.Join(root, "a.md")})
+	assert.Error(t, err)
+	_, err = NewDirLoader(ctx, &DirLoaderConfig{Include: []string{"[a"}})
+	assert.Error(t, err)
+}
+
+func TestDirLoader_Sync(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	manifestPath := filepath.Join(root, ".manifest.json")
+	writeFiles(t, root, map[string]string{
+		"a.md":     "alpha",
+		"b.md":     "bravo",
+		"sub/c.md": "charlie",
+	})
+
+	loader, err := NewDirLoader(ctx, &DirLoaderConfig{ManifestPath: manifestPath})
+	assert.NoError(t, err)
+
+	result, err := loader.Sync(ctx, root)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.md", "b.md", "sub/c.md"}, result.Added)
+	assert.Len(t, result.Docs, 3)
+	assert.NoError(t, loader.Commit(result))
+
+	// Nothing changed.
+	result, err = loader.Sync(ctx, root)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Docs)
+	assert.Equal(t, 3, result.Unchanged)
+	assert.NoError(t, loader.Commit(result))
+
+	// Modify a file, touch another one without changing it, delete one and
+	// add one.
+	writeFiles(t, root, map[string]string{
+		"a.md":     "alpha 2",
+		"sub/d.md": "delta",
+	})
+	later := time.Now().Add(time.Hour)
+	assert.NoError(t, os.Chtimes(filepath.Join(root, "b.md"), later, later))
+	assert.NoError(t, os.Remove(filepath.Join(root, "sub/c.md")))
+
+	result, err = loader.Sync(ctx, root)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sub/d.md"}, result.Added)
+	assert.Equal(t, []string{"a.md"}, result.Modified)
+	assert.Equal(t, []string{"sub/c.md"}, result.Deleted)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Len(t, result.Docs, 2)
+	assert.Equal(t, "alpha 2", result.Docs[0].Content)
+	assert.Equal(t, "modified", result.Docs[0].MetaData[MetaKeyChange])
+	assert.Equal(t, "added", result.Docs[1].MetaData[MetaKeyChange])
+	assert.NoError(t, loader.Commit(result))
+
+	assert.Error(t, loader.Commit(&SyncResult{}))
+
+	// Without the manifest, everything is loaded again.
+	assert.NoError(t, os.Remove(manifestPath))
+	docs, err := loader.Load(ctx, document.Source{URI: root})
+	assert.NoError(t, err)
+	assert.Len(t, docs, 3)
+}
+
+func TestDirLoader_SyncWithoutCommit(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	manifestPath := filepath.Join(root, ".manifest.json")
+	writeFiles(t, root, map[string]string{
+		"a.md": "alpha",
+		"b.md": "bravo",
+	})
+
+	loader, err := NewDirLoader(ctx, &DirLoaderConfig{ManifestPath: manifestPath})
+	assert.NoError(t, err)
+	result, err := loader.Sync(ctx, root)
+	assert.NoError(t, err)
+	assert.NoError(t, loader.Commit(result))
+
+	writeFiles(t, root, map[string]string{
+		"a.md": "alpha 2",
+		"c.md": "charlie",
+	})
+	assert.NoError(t, os.Remove(filepath.Join(root, "b.md")))
+
+	// Indexing the changes fails, so the result is not committed, and the
+	// next sync reports the same changes. Load does not commit either.
+	for i := 0; i < 2; i++ {
+		result, err = loader.Sync(ctx, root)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"c.md"}, result.Added)
+		assert.Equal(t, []string{"a.md"}, result.Modified)
+		assert.Equal(t, []string{"b.md"}, result.Deleted)
+		assert.Len(t, result.Docs, 2)
+
+		docs, err := loader.Load(ctx, document.Source{URI: root})
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+	}
+
+	assert.NoError(t, loader.Commit(result))
+	result, err = loader.Sync(ctx, root)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Docs)
+	assert.Empty(t, result.Deleted)
+	assert.Equal(t, 2, result.Unchanged)
+}
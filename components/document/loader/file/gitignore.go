@@ -0,0 +1,206 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// globRegexp converts a glob pattern of a slash separated path to a regular
+// expression: * and ? match within a path element, ** matches any number of
+// path elements, and [...] is a character class.
+func globRegexp(pattern string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				atStart := i == 0 || pattern[i-1] == '/'
+				switch {
+				case atStart && i+2 < len(pattern) && pattern[i+2] == '/':
+					// **/ matches zero or more directories.
+					sb.WriteString(`(?:.*/)?`)
+					i += 2
+				case atStart && i+2 == len(pattern):
+					sb.WriteString(`.*`)
+					i++
+				default:
+					sb.WriteString(`[^/]*`)
+					i++
+				}
+				continue
+			}
+			sb.WriteString(`[^/]*`)
+		case '?':
+			sb.WriteString(`[^/]`)
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated character class in pattern %q", pattern)
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				c = pattern[i]
+			}
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String(), nil
+}
+
+// glob matches slash separated paths relative to the root directory. A
+// pattern without a slash matches the base name of the path, and a pattern
+// with a slash matches the whole path.
+type glob struct {
+	re *regexp.Regexp
+}
+
+func newGlob(pattern string) (*glob, error) {
+	expr, err := globRegexp(strings.TrimPrefix(pattern, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(pattern, "/") {
+		expr = `(?:^|/)` + expr
+	} else {
+		expr = `^` + expr
+	}
+	re, err := regexp.Compile(expr + `$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return &glob{re: re}, nil
+}
+
+func (g *glob) match(path string) bool {
+	return g.re.MatchString(path)
+}
+
+func newGlobs(patterns []string) ([]*glob, error) {
+	globs := make([]*glob, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := newGlob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		globs = append(globs, g)
+	}
+	return globs, nil
+}
+
+func matchAny(globs []*glob, path string) bool {
+	for _, g := range globs {
+		if g.match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoreRule is a pattern of a .gitignore file.
+type ignoreRule struct {
+	// base is the directory of the .gitignore file relative to the root,
+	// empty for the root.
+	base    string
+	glob    *glob
+	negate  bool
+	dirOnly bool
+}
+
+// gitignore matches paths against the rules of the .gitignore files found
+// while walking a tree. Rules of deeper files come later, so that they take
+// precedence, and the last matching rule wins, as in git.
+type gitignore struct {
+	rules []ignoreRule
+}
+
+// add reads the rules of a .gitignore file in the directory base.
+func (g *gitignore) add(base string, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Trailing spaces are ignored unless escaped.
+		if trimmed := strings.TrimRight(line, " "); strings.HasSuffix(trimmed, `\`) && len(trimmed) < len(line) {
+			line = trimmed + " "
+		} else {
+			line = trimmed
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		// A pattern with a slash other than a trailing one is relative to
+		// the directory of the .gitignore file.
+		if strings.Contains(line, "/") && !strings.HasPrefix(line, "/") {
+			line = "/" + line
+		}
+		var err error
+		if rule.glob, err = newGlob(line); err != nil {
+			return err
+		}
+		g.rules = append(g.rules, rule)
+	}
+	return scanner.Err()
+}
+
+// ignored reports whether the slash separated path relative to the root is
+// ignored.
+func (g *gitignore) ignored(path string, isDir bool) bool {
+	var ignored bool
+	for _, rule := range g.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		rel := path
+		if rule.base != "" {
+			if !strings.HasPrefix(path, rule.base+"/") {
+				continue
+			}
+			rel = path[len(rule.base)+1:]
+		}
+		if rule.glob.match(rel) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"*.md", "a.md", true},
+		{"*.md", "docs/a.md", true},
+		{"*.md", "a.mdx", false},
+		{"docs/*.md", "docs/a.md", true},
+		{"docs/*.md", "docs/sub/a.md", false},
+		{"docs/**/*.md", "docs/a.md", true},
+		{"docs/**/*.md", "docs/sub/deep/a.md", true},
+		{"**/a.md", "a.md", true},
+		{"docs/**", "docs/sub/a.md", true},
+		{"docs/**", "other/a.md", false},
+		{"a?.md", "ab.md", true},
+		{"[ab].md", "b.md", true},
+		{"[!ab].md", "b.md", false},
+		{`\*.md`, "*.md", true},
+		{`\*.md`, "a.md", false},
+	}
+	for _, c := range cases {
+		g, err := newGlob(c.pattern)
+		assert.NoError(t, err)
+		assert.Equal(t, c.match, g.match(c.path), "%s %s", c.pattern, c.path)
+	}
+}
+
+func TestGitignore(t *testing.T) {
+	g := &gitignore{}
+	assert.NoError(t, g.add("", strings.NewReader("# comment\n*.log\n!keep.log\n/root.txt\ntmp/\n\\#hash\n")))
+	assert.NoError(t, g.add("sub", strings.NewReader("*.txt\n!root.txt\nnested/a.md\n")))
+
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"a.log", false, true},
+		{"x/a.log", false, true},
+		{"keep.log", false, false},
+		{"root.txt", false, true},
+		{"x/root.txt", false, false},
+		{"tmp", true, true},
+		{"tmp", false, false},
+		{"#hash", false, true},
+		{"sub/a.txt", false, true},
+		{"sub/root.txt", false, false},
+		{"other/a.txt", false, false},
+		{"sub/nested/a.md", false, true},
+		{"sub/x/nested/a.md", false, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, g.ignored(c.path, c.isDir), c.path)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+// loadPrefix loads the objects under the prefix which pass the filters, in
+// the order of their keys.
+func (l *loader) loadPrefix(ctx context.Context, bucket, prefix string, o *document.LoaderOptions) ([]*schema.Document, error) {
+	keys, err := l.listKeys(ctx, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results  = make([][]*schema.Document, len(keys))
+		sem      = make(chan struct{}, l.concurrency)
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i, key := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, key string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			docs, err := l.loadObject(ctx, bucket, key, o)
+			if err != nil {
+				// Stop the other objects once one of them fails.
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			if l.useObjectKeyAsID && len(docs) > 1 {
+				// keep the IDs unique across the objects under the prefix
+				for idx, doc := range docs {
+					doc.ID = fmt.Sprintf("%s_%d", key, idx)
+				}
+			}
+			results[i] = docs
+		}(i, key)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("s3 loader load prefix err: %w", err)
+	}
+
+	var docs []*schema.Document
+	for _, r := range results {
+		docs = append(docs, r...)
+	}
+	return docs, nil
+}
+
+// listKeys lists the keys of the objects under the prefix which pass the
+// filters, paginating through all the results.
+func (l *loader) listKeys(ctx context.Context, bucket, prefix string) ([]string, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+	if !l.recursive {
+		input.Delimiter = aws.String(separator)
+	}
+
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(l.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("s3 loader list objects of bucket= %s, prefix= %s err: %w", bucket, prefix, err)
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if l.match(strings.TrimPrefix(key, prefix), aws.ToInt64(obj.Size)) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// match reports whether the object with the key relative to the prefix and
+// the size passes the filters.
+func (l *loader) match(rel string, size int64) bool {
+	// Keys ending with a slash are folder placeholders.
+	if rel == "" || strings.HasSuffix(rel, separator) {
+		return false
+	}
+	if l.maxObjectSize > 0 && size > l.maxObjectSize {
+		return false
+	}
+
+	if len(l.suffixes) > 0 {
+		var ok bool
+		for _, suffix := range l.suffixes {
+			if strings.HasSuffix(strings.ToLower(rel), strings.ToLower(suffix)) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if len(l.patterns) == 0 {
+		return true
+	}
+	for _, pattern := range l.patterns {
+		name := rel
+		if !strings.Contains(pattern, separator) {
+			name = path.Base(rel)
+		}
+		// Patterns are validated when the loader is created.
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,312 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubS3 serves ListObjectsV2 and GetObject of a single bucket, two objects
+// per page.
+type stubS3 struct {
+	objects  map[string]string
+	modified time.Time
+	failKey  string
+}
+
+type stubListResult struct {
+	XMLName               xml.Name     `xml:"ListBucketResult"`
+	Name                  string       `xml:"Name"`
+	Prefix                string       `xml:"Prefix"`
+	KeyCount              int          `xml:"KeyCount"`
+	IsTruncated           bool         `xml:"IsTruncated"`
+	NextContinuationToken string       `xml:"NextContinuationToken,omitempty"`
+	Contents              []stubObject `xml:"Contents"`
+	CommonPrefixes        []stubPrefix `xml:"CommonPrefixes"`
+}
+
+type stubObject struct {
+	Key          string `xml:"Key"`
+	ETag         string `xml:"ETag"`
+	Size         int    `xml:"Size"`
+	LastModified string `xml:"LastModified"`
+}
+
+type stubPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+func (s *stubS3) etag(key string) string {
+	return fmt.Sprintf(`"etag-%d"`, len(key))
+}
+
+func (s *stubS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != "bucket" {
+		http.Error(w, "no such bucket", http.StatusNotFound)
+		return
+	}
+
+	if key == "" && r.URL.Query().Get("list-type") == "2" {
+		s.list(w, r)
+		return
+	}
+
+	content, ok := s.objects[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `<Error><Code>NoSuchKey</Code></Error>`)
+		return
+	}
+	if key == s.failKey {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("ETag", s.etag(key))
+	w.Header().Set("Last-Modified", s.modified.Format(http.TimeFormat))
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	_, _ = io.WriteString(w, content)
+}
+
+func (s *stubS3) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+
+	var keys []string
+	prefixes := map[string]bool{}
+	for key := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				prefixes[key[:len(prefix)+i+1]] = true
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	start, _ := strconv.Atoi(query.Get("continuation-token"))
+	end := min(start+2, len(keys))
+	result := stubListResult{Name: "bucket", Prefix: prefix}
+	for _, key := range keys[start:end] {
+		result.Contents = append(result.Contents, stubObject{
+			Key:          key,
+			ETag:         s.etag(key),
+			Size:         len(s.objects[key]),
+			LastModified: s.modified.Format(time.RFC3339),
+		})
+	}
+	result.KeyCount = len(result.Contents)
+	if end < len(keys) {
+		result.IsTruncated = true
+		result.NextContinuationToken = strconv.Itoa(end)
+	} else {
+		for p := range prefixes {
+			result.CommonPrefixes = append(result.CommonPrefixes, stubPrefix{Prefix: p})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+type upperParser struct{}
+
+func (upperParser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	docs, err := parser.TextParser{}.Parse(ctx, reader, opts...)
+	if err != nil {
+		return nil, err
+	}
+	docs[0].Content = strings.ToUpper(docs[0].Content)
+	return docs, nil
+}
+
+// wordParser parses every word into a document.
+type wordParser struct{}
+
+func (wordParser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var docs []*schema.Document
+	for _, word := range strings.Fields(string(data)) {
+		docs = append(docs, &schema.Document{Content: word})
+	}
+	return docs, nil
+}
+
+func TestLoader_LoadPrefix(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubS3{
+		objects: map[string]string{
+			"docs/":         "",
+			"docs/a.md":     "alpha",
+			"docs/b.txt":    "bravo",
+			"docs/big.md":   strings.Repeat("x", 200),
+			"docs/c.MD":     "charlie",
+			"docs/sub/d.md": "delta",
+			"docs/sub/e.go": "package e",
+			"other/f.md":    "foxtrot",
+		},
+		modified: time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+	}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	newLoader := func(conf *LoaderConfig) document.Loader {
+		conf.Region = aws.String("us-east-1")
+		conf.AWSAccessKey = aws.String("ak")
+		conf.AWSSecretKey = aws.String("sk")
+		conf.AWSBaseEndpoint = aws.String(server.URL)
+		conf.UsePathStyle = true
+		l, err := NewS3Loader(ctx, conf)
+		assert.NoError(t, err)
+		return l
+	}
+	contents := func(docs []*schema.Document) []string {
+		var ret []string
+		for _, doc := range docs {
+			ret = append(ret, doc.Content)
+		}
+		return ret
+	}
+
+	t.Run("recursive with filters", func(t *testing.T) {
+		l := newLoader(&LoaderConfig{
+			Recursive:        true,
+			Suffixes:         []string{".md"},
+			MaxObjectSize:    100,
+			Concurrency:      2,
+			UseObjectKeyAsID: true,
+		})
+		docs, err := l.Load(ctx, document.Source{URI: "s3://bucket/docs/"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"alpha", "charlie", "delta"}, contents(docs))
+
+		assert.Equal(t, "docs/a.md", docs[0].ID)
+		assert.Equal(t, map[string]any{
+			MetaKeySource:       "s3://bucket/docs/a.md",
+			MetaKeyBucket:       "bucket",
+			MetaKeyKey:          "docs/a.md",
+			MetaKeyETag:         "etag-9",
+			MetaKeyLastModified: stub.modified,
+			MetaKeySize:         int64(5),
+		}, docs[0].MetaData)
+		assert.Equal(t, "docs/sub/d.md", docs[2].MetaData[MetaKeyKey])
+	})
+
+	t.Run("object key as ID of several documents", func(t *testing.T) {
+		ids := func(docs []*schema.Document) []string {
+			var ret []string
+			for _, doc := range docs {
+				ret = append(ret, doc.ID)
+			}
+			return ret
+		}
+		l := newLoader(&LoaderConfig{Parser: wordParser{}, UseObjectKeyAsID: true})
+		docs, err := l.Load(ctx, document.Source{URI: "s3://bucket/docs/sub/e.go"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"docs/sub/e.go", "docs/sub/e.go"}, ids(docs))
+
+		docs, err = l.Load(ctx, document.Source{URI: "s3://bucket/docs/sub/"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"docs/sub/d.md", "docs/sub/e.go_0", "docs/sub/e.go_1"}, ids(docs))
+	})
+
+	t.Run("direct children with patterns", func(t *testing.T) {
+		l := newLoader(&LoaderConfig{
+			Patterns: []string{"*.txt", "a.*", "sub/*.go"},
+		})
+		docs, err := l.Load(ctx, document.Source{URI: "s3://bucket/docs/"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"alpha", "bravo"}, contents(docs))
+
+		l = newLoader(&LoaderConfig{
+			Recursive: true,
+			Patterns:  []string{"sub/*.go"},
+		})
+		docs, err = l.Load(ctx, document.Source{URI: "s3://bucket/docs/"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"package e"}, contents(docs))
+	})
+
+	t.Run("parser by extension", func(t *testing.T) {
+		p, err := parser.NewExtParser(ctx, &parser.ExtParserConfig{
+			Parsers:        map[string]parser.Parser{".md": upperParser{}},
+			FallbackParser: parser.TextParser{},
+		})
+		assert.NoError(t, err)
+		l := newLoader(&LoaderConfig{Parser: p})
+		docs, err := l.Load(ctx, document.Source{URI: "s3://bucket/docs/"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ALPHA", "bravo", strings.ToUpper(stub.objects["docs/big.md"]), "charlie"}, contents(docs))
+	})
+
+	t.Run("whole bucket", func(t *testing.T) {
+		l := newLoader(&LoaderConfig{Recursive: true, Suffixes: []string{"f.md"}})
+		docs, err := l.Load(ctx, document.Source{URI: "s3://bucket/"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"foxtrot"}, contents(docs))
+	})
+
+	t.Run("single object", func(t *testing.T) {
+		l := newLoader(&LoaderConfig{})
+		docs, err := l.Load(ctx, document.Source{URI: "s3://bucket/docs/b.txt"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"bravo"}, contents(docs))
+		assert.Equal(t, int64(5), docs[0].MetaData[MetaKeySize])
+	})
+
+	t.Run("object fails", func(t *testing.T) {
+		stub.failKey = "docs/c.MD"
+		defer func() { stub.failKey = "" }()
+
+		l := newLoader(&LoaderConfig{Recursive: true})
+		_, err := l.Load(ctx, document.Source{URI: "s3://bucket/docs/"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "get object err")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewS3Loader(ctx, &LoaderConfig{Patterns: []string{"["}})
+		assert.Error(t, err)
+		_, err = NewS3Loader(ctx, &LoaderConfig{MaxObjectSize: -1})
+		assert.Error(t, err)
+		_, err = NewS3Loader(ctx, &LoaderConfig{Concurrency: -1})
+		assert.Error(t, err)
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -35,16 +36,40 @@ import (
 	"github.com/cloudwego/eino/schema"
 )
 
+const (
+	uriPrefix = `s3://`
+	separator = `/`
+
+	defaultConcurrency = 4
+)
+
+const (
+	MetaKeySource       = "_source"
+	MetaKeyBucket       = "_bucket"
+	MetaKeyKey          = "_key"
+	MetaKeyETag         = "_etag"
+	MetaKeyLastModified = "_last_modified"
+	MetaKeySize         = "_size"
+)
+
 // LoaderConfig is the configuration for s3 loader.
 type LoaderConfig struct {
 	Region          *string // the region of the AWS bucket
 	AWSAccessKey    *string
 	AWSSecretKey    *string
 	AWSBaseEndpoint *string
+	UsePathStyle    bool // whether to address buckets as <endpoint>/<bucket> instead of <bucket>.<endpoint>, required by most S3 compatible services
+
+	UseObjectKeyAsID bool // whether to use object key as document ID, suffixed with the index of the document if an object under a prefix is parsed into several documents
 
-	UseObjectKeyAsID bool // whether to use object key as document ID
+	Parser parser.Parser // the parser to parse the s3 object stream into documents, default to parser.TextParser, which directly converts []byte to string. Use parser.ExtParser to pick a parser by the extension of the object key
 
-	Parser parser.Parser // the parser to parse the s3 object stream into documents, default to parser.TextParser, which directly converts []byte to string
+	// The options below apply when the URI is a prefix ending with a slash, such as s3://bucket/docs/.
+	Recursive     bool     // whether to load the objects under nested prefixes, default to only the objects directly under the prefix
+	Patterns      []string // glob patterns of path.Match to load only matching objects. Patterns with a slash match the key relative to the prefix, others match the base name of the key
+	Suffixes      []string // suffixes of the keys to load, such as ".md", matched case-insensitively
+	MaxObjectSize int64    // objects larger than this size in bytes are skipped, 0 means no limit
+	Concurrency   int      // the number of objects fetched and parsed at the same time, default to 4
 }
 
 type loader struct {
@@ -53,6 +78,12 @@ type loader struct {
 	parser parser.Parser
 
 	useObjectKeyAsID bool
+
+	recursive     bool
+	patterns      []string
+	suffixes      []string
+	maxObjectSize int64
+	concurrency   int
 }
 
 // NewS3Loader creates a new s3 loader.
@@ -88,26 +119,51 @@ func NewS3Loader(ctx context.Context, conf *LoaderConfig) (document.Loader, erro
 		}))
 	}
 
+	for _, pattern := range conf.Patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("new s3 loader, invalid pattern %q: %w", pattern, err)
+		}
+	}
+	if conf.MaxObjectSize < 0 {
+		return nil, fmt.Errorf("new s3 loader, max object size must not be negative, got %d", conf.MaxObjectSize)
+	}
+	if conf.Concurrency < 0 {
+		return nil, fmt.Errorf("new s3 loader, concurrency must not be negative, got %d", conf.Concurrency)
+	}
+
 	sdkConfig, err := config.LoadDefaultConfig(ctx, s3Opts...)
 	if err != nil {
 		return nil, fmt.Errorf("new s3 loader, load config err: %w", err)
 	}
 
-	client := s3.NewFromConfig(sdkConfig)
+	client := s3.NewFromConfig(sdkConfig, func(o *s3.Options) {
+		o.UsePathStyle = conf.UsePathStyle
+	})
 
 	p := conf.Parser
 	if p == nil {
 		p = &parser.TextParser{}
 	}
 
+	concurrency := conf.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultConcurrency
+	}
+
 	return &loader{
 		client:           client,
 		parser:           p,
 		useObjectKeyAsID: conf.UseObjectKeyAsID,
+		recursive:        conf.Recursive,
+		patterns:         conf.Patterns,
+		suffixes:         conf.Suffixes,
+		maxObjectSize:    conf.MaxObjectSize,
+		concurrency:      concurrency,
 	}, nil
 }
 
-// Load loads the s3 object from the given URI.
+// Load loads the s3 object from the given URI, or all the objects under the
+// prefix if the URI ends with a slash.
 func (l *loader) Load(ctx context.Context, src document.Source, opts ...document.LoaderOption) (docs []*schema.Document, err error) {
 	ctx = callbacks.EnsureRunInfo(ctx, l.GetType(), components.ComponentOfLoader)
 	ctx = callbacks.OnStart(ctx, &document.LoaderCallbackInput{
@@ -126,12 +182,27 @@ func (l *loader) Load(ctx context.Context, src document.Source, opts ...document
 		return nil, err
 	}
 
-	// prefix is not supported now
+	o := document.GetLoaderCommonOptions(&document.LoaderOptions{}, opts...)
+
 	if isPrefix {
-		err = errors.New("s3 loader does not support batch load with prefix for now")
+		docs, err = l.loadPrefix(ctx, bucket, key, o)
+	} else {
+		docs, err = l.loadObject(ctx, bucket, key, o)
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	_ = callbacks.OnEnd(ctx, &document.LoaderCallbackOutput{
+		Source: src,
+		Docs:   docs,
+	})
+
+	return docs, nil
+}
+
+// loadObject gets an object and parses it into documents.
+func (l *loader) loadObject(ctx context.Context, bucket, key string, o *document.LoaderOptions) ([]*schema.Document, error) {
 	// get object from s3
 	resp, err := l.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
@@ -140,21 +211,32 @@ func (l *loader) Load(ctx context.Context, src document.Source, opts ...document
 	if err != nil {
 		var noKey *types.NoSuchKey
 		if errors.As(err, &noKey) {
-			err = fmt.Errorf("s3 loader bucket= %s, key= %s not found, err: %w", bucket, key, err)
-			return nil, err
+			return nil, fmt.Errorf("s3 loader bucket= %s, key= %s not found, err: %w", bucket, key, err)
 		}
 
-		err = fmt.Errorf("s3 loader get object err: %w", err)
-		return nil, err
+		return nil, fmt.Errorf("s3 loader get object err: %w", err)
 	}
 	defer resp.Body.Close()
 
-	o := document.GetLoaderCommonOptions(&document.LoaderOptions{}, opts...)
+	uri := uriPrefix + bucket + separator + key
+	meta := map[string]any{
+		MetaKeySource: uri,
+		MetaKeyBucket: bucket,
+		MetaKeyKey:    key,
+	}
+	if resp.ETag != nil {
+		meta[MetaKeyETag] = strings.Trim(*resp.ETag, `"`)
+	}
+	if resp.LastModified != nil {
+		meta[MetaKeyLastModified] = *resp.LastModified
+	}
+	if resp.ContentLength != nil {
+		meta[MetaKeySize] = *resp.ContentLength
+	}
 
-	docs, err = l.parser.Parse(ctx, resp.Body, append([]parser.Option{parser.WithURI(src.URI)}, o.ParserOptions...)...)
+	docs, err := l.parser.Parse(ctx, resp.Body, append([]parser.Option{parser.WithURI(uri), parser.WithExtraMeta(meta)}, o.ParserOptions...)...)
 	if err != nil {
-		err = fmt.Errorf("s3 loader parse err: %w", err)
-		return nil, err
+		return nil, fmt.Errorf("s3 loader parse err of [%s]: %w", uri, err)
 	}
 
 	if l.useObjectKeyAsID {
@@ -163,20 +245,10 @@ func (l *loader) Load(ctx context.Context, src document.Source, opts ...document
 		}
 	}
 
-	_ = callbacks.OnEnd(ctx, &document.LoaderCallbackOutput{
-		Source: src,
-		Docs:   docs,
-	})
-
 	return docs, nil
 }
 
 func uriToBucketAndKey(uri string) (bucket string, key string, isPrefix bool, err error) {
-	const (
-		uriPrefix = `s3://`
-		separator = `/`
-	)
-
 	if len(uri) == 0 {
 		return "", "", false, errors.New("s3 loader source uri is empty")
 	}
@@ -194,7 +266,8 @@ func uriToBucketAndKey(uri string) (bucket string, key string, isPrefix bool, er
 	bucket = bucketAndKey[:bucketEnd]
 	key = bucketAndKey[bucketEnd+1:]
 
-	if strings.HasSuffix(key, separator) {
+	// an empty key is the whole bucket
+	if key == "" || strings.HasSuffix(key, separator) {
 		return bucket, key, true, nil
 	}
 
@@ -95,12 +95,6 @@ func TestLoader_Load(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "incomplete")
 
-		_, err = s3Loader.Load(ctx, document.Source{
-			URI: "s3://bucket/prefix/",
-		})
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "does not support batch load with prefix")
-
 		mockey.PatchConvey("get object returns no such key", func() {
 			mockey.Mock((*s3.Client).GetObject).Return(nil, &types.NoSuchKey{}).Build()
 
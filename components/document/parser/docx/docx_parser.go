@@ -37,22 +37,35 @@ const (
 
 // Config is the configuration for Docx parser.
 type Config struct {
-	ToSections bool // whether to split content by sections
-	// deprecated: Comments are not supported yet.
+	ToSections      bool // whether to split content by sections
 	IncludeComments bool // whether to include comments in the parsed content
 	IncludeHeaders  bool // whether to include headers in the parsed content
 	IncludeFooters  bool // whether to include footers in the parsed content
 	IncludeTables   bool // whether to include table content
+
+	// ToMarkdown converts the document to markdown: heading styles become
+	// headings, numbered and bulleted paragraphs become lists, and tables
+	// become markdown tables. Comments are rendered as footnotes.
+	ToMarkdown bool
+	// ImageMode specifies how images are emitted in markdown mode.
+	// Default: ImageModeNone, images are dropped.
+	ImageMode ImageMode
+	// TrackedChanges specifies how tracked changes are handled in markdown mode.
+	// Default: TrackedChangesAccept.
+	TrackedChanges TrackedChanges
 }
 
-// DocxParser reads from io.Reader and parse Docx document content as plain text.
+// DocxParser reads from io.Reader and parse Docx document content as plain text,
+// or as markdown if ToMarkdown is set.
 type DocxParser struct {
-	toSections bool
-	// deprecated: Comments are not supported yet.
+	toSections      bool
 	includeComments bool
 	includeHeaders  bool
 	includeFooters  bool
 	includeTables   bool
+	toMarkdown      bool
+	imageMode       ImageMode
+	trackedChanges  TrackedChanges
 }
 
 // sectionTitles defines the custom display titles for specific section keys.
@@ -75,12 +88,25 @@ func NewDocxParser(_ context.Context, config *Config) (*DocxParser, error) {
 	if config == nil {
 		config = &Config{}
 	}
+	switch config.ImageMode {
+	case ImageModeNone, ImageModeInline, ImageModeDocument:
+	default:
+		return nil, fmt.Errorf("unknown image mode: %s", config.ImageMode)
+	}
+	switch config.TrackedChanges {
+	case TrackedChangesAccept, TrackedChangesReject, TrackedChangesMarkup:
+	default:
+		return nil, fmt.Errorf("unknown tracked changes handling: %s", config.TrackedChanges)
+	}
 	return &DocxParser{
 		toSections:      config.ToSections,
 		includeComments: config.IncludeComments,
 		includeHeaders:  config.IncludeHeaders,
 		includeFooters:  config.IncludeFooters,
 		includeTables:   config.IncludeTables,
+		toMarkdown:      config.ToMarkdown,
+		imageMode:       config.ImageMode,
+		trackedChanges:  config.TrackedChanges,
 	}, nil
 }
 
@@ -88,6 +114,14 @@ func NewDocxParser(_ context.Context, config *Config) (*DocxParser, error) {
 func (wp *DocxParser) Parse(_ context.Context, reader io.Reader, opts ...parser.Option) (docs []*schema.Document, err error) {
 	commonOpts := parser.GetCommonOptions(nil, opts...)
 
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("docx parser read all from reader failed: %w", err)
+	}
+	if wp.toMarkdown {
+		return wp.parseMarkdown(data, commonOpts.ExtraMeta)
+	}
+
 	// Create a temporary file to hold the docx content
 	tempFile, err := os.CreateTemp("", "eino-docx-*.docx")
 	if err != nil {
@@ -101,7 +135,7 @@ func (wp *DocxParser) Parse(_ context.Context, reader io.Reader, opts ...parser.
 	}()
 
 	// Copy the reader content to the temporary file
-	if _, err = io.Copy(tempFile, reader); err != nil {
+	if _, err = tempFile.Write(data); err != nil {
 		return nil, fmt.Errorf("docx parser failed to write to temporary file: %w", err)
 	}
 	// Close the file so it can be read by the library
@@ -119,6 +153,13 @@ func (wp *DocxParser) Parse(_ context.Context, reader io.Reader, opts ...parser.
 	if err != nil {
 		return nil, fmt.Errorf("open Docx document failed: %w", err)
 	}
+	if wp.includeComments {
+		p, err := openPkg(data)
+		if err != nil {
+			return nil, fmt.Errorf("open Docx document failed: %w", err)
+		}
+		sections["comments"] = p.commentsText()
+	}
 
 	// Extract content based on configuration
 	if wp.toSections {
@@ -165,6 +206,106 @@ func (wp *DocxParser) Parse(_ context.Context, reader io.Reader, opts ...parser.
 	return docs, nil
 }
 
+// parseMarkdown converts the document to markdown. Headers, main content and
+// footers are separate sections, and images are separate documents in
+// ImageModeDocument.
+func (wp *DocxParser) parseMarkdown(data []byte, extraMeta map[string]any) ([]*schema.Document, error) {
+	p, err := openPkg(data)
+	if err != nil {
+		return nil, fmt.Errorf("open Docx document failed: %w", err)
+	}
+	c := newConverter(p, wp)
+
+	type section struct{ key, content string }
+	var sections []section
+	convertParts := func(key, prefix string) error {
+		var parts []string
+		seen := map[string]bool{}
+		for _, name := range p.partNames(prefix) {
+			text, err := c.convert(name)
+			if err != nil {
+				return fmt.Errorf("convert %s failed: %w", name, err)
+			}
+			// Sections of a document often repeat the same header.
+			if text = strings.TrimSpace(text); text != "" && !seen[text] {
+				seen[text] = true
+				parts = append(parts, text)
+			}
+		}
+		sections = append(sections, section{key, strings.Join(parts, "\n\n")})
+		return nil
+	}
+
+	if wp.includeHeaders {
+		if err = convertParts("headers", "header"); err != nil {
+			return nil, err
+		}
+	}
+	main, err := c.convert("word/document.xml")
+	if err != nil {
+		return nil, fmt.Errorf("convert word/document.xml failed: %w", err)
+	}
+	if notes := c.footnotes(); notes != "" {
+		main += "\n\n" + notes
+	}
+	sections = append(sections, section{"main", main})
+	if wp.includeFooters {
+		if err = convertParts("footers", "footer"); err != nil {
+			return nil, err
+		}
+	}
+
+	newDoc := func(key, content string) *schema.Document {
+		metadata := make(map[string]interface{}, len(extraMeta)+1)
+		for k, v := range extraMeta {
+			metadata[k] = v
+		}
+		metadata[SectionTypeKey] = key
+		return &schema.Document{
+			ID:       uuid.New().String(),
+			Content:  content,
+			MetaData: metadata,
+		}
+	}
+
+	var docs []*schema.Document
+	if wp.toSections {
+		for _, s := range sections {
+			if content := strings.TrimSpace(c.resolve(s.content)); content != "" {
+				docs = append(docs, newDoc(s.key, content))
+			}
+		}
+	} else {
+		var parts []string
+		for _, s := range sections {
+			if content := strings.TrimSpace(c.resolve(s.content)); content != "" {
+				parts = append(parts, content)
+			}
+		}
+		if len(parts) > 0 {
+			docs = append(docs, newDoc("fullContent", strings.Join(parts, "\n\n")))
+		}
+	}
+
+	if wp.imageMode == ImageModeDocument {
+		for _, img := range c.imgs {
+			content := img.caption
+			if content == "" {
+				content = img.alt
+			}
+			doc := newDoc("image", content)
+			doc.MetaData[MetaKeyImageName] = img.name
+			doc.MetaData[MetaKeyImageMIME] = img.mime
+			doc.MetaData[MetaKeyImageData] = img.data
+			if img.caption != "" {
+				doc.MetaData[MetaKeyImageCaption] = img.caption
+			}
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
 func GetSectionType(doc *schema.Document) (string, bool) {
 	if doc == nil {
 		return "", false
@@ -17,6 +17,8 @@
 package docx
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"os"
 	"testing"
@@ -73,3 +75,177 @@ func TestDocxParser_Parse(t *testing.T) {
 
 	})
 }
+
+const (
+	testStyles = `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/></w:style>
+<w:style w:type="paragraph" w:styleId="Clause"><w:name w:val="Clause"/><w:basedOn w:val="Heading2"/></w:style>
+<w:style w:type="paragraph" w:styleId="Caption"><w:name w:val="caption"/></w:style>
+<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:pPr><w:numPr><w:numId w:val="2"/></w:numPr></w:pPr></w:style>
+</w:styles>`
+	testNumbering = `<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:abstractNum w:abstractNumId="0">
+<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="decimal"/></w:lvl>
+<w:lvl w:ilvl="1"><w:start w:val="1"/><w:numFmt w:val="lowerLetter"/></w:lvl>
+</w:abstractNum>
+<w:abstractNum w:abstractNumId="1"><w:lvl w:ilvl="0"><w:numFmt w:val="bullet"/></w:lvl></w:abstractNum>
+<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>
+<w:num w:numId="2"><w:abstractNumId w:val="1"/></w:num>
+</w:numbering>`
+	testComments = `<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:comment w:id="0" w:author="Alice"><w:p><w:r><w:t>Check the party names.</w:t></w:r></w:p></w:comment>
+</w:comments>`
+	testDocument = `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+ xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+ xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"
+ xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"
+ xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"><w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Service Agreement</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Between the parties listed on </w:t></w:r><w:hyperlink r:id="rId1"><w:r><w:t>our site</w:t></w:r></w:hyperlink><w:r><w:t>.</w:t></w:r><w:r><w:commentReference w:id="0"/></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="Clause"/></w:pPr><w:r><w:t>Terms</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Scope</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Services</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Support</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">The fee is </w:t></w:r><w:del w:id="1" w:author="Bob"><w:r><w:delText>100</w:delText></w:r></w:del><w:ins w:id="2" w:author="Bob"><w:r><w:t>200</w:t></w:r></w:ins><w:r><w:t xml:space="preserve"> dollars.</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="ListBullet"/></w:pPr><w:r><w:t>Notes apply</w:t></w:r></w:p>
+<w:tbl>
+<w:tr><w:tc><w:p><w:r><w:t>Item</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Price</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Unit</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:tcPr><w:vMerge w:val="restart"/></w:tcPr><w:p><w:r><w:t>Hosting</w:t></w:r></w:p></w:tc><w:tc><w:tcPr><w:gridSpan w:val="2"/></w:tcPr><w:p><w:r><w:t>10 | month</w:t></w:r></w:p><w:p><w:r><w:t>billed yearly</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:tcPr><w:vMerge/></w:tcPr><w:p/></w:tc><w:tc><w:p><w:r><w:t>5</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>day</w:t></w:r></w:p></w:tc></w:tr>
+</w:tbl>
+<w:p><w:r><w:drawing><wp:inline><wp:docPr id="1" name="Picture 1" descr="Network diagram"/><a:graphic><a:graphicData><pic:pic><pic:blipFill><a:blip r:embed="rId2"/></pic:blipFill></pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="Caption"/></w:pPr><w:r><w:t>Figure 1: Deployment</w:t></w:r></w:p>
+</w:body></w:document>`
+	testRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com" TargetMode="External"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1.png"/>
+</Relationships>`
+	testHeader = `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>Confidential</w:t></w:r></w:p></w:hdr>`
+	testFooter = `<w:ftr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>Page footer</w:t></w:r></w:p></w:ftr>`
+)
+
+// buildDocx zips the parts of a minimal docx document.
+func buildDocx(t *testing.T) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"word/document.xml":            testDocument,
+		"word/_rels/document.xml.rels": testRels,
+		"word/styles.xml":              testStyles,
+		"word/numbering.xml":           testNumbering,
+		"word/comments.xml":            testComments,
+		"word/header1.xml":             testHeader,
+		"word/header2.xml":             testHeader,
+		"word/footer1.xml":             testFooter,
+		"word/media/image1.png":        "PNG",
+	} {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestDocxParser_Markdown(t *testing.T) {
+	ctx := context.Background()
+	const table = "| Item | Price | Unit |\n" +
+		"| --- | --- | --- |\n" +
+		"| Hosting | 10 \\| month<br>billed yearly |  |\n" +
+		"|  | 5 | day |"
+
+	t.Run("sections", func(t *testing.T) {
+		p, err := NewDocxParser(ctx, &Config{
+			ToSections:      true,
+			ToMarkdown:      true,
+			IncludeComments: true,
+			IncludeHeaders:  true,
+			IncludeFooters:  true,
+			IncludeTables:   true,
+			ImageMode:       ImageModeDocument,
+		})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, buildDocx(t), parser.WithExtraMeta(map[string]any{"test": "test"}))
+		assert.NoError(t, err)
+		assert.Equal(t, 4, len(docs))
+
+		assert.Equal(t, "headers", docs[0].MetaData[SectionTypeKey])
+		assert.Equal(t, "Confidential", docs[0].Content)
+
+		assert.Equal(t, "main", docs[1].MetaData[SectionTypeKey])
+		assert.Equal(t, "test", docs[1].MetaData["test"])
+		assert.Equal(t, "# Service Agreement\n\n"+
+			"Between the parties listed on [our site](https://example.com).[^1]\n\n"+
+			"## Terms\n\n"+
+			"1. Scope\n"+
+			"    1. Services\n"+
+			"    2. Support\n"+
+			"2. The fee is 200 dollars.\n"+
+			"- Notes apply\n\n"+
+			table+"\n\n"+
+			"![Figure 1: Deployment](image1.png)\n\n"+
+			"Figure 1: Deployment\n\n"+
+			"[^1]: Alice: Check the party names.", docs[1].Content)
+
+		assert.Equal(t, "footers", docs[2].MetaData[SectionTypeKey])
+		assert.Equal(t, "Page footer", docs[2].Content)
+
+		assert.Equal(t, map[string]any{
+			"test":              "test",
+			SectionTypeKey:      "image",
+			MetaKeyImageName:    "image1.png",
+			MetaKeyImageMIME:    "image/png",
+			MetaKeyImageData:    "UE5H",
+			MetaKeyImageCaption: "Figure 1: Deployment",
+		}, docs[3].MetaData)
+		assert.Equal(t, "Figure 1: Deployment", docs[3].Content)
+	})
+
+	t.Run("tracked changes and inline images", func(t *testing.T) {
+		for _, tt := range []struct {
+			changes TrackedChanges
+			want    string
+		}{
+			{TrackedChangesReject, "2. The fee is 100 dollars."},
+			{TrackedChangesMarkup, "2. The fee is <del>100</del><ins>200</ins> dollars."},
+		} {
+			p, err := NewDocxParser(ctx, &Config{ToMarkdown: true, ImageMode: ImageModeInline, TrackedChanges: tt.changes})
+			assert.NoError(t, err)
+
+			docs, err := p.Parse(ctx, buildDocx(t))
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(docs))
+			assert.Equal(t, "fullContent", docs[0].MetaData[SectionTypeKey])
+			assert.Contains(t, docs[0].Content, tt.want)
+			assert.Contains(t, docs[0].Content, "![Figure 1: Deployment](data:image/png;base64,UE5H)")
+			assert.NotContains(t, docs[0].Content, "| Item |")
+			assert.NotContains(t, docs[0].Content, "[^1]")
+		}
+	})
+
+	t.Run("comments in plain text", func(t *testing.T) {
+		p, err := NewDocxParser(ctx, &Config{ToSections: true, IncludeComments: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, buildDocx(t))
+		assert.NoError(t, err)
+		var comments []string
+		for _, doc := range docs {
+			if typ, _ := GetSectionType(doc); typ == "comments" {
+				comments = append(comments, doc.Content)
+			}
+		}
+		assert.Equal(t, []string{"=== COMMENTS ===\nAlice: Check the party names."}, comments)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewDocxParser(ctx, &Config{ImageMode: "unknown"})
+		assert.Error(t, err)
+		_, err = NewDocxParser(ctx, &Config{TrackedChanges: "unknown"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,530 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docx
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ImageMode specifies how images are emitted in markdown mode.
+type ImageMode string
+
+const (
+	// ImageModeNone drops images.
+	ImageModeNone ImageMode = ""
+	// ImageModeInline embeds images in the content as base64 data URIs.
+	ImageModeInline ImageMode = "inline"
+	// ImageModeDocument references images by file name in the content, and
+	// emits every image as a separate document whose content is its caption.
+	ImageModeDocument ImageMode = "document"
+)
+
+// TrackedChanges specifies how tracked insertions and deletions are handled
+// in markdown mode.
+type TrackedChanges string
+
+const (
+	// TrackedChangesAccept keeps insertions and drops deletions, as if all
+	// changes were accepted.
+	TrackedChangesAccept TrackedChanges = ""
+	// TrackedChangesReject keeps deletions and drops insertions, as if all
+	// changes were rejected.
+	TrackedChangesReject TrackedChanges = "reject"
+	// TrackedChangesMarkup keeps both, wrapped in <ins> and <del> tags.
+	TrackedChangesMarkup TrackedChanges = "markup"
+)
+
+// Metadata keys of image documents.
+const (
+	MetaKeyImageName    = "_image_name"
+	MetaKeyImageMIME    = "_image_mime"
+	MetaKeyImageData    = "_image_data" // base64 encoded
+	MetaKeyImageCaption = "_image_caption"
+)
+
+type image struct {
+	name, mime, data string
+	alt, caption     string
+}
+
+// block is a rendered paragraph or table of a part.
+type block struct {
+	text    string
+	list    bool
+	caption bool
+	images  []int
+}
+
+// listCounter holds the current number of every level of a list.
+type listCounter struct {
+	n   [9]int
+	set [9]bool
+}
+
+// converter renders WordprocessingML parts as markdown.
+type converter struct {
+	pkg     *pkg
+	part    string
+	images  ImageMode
+	changes TrackedChanges
+	tables  bool
+	notes   bool
+
+	// cell is the depth of table cells being rendered, in which paragraphs
+	// are rendered as plain text.
+	cell     int
+	imgs     []*image
+	lists    map[string]*listCounter
+	noteIDs  map[string]int
+	noteRefs []string
+	err      error
+}
+
+func newConverter(p *pkg, wp *DocxParser) *converter {
+	return &converter{
+		pkg:     p,
+		images:  wp.imageMode,
+		changes: wp.trackedChanges,
+		tables:  wp.includeTables,
+		notes:   wp.includeComments,
+		lists:   map[string]*listCounter{},
+		noteIDs: map[string]int{},
+	}
+}
+
+// imagePlaceholder marks the position of an image until its caption is known.
+const imagePlaceholder = "\x00image%d\x00"
+
+// convert renders a part, such as word/document.xml or word/header1.xml.
+func (c *converter) convert(part string) (string, error) {
+	n, err := c.pkg.part(part)
+	if err != nil || n == nil {
+		return "", err
+	}
+	c.part = part
+	if body := n.child("body"); body != nil {
+		n = body
+	}
+	blocks := c.blocks(n)
+	if c.err != nil {
+		return "", c.err
+	}
+
+	var sb strings.Builder
+	for i, b := range blocks {
+		if i > 0 {
+			if b.list && blocks[i-1].list {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString("\n\n")
+			}
+		}
+		sb.WriteString(b.text)
+	}
+	return sb.String(), nil
+}
+
+// footnotes renders the comments referenced so far as markdown footnotes.
+func (c *converter) footnotes() string {
+	lines := make([]string, 0, len(c.noteRefs))
+	for i, id := range c.noteRefs {
+		cm := c.pkg.comments[id]
+		text := cm.text
+		if cm.author != "" {
+			text = cm.author + ": " + text
+		}
+		lines = append(lines, fmt.Sprintf("[^%d]: %s", i+1, text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// resolve replaces the image placeholders in text.
+func (c *converter) resolve(text string) string {
+	for i, img := range c.imgs {
+		alt := img.caption
+		if alt == "" {
+			alt = img.alt
+		}
+		alt = strings.NewReplacer("[", "", "]", "", "\n", " ").Replace(alt)
+		var md string
+		switch c.images {
+		case ImageModeInline:
+			md = fmt.Sprintf("![%s](data:%s;base64,%s)", alt, img.mime, img.data)
+		case ImageModeDocument:
+			md = fmt.Sprintf("![%s](%s)", alt, img.name)
+		}
+		text = strings.ReplaceAll(text, fmt.Sprintf(imagePlaceholder, i), md)
+	}
+	return text
+}
+
+func (c *converter) blocks(n *node) []block {
+	if n == nil {
+		return nil
+	}
+	var (
+		out []block
+		// caption is a caption paragraph waiting for the image after it.
+		caption string
+	)
+	for i := range n.Nodes {
+		child := &n.Nodes[i]
+		switch child.name() {
+		case "p":
+			b, ok := c.paragraph(child)
+			if !ok {
+				continue
+			}
+			if b.caption {
+				if len(out) > 0 && c.captionImages(out[len(out)-1].images, b.text) {
+					caption = ""
+				} else {
+					caption = b.text
+				}
+			} else if caption != "" {
+				c.captionImages(b.images, caption)
+				caption = ""
+			}
+			out = append(out, b)
+		case "tbl":
+			caption = ""
+			if !c.tables {
+				continue
+			}
+			if text := c.table(child); text != "" {
+				out = append(out, block{text: text})
+			}
+		case "sdt":
+			out = append(out, c.blocks(child.child("sdtContent"))...)
+		case "customXml":
+			out = append(out, c.blocks(child)...)
+		}
+	}
+	return out
+}
+
+// captionImages sets the caption of images that have none, reporting whether
+// there were any.
+func (c *converter) captionImages(images []int, caption string) bool {
+	var ok bool
+	for _, i := range images {
+		if c.imgs[i].caption == "" {
+			c.imgs[i].caption = caption
+			ok = true
+		}
+	}
+	return ok
+}
+
+func (c *converter) paragraph(p *node) (block, bool) {
+	var (
+		sb  strings.Builder
+		b   block
+		ppr = p.child("pPr")
+	)
+	start := len(c.imgs)
+	c.inline(p, &sb)
+	for i := start; i < len(c.imgs); i++ {
+		b.images = append(b.images, i)
+	}
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return b, false
+	}
+
+	styleID := ppr.val("pStyle")
+	numID, ilvl := "", ""
+	if numPr := ppr.child("numPr"); numPr != nil {
+		numID, ilvl = numPr.val("numId"), numPr.val("ilvl")
+	} else {
+		numID, ilvl = c.pkg.styleNumbering(styleID)
+	}
+	level := c.pkg.headingLevel(styleID)
+	if lvl := ppr.val("outlineLvl"); lvl != "" {
+		if l, err := strconv.Atoi(lvl); err == nil && l < 9 {
+			level = l + 1
+		}
+	}
+
+	switch {
+	case c.cell > 0:
+	case level > 0:
+		text = strings.Repeat("#", min(level, 6)) + " " + strings.Join(strings.Fields(text), " ")
+	case numID != "" && numID != "0":
+		text = c.listItem(numID, ilvl, text)
+		b.list = true
+	default:
+		b.caption = c.pkg.styleName(styleID) == "caption"
+	}
+	b.text = text
+	return b, true
+}
+
+func (c *converter) listItem(numID, ilvl, text string) string {
+	level, _ := strconv.Atoi(ilvl)
+	level = max(0, min(level, 8))
+	counter, ok := c.lists[numID]
+	if !ok {
+		counter = &listCounter{}
+		c.lists[numID] = counter
+	}
+	for l := level + 1; l < len(counter.set); l++ {
+		counter.set[l] = false
+	}
+
+	format, start := "bullet", 1
+	if num, ok := c.pkg.nums[numID]; ok && level < len(num.formats) {
+		format, start = num.formats[level], num.starts[level]
+	}
+	if counter.set[level] {
+		counter.n[level]++
+	} else {
+		counter.n[level], counter.set[level] = start, true
+	}
+
+	indent := strings.Repeat("    ", level)
+	marker := "- "
+	if format != "bullet" && format != "none" && format != "" {
+		marker = strconv.Itoa(counter.n[level]) + ". "
+	}
+	cont := "\n" + indent + strings.Repeat(" ", len(marker))
+	return indent + marker + strings.ReplaceAll(text, "\n", cont)
+}
+
+// inline renders the runs under n.
+func (c *converter) inline(n *node, sb *strings.Builder) {
+	if n == nil {
+		return
+	}
+	for i := range n.Nodes {
+		child := &n.Nodes[i]
+		switch child.name() {
+		case "r":
+			c.run(child, sb)
+		case "hyperlink":
+			var inner strings.Builder
+			c.inline(child, &inner)
+			text := inner.String()
+			rel, err := c.pkg.rel(c.part, child.relAttr("id"))
+			if err != nil {
+				c.err = err
+			}
+			if rel.external && rel.target != "" && strings.TrimSpace(text) != "" {
+				fmt.Fprintf(sb, "[%s](%s)", text, rel.target)
+			} else {
+				sb.WriteString(text)
+			}
+		case "ins", "moveTo":
+			c.change(child, sb, TrackedChangesAccept, "ins")
+		case "del", "moveFrom":
+			c.change(child, sb, TrackedChangesReject, "del")
+		case "sdt":
+			c.inline(child.child("sdtContent"), sb)
+		case "smartTag", "fldSimple", "customXml", "dir", "bdo":
+			c.inline(child, sb)
+		}
+	}
+}
+
+// change renders a tracked change, which is kept as is if changes are
+// handled by keep, and wrapped in the tag if they are marked up.
+func (c *converter) change(n *node, sb *strings.Builder, keep TrackedChanges, tag string) {
+	switch c.changes {
+	case keep:
+		c.inline(n, sb)
+	case TrackedChangesMarkup:
+		var inner strings.Builder
+		c.inline(n, &inner)
+		if inner.Len() > 0 {
+			fmt.Fprintf(sb, "<%s>%s</%s>", tag, inner.String(), tag)
+		}
+	}
+}
+
+func (c *converter) run(r *node, sb *strings.Builder) {
+	for i := range r.Nodes {
+		child := &r.Nodes[i]
+		switch child.name() {
+		case "t", "delText":
+			sb.WriteString(child.Text)
+		case "tab", "ptab":
+			sb.WriteString("\t")
+		case "br":
+			if typ := child.attr("type"); typ == "" || typ == "textWrapping" {
+				sb.WriteString("\n")
+			}
+		case "cr":
+			sb.WriteString("\n")
+		case "noBreakHyphen":
+			sb.WriteString("-")
+		case "drawing", "pict", "object":
+			sb.WriteString(c.image(child))
+		case "commentReference":
+			if !c.notes {
+				continue
+			}
+			id := child.attr("id")
+			if _, ok := c.pkg.comments[id]; !ok {
+				continue
+			}
+			n, ok := c.noteIDs[id]
+			if !ok {
+				c.noteRefs = append(c.noteRefs, id)
+				n = len(c.noteRefs)
+				c.noteIDs[id] = n
+			}
+			fmt.Fprintf(sb, "[^%d]", n)
+		}
+	}
+}
+
+// image records the image in a drawing and returns its placeholder.
+func (c *converter) image(n *node) string {
+	if c.images == ImageModeNone {
+		return ""
+	}
+	var id, alt string
+	n.find(func(d *node) bool {
+		switch d.name() {
+		case "docPr":
+			if alt = d.attr("descr"); alt == "" {
+				alt = d.attr("title")
+			}
+		case "blip":
+			if id == "" {
+				id = d.relAttr("embed")
+			}
+		case "imagedata":
+			if id == "" {
+				id = d.relAttr("id")
+			}
+			if alt == "" {
+				alt = d.attr("title")
+			}
+		}
+		return true
+	})
+	if id == "" {
+		return ""
+	}
+	rel, err := c.pkg.rel(c.part, id)
+	if err != nil {
+		c.err = err
+		return ""
+	}
+	if rel.external || rel.target == "" {
+		return ""
+	}
+	data, err := c.pkg.read(rel.target)
+	if err != nil {
+		c.err = err
+		return ""
+	}
+	if data == nil {
+		return ""
+	}
+	typ := mime.TypeByExtension(path.Ext(rel.target))
+	if typ == "" {
+		typ = "application/octet-stream"
+	}
+	c.imgs = append(c.imgs, &image{
+		name: path.Base(rel.target),
+		mime: typ,
+		data: base64.StdEncoding.EncodeToString(data),
+		alt:  strings.TrimSpace(alt),
+	})
+	return fmt.Sprintf(imagePlaceholder, len(c.imgs)-1)
+}
+
+// table renders a table as a markdown table with its first row as the
+// header. Merged cells are repeated as empty cells.
+func (c *converter) table(n *node) string {
+	c.cell++
+	defer func() { c.cell-- }()
+
+	var (
+		rows  [][]string
+		width int
+	)
+	for i := range n.Nodes {
+		tr := &n.Nodes[i]
+		if tr.name() != "tr" {
+			continue
+		}
+		var row []string
+		for j := range tr.Nodes {
+			tc := &tr.Nodes[j]
+			if tc.name() != "tc" {
+				continue
+			}
+			tcPr := tc.child("tcPr")
+			var text string
+			if vMerge := tcPr.child("vMerge"); vMerge == nil || vMerge.attr("val") == "restart" {
+				text = c.cellText(tc)
+			}
+			row = append(row, text)
+			span, _ := strconv.Atoi(tcPr.val("gridSpan"))
+			for k := 1; k < span; k++ {
+				row = append(row, "")
+			}
+		}
+		rows = append(rows, row)
+		width = max(width, len(row))
+	}
+	if width == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for k := 0; k < width; k++ {
+			var cell string
+			if k < len(cells) {
+				cell = cells[k]
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(rows[0])
+	sep := make([]string, width)
+	for k := range sep {
+		sep[k] = "---"
+	}
+	writeRow(sep)
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// cellText renders the paragraphs of a cell on a single line, separated by
+// <br>. Nested tables are flattened the same way.
+func (c *converter) cellText(tc *node) string {
+	var parts []string
+	for _, b := range c.blocks(tc) {
+		parts = append(parts, b.text)
+	}
+	text := strings.Join(parts, "\n")
+	return strings.NewReplacer("\n", "<br>", "|", "\\|").Replace(text)
+}
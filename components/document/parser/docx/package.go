@@ -0,0 +1,439 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// node is a generic XML element. Elements and attributes are matched by their
+// local name, as WordprocessingML documents use fixed prefixes.
+type node struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Nodes   []node     `xml:",any"`
+	Text    string     `xml:",chardata"`
+}
+
+const relationshipsNS = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+func (n *node) name() string {
+	return n.XMLName.Local
+}
+
+// attr returns the value of the attribute with the given local name, ignoring
+// relationship attributes such as r:id.
+func (n *node) attr(local string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == local && a.Name.Space != relationshipsNS {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// relAttr returns the value of a relationship attribute, such as r:id or
+// r:embed.
+func (n *node) relAttr(local string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == local && a.Name.Space == relationshipsNS {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *node) child(name string) *node {
+	if n == nil {
+		return nil
+	}
+	for i := range n.Nodes {
+		if n.Nodes[i].name() == name {
+			return &n.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// val returns the w:val attribute of the named child.
+func (n *node) val(name string) string {
+	if c := n.child(name); c != nil {
+		return c.attr("val")
+	}
+	return ""
+}
+
+// find calls fn on n and its descendants in document order, skipping the
+// descendants of nodes for which fn returns false.
+func (n *node) find(fn func(*node) bool) {
+	if !fn(n) {
+		return
+	}
+	for i := range n.Nodes {
+		n.Nodes[i].find(fn)
+	}
+}
+
+// pkg is an opened docx file.
+type pkg struct {
+	files map[string]*zip.File
+	rels  map[string]map[string]relationship
+	// styles by ID.
+	styles map[string]*style
+	// nums are numbering instances by ID.
+	nums map[string]*numbering
+	// comments by ID.
+	comments map[string]comment
+}
+
+type relationship struct {
+	target   string
+	external bool
+}
+
+type style struct {
+	name, basedOn string
+	outlineLevel  int // 1-based, 0 if none
+	numID, ilvl   string
+}
+
+type numbering struct {
+	formats []string // by level
+	starts  []int    // by level
+}
+
+type comment struct {
+	author string
+	text   string
+}
+
+func openPkg(data []byte) (*pkg, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open docx archive failed: %w", err)
+	}
+	p := &pkg{
+		files:    map[string]*zip.File{},
+		rels:     map[string]map[string]relationship{},
+		styles:   map[string]*style{},
+		nums:     map[string]*numbering{},
+		comments: map[string]comment{},
+	}
+	for _, f := range zr.File {
+		p.files[f.Name] = f
+	}
+	if _, ok := p.files["word/document.xml"]; !ok {
+		return nil, fmt.Errorf("word/document.xml not found in docx archive")
+	}
+	if err = p.readStyles(); err != nil {
+		return nil, err
+	}
+	if err = p.readNumbering(); err != nil {
+		return nil, err
+	}
+	if err = p.readComments(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *pkg) read(name string) ([]byte, error) {
+	f, ok := p.files[name]
+	if !ok {
+		return nil, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s failed: %w", name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("read %s failed: %w", name, err)
+	}
+	return data, nil
+}
+
+// part parses the XML part with the given name, returning nil if the part
+// does not exist.
+func (p *pkg) part(name string) (*node, error) {
+	data, err := p.read(name)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var n node
+	if err = xml.Unmarshal(data, &n); err != nil {
+		return nil, fmt.Errorf("parse %s failed: %w", name, err)
+	}
+	return &n, nil
+}
+
+// partNames returns the names of the parts in the word directory whose base
+// name starts with prefix, such as header1.xml and header2.xml.
+func (p *pkg) partNames(prefix string) []string {
+	var names []string
+	for name := range p.files {
+		if dir, base := path.Split(name); dir == "word/" && strings.HasPrefix(base, prefix) && strings.HasSuffix(base, ".xml") {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// header2.xml before header10.xml.
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// rel resolves a relationship of the given part.
+func (p *pkg) rel(part, id string) (relationship, error) {
+	rels, ok := p.rels[part]
+	if !ok {
+		rels = map[string]relationship{}
+		dir, base := path.Split(part)
+		n, err := p.part(dir + "_rels/" + base + ".rels")
+		if err != nil {
+			return relationship{}, err
+		}
+		if n != nil {
+			for _, r := range n.Nodes {
+				target := r.attr("Target")
+				external := r.attr("TargetMode") == "External"
+				if !external {
+					if strings.HasPrefix(target, "/") {
+						target = strings.TrimPrefix(target, "/")
+					} else {
+						target = path.Join(dir, target)
+					}
+				}
+				rels[r.attr("Id")] = relationship{target: target, external: external}
+			}
+		}
+		p.rels[part] = rels
+	}
+	return rels[id], nil
+}
+
+func (p *pkg) readStyles() error {
+	n, err := p.part("word/styles.xml")
+	if err != nil || n == nil {
+		return err
+	}
+	for _, s := range n.Nodes {
+		if s.name() != "style" {
+			continue
+		}
+		st := &style{
+			name:    strings.ToLower(s.val("name")),
+			basedOn: s.val("basedOn"),
+		}
+		ppr := s.child("pPr")
+		if lvl := ppr.val("outlineLvl"); lvl != "" {
+			if l, err := strconv.Atoi(lvl); err == nil && l < 9 {
+				st.outlineLevel = l + 1
+			}
+		}
+		if numPr := ppr.child("numPr"); numPr != nil {
+			st.numID, st.ilvl = numPr.val("numId"), numPr.val("ilvl")
+		}
+		p.styles[s.attr("styleId")] = st
+	}
+	return nil
+}
+
+func (p *pkg) readNumbering() error {
+	n, err := p.part("word/numbering.xml")
+	if err != nil || n == nil {
+		return err
+	}
+	abstract := map[string]*numbering{}
+	for _, a := range n.Nodes {
+		if a.name() != "abstractNum" {
+			continue
+		}
+		num := &numbering{}
+		for _, l := range a.Nodes {
+			if l.name() != "lvl" {
+				continue
+			}
+			ilvl, err := strconv.Atoi(l.attr("ilvl"))
+			if err != nil || ilvl < 0 || ilvl > 8 {
+				continue
+			}
+			for len(num.formats) <= ilvl {
+				num.formats = append(num.formats, "")
+				num.starts = append(num.starts, 1)
+			}
+			num.formats[ilvl] = l.val("numFmt")
+			if start, err := strconv.Atoi(l.val("start")); err == nil {
+				num.starts[ilvl] = start
+			}
+		}
+		abstract[a.attr("abstractNumId")] = num
+	}
+	for _, c := range n.Nodes {
+		if c.name() != "num" {
+			continue
+		}
+		base, ok := abstract[c.val("abstractNumId")]
+		if !ok {
+			continue
+		}
+		num := &numbering{
+			formats: base.formats,
+			starts:  append([]int(nil), base.starts...),
+		}
+		for _, o := range c.Nodes {
+			if o.name() != "lvlOverride" {
+				continue
+			}
+			ilvl, err := strconv.Atoi(o.attr("ilvl"))
+			if err != nil || ilvl < 0 || ilvl >= len(num.starts) {
+				continue
+			}
+			if start, err := strconv.Atoi(o.val("startOverride")); err == nil {
+				num.starts[ilvl] = start
+			}
+		}
+		p.nums[c.attr("numId")] = num
+	}
+	return nil
+}
+
+func (p *pkg) readComments() error {
+	n, err := p.part("word/comments.xml")
+	if err != nil || n == nil {
+		return err
+	}
+	for i := range n.Nodes {
+		c := &n.Nodes[i]
+		if c.name() != "comment" {
+			continue
+		}
+		var paragraphs []string
+		for _, para := range c.Nodes {
+			if para.name() == "p" {
+				if text := strings.TrimSpace(plainText(&para)); text != "" {
+					paragraphs = append(paragraphs, text)
+				}
+			}
+		}
+		p.comments[c.attr("id")] = comment{author: c.attr("author"), text: strings.Join(paragraphs, " ")}
+	}
+	return nil
+}
+
+// headingLevel returns the heading level of a paragraph style, from its name
+// or outline level, following basedOn.
+func (p *pkg) headingLevel(styleID string) int {
+	for depth := 0; depth < 10; depth++ {
+		st, ok := p.styles[styleID]
+		if !ok {
+			return 0
+		}
+		switch {
+		case st.name == "title":
+			return 1
+		case strings.HasPrefix(st.name, "heading "):
+			if l, err := strconv.Atoi(strings.TrimPrefix(st.name, "heading ")); err == nil && l > 0 {
+				return l
+			}
+		case st.outlineLevel > 0:
+			return st.outlineLevel
+		}
+		styleID = st.basedOn
+	}
+	return 0
+}
+
+// styleName returns the lower-cased name of a style.
+func (p *pkg) styleName(styleID string) string {
+	if st, ok := p.styles[styleID]; ok {
+		return st.name
+	}
+	return strings.ToLower(styleID)
+}
+
+// styleNumbering returns the numbering that a paragraph style applies.
+func (p *pkg) styleNumbering(styleID string) (numID, ilvl string) {
+	for depth := 0; depth < 10; depth++ {
+		st, ok := p.styles[styleID]
+		if !ok {
+			return "", ""
+		}
+		if st.numID != "" {
+			return st.numID, st.ilvl
+		}
+		styleID = st.basedOn
+	}
+	return "", ""
+}
+
+// plainText returns the text of the runs under n, accepting tracked changes.
+func plainText(n *node) string {
+	var sb strings.Builder
+	n.find(func(c *node) bool {
+		switch c.name() {
+		case "t":
+			sb.WriteString(c.Text)
+		case "tab":
+			sb.WriteString("\t")
+		case "br", "cr":
+			sb.WriteString("\n")
+		case "del", "moveFrom", "instrText":
+			return false
+		}
+		return true
+	})
+	return sb.String()
+}
+
+// commentsText renders the comments one per line, prefixed by their author.
+func (p *pkg) commentsText() string {
+	ids := make([]string, 0, len(p.comments))
+	for id := range p.comments {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+	var lines []string
+	for _, id := range ids {
+		c := p.comments[id]
+		if c.text == "" {
+			continue
+		}
+		if c.author != "" {
+			lines = append(lines, c.author+": "+c.text)
+		} else {
+			lines = append(lines, c.text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -19,10 +19,12 @@ package html
 import (
 	"context"
 	"io"
+	"net/url"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/microcosm-cc/bluemonday"
+	xhtml "golang.org/x/net/html"
 
 	"github.com/cloudwego/eino/components/document/parser"
 	"github.com/cloudwego/eino/schema"
@@ -41,6 +43,14 @@ var _ parser.Parser = (*Parser)(nil)
 type Config struct {
 	// content selector of goquery. eg: body for <body>, #id for <div id="id">
 	Selector *string
+	// ToMarkdown converts the content to markdown, keeping headings, lists,
+	// tables, code blocks, emphasis, links and images. Relative URLs are
+	// resolved against the <base> of the page or the URI option.
+	ToMarkdown bool
+	// Readability extracts the main content of the page, such as the article
+	// of a blog post, dropping navigation, sidebars, footers and other
+	// boilerplate. It is ignored if Selector is set.
+	Readability bool
 }
 
 var (
@@ -59,7 +69,8 @@ func NewParser(ctx context.Context, conf *Config) (*Parser, error) {
 }
 
 // Parser implements parser.Parser. It parses HTML content to text.
-// use goquery to parse the HTML content, will read the <body> content as text (remove tags).
+// use goquery to parse the HTML content, will read the <body> content as text (remove tags),
+// or as markdown if Config.ToMarkdown is set.
 // will extract title/description/language/charset from the HTML content as meta data.
 type Parser struct {
 	conf *Config
@@ -73,14 +84,7 @@ func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Opt
 
 	option := parser.GetCommonOptions(&parser.Options{}, opts...)
 
-	var contentSel *goquery.Selection
-
-	if p.conf.Selector != nil {
-		contentSel = doc.Find(*p.conf.Selector).Contents()
-	} else {
-		contentSel = doc.Contents()
-	}
-
+	// Metadata is read first, as readability removes elements from the document.
 	meta, err := p.getMetaData(ctx, doc)
 	if err != nil {
 		return nil, err
@@ -93,8 +97,32 @@ func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Opt
 		}
 	}
 
-	sanitized := bluemonday.UGCPolicy().Sanitize(contentSel.Text())
-	content := strings.TrimSpace(sanitized)
+	var content string
+	if p.conf.ToMarkdown {
+		var nodes []*xhtml.Node
+		switch {
+		case p.conf.Selector != nil:
+			nodes = doc.Find(*p.conf.Selector).Nodes
+		case p.conf.Readability:
+			nodes = readability(doc.Get(0))
+		default:
+			nodes = doc.Nodes
+		}
+		w := &mdWriter{base: baseURL(doc, option.URI)}
+		content = w.markdown(nodes)
+	} else {
+		var contentSel *goquery.Selection
+		switch {
+		case p.conf.Selector != nil:
+			contentSel = doc.Find(*p.conf.Selector).Contents()
+		case p.conf.Readability:
+			contentSel = doc.FindNodes(readability(doc.Get(0))...)
+		default:
+			contentSel = doc.Contents()
+		}
+		sanitized := bluemonday.UGCPolicy().Sanitize(contentSel.Text())
+		content = strings.TrimSpace(sanitized)
+	}
 
 	document := &schema.Document{
 		Content:  content,
@@ -139,3 +167,24 @@ func (p *Parser) getMetaData(ctx context.Context, doc *goquery.Document) (map[st
 
 	return meta, nil
 }
+
+// baseURL returns the URL which relative links are resolved against: the
+// <base> of the page, resolved against the URI of the document, or nil if
+// neither is absolute.
+func baseURL(doc *goquery.Document, uri string) *url.URL {
+	var base *url.URL
+	if u, err := url.Parse(uri); err == nil && u.IsAbs() {
+		base = u
+	}
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if u, err := url.Parse(strings.TrimSpace(href)); err == nil {
+			if base != nil {
+				return base.ResolveReference(u)
+			}
+			if u.IsAbs() {
+				return u
+			}
+		}
+	}
+	return base
+}
@@ -19,6 +19,7 @@ package html
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/cloudwego/eino/components/document/parser"
@@ -84,3 +85,86 @@ func TestHTMLParser(t *testing.T) {
 	})
 
 }
+
+func TestHTMLParser_Markdown(t *testing.T) {
+	ctx := context.Background()
+	uri := parser.WithURI("https://example.com/blog/parser.html")
+
+	t.Run("readability", func(t *testing.T) {
+		p, err := NewParser(ctx, &Config{ToMarkdown: true, Readability: true})
+		assert.NoError(t, err)
+		f, err := os.Open("testdata/article.html")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		docs, err := p.Parse(ctx, f, uri)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(docs))
+		assert.Equal(t, "Writing a Parser", docs[0].MetaData[MetaKeyTitle])
+		assert.Equal(t, "an article with boilerplate", docs[0].MetaData[MetaKeyDesc])
+		assert.Equal(t, `# Writing a Parser
+
+Parsers turn documents into text, which is split into chunks, embedded and indexed. This post shows how to write one, step by step.
+
+## Steps
+
+1. Read the **whole** input, or stream it
+2. Build a tree
+   - nodes
+   - attributes
+3. Render the tree
+
+The parser is configured as shown below, see the [documentation](https://example.com/docs/parser.html) for details, and the steps above.
+
+`+"```go\np, err := html.NewParser(ctx, &html.Config{\n    ToMarkdown: true,\n})\n```"+`
+
+| Option | Default |
+| --- | --- |
+| `+"`ToMarkdown`"+` | false |
+| Readability | false \| off |
+
+Use *markdown* output for chunking, as it keeps the structure of the page. ![tree](https://example.com/img/tree.png)`, docs[0].Content)
+	})
+
+	t.Run("whole page", func(t *testing.T) {
+		p, err := NewParser(ctx, &Config{ToMarkdown: true})
+		assert.NoError(t, err)
+		f, err := os.Open("testdata/article.html")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		docs, err := p.Parse(ctx, f, uri)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(docs))
+		assert.Contains(t, docs[0].Content, "[Home](https://example.com/)")
+		assert.Contains(t, docs[0].Content, "### Popular posts")
+		assert.Contains(t, docs[0].Content, "Great post")
+		assert.NotContains(t, docs[0].Content, "tracking")
+	})
+
+	t.Run("readability text", func(t *testing.T) {
+		p, err := NewParser(ctx, &Config{Readability: true})
+		assert.NoError(t, err)
+		f, err := os.Open("testdata/article.html")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		docs, err := p.Parse(ctx, f)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(docs))
+		assert.True(t, strings.HasPrefix(docs[0].Content, "Writing a Parser"))
+		assert.NotContains(t, docs[0].Content, "Popular posts")
+		assert.NotContains(t, docs[0].Content, "Great post")
+	})
+
+	t.Run("selector with base", func(t *testing.T) {
+		sel := "#xid"
+		p, err := NewParser(ctx, &Config{ToMarkdown: true, Selector: &sel})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, strings.NewReader(`<html><head><base href="https://cdn.example.com/a/"></head>
+<body><p>skipped</p><div id="xid"><h3>Title</h3><p>see <a href="b.html">b</a><br>and <a href="javascript:void(0)">c</a></p></div></body></html>`), uri)
+		assert.NoError(t, err)
+		assert.Equal(t, "### Title\n\nsee [b](https://cdn.example.com/a/b.html)\nand c", docs[0].Content)
+	})
+}
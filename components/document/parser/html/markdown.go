@@ -0,0 +1,362 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package html
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// skippedTags are not rendered, along with their content.
+var skippedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "head": true,
+	"iframe": true, "svg": true, "canvas": true, "button": true, "input": true,
+	"select": true, "textarea": true, "object": true, "embed": true,
+}
+
+// blockTags are rendered as separate paragraphs.
+var blockTags = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "main": true,
+	"header": true, "footer": true, "aside": true, "nav": true, "figure": true,
+	"figcaption": true, "address": true, "details": true, "summary": true,
+	"form": true, "fieldset": true, "center": true, "body": true, "html": true,
+	"dl": true, "dt": true, "dd": true,
+}
+
+var (
+	spaceRe     = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLineRe = regexp.MustCompile(`\n{3,}`)
+)
+
+// mdWriter converts HTML nodes to markdown. Blocks are separated by blank
+// lines, which are normalized once the whole output is written.
+type mdWriter struct {
+	base *url.URL
+}
+
+// markdown renders nodes as markdown.
+func (w *mdWriter) markdown(nodes []*xhtml.Node) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		w.node(&sb, n)
+	}
+	return normalize(sb.String())
+}
+
+// normalize trims trailing spaces of lines and collapses blank lines.
+func normalize(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+	return strings.TrimSpace(blankLineRe.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+func (w *mdWriter) children(n *xhtml.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(&sb, c)
+	}
+	return sb.String()
+}
+
+// inline renders the children of n on a single line.
+func (w *mdWriter) inline(n *xhtml.Node) string {
+	return strings.TrimSpace(spaceRe.ReplaceAllString(w.children(n), " "))
+}
+
+func (w *mdWriter) node(sb *strings.Builder, n *xhtml.Node) {
+	switch n.Type {
+	case xhtml.TextNode:
+		sb.WriteString(spaceRe.ReplaceAllString(n.Data, " "))
+		return
+	case xhtml.DocumentNode:
+		sb.WriteString(w.children(n))
+		return
+	case xhtml.ElementNode:
+	default:
+		return
+	}
+
+	tag := n.Data
+	switch {
+	case skippedTags[tag]:
+	case len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6':
+		if text := w.inline(n); text != "" {
+			sb.WriteString("\n\n" + strings.Repeat("#", int(tag[1]-'0')) + " " + text + "\n\n")
+		}
+	case tag == "ul" || tag == "ol":
+		sb.WriteString("\n\n" + w.list(n) + "\n\n")
+	case tag == "li":
+		// An item outside of a list.
+		sb.WriteString("\n\n- " + normalize(w.children(n)) + "\n\n")
+	case tag == "pre":
+		sb.WriteString("\n\n" + codeBlock(n) + "\n\n")
+	case tag == "blockquote":
+		if text := normalize(w.children(n)); text != "" {
+			sb.WriteString("\n\n> " + strings.ReplaceAll(text, "\n", "\n> ") + "\n\n")
+		}
+	case tag == "table":
+		sb.WriteString("\n\n" + w.table(n) + "\n\n")
+	case tag == "hr":
+		sb.WriteString("\n\n---\n\n")
+	case tag == "br":
+		sb.WriteString("\n")
+	case tag == "a":
+		w.link(sb, n)
+	case tag == "img":
+		w.image(sb, n)
+	case tag == "strong" || tag == "b":
+		emphasize(sb, w.children(n), "**")
+	case tag == "em" || tag == "i":
+		emphasize(sb, w.children(n), "*")
+	case tag == "del" || tag == "s" || tag == "strike":
+		emphasize(sb, w.children(n), "~~")
+	case tag == "code" || tag == "kbd" || tag == "samp":
+		if text := textContent(n); strings.TrimSpace(text) != "" {
+			fence := "`"
+			if strings.Contains(text, "`") {
+				fence = "``"
+			}
+			sb.WriteString(fence + spaceRe.ReplaceAllString(text, " ") + fence)
+		}
+	case blockTags[tag]:
+		sb.WriteString("\n\n" + w.children(n) + "\n\n")
+	default:
+		sb.WriteString(w.children(n))
+	}
+}
+
+// emphasize wraps text with the marker, keeping the surrounding spaces
+// outside of it.
+func emphasize(sb *strings.Builder, text, marker string) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		sb.WriteString(text)
+		return
+	}
+	if strings.TrimLeft(text, " \n") != text {
+		sb.WriteString(" ")
+	}
+	sb.WriteString(marker + trimmed + marker)
+	if strings.TrimRight(text, " \n") != text {
+		sb.WriteString(" ")
+	}
+}
+
+func (w *mdWriter) link(sb *strings.Builder, n *xhtml.Node) {
+	text := w.inline(n)
+	href := strings.TrimSpace(attr(n, "href"))
+	if text == "" {
+		return
+	}
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		sb.WriteString(text)
+		return
+	}
+	sb.WriteString("[" + text + "](" + w.resolve(href) + ")")
+}
+
+func (w *mdWriter) image(sb *strings.Builder, n *xhtml.Node) {
+	src := strings.TrimSpace(attr(n, "src"))
+	if src == "" || strings.HasPrefix(src, "data:") {
+		return
+	}
+	alt := strings.NewReplacer("[", "", "]", "").Replace(spaceRe.ReplaceAllString(attr(n, "alt"), " "))
+	sb.WriteString("![" + strings.TrimSpace(alt) + "](" + w.resolve(src) + ")")
+}
+
+// resolve makes ref absolute against the base URL. Spaces and parentheses
+// are escaped so that the URL does not end the markdown link.
+func (w *mdWriter) resolve(ref string) string {
+	if w.base != nil {
+		if u, err := url.Parse(ref); err == nil {
+			ref = w.base.ResolveReference(u).String()
+		}
+	}
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(ref)
+}
+
+func (w *mdWriter) list(n *xhtml.Node) string {
+	ordered := n.Data == "ol"
+	num := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil && ordered {
+		num = start
+	}
+	var items []string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != xhtml.ElementNode {
+			continue
+		}
+		var text string
+		switch c.Data {
+		case "li":
+			text = normalize(w.children(c))
+		case "ul", "ol":
+			// A nested list directly in a list, belonging to the previous
+			// item.
+			if len(items) > 0 {
+				items[len(items)-1] += "\n" + indent(w.list(c), "    ")
+			}
+			continue
+		default:
+			continue
+		}
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(num) + ". "
+			num++
+		}
+		// Blank lines inside an item would end the list.
+		text = blankLineRe.ReplaceAllString(strings.ReplaceAll(text, "\n\n", "\n"), "\n")
+		items = append(items, marker+indent(text, strings.Repeat(" ", len(marker)))[len(marker):])
+	}
+	return strings.Join(items, "\n")
+}
+
+// indent prefixes every non-empty line of s.
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func codeBlock(n *xhtml.Node) string {
+	lang := language(n)
+	for c := n.FirstChild; c != nil && lang == ""; c = c.NextSibling {
+		if c.Type == xhtml.ElementNode && c.Data == "code" {
+			lang = language(c)
+		}
+	}
+	code := strings.Trim(textContent(n), "\n")
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+// language returns the language of a code element from its class, such as
+// language-go or lang-go.
+func language(n *xhtml.Node) string {
+	for _, class := range strings.Fields(attr(n, "class")) {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if strings.HasPrefix(class, prefix) {
+				return strings.TrimPrefix(class, prefix)
+			}
+		}
+	}
+	return ""
+}
+
+// table renders a table with its first row as the header. Cells spanning
+// several columns are followed by empty cells.
+func (w *mdWriter) table(n *xhtml.Node) string {
+	var rows [][]string
+	var walk func(*xhtml.Node)
+	walk = func(n *xhtml.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != xhtml.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "thead", "tbody", "tfoot":
+				walk(c)
+			case "tr":
+				var row []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type != xhtml.ElementNode || cell.Data != "td" && cell.Data != "th" {
+						continue
+					}
+					text := strings.ReplaceAll(spaceRe.ReplaceAllString(normalize(w.children(cell)), " "), "|", "\\|")
+					row = append(row, text)
+					span, _ := strconv.Atoi(attr(cell, "colspan"))
+					for k := 1; k < span && k < 100; k++ {
+						row = append(row, "")
+					}
+				}
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	walk(n)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for k := 0; k < width; k++ {
+			var c string
+			if k < len(cells) {
+				c = cells[k]
+			}
+			sb.WriteString(" " + c + " |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(rows[0])
+	sep := make([]string, width)
+	for k := range sep {
+		sep[k] = "---"
+	}
+	writeRow(sep)
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func attr(n *xhtml.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// textContent returns the text under n as is.
+func textContent(n *xhtml.Node) string {
+	if n.Type == xhtml.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == xhtml.ElementNode && c.Data == "br" {
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
@@ -0,0 +1,254 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package html
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// The class and id patterns below follow the readability algorithm of
+// Arc90, which is also used by the reader views of browsers.
+var (
+	unlikelyRe = regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote|navbar|cookie|newsletter|subscribe`)
+	maybeRe    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveRe = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeRe = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// unlikelyTags never contain the main content.
+var unlikelyTags = map[string]bool{
+	"nav": true, "aside": true, "footer": true, "header": true, "form": true,
+	"script": true, "style": true, "noscript": true, "iframe": true,
+}
+
+// paragraphTags are the elements whose text is scored.
+var paragraphTags = map[string]bool{
+	"p": true, "pre": true, "td": true, "section": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "blockquote": true, "li": true,
+}
+
+// readability finds the main content of a document, removing navigation,
+// sidebars, footers and other boilerplate. It returns the nodes to render,
+// which are the best scored element and its related siblings, or the body
+// if no element has enough text. The document is modified.
+func readability(root *xhtml.Node) []*xhtml.Node {
+	body := findElement(root, "body")
+	if body == nil {
+		body = root
+	}
+	removeUnlikely(body)
+
+	scores := map[*xhtml.Node]float64{}
+	var candidates []*xhtml.Node
+	addScore := func(n *xhtml.Node, score float64) {
+		if n == nil || n.Type != xhtml.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+
+	walkElements(body, func(n *xhtml.Node) bool {
+		if !paragraphTags[n.Data] && !(n.Data == "div" && !hasBlockChild(n)) {
+			return true
+		}
+		text := strings.TrimSpace(spaceRe.ReplaceAllString(textContent(n), " "))
+		if len(text) < 25 {
+			return true
+		}
+		score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，"))
+		score += min(float64(len(text)/100), 3)
+		addScore(n.Parent, score)
+		if n.Parent != nil {
+			addScore(n.Parent.Parent, score/2)
+		}
+		return true
+	})
+	if len(candidates) == 0 {
+		return []*xhtml.Node{body}
+	}
+
+	for _, n := range candidates {
+		scores[n] *= 1 - linkDensity(n)
+	}
+	// Candidates are ordered by the document, so that ties go to the first.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i]] > scores[candidates[j]]
+	})
+	top := candidates[0]
+	if top == body || scores[top] <= 0 {
+		return []*xhtml.Node{body}
+	}
+	if top.Parent == nil || top.Parent.Type != xhtml.ElementNode {
+		return []*xhtml.Node{top}
+	}
+
+	// Siblings of the top candidate may be part of the article, such as a
+	// lead paragraph outside of the article element.
+	threshold := max(10, scores[top]*0.2)
+	var nodes []*xhtml.Node
+	for s := top.Parent.FirstChild; s != nil; s = s.NextSibling {
+		if s.Type != xhtml.ElementNode {
+			continue
+		}
+		if s == top {
+			nodes = append(nodes, s)
+			continue
+		}
+		bonus := 0.0
+		if class := attr(s, "class"); class != "" && class == attr(top, "class") {
+			bonus = scores[top] * 0.2
+		}
+		if score, ok := scores[s]; ok && score+bonus >= threshold {
+			nodes = append(nodes, s)
+			continue
+		}
+		if s.Data == "p" {
+			text := strings.TrimSpace(spaceRe.ReplaceAllString(textContent(s), " "))
+			density := linkDensity(s)
+			if len(text) > 80 && density < 0.25 ||
+				len(text) > 0 && density == 0 && strings.Contains(text, ". ") {
+				nodes = append(nodes, s)
+			}
+		}
+	}
+	return nodes
+}
+
+// removeUnlikely removes the elements which are unlikely to be part of the
+// main content, by their tag, or their class and id.
+func removeUnlikely(n *xhtml.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == xhtml.ElementNode {
+			match := attr(c, "class") + " " + attr(c, "id")
+			if role := attr(c, "role"); role == "navigation" || role == "complementary" || role == "banner" || role == "contentinfo" {
+				n.RemoveChild(c)
+			} else if unlikelyTags[c.Data] ||
+				unlikelyRe.MatchString(match) && !maybeRe.MatchString(match) && c.Data != "body" && c.Data != "a" && !hasAncestor(c, "table", "code") {
+				n.RemoveChild(c)
+			} else {
+				removeUnlikely(c)
+			}
+		}
+		c = next
+	}
+}
+
+// initialScore scores an element by its tag and its class and id.
+func initialScore(n *xhtml.Node) float64 {
+	var score float64
+	switch n.Data {
+	case "div", "article", "main":
+		score = 5
+	case "pre", "td", "blockquote":
+		score = 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score = -5
+	}
+	return score + classWeight(n)
+}
+
+func classWeight(n *xhtml.Node) float64 {
+	var weight float64
+	for _, s := range []string{attr(n, "class"), attr(n, "id")} {
+		if s == "" {
+			continue
+		}
+		if negativeRe.MatchString(s) {
+			weight -= 25
+		}
+		if positiveRe.MatchString(s) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity returns the share of the text of n which is in links.
+func linkDensity(n *xhtml.Node) float64 {
+	text := len(strings.TrimSpace(spaceRe.ReplaceAllString(textContent(n), " ")))
+	if text == 0 {
+		return 0
+	}
+	var links int
+	walkElements(n, func(c *xhtml.Node) bool {
+		if c.Data == "a" {
+			links += len(strings.TrimSpace(spaceRe.ReplaceAllString(textContent(c), " ")))
+			return false
+		}
+		return true
+	})
+	return min(float64(links)/float64(text), 1)
+}
+
+func hasBlockChild(n *xhtml.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != xhtml.ElementNode {
+			continue
+		}
+		if blockTags[c.Data] || paragraphTags[c.Data] || c.Data == "table" || c.Data == "ul" || c.Data == "ol" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAncestor(n *xhtml.Node, tags ...string) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		for _, tag := range tags {
+			if p.Type == xhtml.ElementNode && p.Data == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// walkElements calls fn on the elements under n in document order, skipping
+// the descendants of an element when fn returns false.
+func walkElements(n *xhtml.Node, fn func(*xhtml.Node) bool) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == xhtml.ElementNode && fn(c) {
+			walkElements(c, fn)
+		}
+	}
+}
+
+func findElement(n *xhtml.Node, tag string) *xhtml.Node {
+	var found *xhtml.Node
+	walkElements(n, func(c *xhtml.Node) bool {
+		if found != nil {
+			return false
+		}
+		if c.Data == tag {
+			found = c
+			return false
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,442 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pdf
+
+import (
+	"math"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// glyph is a character drawn on a page, in default user space: x grows to the
+// right and y grows upwards.
+type glyph struct {
+	x, y float64 // origin on the baseline
+	w    float64 // advance width
+	size float64 // font size
+	s    string
+}
+
+// segment is a horizontal or vertical ruling line.
+type segment struct {
+	x0, y0, x1, y1 float64 // x0 <= x1 and y0 <= y1
+}
+
+func (s segment) horizontal() bool {
+	return s.y1-s.y0 < rulingTolerance
+}
+
+// pageContent is what a content stream draws, as far as text extraction is
+// concerned.
+type pageContent struct {
+	glyphs   []glyph
+	segments []segment
+}
+
+const (
+	// rulingTolerance is the thickness up to which a filled rectangle is a
+	// line, and the distance within which lines are considered to touch.
+	rulingTolerance = 3
+	// maxFormDepth bounds the nesting of form XObjects.
+	maxFormDepth = 8
+)
+
+type matrix [3][3]float64
+
+var identity = matrix{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+func (m matrix) mul(n matrix) matrix {
+	var r matrix
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				r[i][j] += m[i][k] * n[k][j]
+			}
+		}
+	}
+	return r
+}
+
+func (m matrix) apply(x, y float64) (float64, float64) {
+	return x*m[0][0] + y*m[1][0] + m[2][0], x*m[0][1] + y*m[1][1] + m[2][1]
+}
+
+func translate(x, y float64) matrix {
+	return matrix{{1, 0, 0}, {0, 1, 0}, {x, y, 1}}
+}
+
+func matrixOf(args []pdf.Value) matrix {
+	m := identity
+	for i := 0; i < 6 && i < len(args); i++ {
+		m[i/2][i%2] = args[i].Float64()
+	}
+	return m
+}
+
+// font decodes the strings shown with a font and measures their glyphs.
+type font struct {
+	enc pdf.TextEncoding
+	// codeLen is the number of bytes per character code: 2 for composite
+	// fonts, which are assumed to use a two-byte encoding such as
+	// Identity-H, and 1 otherwise.
+	codeLen int
+	// widths maps character codes to glyph widths in thousandths of an em;
+	// codes not in it have defaultWidth.
+	widths       map[int]float64
+	defaultWidth float64
+	// standard is set for simple fonts without a Widths array, which are
+	// measured with standardWidth.
+	standard string
+}
+
+func newFont(v pdf.Value) *font {
+	f := &font{codeLen: 1, widths: map[int]float64{}}
+	if v.IsNull() {
+		f.enc = nopEncoding{}
+		f.standard = "Helvetica"
+		return f
+	}
+	f.enc = pdf.Font{V: v}.Encoder()
+	if v.Key("Subtype").Name() == "Type0" {
+		f.codeLen = 2
+		desc := v.Key("DescendantFonts").Index(0)
+		f.defaultWidth = 1000
+		if dw := desc.Key("DW"); !dw.IsNull() {
+			f.defaultWidth = dw.Float64()
+		}
+		// W is a list of "c [w1 w2 ...]" and "cFirst cLast w" entries.
+		w := desc.Key("W")
+		for i := 0; i < w.Len(); {
+			first := int(w.Index(i).Int64())
+			if i+1 < w.Len() && w.Index(i+1).Kind() == pdf.Array {
+				ws := w.Index(i + 1)
+				for j := 0; j < ws.Len(); j++ {
+					f.widths[first+j] = ws.Index(j).Float64()
+				}
+				i += 2
+				continue
+			}
+			if i+2 >= w.Len() {
+				break
+			}
+			last, width := int(w.Index(i+1).Int64()), w.Index(i+2).Float64()
+			for c := first; c <= last && c-first < 1<<16; c++ {
+				f.widths[c] = width
+			}
+			i += 3
+		}
+		return f
+	}
+
+	widths := v.Key("Widths")
+	if widths.Len() == 0 {
+		f.standard = v.Key("BaseFont").Name()
+		return f
+	}
+	first := int(v.Key("FirstChar").Int64())
+	for i := 0; i < widths.Len(); i++ {
+		f.widths[first+i] = widths.Index(i).Float64()
+	}
+	if mw := v.Key("FontDescriptor").Key("MissingWidth"); !mw.IsNull() {
+		f.defaultWidth = mw.Float64()
+	}
+	return f
+}
+
+func (f *font) width(code int) float64 {
+	if f.standard != "" {
+		return standardWidth(f.standard, code)
+	}
+	if w, ok := f.widths[code]; ok {
+		return w
+	}
+	return f.defaultWidth
+}
+
+type nopEncoding struct{}
+
+func (nopEncoding) Decode(raw string) string { return raw }
+
+// standardWidth approximates the width of a character of one of the
+// standard 14 fonts, which PDFs may use without declaring widths.
+func standardWidth(baseFont string, code int) float64 {
+	if strings.Contains(baseFont, "Courier") {
+		return 600
+	}
+	if code >= 32 && code < 32+len(helveticaWidths) {
+		return helveticaWidths[code-32]
+	}
+	return 556
+}
+
+// helveticaWidths are the widths of the printable ASCII characters of
+// Helvetica.
+var helveticaWidths = [...]float64{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // ' ' to '/'
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // '0' to '?'
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // '@' to 'O'
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // 'P' to '_'
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // '`' to 'o'
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // 'p' to '~'
+}
+
+type graphicsState struct {
+	ctm matrix
+	// text state
+	font                   *font
+	fontSize               float64
+	charSpace, wordSpace   float64
+	scale, leading, rise   float64
+	textMatrix, lineMatrix matrix
+}
+
+// readContent interprets the content stream of a page.
+func readContent(page pdf.Page) pageContent {
+	var c pageContent
+	strm := page.V.Key("Contents")
+	if strm.IsNull() {
+		return c
+	}
+	c.interpret(strm, page.Resources(), identity, 0)
+	return c
+}
+
+// interpret runs a content stream, which is the page's or a form XObject's,
+// with the given resources and initial transformation matrix.
+func (c *pageContent) interpret(strm, resources pdf.Value, ctm matrix, depth int) {
+	fonts := map[string]*font{}
+	g := graphicsState{ctm: ctm, scale: 1, font: newFont(pdf.Value{})}
+	var (
+		stack []graphicsState
+		// path is the current path, as line segments in user space.
+		path           []segment
+		cx, cy         float64 // current point
+		startX, startY float64 // start of the current subpath
+	)
+
+	lineTo := func(x, y float64) {
+		x0, y0 := g.ctm.apply(cx, cy)
+		x1, y1 := g.ctm.apply(x, y)
+		path = append(path, segment{math.Min(x0, x1), math.Min(y0, y1), math.Max(x0, x1), math.Max(y0, y1)})
+		cx, cy = x, y
+	}
+	paint := func(stroke bool) {
+		for _, s := range path {
+			thin := s.x1-s.x0 < rulingTolerance || s.y1-s.y0 < rulingTolerance
+			if stroke && thin {
+				c.segments = append(c.segments, s)
+			}
+		}
+		path = path[:0]
+	}
+	fillRect := func(x, y, w, h float64) {
+		x0, y0 := g.ctm.apply(x, y)
+		x1, y1 := g.ctm.apply(x+w, y+h)
+		s := segment{math.Min(x0, x1), math.Min(y0, y1), math.Max(x0, x1), math.Max(y0, y1)}
+		switch {
+		case s.y1-s.y0 < rulingTolerance:
+			mid := (s.y0 + s.y1) / 2
+			c.segments = append(c.segments, segment{s.x0, mid, s.x1, mid})
+		case s.x1-s.x0 < rulingTolerance:
+			mid := (s.x0 + s.x1) / 2
+			c.segments = append(c.segments, segment{mid, s.y0, mid, s.y1})
+		}
+	}
+	// rects holds the rectangles of the current path, which are lines when
+	// filled and thin enough.
+	var rects [][4]float64
+
+	showText := func(raw string) {
+		f := g.font
+		trm := matrix{{g.fontSize * g.scale, 0, 0}, {0, g.fontSize, 0}, {0, g.rise, 1}}.mul(g.textMatrix).mul(g.ctm)
+		size := math.Hypot(trm[1][0], trm[1][1])
+		for i := 0; i+f.codeLen <= len(raw); i += f.codeLen {
+			code := 0
+			for j := 0; j < f.codeLen; j++ {
+				code = code<<8 | int(raw[i+j])
+			}
+			w0 := f.width(code) / 1000
+			trm = matrix{{g.fontSize * g.scale, 0, 0}, {0, g.fontSize, 0}, {0, g.rise, 1}}.mul(g.textMatrix).mul(g.ctm)
+			if s := f.enc.Decode(raw[i : i+f.codeLen]); s != "" {
+				c.glyphs = append(c.glyphs, glyph{
+					x:    trm[2][0],
+					y:    trm[2][1],
+					w:    w0 * math.Hypot(trm[0][0], trm[0][1]),
+					size: size,
+					s:    s,
+				})
+			}
+			tx := w0*g.fontSize + g.charSpace
+			if f.codeLen == 1 && code == ' ' {
+				tx += g.wordSpace
+			}
+			g.textMatrix = translate(tx*g.scale, 0).mul(g.textMatrix)
+		}
+	}
+	nextLine := func() {
+		g.lineMatrix = translate(0, -g.leading).mul(g.lineMatrix)
+		g.textMatrix = g.lineMatrix
+	}
+
+	pdf.Interpret(strm, func(stk *pdf.Stack, op string) {
+		args := make([]pdf.Value, stk.Len())
+		for i := len(args) - 1; i >= 0; i-- {
+			args[i] = stk.Pop()
+		}
+		arg := func(i int) float64 {
+			if i < len(args) {
+				return args[i].Float64()
+			}
+			return 0
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, g)
+		case "Q":
+			if n := len(stack); n > 0 {
+				g, stack = stack[n-1], stack[:n-1]
+			}
+		case "cm":
+			g.ctm = matrixOf(args).mul(g.ctm)
+
+		// path construction and painting
+		case "m":
+			cx, cy = arg(0), arg(1)
+			startX, startY = cx, cy
+		case "l":
+			lineTo(arg(0), arg(1))
+		case "c":
+			cx, cy = arg(4), arg(5)
+		case "v", "y":
+			cx, cy = arg(2), arg(3)
+		case "h":
+			lineTo(startX, startY)
+		case "re":
+			x, y, w, h := arg(0), arg(1), arg(2), arg(3)
+			rects = append(rects, [4]float64{x, y, w, h})
+			cx, cy = x, y
+			lineTo(x+w, y)
+			lineTo(x+w, y+h)
+			lineTo(x, y+h)
+			lineTo(x, y)
+			startX, startY = x, y
+		case "S", "s":
+			paint(true)
+			rects = rects[:0]
+		case "f", "F", "f*":
+			for _, r := range rects {
+				fillRect(r[0], r[1], r[2], r[3])
+			}
+			paint(false)
+			rects = rects[:0]
+		case "B", "B*", "b", "b*":
+			paint(true)
+			rects = rects[:0]
+		case "n":
+			path = path[:0]
+			rects = rects[:0]
+
+		// text
+		case "BT":
+			g.textMatrix, g.lineMatrix = identity, identity
+		case "Tf":
+			if len(args) < 2 {
+				return
+			}
+			name := args[0].Name()
+			f, ok := fonts[name]
+			if !ok {
+				f = newFont(resources.Key("Font").Key(name))
+				fonts[name] = f
+			}
+			g.font, g.fontSize = f, arg(1)
+		case "Tc":
+			g.charSpace = arg(0)
+		case "Tw":
+			g.wordSpace = arg(0)
+		case "Tz":
+			g.scale = arg(0) / 100
+		case "TL":
+			g.leading = arg(0)
+		case "Ts":
+			g.rise = arg(0)
+		case "Td":
+			g.lineMatrix = translate(arg(0), arg(1)).mul(g.lineMatrix)
+			g.textMatrix = g.lineMatrix
+		case "TD":
+			g.leading = -arg(1)
+			g.lineMatrix = translate(arg(0), arg(1)).mul(g.lineMatrix)
+			g.textMatrix = g.lineMatrix
+		case "Tm":
+			g.lineMatrix = matrixOf(args)
+			g.textMatrix = g.lineMatrix
+		case "T*":
+			nextLine()
+		case "Tj":
+			if len(args) == 1 {
+				showText(args[0].RawString())
+			}
+		case "'":
+			if len(args) == 1 {
+				nextLine()
+				showText(args[0].RawString())
+			}
+		case "\"":
+			if len(args) == 3 {
+				g.wordSpace, g.charSpace = arg(0), arg(1)
+				nextLine()
+				showText(args[2].RawString())
+			}
+		case "TJ":
+			if len(args) != 1 {
+				return
+			}
+			for i := 0; i < args[0].Len(); i++ {
+				v := args[0].Index(i)
+				if v.Kind() == pdf.String {
+					showText(v.RawString())
+					continue
+				}
+				tx := -v.Float64() / 1000 * g.fontSize * g.scale
+				g.textMatrix = translate(tx, 0).mul(g.textMatrix)
+			}
+
+		// form XObjects
+		case "Do":
+			if len(args) != 1 || depth >= maxFormDepth {
+				return
+			}
+			xobj := resources.Key("XObject").Key(args[0].Name())
+			if xobj.Key("Subtype").Name() != "Form" {
+				return
+			}
+			res := xobj.Key("Resources")
+			if res.IsNull() {
+				res = resources
+			}
+			m := identity
+			if xobj.Key("Matrix").Len() == 6 {
+				for i := 0; i < 6; i++ {
+					m[i/2][i%2] = xobj.Key("Matrix").Index(i).Float64()
+				}
+			}
+			c.interpret(xobj, res, m.mul(g.ctm), depth+1)
+		}
+	})
+}
@@ -0,0 +1,391 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pdf
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Layout thresholds, relative to the font size.
+const (
+	// spaceGap is the gap between glyphs that separates words.
+	spaceGap = 0.15
+	// textColumnWidth is the minimum width of a column of running text,
+	// relative to the median font size of the page.
+	textColumnWidth = 12.0
+	// fragmentGap is the gap that separates two fragments of a line, which
+	// may belong to different columns.
+	fragmentGap = 1.0
+	// columnGap is the minimum width of the gutter between columns, relative
+	// to the median font size of the page.
+	columnGap = 1.2
+	// paragraphGap is the minimum vertical gap between paragraphs, relative
+	// to the median font size of the page.
+	paragraphGap = 0.4
+)
+
+// line is a run of glyphs sharing a baseline, broken into fragments at wide
+// gaps.
+type line struct {
+	y, size float64
+	frags   []fragment
+	text    string
+}
+
+type fragment struct {
+	x0, x1 float64
+	text   string
+}
+
+// groupLines clusters glyphs into lines from top to bottom, and builds their
+// text from left to right, inserting spaces at gaps between words.
+func groupLines(glyphs []glyph) []line {
+	sorted := make([]glyph, len(glyphs))
+	copy(sorted, glyphs)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].y > sorted[j].y })
+
+	var groups [][]glyph
+	for _, g := range sorted {
+		if n := len(groups); n > 0 {
+			first := groups[n-1][0]
+			if math.Abs(first.y-g.y) < 0.5*math.Max(first.size, g.size) {
+				groups[n-1] = append(groups[n-1], g)
+				continue
+			}
+		}
+		groups = append(groups, []glyph{g})
+	}
+
+	lines := make([]line, 0, len(groups))
+	for _, gs := range groups {
+		sort.SliceStable(gs, func(i, j int) bool { return gs[i].x < gs[j].x })
+		l := line{y: gs[0].y}
+		var (
+			sb   strings.Builder
+			frag fragment
+			prev *glyph
+		)
+		flush := func() {
+			frag.text = strings.TrimSpace(sb.String())
+			if frag.text != "" {
+				l.frags = append(l.frags, frag)
+			}
+			sb.Reset()
+		}
+		for i := range gs {
+			g := &gs[i]
+			l.size = math.Max(l.size, g.size)
+			if prev != nil {
+				gap := g.x - (prev.x + prev.w)
+				// Skip glyphs drawn twice to fake bold text.
+				if g.s == prev.s && math.Abs(g.x-prev.x) < 0.1*g.size {
+					continue
+				}
+				switch {
+				case gap > fragmentGap*g.size:
+					flush()
+				case gap > spaceGap*g.size && g.s != " " && prev.s != " ":
+					sb.WriteString(" ")
+				}
+			}
+			if sb.Len() == 0 {
+				frag.x0 = g.x
+			}
+			sb.WriteString(g.s)
+			frag.x1 = g.x + g.w
+			prev = g
+		}
+		flush()
+		if len(l.frags) == 0 {
+			continue
+		}
+		texts := make([]string, len(l.frags))
+		for i, f := range l.frags {
+			texts[i] = f.text
+		}
+		l.text = strings.Join(texts, " ")
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// block is a unit of the reading order: a fragment of a line, or a table.
+type block struct {
+	x0, y0, x1, y1 float64
+	baseline       float64
+	text           string
+	table          bool
+}
+
+// layoutPage reconstructs the text of a page in reading order. Tables found
+// from ruling lines are rendered as markdown; other text is split into
+// columns and paragraphs by recursive XY cuts, which separate paragraphs by
+// blank lines.
+func layoutPage(c pageContent) string {
+	tables := findTables(c.segments)
+	var rest []glyph
+	sizes := make([]float64, 0, len(c.glyphs))
+	for _, g := range c.glyphs {
+		if strings.TrimSpace(g.s) != "" {
+			sizes = append(sizes, g.size)
+		}
+	}
+	sort.Float64s(sizes)
+	median := 10.0
+	if len(sizes) > 0 && sizes[len(sizes)/2] > 0 {
+		median = sizes[len(sizes)/2]
+	}
+
+glyphs:
+	for _, g := range c.glyphs {
+		if g.size <= 0 {
+			g.size = median
+		}
+		for _, t := range tables {
+			if t.contains(g) {
+				t.add(g)
+				continue glyphs
+			}
+		}
+		rest = append(rest, g)
+	}
+
+	var blocks []block
+	for _, t := range tables {
+		if md := t.markdown(); md != "" {
+			blocks = append(blocks, block{x0: t.xs[0], x1: t.xs[len(t.xs)-1], y0: t.ys[len(t.ys)-1], y1: t.ys[0], text: md, table: true})
+		}
+	}
+	for _, l := range groupLines(rest) {
+		for _, f := range l.frags {
+			blocks = append(blocks, block{x0: f.x0, x1: f.x1, y0: l.y - 0.3*l.size, y1: l.y + 0.9*l.size, baseline: l.y, text: f.text})
+		}
+	}
+
+	lay := layout{median: median}
+	var paragraphs []string
+	for _, leaf := range lay.cut(blocks) {
+		if p := renderBlocks(leaf, median); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+type layout struct {
+	median float64
+}
+
+// cut splits blocks into columns and paragraphs in reading order.
+func (l layout) cut(blocks []block) [][]block {
+	if len(blocks) <= 1 {
+		return [][]block{blocks}
+	}
+	if cols := l.columns(blocks); len(cols) > 1 {
+		var ret [][]block
+		for _, col := range cols {
+			ret = append(ret, l.cut(col)...)
+		}
+		return ret
+	}
+	bands := l.mergeBands(l.bands(blocks))
+	if len(bands) <= 1 {
+		return [][]block{blocks}
+	}
+	var ret [][]block
+	for _, band := range bands {
+		ret = append(ret, l.cut(band)...)
+	}
+	return ret
+}
+
+// gutters returns the vertical strips free of text, wider than columnGap.
+func (l layout) gutters(blocks []block) [][2]float64 {
+	sorted := make([]block, len(blocks))
+	copy(sorted, blocks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].x0 < sorted[j].x0 })
+	var ret [][2]float64
+	right := sorted[0].x1
+	for _, b := range sorted[1:] {
+		if b.x0-right > columnGap*l.median {
+			ret = append(ret, [2]float64{right, b.x0})
+		}
+		right = math.Max(right, b.x1)
+	}
+	return ret
+}
+
+// columns splits blocks at their gutters, if every column has several lines
+// and the columns are not merely the cells of aligned rows.
+func (l layout) columns(blocks []block) [][]block {
+	gutters := l.gutters(blocks)
+	if len(gutters) == 0 {
+		return nil
+	}
+	cols := make([][]block, len(gutters)+1)
+	for _, b := range blocks {
+		i := sort.Search(len(gutters), func(i int) bool { return gutters[i][0] >= b.x1 })
+		cols[i] = append(cols[i], b)
+	}
+	for _, col := range cols {
+		if len(baselines(col, l.median)) < 2 {
+			return nil
+		}
+	}
+	// Short fragments on shared baselines form a table without rulings,
+	// such as a form, which reads row by row.
+	if alignedRows(cols, l.median) && !textColumns(cols, l.median) {
+		return nil
+	}
+	return cols
+}
+
+// bands splits blocks at horizontal gaps wider than paragraphGap, from top
+// to bottom.
+func (l layout) bands(blocks []block) [][]block {
+	sorted := make([]block, len(blocks))
+	copy(sorted, blocks)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].y1 > sorted[j].y1 })
+	var bands [][]block
+	bottom := math.Inf(1)
+	for _, b := range sorted {
+		if len(bands) == 0 || bottom-b.y1 > paragraphGap*l.median {
+			bands = append(bands, nil)
+			bottom = b.y0
+		}
+		bands[len(bands)-1] = append(bands[len(bands)-1], b)
+		bottom = math.Min(bottom, b.y0)
+	}
+	return bands
+}
+
+// mergeBands joins adjacent bands that share a gutter, so that the
+// paragraphs of a column stay together when paragraph breaks line up across
+// columns.
+func (l layout) mergeBands(bands [][]block) [][]block {
+	var ret [][]block
+	var prev [][2]float64
+	for _, band := range bands {
+		gutters := l.gutters(band)
+		if n := len(ret); n > 0 && sharesGutter(prev, gutters, columnGap*l.median) {
+			ret[n-1] = append(ret[n-1], band...)
+			prev = l.gutters(ret[n-1])
+			continue
+		}
+		ret = append(ret, band)
+		prev = gutters
+	}
+	return ret
+}
+
+func sharesGutter(a, b [][2]float64, minWidth float64) bool {
+	for _, ga := range a {
+		for _, gb := range b {
+			if math.Min(ga[1], gb[1])-math.Max(ga[0], gb[0]) > minWidth {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// baselines returns the distinct baselines of text blocks.
+func baselines(blocks []block, median float64) []float64 {
+	var ys []float64
+	for _, b := range blocks {
+		if b.table {
+			ys = append(ys, b.y0, b.y1)
+			continue
+		}
+		ys = append(ys, b.baseline)
+	}
+	sort.Float64s(ys)
+	var ret []float64
+	for _, y := range ys {
+		if n := len(ret); n > 0 && y-ret[n-1] < 0.2*median {
+			continue
+		}
+		ret = append(ret, y)
+	}
+	return ret
+}
+
+// alignedRows reports whether most lines of every column share their
+// baseline with a line of the neighboring column.
+func alignedRows(cols [][]block, median float64) bool {
+	for i := 1; i < len(cols); i++ {
+		a, b := baselines(cols[i-1], median), baselines(cols[i], median)
+		matched := 0
+		for _, y := range a {
+			j := sort.SearchFloat64s(b, y-0.2*median)
+			if j < len(b) && b[j] < y+0.2*median {
+				matched++
+			}
+		}
+		if float64(matched) < 0.8*float64(len(a)) || float64(matched) < 0.8*float64(len(b)) {
+			return false
+		}
+	}
+	return true
+}
+
+// textColumns reports whether every column is wide enough for running text,
+// and its fragments mostly span its width, as lines of running text do.
+func textColumns(cols [][]block, median float64) bool {
+	for _, col := range cols {
+		x0, x1 := math.Inf(1), math.Inf(-1)
+		widths := make([]float64, len(col))
+		for i, b := range col {
+			x0, x1 = math.Min(x0, b.x0), math.Max(x1, b.x1)
+			widths[i] = b.x1 - b.x0
+		}
+		sort.Float64s(widths)
+		if x1-x0 < textColumnWidth*median || widths[len(widths)/2] < 0.6*(x1-x0) {
+			return false
+		}
+	}
+	return true
+}
+
+// renderBlocks writes the blocks of a paragraph line by line.
+func renderBlocks(blocks []block, median float64) string {
+	sort.SliceStable(blocks, func(i, j int) bool { return blocks[i].y1 > blocks[j].y1 })
+	var (
+		lines [][]block
+		ys    []float64
+	)
+	for _, b := range blocks {
+		if n := len(lines); n > 0 && !b.table && !lines[n-1][0].table && math.Abs(ys[n-1]-b.baseline) < 0.5*median {
+			lines[n-1] = append(lines[n-1], b)
+			continue
+		}
+		lines = append(lines, []block{b})
+		ys = append(ys, b.baseline)
+	}
+	out := make([]string, 0, len(lines))
+	for _, l := range lines {
+		sort.SliceStable(l, func(i, j int) bool { return l[i].x0 < l[j].x0 })
+		texts := make([]string, len(l))
+		for i, b := range l {
+			texts[i] = b.text
+		}
+		out = append(out, strings.Join(texts, " "))
+	}
+	return strings.Join(out, "\n")
+}
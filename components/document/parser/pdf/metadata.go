@@ -0,0 +1,118 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pdf
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Metadata keys set in layout mode.
+const (
+	MetaKeyTitle        = "_title"
+	MetaKeyAuthor       = "_author"
+	MetaKeySubject      = "_subject"
+	MetaKeyKeywords     = "_keywords"
+	MetaKeyCreator      = "_creator"
+	MetaKeyProducer     = "_producer"
+	MetaKeyCreationDate = "_creation_date"
+	MetaKeyModDate      = "_mod_date"
+	// MetaKeyPageCount is the number of pages of the PDF.
+	MetaKeyPageCount = "_page_count"
+	// MetaKeyPage is the 1-based page number of a document parsed with ToPages.
+	MetaKeyPage = "_page"
+	// MetaKeyPageOffsets holds the byte offsets in Content where each page
+	// starts, for a document parsed without ToPages.
+	MetaKeyPageOffsets = "_page_offsets"
+)
+
+var infoKeys = []struct{ name, key string }{
+	{"Title", MetaKeyTitle},
+	{"Author", MetaKeyAuthor},
+	{"Subject", MetaKeySubject},
+	{"Keywords", MetaKeyKeywords},
+	{"Creator", MetaKeyCreator},
+	{"Producer", MetaKeyProducer},
+	{"CreationDate", MetaKeyCreationDate},
+	{"ModDate", MetaKeyModDate},
+}
+
+// docInfo reads the document information dictionary. Dates are formatted as
+// RFC 3339, or kept as is if they are not valid PDF dates.
+func docInfo(r *pdf.Reader) (meta map[string]any) {
+	meta = map[string]any{MetaKeyPageCount: r.NumPage()}
+	defer func() {
+		// The info dictionary is optional, and a malformed one should not
+		// fail the whole document.
+		_ = recover()
+	}()
+	info := r.Trailer().Key("Info")
+	for _, k := range infoKeys {
+		v := info.Key(k.name)
+		if v.Kind() != pdf.String {
+			continue
+		}
+		s := strings.TrimSpace(v.Text())
+		if s == "" {
+			continue
+		}
+		if k.key == MetaKeyCreationDate || k.key == MetaKeyModDate {
+			if t, ok := parseDate(s); ok {
+				s = t.Format(time.RFC3339)
+			}
+		}
+		meta[k.key] = s
+	}
+	return meta
+}
+
+// parseDate parses a PDF date string such as D:20240102030405+08'00'. All
+// fields after the year are optional.
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimPrefix(s, "D:")
+	s = strings.ReplaceAll(s, "'", "")
+	digits := len(s)
+	for i, c := range s {
+		if c < '0' || c > '9' {
+			digits = i
+			break
+		}
+	}
+	if digits < 4 || digits > 14 || digits%2 != 0 {
+		return time.Time{}, false
+	}
+	layout := "20060102150405"[:digits]
+	zone := s[digits:]
+	switch {
+	case zone == "" || zone[0] == 'Z':
+		zone = ""
+	case len(zone) == 5 && (zone[0] == '+' || zone[0] == '-'):
+		layout += "-0700"
+	case len(zone) == 3 && (zone[0] == '+' || zone[0] == '-'):
+		zone += "00"
+		layout += "-0700"
+	default:
+		return time.Time{}, false
+	}
+	t, err := time.Parse(layout, s[:digits]+zone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -20,6 +20,7 @@ import "github.com/cloudwego/eino/components/document/parser"
 
 type options struct {
 	toPages *bool
+	layout  *bool
 }
 
 // WithToPages is a parser option that specifies whether to parse the PDF into pages.
@@ -28,3 +29,10 @@ func WithToPages(toPages bool) parser.Option {
 		opts.toPages = &toPages
 	})
 }
+
+// WithLayout is a parser option that specifies whether to parse the PDF in layout mode.
+func WithLayout(layout bool) parser.Option {
+	return parser.WrapImplSpecificOptFn(func(opts *options) {
+		opts.layout = &layout
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/cloudwego/eino/components/document/parser"
 	"github.com/cloudwego/eino/schema"
@@ -30,13 +31,23 @@ import (
 // Config is the configuration for PDF parser.
 type Config struct {
 	ToPages bool // whether to
+	// Layout enables the layout mode, see PDFParser.
+	Layout bool
 }
 
 // PDFParser reads from io.Reader and parse its content as plain text.
 // Attention: This is in alpha stage, and may not support all PDF use cases well enough.
 // For example, it will not preserve whitespace and new line for now.
+//
+// In layout mode, the text is instead reconstructed from glyph positions:
+// lines, columns and paragraphs are laid out in reading order, and tables
+// drawn with ruling lines are rendered as markdown. Every document then
+// carries the document information (see MetaKeyTitle and the other
+// MetaKey constants), the page count, and its page number, or the offsets of
+// the pages if the PDF is not parsed into pages.
 type PDFParser struct {
 	ToPages bool
+	Layout  bool
 }
 
 // NewPDFParser creates a new PDF parser.
@@ -44,7 +55,7 @@ func NewPDFParser(ctx context.Context, config *Config) (*PDFParser, error) {
 	if config == nil {
 		config = &Config{}
 	}
-	return &PDFParser{ToPages: config.ToPages}, nil
+	return &PDFParser{ToPages: config.ToPages, Layout: config.Layout}, nil
 }
 
 // Parse parses the PDF content from io.Reader.
@@ -53,6 +64,7 @@ func (pp *PDFParser) Parse(ctx context.Context, reader io.Reader, opts ...parser
 
 	specificOpts := parser.GetImplSpecificOptions(&options{
 		toPages: &pp.ToPages,
+		layout:  &pp.Layout,
 	}, opts...)
 
 	data, err := io.ReadAll(reader)
@@ -67,11 +79,13 @@ func (pp *PDFParser) Parse(ctx context.Context, reader io.Reader, opts ...parser
 		return nil, fmt.Errorf("create new pdf reader failed: %w", err)
 	}
 
+	toPages := specificOpts.toPages != nil && *specificOpts.toPages
+	if specificOpts.layout != nil && *specificOpts.layout {
+		return parseLayout(f, toPages, commonOpts.ExtraMeta)
+	}
+
 	pages := f.NumPage()
-	var (
-		buf     bytes.Buffer
-		toPages = specificOpts.toPages != nil && *specificOpts.toPages
-	)
+	var buf bytes.Buffer
 	fonts := make(map[string]*pdf.Font)
 	for i := 1; i <= pages; i++ {
 		p := f.Page(i)
@@ -105,3 +119,58 @@ func (pp *PDFParser) Parse(ctx context.Context, reader io.Reader, opts ...parser
 
 	return docs, nil
 }
+
+func parseLayout(f *pdf.Reader, toPages bool, extraMeta map[string]any) ([]*schema.Document, error) {
+	info := docInfo(f)
+	newMeta := func() map[string]any {
+		meta := make(map[string]any, len(info)+len(extraMeta)+1)
+		for k, v := range info {
+			meta[k] = v
+		}
+		for k, v := range extraMeta {
+			meta[k] = v
+		}
+		return meta
+	}
+
+	var (
+		docs    []*schema.Document
+		buf     strings.Builder
+		offsets []int
+	)
+	for i := 1; i <= f.NumPage(); i++ {
+		text, err := layoutText(f.Page(i))
+		if err != nil {
+			return nil, fmt.Errorf("read pdf page failed: %w, page= %d", err, i)
+		}
+		if toPages {
+			meta := newMeta()
+			meta[MetaKeyPage] = i
+			docs = append(docs, &schema.Document{Content: text, MetaData: meta})
+			continue
+		}
+		if i > 1 {
+			buf.WriteString("\n\n")
+		}
+		offsets = append(offsets, buf.Len())
+		buf.WriteString(text)
+	}
+
+	if !toPages {
+		meta := newMeta()
+		meta[MetaKeyPageOffsets] = offsets
+		docs = append(docs, &schema.Document{Content: buf.String(), MetaData: meta})
+	}
+	return docs, nil
+}
+
+// layoutText lays out a page, recovering from malformed content streams on
+// which the underlying reader panics.
+func layoutText(p pdf.Page) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return layoutPage(readContent(p)), nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/cloudwego/eino/components/document/parser"
 	"github.com/stretchr/testify/assert"
@@ -44,3 +45,111 @@ func TestLoader_Load(t *testing.T) {
 		assert.Equal(t, map[string]any{"test": "test"}, docs[1].MetaData)
 	})
 }
+
+func TestPDFParser_Layout(t *testing.T) {
+	ctx := context.Background()
+	page1 := "Layout Test Title\n\n" +
+		"Left column starts\nhere and flows down\nto its last line.\n\n" +
+		"Right column line one\ncontinues the second\ncolumn to the end.\n\n" +
+		"Kerned words together\n\n" +
+		"| Name | Qty | Price |\n| --- | --- | --- |\n| Apple | 3 | 1.50 |\n| Pear | 5 | 2.00 |\n\n" +
+		"Footer paragraph after the table."
+	page2 := "Text inside a form\nspans two lines"
+	info := map[string]any{
+		MetaKeyTitle:        "Layout Test",
+		MetaKeyAuthor:       "Eino",
+		MetaKeyProducer:     "hand written",
+		MetaKeyCreationDate: "2024-01-02T03:04:05+08:00",
+		MetaKeyPageCount:    2,
+		"test":              "test",
+	}
+	withMeta := func(k string, v any) map[string]any {
+		meta := map[string]any{k: v}
+		for k, v := range info {
+			meta[k] = v
+		}
+		return meta
+	}
+
+	t.Run("to pages", func(t *testing.T) {
+		f, err := os.Open("./testdata/layout.pdf")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		p, err := NewPDFParser(ctx, &Config{Layout: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, f, WithToPages(true), parser.WithExtraMeta(map[string]any{"test": "test"}))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(docs))
+		assert.Equal(t, page1, docs[0].Content)
+		assert.Equal(t, withMeta(MetaKeyPage, 1), docs[0].MetaData)
+		assert.Equal(t, page2, docs[1].Content)
+		assert.Equal(t, withMeta(MetaKeyPage, 2), docs[1].MetaData)
+	})
+
+	t.Run("single document", func(t *testing.T) {
+		f, err := os.Open("./testdata/layout.pdf")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		p, err := NewPDFParser(ctx, nil)
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, f, WithLayout(true), parser.WithExtraMeta(map[string]any{"test": "test"}))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(docs))
+		assert.Equal(t, page1+"\n\n"+page2, docs[0].Content)
+		assert.Equal(t, withMeta(MetaKeyPageOffsets, []int{0, len(page1) + 2}), docs[0].MetaData)
+	})
+}
+
+func TestLayoutPage(t *testing.T) {
+	// glyphs lays out s with Courier metrics, one glyph per character.
+	glyphs := func(x, y float64, s string) []glyph {
+		var ret []glyph
+		for _, r := range s {
+			ret = append(ret, glyph{x: x, y: y, w: 6, size: 10, s: string(r)})
+			x += 6
+		}
+		return ret
+	}
+
+	t.Run("aligned rows read row by row", func(t *testing.T) {
+		var c pageContent
+		for i, row := range [][2]string{{"Name:", "Eino"}, {"Version:", "1.0"}, {"License:", "Apache"}} {
+			c.glyphs = append(c.glyphs, glyphs(72, 700-float64(12*i), row[0])...)
+			c.glyphs = append(c.glyphs, glyphs(200, 700-float64(12*i), row[1])...)
+		}
+		assert.Equal(t, "Name: Eino\nVersion: 1.0\nLicense: Apache", layoutPage(c))
+	})
+
+	t.Run("superscripts stay on their line", func(t *testing.T) {
+		c := pageContent{glyphs: glyphs(72, 700, "E=mc")}
+		c.glyphs = append(c.glyphs, glyph{x: 96, y: 704, w: 4, size: 7, s: "2"})
+		assert.Equal(t, "E=mc2", layoutPage(c))
+	})
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"D:20240102030405+08'00'", "2024-01-02T03:04:05+08:00", true},
+		{"D:20240102030405Z", "2024-01-02T03:04:05Z", true},
+		{"D:20240102030405-05'30", "2024-01-02T03:04:05-05:30", true},
+		{"D:2024", "2024-01-01T00:00:00Z", true},
+		{"20240102", "2024-01-02T00:00:00Z", true},
+		{"D:2024010", "", false},
+		{"yesterday", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parseDate(tt.in)
+		assert.Equal(t, tt.ok, ok, tt.in)
+		if ok {
+			assert.Equal(t, tt.want, got.Format(time.RFC3339), tt.in)
+		}
+	}
+}
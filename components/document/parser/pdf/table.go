@@ -0,0 +1,212 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pdf
+
+import (
+	"sort"
+	"strings"
+)
+
+// table is a grid of cells bounded by ruling lines.
+type table struct {
+	// xs are the x coordinates of the vertical lines from left to right,
+	// ys the y coordinates of the horizontal lines from top to bottom.
+	xs, ys []float64
+	cells  [][][]glyph
+}
+
+func (t *table) contains(g glyph) bool {
+	cx, cy := g.x+g.w/2, g.y+g.size*0.3
+	return cx > t.xs[0] && cx < t.xs[len(t.xs)-1] && cy < t.ys[0] && cy > t.ys[len(t.ys)-1]
+}
+
+// add puts g into the cell containing its center.
+func (t *table) add(g glyph) {
+	cx, cy := g.x+g.w/2, g.y+g.size*0.3
+	col := sort.SearchFloat64s(t.xs, cx) - 1
+	row := 0
+	for row+1 < len(t.ys)-1 && cy < t.ys[row+1] {
+		row++
+	}
+	if col >= 0 && col < len(t.xs)-1 {
+		t.cells[row][col] = append(t.cells[row][col], g)
+	}
+}
+
+// markdown renders the table with its first non-empty row as the header.
+// Rows without any text are dropped.
+func (t *table) markdown() string {
+	var rows [][]string
+	for _, cells := range t.cells {
+		row := make([]string, len(cells))
+		empty := true
+		for i, glyphs := range cells {
+			var lines []string
+			for _, l := range groupLines(glyphs) {
+				lines = append(lines, l.text)
+			}
+			row[i] = strings.ReplaceAll(strings.Join(lines, " "), "|", "\\|")
+			if row[i] != "" {
+				empty = false
+			}
+		}
+		if !empty {
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for _, c := range cells {
+			sb.WriteString(" " + c + " |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(rows[0])
+	sep := make([]string, len(rows[0]))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeRow(sep)
+	for _, r := range rows[1:] {
+		writeRow(r)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// findTables returns the grids formed by ruling lines that have at least two
+// rows and two columns.
+func findTables(segments []segment) []*table {
+	var hs, vs []segment
+	for _, s := range segments {
+		switch {
+		case s.horizontal() && s.x1-s.x0 > rulingTolerance:
+			hs = append(hs, s)
+		case !s.horizontal() && s.y1-s.y0 > rulingTolerance:
+			vs = append(vs, s)
+		}
+	}
+	hs = mergeSegments(hs, true)
+	vs = mergeSegments(vs, false)
+
+	// Group the lines that touch each other with union-find.
+	parent := make([]int, len(hs)+len(vs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i, h := range hs {
+		for j, v := range vs {
+			if v.x0 >= h.x0-rulingTolerance && v.x0 <= h.x1+rulingTolerance &&
+				h.y0 >= v.y0-rulingTolerance && h.y0 <= v.y1+rulingTolerance {
+				parent[find(i)] = find(len(hs) + j)
+			}
+		}
+	}
+	groups := map[int]*[2][]float64{}
+	var roots []int
+	for i := range parent {
+		r := find(i)
+		grid, ok := groups[r]
+		if !ok {
+			grid = &[2][]float64{}
+			groups[r] = grid
+			roots = append(roots, r)
+		}
+		if i < len(hs) {
+			grid[1] = append(grid[1], hs[i].y0)
+		} else {
+			grid[0] = append(grid[0], vs[i-len(hs)].x0)
+		}
+	}
+
+	var tables []*table
+	for _, r := range roots {
+		xs, ys := clusterCoords(groups[r][0]), clusterCoords(groups[r][1])
+		if len(xs) < 3 || len(ys) < 3 {
+			continue
+		}
+		sort.Sort(sort.Reverse(sort.Float64Slice(ys)))
+		t := &table{xs: xs, ys: ys, cells: make([][][]glyph, len(ys)-1)}
+		for i := range t.cells {
+			t.cells[i] = make([][]glyph, len(xs)-1)
+		}
+		tables = append(tables, t)
+	}
+	return tables
+}
+
+// mergeSegments joins collinear lines that overlap or touch.
+func mergeSegments(segs []segment, horizontal bool) []segment {
+	key := func(s segment) (pos, lo, hi float64) {
+		if horizontal {
+			return s.y0, s.x0, s.x1
+		}
+		return s.x0, s.y0, s.y1
+	}
+	sort.Slice(segs, func(i, j int) bool {
+		pi, li, _ := key(segs[i])
+		pj, lj, _ := key(segs[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return li < lj
+	})
+	var ret []segment
+	for _, s := range segs {
+		if n := len(ret); n > 0 {
+			pp, _, ph := key(ret[n-1])
+			p, l, h := key(s)
+			if p-pp < rulingTolerance && l <= ph+rulingTolerance {
+				if h > ph {
+					if horizontal {
+						ret[n-1].x1 = h
+					} else {
+						ret[n-1].y1 = h
+					}
+				}
+				continue
+			}
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+// clusterCoords returns the sorted distinct values of coords, treating
+// values closer than rulingTolerance as one.
+func clusterCoords(coords []float64) []float64 {
+	sort.Float64s(coords)
+	var ret []float64
+	for _, c := range coords {
+		if n := len(ret); n > 0 && c-ret[n-1] < rulingTolerance {
+			continue
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xlsx
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+// CSVParser parses CSV and TSV files into documents the same way XlsxParser
+// parses a sheet: one document per row, or one per window of rows.
+type CSVParser struct {
+	Config *CSVConfig
+}
+
+// CSVConfig Used to configure CSVParser
+type CSVConfig struct {
+	// Comma is the field delimiter. By default, it is a tab if the source URI
+	// ends with .tsv, and a comma otherwise.
+	Comma rune
+	// NoHeader is set to false by default, which means that the first row is used as the table header
+	NoHeader bool
+	// IDPrefix is set to customize the prefix of document ID, default 1,2,3, ...
+	IDPrefix string
+	// TypedValues stores integers, floats, booleans and RFC 3339 or
+	// YYYY-MM-DD dates in the row metadata as int64, float64, bool and
+	// time.Time, instead of their text.
+	TypedValues bool
+	// RowsPerDocument groups this many rows into one document, rendered as a
+	// markdown table with the header repeated. By default, every row is a
+	// document with tab separated cells.
+	RowsPerDocument int
+}
+
+// NewCSVParser Create a new CSVParser
+func NewCSVParser(ctx context.Context, config *CSVConfig) (parser.Parser, error) {
+	if config == nil {
+		config = &CSVConfig{}
+	}
+	if config.RowsPerDocument < 0 {
+		return nil, fmt.Errorf("RowsPerDocument must not be negative, got %d", config.RowsPerDocument)
+	}
+	return &CSVParser{Config: config}, nil
+}
+
+// Parse parses the CSV content from io.Reader.
+func (cp *CSVParser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	option := parser.GetCommonOptions(&parser.Options{}, opts...)
+
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	switch {
+	case cp.Config.Comma != 0:
+		r.Comma = cp.Config.Comma
+	case strings.EqualFold(filepath.Ext(option.URI), ".tsv"):
+		r.Comma = '\t'
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv failed: %w", err)
+	}
+
+	t := &table{rows: make([][]cell, len(records))}
+	for i, record := range records {
+		// Strip the byte order mark written by spreadsheet applications.
+		if i == 0 && len(record) > 0 {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+		}
+		// Blank lines are skipped by the csv reader, and lines of empty
+		// fields are treated like the empty rows of a sheet.
+		if strings.Join(record, "") == "" {
+			continue
+		}
+		t.rows[i] = make([]cell, len(record))
+		for j, text := range record {
+			c := cell{text: text, value: text}
+			if cp.Config.TypedValues {
+				c.value = inferValue(text)
+			}
+			t.rows[i][j] = c
+		}
+	}
+
+	return t.documents(rowOptions{
+		noHeader:   cp.Config.NoHeader,
+		idPrefix:   cp.Config.IDPrefix,
+		rowsPerDoc: cp.Config.RowsPerDocument,
+	}, option.ExtraMeta), nil
+}
+
+// inferValue parses the text of a CSV field as an integer, a float, a
+// boolean or a date, returning the text if it is none of them.
+func inferValue(text string) any {
+	s := strings.TrimSpace(text)
+	// Leading zeros mark codes such as zip codes, which are not numbers.
+	if s == "" || len(s) > 1 && s[0] == '0' && s[1] != '.' {
+		return text
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return f
+	}
+	switch strings.ToLower(s) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return text
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xlsx
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVParser_Parse(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("TestCSVParser_WithDefault", func(t *testing.T) {
+		p, err := NewCSVParser(ctx, nil)
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, strings.NewReader("\ufeffname,zip,score\nAlice,02134,9.5\n\n,,\n\"Bob, Jr.\",10001,8\n"),
+			parser.WithExtraMeta(map[string]any{"test": "test"}))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(docs))
+		assert.Equal(t, "1", docs[0].ID)
+		assert.Equal(t, "Alice\t02134\t9.5", docs[0].Content)
+		assert.Equal(t, map[string]any{"name": "Alice", "zip": "02134", "score": "9.5"}, docs[0].MetaData[MetaDataRow])
+		assert.Equal(t, map[string]any{"test": "test"}, docs[0].MetaData[MetaDataExt])
+		assert.Equal(t, "3", docs[1].ID)
+		assert.Equal(t, "Bob, Jr.\t10001\t8", docs[1].Content)
+	})
+
+	t.Run("TestCSVParser_WithTSVAndTypedValues", func(t *testing.T) {
+		p, err := NewCSVParser(ctx, &CSVConfig{TypedValues: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, strings.NewReader("name\tzip\tscore\tactive\tjoined\nAlice\t02134\t9.5\ttrue\t2024-03-01\n"),
+			parser.WithURI("data/users.tsv"))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(docs))
+		assert.Equal(t, map[string]any{
+			"name":   "Alice",
+			"zip":    "02134",
+			"score":  9.5,
+			"active": true,
+			"joined": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		}, docs[0].MetaData[MetaDataRow])
+	})
+
+	t.Run("TestCSVParser_WithRowsPerDocumentAndNoHeader", func(t *testing.T) {
+		p, err := NewCSVParser(ctx, &CSVConfig{Comma: ';', NoHeader: true, RowsPerDocument: 2})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, strings.NewReader("a;b|c\nd;e\nf;\"g\nh\""))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(docs))
+		assert.Equal(t, "0", docs[0].ID)
+		assert.Equal(t, "| A | B |\n| --- | --- |\n| a | b\\|c |\n| d | e |", docs[0].Content)
+		assert.Equal(t, 1, docs[0].MetaData[MetaDataRowStart])
+		assert.Equal(t, 2, docs[0].MetaData[MetaDataRowEnd])
+		assert.Equal(t, "| A | B |\n| --- | --- |\n| f | g<br>h |", docs[1].Content)
+	})
+}
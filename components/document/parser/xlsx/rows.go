@@ -0,0 +1,205 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xlsx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// cell is a parsed cell, with its displayed text and its value, which is the
+// text unless typed values are enabled.
+type cell struct {
+	text  string
+	value any
+}
+
+// table is a parsed sheet or CSV file.
+type table struct {
+	// sheet is the sheet name, empty for CSV files.
+	sheet string
+	rows  [][]cell
+}
+
+// rowOptions configures how rows become documents.
+type rowOptions struct {
+	noHeader   bool
+	idPrefix   string
+	rowsPerDoc int
+	// sheetID adds the sheet name to document IDs, to keep them unique
+	// across sheets.
+	sheetID bool
+}
+
+func (o rowOptions) id(sheet string, i int) string {
+	if o.sheetID {
+		return fmt.Sprintf("%s%s_%d", o.idPrefix, sheet, i)
+	}
+	return fmt.Sprintf("%s%d", o.idPrefix, i)
+}
+
+// documents turns the rows of t into documents: one per row with tab
+// separated cells, or one per window of rowsPerDoc rows rendered as a
+// markdown table with the header repeated.
+func (t *table) documents(o rowOptions, extraMeta map[string]any) []*schema.Document {
+	if len(t.rows) == 0 {
+		return nil
+	}
+
+	// Process the header
+	startIdx := 0
+	var headers []string
+	if !o.noHeader {
+		for _, c := range t.rows[0] {
+			headers = append(headers, c.text)
+		}
+		startIdx = 1
+	}
+
+	newMeta := func() map[string]any {
+		meta := make(map[string]any)
+		if extraMeta != nil {
+			meta[MetaDataExt] = extraMeta
+		}
+		if t.sheet != "" {
+			meta[MetaDataSheet] = t.sheet
+		}
+		return meta
+	}
+	rowMeta := func(row []cell) map[string]any {
+		meta := make(map[string]any)
+		for j, header := range headers {
+			if j < len(row) {
+				meta[header] = row[j].value
+			}
+		}
+		return meta
+	}
+
+	var ret []*schema.Document
+	if o.rowsPerDoc <= 1 {
+		for i := startIdx; i < len(t.rows); i++ {
+			row := t.rows[i]
+			if len(row) == 0 {
+				continue
+			}
+			contentParts := make([]string, len(row))
+			for j, c := range row {
+				contentParts[j] = strings.TrimSpace(c.text)
+			}
+			meta := newMeta()
+			meta[MetaDataRow] = rowMeta(row)
+			ret = append(ret, &schema.Document{
+				ID:       o.id(t.sheet, i),
+				Content:  strings.Join(contentParts, "\t"),
+				MetaData: meta,
+			})
+		}
+		return ret
+	}
+
+	var window []int
+	flush := func() {
+		if len(window) == 0 {
+			return
+		}
+		rows := make([][]cell, len(window))
+		metas := make([]map[string]any, len(window))
+		for k, i := range window {
+			rows[k] = t.rows[i]
+			metas[k] = rowMeta(t.rows[i])
+		}
+		meta := newMeta()
+		meta[MetaDataRows] = metas
+		meta[MetaDataRowStart] = window[0] + 1
+		meta[MetaDataRowEnd] = window[len(window)-1] + 1
+		ret = append(ret, &schema.Document{
+			ID:       o.id(t.sheet, window[0]),
+			Content:  markdownTable(headers, rows),
+			MetaData: meta,
+		})
+		window = window[:0]
+	}
+	for i := startIdx; i < len(t.rows); i++ {
+		if len(t.rows[i]) == 0 {
+			continue
+		}
+		window = append(window, i)
+		if len(window) == o.rowsPerDoc {
+			flush()
+		}
+	}
+	flush()
+	return ret
+}
+
+// markdownTable renders rows under headers. Without headers, the columns are
+// named A, B, C and so on, like in a spreadsheet.
+func markdownTable(headers []string, rows [][]cell) string {
+	width := len(headers)
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	escape := strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for j := 0; j < width; j++ {
+			var c string
+			if j < len(cells) {
+				c = escape.Replace(strings.TrimSpace(cells[j]))
+			}
+			sb.WriteString(" " + c + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	header := make([]string, width)
+	for j := range header {
+		if j < len(headers) {
+			header[j] = headers[j]
+		} else if headers == nil {
+			header[j] = columnName(j)
+		}
+	}
+	writeRow(header)
+	sep := make([]string, width)
+	for j := range sep {
+		sep[j] = "---"
+	}
+	writeRow(sep)
+	for _, row := range rows {
+		texts := make([]string, len(row))
+		for j, c := range row {
+			texts[j] = c.text
+		}
+		writeRow(texts)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// columnName returns the spreadsheet name of the 0-based column j.
+func columnName(j int) string {
+	name := ""
+	for j++; j > 0; j = (j - 1) / 26 {
+		name = string(rune('A'+(j-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xlsx
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// sheetReader reads the sheets of a workbook into tables.
+type sheetReader struct {
+	file   *excelize.File
+	merged bool
+	typed  bool
+
+	date1904 *bool
+	// dateStyles caches whether a style ID has a date number format.
+	dateStyles map[int]bool
+}
+
+func (r *sheetReader) read(sheet string) (*table, error) {
+	texts, err := r.file.GetRows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var raws [][]string
+	if r.typed {
+		if raws, err = r.file.GetRows(sheet, excelize.Options{RawCellValue: true}); err != nil {
+			return nil, err
+		}
+	}
+
+	t := &table{sheet: sheet, rows: make([][]cell, len(texts))}
+	for i, row := range texts {
+		if len(row) == 0 {
+			continue
+		}
+		t.rows[i] = make([]cell, len(row))
+		for j, text := range row {
+			c := cell{text: text, value: text}
+			if r.typed && text != "" && i < len(raws) && j < len(raws[i]) {
+				if c.value, err = r.value(sheet, j, i, raws[i][j], text); err != nil {
+					return nil, err
+				}
+			}
+			t.rows[i][j] = c
+		}
+	}
+
+	if r.merged {
+		if err = r.resolveMerged(sheet, t); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// resolveMerged copies the top-left cell of every merged range to the other
+// cells of the range.
+func (r *sheetReader) resolveMerged(sheet string, t *table) error {
+	merges, err := r.file.GetMergeCells(sheet)
+	if err != nil {
+		return err
+	}
+	for _, m := range merges {
+		col0, row0, err := excelize.CellNameToCoordinates(m.GetStartAxis())
+		if err != nil {
+			return err
+		}
+		col1, row1, err := excelize.CellNameToCoordinates(m.GetEndAxis())
+		if err != nil {
+			return err
+		}
+		// Coordinates are 1-based.
+		row0, col0 = row0-1, col0-1
+		if row0 >= len(t.rows) || col0 >= len(t.rows[row0]) {
+			continue
+		}
+		top := t.rows[row0][col0]
+		for i := row0; i < row1; i++ {
+			for i >= len(t.rows) {
+				t.rows = append(t.rows, nil)
+			}
+			for len(t.rows[i]) < col1 {
+				t.rows[i] = append(t.rows[i], cell{})
+			}
+			for j := col0; j < col1; j++ {
+				t.rows[i][j] = top
+			}
+		}
+	}
+	return nil
+}
+
+// value returns the typed value of the cell at 0-based column col and row
+// row, from its raw and displayed text.
+func (r *sheetReader) value(sheet string, col, row int, raw, text string) (any, error) {
+	axis, err := excelize.CoordinatesToCellName(col+1, row+1)
+	if err != nil {
+		return nil, err
+	}
+	typ, err := r.file.GetCellType(sheet, axis)
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case excelize.CellTypeBool:
+		return raw == "1" || strings.EqualFold(raw, "true"), nil
+	case excelize.CellTypeDate:
+		if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			return t, nil
+		}
+		return text, nil
+	case excelize.CellTypeUnset, excelize.CellTypeNumber:
+	default:
+		return text, nil
+	}
+
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return text, nil
+	}
+	isDate, err := r.isDate(sheet, axis)
+	if err != nil {
+		return nil, err
+	}
+	if isDate {
+		if r.date1904 == nil {
+			props, err := r.file.GetWorkbookProps()
+			if err != nil {
+				return nil, err
+			}
+			r.date1904 = props.Date1904
+			if r.date1904 == nil {
+				r.date1904 = new(bool)
+			}
+		}
+		if t, err := excelize.ExcelDateToTime(f, *r.date1904); err == nil {
+			return t, nil
+		}
+		return text, nil
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f), nil
+	}
+	return f, nil
+}
+
+// isDate reports whether the number format of a cell is a date or time
+// format.
+func (r *sheetReader) isDate(sheet, axis string) (bool, error) {
+	id, err := r.file.GetCellStyle(sheet, axis)
+	if err != nil {
+		return false, err
+	}
+	if isDate, ok := r.dateStyles[id]; ok {
+		return isDate, nil
+	}
+	style, err := r.file.GetStyle(id)
+	if err != nil {
+		return false, err
+	}
+	var isDate bool
+	if style.CustomNumFmt != nil {
+		isDate = isDateFormat(*style.CustomNumFmt)
+	} else {
+		isDate = isBuiltInDateFormat(style.NumFmt)
+	}
+	if r.dateStyles == nil {
+		r.dateStyles = map[int]bool{}
+	}
+	r.dateStyles[id] = isDate
+	return isDate, nil
+}
+
+// isBuiltInDateFormat reports whether a built-in number format ID is a date
+// or time format, including the East Asian ones.
+func isBuiltInDateFormat(id int) bool {
+	return id >= 14 && id <= 22 || id >= 27 && id <= 36 || id >= 45 && id <= 47 || id >= 50 && id <= 58
+}
+
+// isDateFormat reports whether a number format code contains date or time
+// tokens outside of literal text.
+func isDateFormat(code string) bool {
+	// Only the first section, which applies to positive numbers, is checked.
+	var (
+		quoted, bracket bool
+		digits, date    bool
+	)
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case quoted:
+			quoted = c != '"'
+		case bracket:
+			bracket = c != ']'
+		case c == '"':
+			quoted = true
+		case c == '[':
+			// [h], [m] and [s] are elapsed time, other brackets are colors,
+			// conditions and locales.
+			if end := strings.IndexByte(code[i:], ']'); end > 0 {
+				if strings.Trim(strings.ToLower(code[i+1:i+end]), "hms") == "" {
+					date = true
+				}
+			}
+			bracket = true
+		case c == '\\' || c == '_' || c == '*':
+			i++
+		case c == ';':
+			return date && !digits
+		default:
+			switch c | 0x20 {
+			case 'y', 'd', 'h', 's', 'm', 'e':
+				date = true
+			case 'g':
+				// General
+				digits = digits || !date
+				i += len("general") - 1
+			}
+			// Zeros after seconds are fractions of a second.
+			if (c == '0' || c == '#' || c == '?') && !date {
+				digits = true
+			}
+		}
+	}
+	return date && !digits
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"strings"
 
 	"github.com/cloudwego/eino/components/document/parser"
 	"github.com/cloudwego/eino/schema"
@@ -30,6 +29,15 @@ import (
 const (
 	MetaDataRow = "_row"
 	MetaDataExt = "_ext"
+	// MetaDataSheet is the name of the sheet of the document.
+	MetaDataSheet = "_sheet"
+	// MetaDataRows holds the metadata of every row of a document grouping
+	// several rows, in the same form as MetaDataRow.
+	MetaDataRows = "_rows"
+	// MetaDataRowStart and MetaDataRowEnd are the 1-based numbers of the first
+	// and last rows of a document grouping several rows.
+	MetaDataRowStart = "_row_start"
+	MetaDataRowEnd   = "_row_end"
 )
 
 // XlsxParser Custom parser for parsing Xlsx file content
@@ -48,6 +56,19 @@ type Config struct {
 	NoHeader bool
 	// IDPrefix is set to customize the prefix of document ID, default 1,2,3, ...
 	IDPrefix string
+	// AllSheets parses every sheet in workbook order instead of SheetName.
+	// Document IDs then include the sheet name, as <IDPrefix><sheet>_<row>.
+	AllSheets bool
+	// ResolveMergedCells gives every cell of a merged range the value of its
+	// top-left cell, instead of leaving the other cells empty.
+	ResolveMergedCells bool
+	// TypedValues stores numbers, booleans and dates in the row metadata as
+	// int64, float64, bool and time.Time, instead of their displayed text.
+	TypedValues bool
+	// RowsPerDocument groups this many rows into one document, rendered as a
+	// markdown table with the header repeated. By default, every row is a
+	// document with tab separated cells.
+	RowsPerDocument int
 }
 
 // NewXlsxParser Create a new xlsxParser
@@ -56,30 +77,21 @@ func NewXlsxParser(ctx context.Context, config *Config) (xlp parser.Parser, err
 	if config == nil {
 		config = &Config{}
 	}
+	if config.RowsPerDocument < 0 {
+		return nil, fmt.Errorf("RowsPerDocument must not be negative, got %d", config.RowsPerDocument)
+	}
 	// NoHeader is false by default, which means HasHeader is true by default
 	xlp = &XlsxParser{Config: config}
 	return xlp, nil
 }
 
-// generateID generates document ID based on configuration
-func (xlp *XlsxParser) generateID(i int) string {
-	if xlp.Config.IDPrefix == "" {
-		return fmt.Sprintf("%d", i)
-	}
-	return fmt.Sprintf("%s%d", xlp.Config.IDPrefix, i)
-}
-
-// buildRowMetaData builds row metadata from row data and headers
-func (xlp *XlsxParser) buildRowMetaData(row []string, headers []string) map[string]any {
-	metaData := make(map[string]any)
-	if !xlp.Config.NoHeader {
-		for j, header := range headers {
-			if j < len(row) {
-				metaData[header] = row[j]
-			}
-		}
+func (xlp *XlsxParser) rowOptions() rowOptions {
+	return rowOptions{
+		noHeader:   xlp.Config.NoHeader,
+		idPrefix:   xlp.Config.IDPrefix,
+		rowsPerDoc: xlp.Config.RowsPerDocument,
+		sheetID:    xlp.Config.AllSheets,
 	}
-	return metaData
 }
 
 // Parse parses the XLSX content from io.Reader.
@@ -97,63 +109,27 @@ func (xlp *XlsxParser) Parse(ctx context.Context, reader io.Reader, opts ...pars
 		return nil, nil
 	}
 
-	// Default
-	sheetName := sheets[0]
-	if xlp.Config.SheetName != "" {
-		sheetName = xlp.Config.SheetName
+	if !xlp.Config.AllSheets {
+		// Default
+		sheetName := sheets[0]
+		if xlp.Config.SheetName != "" {
+			sheetName = xlp.Config.SheetName
+		}
+		sheets = []string{sheetName}
 	}
 
-	// Get all rows, header + data rows
-	rows, err := xlFile.GetRows(sheetName)
-	if err != nil {
-		return nil, err
+	r := &sheetReader{
+		file:   xlFile,
+		merged: xlp.Config.ResolveMergedCells,
+		typed:  xlp.Config.TypedValues,
 	}
-	if len(rows) == 0 {
-		return nil, nil
-	}
-
 	var ret []*schema.Document
-
-	// Process the header
-	startIdx := 0
-	var headers []string
-	if !xlp.Config.NoHeader && len(rows) > 0 {
-		headers = rows[0]
-		startIdx = 1
-	}
-
-	// Process rows of data
-	for i := startIdx; i < len(rows); i++ {
-		row := rows[i]
-		if len(row) == 0 {
-			continue
+	for _, sheetName := range sheets {
+		t, err := r.read(sheetName)
+		if err != nil {
+			return nil, err
 		}
-		// Convert row data to strings
-		contentParts := make([]string, len(row))
-		for j, cell := range row {
-			contentParts[j] = strings.TrimSpace(cell)
-		}
-		content := strings.Join(contentParts, "\t")
-
-		meta := make(map[string]any)
-
-		// Build the row's Meta
-		rowMeta := xlp.buildRowMetaData(row, headers)
-		meta[MetaDataRow] = rowMeta
-
-		// Get the Common ExtraMeta
-		if option.ExtraMeta != nil {
-			meta[MetaDataExt] = option.ExtraMeta
-		}
-
-		// Create New Document
-		nDoc := &schema.Document{
-			ID:       xlp.generateID(i),
-			Content:  content,
-			MetaData: meta,
-		}
-
-		ret = append(ret, nDoc)
+		ret = append(ret, t.documents(xlp.rowOptions(), option.ExtraMeta)...)
 	}
 
 	return ret, nil
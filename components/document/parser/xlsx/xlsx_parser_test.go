@@ -17,12 +17,15 @@
 package xlsx
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/cloudwego/eino/components/document/parser"
 	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
 )
 
 func TestXlsxParser_Parse(t *testing.T) {
@@ -103,3 +106,108 @@ func TestXlsxParser_Parse(t *testing.T) {
 		assert.Equal(t, map[string]any{"test": "test"}, docs[0].MetaData[MetaDataExt])
 	})
 }
+
+// buildWorkbook creates a workbook with typed values and a merged cell in
+// Sheet1, and a second sheet.
+func buildWorkbook(t *testing.T) *bytes.Reader {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+
+	for axis, v := range map[string]any{
+		"A1": "Region", "B1": "Date", "C1": "Units", "D1": "Price", "E1": "Active",
+		"A2": "North", "B2": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "C2": 10, "D2": 1.5, "E2": true,
+		"B3": time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), "C3": 20, "D3": 2.25, "E3": false,
+		"A4": "South", "B4": time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), "C4": 30, "D4": 3, "E4": true,
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", axis, v))
+	}
+	style, err := f.NewStyle(&excelize.Style{NumFmt: 14})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B4", style))
+	assert.NoError(t, f.MergeCell("Sheet1", "A2", "A3"))
+
+	_, err = f.NewSheet("Notes")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Notes", "A1", &[]any{"Key", "Value"}))
+	assert.NoError(t, f.SetSheetRow("Notes", "A2", &[]any{"owner", "sales"}))
+
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestXlsxParser_Options(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all sheets, merged cells and typed values", func(t *testing.T) {
+		p, err := NewXlsxParser(ctx, &Config{AllSheets: true, ResolveMergedCells: true, TypedValues: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, buildWorkbook(t))
+		assert.NoError(t, err)
+		assert.Equal(t, 4, len(docs))
+
+		assert.Equal(t, "Sheet1_2", docs[1].ID)
+		assert.Equal(t, "North\t03-02-24\t20\t2.25\tFALSE", docs[1].Content)
+		assert.Equal(t, map[string]any{
+			MetaDataSheet: "Sheet1",
+			MetaDataRow: map[string]any{
+				"Region": "North",
+				"Date":   time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+				"Units":  int64(20),
+				"Price":  2.25,
+				"Active": false,
+			},
+		}, docs[1].MetaData)
+
+		assert.Equal(t, "Notes_1", docs[3].ID)
+		assert.Equal(t, "Notes", docs[3].MetaData[MetaDataSheet])
+		assert.Equal(t, map[string]any{"Key": "owner", "Value": "sales"}, docs[3].MetaData[MetaDataRow])
+	})
+
+	t.Run("rows per document", func(t *testing.T) {
+		p, err := NewXlsxParser(ctx, &Config{RowsPerDocument: 2, IDPrefix: "sales_"})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, buildWorkbook(t), parser.WithExtraMeta(map[string]any{"test": "test"}))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(docs))
+		assert.Equal(t, "sales_1", docs[0].ID)
+		assert.Equal(t, "| Region | Date | Units | Price | Active |\n"+
+			"| --- | --- | --- | --- | --- |\n"+
+			"| North | 03-01-24 | 10 | 1.5 | TRUE |\n"+
+			"|  | 03-02-24 | 20 | 2.25 | FALSE |", docs[0].Content)
+		assert.Equal(t, 2, docs[0].MetaData[MetaDataRowStart])
+		assert.Equal(t, 3, docs[0].MetaData[MetaDataRowEnd])
+		assert.Equal(t, map[string]any{"test": "test"}, docs[0].MetaData[MetaDataExt])
+		assert.Len(t, docs[0].MetaData[MetaDataRows], 2)
+
+		assert.Equal(t, "sales_3", docs[1].ID)
+		assert.Equal(t, "| Region | Date | Units | Price | Active |\n"+
+			"| --- | --- | --- | --- | --- |\n"+
+			"| South | 03-03-24 | 30 | 3 | TRUE |", docs[1].Content)
+		assert.Equal(t, []map[string]any{{"Region": "South", "Date": "03-03-24", "Units": "30", "Price": "3", "Active": "TRUE"}}, docs[1].MetaData[MetaDataRows])
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewXlsxParser(ctx, &Config{RowsPerDocument: -1})
+		assert.Error(t, err)
+	})
+}
+
+func TestIsDateFormat(t *testing.T) {
+	for code, want := range map[string]bool{
+		"yyyy-mm-dd":          true,
+		"[h]:mm:ss":           true,
+		"hh:mm:ss.000":        true,
+		`[$-409]d\-mmm;@`:     true,
+		"0.00":                false,
+		"#,##0.00_);[Red]...": false,
+		"0.00E+00":            false,
+		`0 "days"`:            false,
+		"General":             false,
+	} {
+		assert.Equal(t, want, isDateFormat(code), code)
+	}
+}
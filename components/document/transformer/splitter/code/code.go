@@ -0,0 +1,426 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package code
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguagePython     Language = "python"
+	LanguageTypeScript Language = "typescript"
+	LanguageJavaScript Language = "javascript"
+)
+
+// Metadata keys set on every chunk. Symbol and kind are omitted for chunks
+// that do not belong to a declaration, and everything but the line range is
+// omitted for documents in an unknown language.
+const (
+	// MetaKeyLanguage is the Language of the source document.
+	MetaKeyLanguage = "language"
+	// MetaKeySymbol is the name of the declaration in the chunk, e.g.
+	// "Server.Start" for a method. Go var, const and type groups list all
+	// their names separated by ", ".
+	MetaKeySymbol = "symbol"
+	// MetaKeyKind is the kind of the declaration, e.g. "function", "method",
+	// "class", "type", or "code" for other top-level statements.
+	MetaKeyKind = "kind"
+	// MetaKeyStartLine and MetaKeyEndLine are the 1-based, inclusive line
+	// range of the chunk in the source, not counting the context header.
+	MetaKeyStartLine = "start_line"
+	MetaKeyEndLine   = "end_line"
+)
+
+// Declaration kinds.
+const (
+	KindPackage   = "package"
+	KindImport    = "import"
+	KindFunction  = "function"
+	KindMethod    = "method"
+	KindType      = "type"
+	KindClass     = "class"
+	KindInterface = "interface"
+	KindEnum      = "enum"
+	KindNamespace = "namespace"
+	KindVariable  = "variable"
+	KindConstant  = "constant"
+	KindCode      = "code"
+)
+
+// Metadata keys set by file loaders, used to detect the language.
+const (
+	metaKeyExtension = "_extension"
+	metaKeyFileName  = "_file_name"
+	metaKeySource    = "_source"
+)
+
+// IDGenerator generates new IDs for split chunks
+type IDGenerator func(ctx context.Context, originalID string, splitIndex int) string
+
+// defaultIDGenerator keeps the original ID
+func defaultIDGenerator(ctx context.Context, originalID string, _ int) string {
+	return originalID
+}
+
+type Config struct {
+	// ChunkSize is the maximum length of a chunk, measured with LenFunc and
+	// including the context header. Each declaration becomes one chunk;
+	// larger ones are split into class members, then along the language's
+	// statement boundaries, then lines. A single line is never split.
+	ChunkSize int
+	// LenFunc is used to calculate string length. Use builtin function len() by default.
+	LenFunc func(string) int
+	// Language of the documents. If empty, it is detected per document from
+	// the file extension recorded by file loaders in the "_extension",
+	// "_file_name" or "_source" metadata. Documents in an unknown language
+	// are split at blank lines and lines only.
+	Language Language
+	// IDGenerator is an optional function to generate new IDs for split chunks.
+	// If nil, the original document ID will be used for all splits.
+	IDGenerator IDGenerator
+}
+
+// NewSplitter creates a splitter for Go, Python, TypeScript and JavaScript
+// source code that splits along top-level declarations. Chunks of Go code
+// start with the package clause, and chunks holding a member of a split
+// class start with the class signature, so every chunk keeps its context.
+func NewSplitter(ctx context.Context, config *Config) (document.Transformer, error) {
+	if config.ChunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be greater than zero")
+	}
+	if config.Language != "" && languages[config.Language] == nil {
+		return nil, fmt.Errorf("unsupported language: %s", config.Language)
+	}
+	lenFunc := config.LenFunc
+	if lenFunc == nil {
+		lenFunc = func(s string) int { return len(s) }
+	}
+	idGenerator := config.IDGenerator
+	if idGenerator == nil {
+		idGenerator = defaultIDGenerator
+	}
+	return &splitter{
+		chunkSize:   config.ChunkSize,
+		lenFunc:     lenFunc,
+		language:    config.Language,
+		idGenerator: idGenerator,
+	}, nil
+}
+
+type splitter struct {
+	chunkSize   int
+	lenFunc     func(string) int
+	language    Language
+	idGenerator IDGenerator
+}
+
+// unit is a range of source lines, usually one declaration.
+type unit struct {
+	symbol string
+	kind   string
+	// header is the context prepended to the unit's chunks, e.g. the
+	// package clause or the signature of the enclosing class.
+	header string
+	// start is the 0-based index of the first line; lines holds the text.
+	start int
+	lines []string
+}
+
+// syntax parses source code of one language into units.
+type syntax interface {
+	// units returns the top-level units of src, covering all of its
+	// non-blank lines in order.
+	units(src string) []unit
+	// members splits a class-like unit into its header part and members,
+	// or returns nil if u cannot be split that way.
+	members(u unit) []unit
+	// separators are the line predicates at which an oversized unit is
+	// split, tried in order before splitting at every line.
+	separators() []func(line string) bool
+}
+
+var languages = map[Language]syntax{
+	LanguageGo:         goSyntax{},
+	LanguagePython:     pythonSyntax{},
+	LanguageTypeScript: tsSyntax{},
+	LanguageJavaScript: tsSyntax{},
+}
+
+var extensions = map[string]Language{
+	".go":  LanguageGo,
+	".py":  LanguagePython,
+	".pyi": LanguagePython,
+	".ts":  LanguageTypeScript,
+	".tsx": LanguageTypeScript,
+	".mts": LanguageTypeScript,
+	".cts": LanguageTypeScript,
+	".js":  LanguageJavaScript,
+	".jsx": LanguageJavaScript,
+	".mjs": LanguageJavaScript,
+	".cjs": LanguageJavaScript,
+}
+
+type chunk struct {
+	unit
+	end int // 0-based index of the last line
+}
+
+func (s *splitter) Transform(ctx context.Context, docs []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	var ret []*schema.Document
+	for _, doc := range docs {
+		lang := s.language
+		if lang == "" {
+			lang = detectLanguage(doc.MetaData)
+		}
+		for i, c := range s.splitText(doc.Content, lang) {
+			metaData := deepCopyAnyMap(doc.MetaData)
+			if metaData == nil {
+				metaData = make(map[string]any, 5)
+			}
+			if lang != "" {
+				metaData[MetaKeyLanguage] = string(lang)
+			}
+			if c.symbol != "" {
+				metaData[MetaKeySymbol] = c.symbol
+			}
+			if c.kind != "" {
+				metaData[MetaKeyKind] = c.kind
+			}
+			metaData[MetaKeyStartLine] = c.start + 1
+			metaData[MetaKeyEndLine] = c.end + 1
+
+			ret = append(ret, &schema.Document{
+				ID:       s.idGenerator(ctx, doc.ID, i),
+				Content:  withHeader(c.header, c.lines),
+				MetaData: metaData,
+			})
+		}
+	}
+	return ret, nil
+}
+
+func (s *splitter) GetType() string {
+	return "CodeSplitter"
+}
+
+func detectLanguage(metaData map[string]any) Language {
+	for _, key := range []string{metaKeyExtension, metaKeyFileName, metaKeySource} {
+		v, _ := metaData[key].(string)
+		if v == "" {
+			continue
+		}
+		ext := v
+		if key != metaKeyExtension {
+			ext = path.Ext(strings.ReplaceAll(v, "\\", "/"))
+		}
+		if lang, ok := extensions[strings.ToLower(ext)]; ok {
+			return lang
+		}
+	}
+	return ""
+}
+
+func (s *splitter) splitText(text string, lang Language) []chunk {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	syn := languages[lang]
+	if syn == nil {
+		u := unit{lines: strings.Split(text, "\n")}
+		return s.splitLines(u, []func(string) bool{isBlank})
+	}
+
+	var chunks []chunk
+	for _, u := range syn.units(text) {
+		chunks = append(chunks, s.splitUnit(syn, u)...)
+	}
+	return chunks
+}
+
+func (s *splitter) splitUnit(syn syntax, u unit) []chunk {
+	if s.fits(u.header, u.lines) {
+		return trimChunk(chunk{unit: u, end: u.start + len(u.lines) - 1})
+	}
+	if members := syn.members(u); len(members) > 1 {
+		var chunks []chunk
+		for _, m := range members {
+			chunks = append(chunks, s.splitUnit(syn, m)...)
+		}
+		return chunks
+	}
+	return s.splitLines(u, append([]func(string) bool{isBlank}, syn.separators()...))
+}
+
+// splitLines splits an oversized unit into line ranges of up to the chunk
+// size, preferring the earlier separators.
+func (s *splitter) splitLines(u unit, seps []func(string) bool) []chunk {
+	fits := func(lo, hi int) bool { return s.fits(u.header, u.lines[lo:hi]) }
+
+	var split func(lo, hi int, seps []func(string) bool) [][2]int
+	split = func(lo, hi int, seps []func(string) bool) [][2]int {
+		if hi-lo <= 1 || fits(lo, hi) {
+			return [][2]int{{lo, hi}}
+		}
+		var (
+			sep  func(string) bool
+			rest []func(string) bool
+		)
+		if len(seps) > 0 {
+			sep, rest = seps[0], seps[1:]
+		} else {
+			sep = func(string) bool { return true }
+		}
+
+		// split before every line matching sep
+		var segments [][2]int
+		segStart := lo
+		for i := lo + 1; i < hi; i++ {
+			if sep(u.lines[i]) {
+				segments = append(segments, [2]int{segStart, i})
+				segStart = i
+			}
+		}
+		segments = append(segments, [2]int{segStart, hi})
+		if len(segments) == 1 {
+			return split(lo, hi, rest)
+		}
+
+		// split oversized segments further and pack the pieces
+		var ranges [][2]int
+		for _, seg := range segments {
+			for _, r := range split(seg[0], seg[1], rest) {
+				if n := len(ranges); n > 0 && fits(ranges[n-1][0], r[1]) {
+					ranges[n-1][1] = r[1]
+				} else {
+					ranges = append(ranges, r)
+				}
+			}
+		}
+		return ranges
+	}
+
+	var chunks []chunk
+	for _, r := range split(0, len(u.lines), seps) {
+		part := u
+		part.start = u.start + r[0]
+		part.lines = u.lines[r[0]:r[1]]
+		chunks = append(chunks, trimChunk(chunk{unit: part, end: part.start + len(part.lines) - 1})...)
+	}
+	return chunks
+}
+
+func (s *splitter) fits(header string, lines []string) bool {
+	return s.lenFunc(withHeader(header, lines)) <= s.chunkSize
+}
+
+// trimChunk drops the leading and trailing blank lines of c, or c itself if
+// it is blank.
+func trimChunk(c chunk) []chunk {
+	for len(c.lines) > 0 && isBlank(c.lines[0]) {
+		c.lines = c.lines[1:]
+		c.start++
+	}
+	for len(c.lines) > 0 && isBlank(c.lines[len(c.lines)-1]) {
+		c.lines = c.lines[:len(c.lines)-1]
+		c.end--
+	}
+	if len(c.lines) == 0 {
+		return nil
+	}
+	return []chunk{c}
+}
+
+func withHeader(header string, lines []string) string {
+	text := strings.Join(lines, "\n")
+	if header == "" {
+		return text
+	}
+	return header + "\n\n" + text
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// indentOf returns the indentation of line in columns, counting a tab as
+// four.
+func indentOf(line string) int {
+	n := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			n++
+		case '\t':
+			n += 4 - n%4
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// groupUnits turns the start lines of units into units covering
+// lines[from:to], classifying each with classify. Consecutive imports and
+// consecutive units of other statements are merged.
+func groupUnits(lines []string, from, to int, starts []int, classify func(lines []string) (symbol, kind string)) []unit {
+	var units []unit
+	for i, start := range starts {
+		end := to
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		symbol, kind := classify(lines[start:end])
+		if n := len(units); n > 0 && (kind == KindCode || kind == KindImport) && units[n-1].kind == kind {
+			units[n-1].lines = lines[units[n-1].start:end]
+			continue
+		}
+		units = append(units, unit{symbol: symbol, kind: kind, start: start, lines: lines[start:end]})
+	}
+	if len(units) > 0 && units[0].start > from {
+		// leading lines before the first start, e.g. a license comment
+		units[0].lines = lines[from : units[0].start+len(units[0].lines)]
+		units[0].start = from
+	}
+	return units
+}
+
+// withLeadingComments moves the start line i of a unit up over the comment
+// lines directly above it.
+func withLeadingComments(lines []string, i int, isComment func(line string) bool) int {
+	for i > 0 && !isBlank(lines[i-1]) && isComment(lines[i-1]) {
+		i--
+	}
+	return i
+}
+
+func deepCopyAnyMap(anyMap map[string]any) map[string]any {
+	if anyMap == nil {
+		return nil
+	}
+	ret := make(map[string]any, len(anyMap))
+	for k, v := range anyMap {
+		ret[k] = v
+	}
+	return ret
+}
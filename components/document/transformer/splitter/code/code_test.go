@@ -0,0 +1,287 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package code
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// result summarizes a chunk for comparison.
+type result struct {
+	symbol, kind string
+	start, end   int // 1-based
+	content      string
+}
+
+func split(t *testing.T, lang Language, chunkSize int, src string) []result {
+	t.Helper()
+	s, err := NewSplitter(context.Background(), &Config{ChunkSize: chunkSize, Language: lang})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []result
+	for _, c := range s.(*splitter).splitText(src, lang) {
+		got = append(got, result{symbol: c.symbol, kind: c.kind, start: c.start + 1, end: c.end + 1, content: withHeader(c.header, c.lines)})
+	}
+	return got
+}
+
+func lines(l ...string) string {
+	return strings.Join(l, "\n")
+}
+
+func TestSplitter_Go(t *testing.T) {
+	src := lines(
+		"// Package demo is a demo.",
+		"package demo",
+		"",
+		"import \"fmt\"",
+		"",
+		"// Server serves.",
+		"type Server[T any] struct {",
+		"\tname string",
+		"}",
+		"",
+		"// Start starts the server.",
+		"func (s *Server[T]) Start() {",
+		"\tfmt.Println(s.name)",
+		"}",
+		"",
+		"const a, b = 1, 2",
+		"",
+		"func run() {",
+		"\tx := 1",
+		"\tif x > 0 {",
+		"\t\tx++",
+		"\t}",
+		"",
+		"\tfor x < 10 {",
+		"\t\tx *= 2",
+		"\t}",
+		"}",
+		"// trailing",
+	)
+	got := split(t, LanguageGo, 1000, src)
+	want := []result{
+		{"demo", KindPackage, 1, 2, "// Package demo is a demo.\npackage demo"},
+		{"", KindImport, 4, 4, "package demo\n\nimport \"fmt\""},
+		{"Server", KindType, 6, 9, "package demo\n\n// Server serves.\ntype Server[T any] struct {\n\tname string\n}"},
+		{"Server.Start", KindMethod, 11, 14, "package demo\n\n// Start starts the server.\nfunc (s *Server[T]) Start() {\n\tfmt.Println(s.name)\n}"},
+		{"a, b", KindConstant, 16, 16, "package demo\n\nconst a, b = 1, 2"},
+		{"run", KindFunction, 18, 28, "package demo\n\n" + strings.Join(strings.Split(src, "\n")[17:], "\n")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("split() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// An oversized function is split at blank lines, keeping the header.
+	got = split(t, LanguageGo, 60, src)
+	got = got[len(got)-2:]
+	want = []result{
+		{"run", KindFunction, 18, 22, "package demo\n\nfunc run() {\n\tx := 1\n\tif x > 0 {\n\t\tx++\n\t}"},
+		{"run", KindFunction, 24, 28, "package demo\n\n\tfor x < 10 {\n\t\tx *= 2\n\t}\n}\n// trailing"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("split() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Source that does not parse falls back to declaration keywords.
+	got = split(t, LanguageGo, 1000, "package demo\n\nfunc (s Server) Broken( {\n}\n\ntype T int")
+	want = []result{
+		{"demo", KindPackage, 1, 1, "package demo"},
+		{"Server.Broken", KindMethod, 3, 4, "package demo\n\nfunc (s Server) Broken( {\n}"},
+		{"T", KindType, 6, 6, "package demo\n\ntype T int"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("split() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestSplitter_Python(t *testing.T) {
+	src := lines(
+		"import os",
+		"from sys import argv",
+		"",
+		"X = {",
+		"'a': 1,",
+		"}",
+		"",
+		"@cache",
+		"def f(a,",
+		"b):",
+		"    s = \"\"\"",
+		"def not_a_function():",
+		"\"\"\"",
+		"    return s",
+		"",
+		"# Greeter greets.",
+		"class Greeter(",
+		"        Base):",
+		"    \"\"\"Doc.\"\"\"",
+		"    greeting = 'hi'",
+		"",
+		"    def greet(self):",
+		"        return self.greeting",
+		"",
+		"    @staticmethod",
+		"    async def make():",
+		"        return Greeter()",
+	)
+	got := split(t, LanguagePython, 1000, src)
+	want := []result{
+		{"", KindImport, 1, 2, "import os\nfrom sys import argv"},
+		{"", KindCode, 4, 6, "X = {\n'a': 1,\n}"},
+		{"f", KindFunction, 8, 14, lines("@cache", "def f(a,", "b):", "    s = \"\"\"", "def not_a_function():", "\"\"\"", "    return s")},
+		{"Greeter", KindClass, 16, 27, strings.Join(strings.Split(src, "\n")[15:], "\n")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("split() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// An oversized class is split into its members under its signature.
+	got = split(t, LanguagePython, 100, src)[3:]
+	sig := "class Greeter(\n        Base):\n\n"
+	want = []result{
+		{"Greeter", KindClass, 16, 20, lines("# Greeter greets.", "class Greeter(", "        Base):", "    \"\"\"Doc.\"\"\"", "    greeting = 'hi'")},
+		{"Greeter.greet", KindMethod, 22, 23, sig + "    def greet(self):\n        return self.greeting"},
+		{"Greeter.make", KindMethod, 25, 27, sig + "    @staticmethod\n    async def make():\n        return Greeter()"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("split() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestSplitter_TypeScript(t *testing.T) {
+	src := lines(
+		"import { a } from './a';",
+		"import b from 'b';",
+		"",
+		"/**",
+		" * Adds.",
+		" */",
+		"export function add(x: number, y: number): number {",
+		"  return x + y;",
+		"}",
+		"",
+		"export const mul = (x: number, y: number) => x * y;",
+		"const re = /}/g;",
+		"const tpl = `${ {a: 1}.a }",
+		"}`;",
+		"",
+		"@Component({",
+		"  selector: 'x',",
+		"})",
+		"export class Widget extends Base {",
+		"  private name = 'w';",
+		"",
+		"  render(): string {",
+		"    return `<div>${this.name}</div>`;",
+		"  }",
+		"}",
+		"type T = string",
+		"  | number;",
+		"export default {",
+		"  a: 1,",
+		"};",
+	)
+	got := split(t, LanguageTypeScript, 1000, src)
+	want := []result{
+		{"", KindImport, 1, 2, "import { a } from './a';\nimport b from 'b';"},
+		{"add", KindFunction, 4, 9, "/**\n * Adds.\n */\nexport function add(x: number, y: number): number {\n  return x + y;\n}"},
+		{"mul", KindFunction, 11, 11, "export const mul = (x: number, y: number) => x * y;"},
+		{"re", KindVariable, 12, 12, "const re = /}/g;"},
+		{"tpl", KindVariable, 13, 14, "const tpl = `${ {a: 1}.a }\n}`;"},
+		{"Widget", KindClass, 16, 25, strings.Join(strings.Split(src, "\n")[15:25], "\n")},
+		{"T", KindType, 26, 27, "type T = string\n  | number;"},
+		{"", KindCode, 28, 30, "export default {\n  a: 1,\n};"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("split() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// An oversized class is split into its members under its signature.
+	got = split(t, LanguageTypeScript, 100, src)[5:8]
+	sig := "export class Widget extends Base {\n\n"
+	want = []result{
+		{"Widget", KindClass, 16, 19, "@Component({\n  selector: 'x',\n})\nexport class Widget extends Base {"},
+		{"Widget.name", KindVariable, 20, 20, sig + "  private name = 'w';"},
+		{"Widget.render", KindMethod, 22, 25, sig + "  render(): string {\n    return `<div>${this.name}</div>`;\n  }\n}"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("split() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestSplitter_Transform(t *testing.T) {
+	ctx := context.Background()
+	if _, err := NewSplitter(ctx, &Config{}); err == nil {
+		t.Fatal("expected an error for a zero chunk size")
+	}
+	if _, err := NewSplitter(ctx, &Config{ChunkSize: 10, Language: "cobol"}); err == nil {
+		t.Fatal("expected an error for an unsupported language")
+	}
+
+	s, err := NewSplitter(ctx, &Config{
+		ChunkSize: 1000,
+		IDGenerator: func(ctx context.Context, originalID string, splitIndex int) string {
+			return fmt.Sprintf("%s_part%d", originalID, splitIndex)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Transform(ctx, []*schema.Document{
+		{ID: "py", Content: "def f():\n    pass\n", MetaData: map[string]any{"_extension": ".py"}},
+		{ID: "ts", Content: "let x = 1;", MetaData: map[string]any{"_source": "C:\\src\\A.TSX"}},
+		{ID: "txt", Content: "a\n\nb", MetaData: map[string]any{"_file_name": "notes.txt"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*schema.Document{
+		{ID: "py_part0", Content: "def f():\n    pass", MetaData: map[string]any{
+			"_extension":     ".py",
+			MetaKeyLanguage:  "python",
+			MetaKeySymbol:    "f",
+			MetaKeyKind:      KindFunction,
+			MetaKeyStartLine: 1,
+			MetaKeyEndLine:   2,
+		}},
+		{ID: "ts_part0", Content: "let x = 1;", MetaData: map[string]any{
+			"_source":        "C:\\src\\A.TSX",
+			MetaKeyLanguage:  "typescript",
+			MetaKeySymbol:    "x",
+			MetaKeyKind:      KindVariable,
+			MetaKeyStartLine: 1,
+			MetaKeyEndLine:   1,
+		}},
+		{ID: "txt_part0", Content: "a\n\nb", MetaData: map[string]any{
+			"_file_name":     "notes.txt",
+			MetaKeyStartLine: 1,
+			MetaKeyEndLine:   3,
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Transform() = %v, want %v", got, want)
+	}
+}
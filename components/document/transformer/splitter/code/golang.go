@@ -0,0 +1,189 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package code
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// goSyntax splits Go source with go/parser. Every chunk after the package
+// clause is prefixed with it.
+type goSyntax struct{}
+
+var (
+	goDeclRe    = regexp.MustCompile(`^(package|import|const|var|type|func)\b\s*(?:\(\s*(?:\w+\s+)?\*?\s*(\w+)[^)]*\)\s*)?(\w+)?`)
+	goStmtRe    = regexp.MustCompile(`^\s*(?:if|for|switch|select|go|defer|return)\b`)
+	goPackageRe = regexp.MustCompile(`^package\s+(\w+)`)
+)
+
+func (goSyntax) units(src string) []unit {
+	lines := strings.Split(src, "\n")
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil || f.Name == nil {
+		return goFallbackUnits(lines)
+	}
+
+	header := "package " + f.Name.Name
+	line := func(pos token.Pos) int { return fset.Position(pos).Line - 1 }
+
+	// The preamble up to the package clause, including the file comments.
+	pkgEnd := line(f.Name.End())
+	units := []unit{{symbol: f.Name.Name, kind: KindPackage, start: 0, lines: lines[:pkgEnd+1]}}
+
+	next := pkgEnd + 1
+	for _, decl := range f.Decls {
+		// Start right after the previous declaration, so that doc and
+		// floating comments stay with the declaration that follows them.
+		start := next
+		for start < line(decl.Pos()) && isBlank(lines[start]) {
+			start++
+		}
+		end := line(decl.End())
+		symbol, kind := goDecl(decl)
+		units = append(units, unit{symbol: symbol, kind: kind, header: header, start: start, lines: lines[start : end+1]})
+		next = end + 1
+	}
+	// Trailing comments belong to the last declaration.
+	last := &units[len(units)-1]
+	last.lines = lines[last.start:]
+	return units
+}
+
+func goDecl(decl ast.Decl) (symbol, kind string) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return d.Name.Name, KindFunction
+		}
+		return goRecvName(d.Recv.List[0].Type) + "." + d.Name.Name, KindMethod
+	case *ast.GenDecl:
+		var names []string
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, s.Name.Name)
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					names = append(names, n.Name)
+				}
+			}
+		}
+		symbol = strings.Join(names, ", ")
+		switch d.Tok {
+		case token.IMPORT:
+			return "", KindImport
+		case token.CONST:
+			return symbol, KindConstant
+		case token.VAR:
+			return symbol, KindVariable
+		default:
+			return symbol, KindType
+		}
+	}
+	return "", KindCode
+}
+
+// goRecvName returns the type name of a method receiver, without pointer
+// and type parameters.
+func goRecvName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// goFallbackUnits splits source that does not parse at the declaration
+// keywords in column 0.
+func goFallbackUnits(lines []string) []unit {
+	var (
+		starts []int
+		header string
+	)
+	for i, l := range lines {
+		if m := goPackageRe.FindStringSubmatch(l); m != nil && header == "" {
+			header = "package " + m[1]
+		}
+		if goDeclRe.MatchString(l) {
+			starts = append(starts, withLeadingComments(lines, i, isGoComment))
+		}
+	}
+	if len(starts) == 0 {
+		return []unit{{kind: KindCode, lines: lines}}
+	}
+	units := groupUnits(lines, 0, len(lines), starts, func(lines []string) (string, string) {
+		for _, l := range lines {
+			m := goDeclRe.FindStringSubmatch(l)
+			if m == nil {
+				continue
+			}
+			switch m[1] {
+			case "package":
+				return m[3], KindPackage
+			case "import":
+				return "", KindImport
+			case "const":
+				return m[3], KindConstant
+			case "var":
+				return m[3], KindVariable
+			case "type":
+				return m[3], KindType
+			case "func":
+				if m[2] != "" {
+					return m[2] + "." + m[3], KindMethod
+				}
+				return m[3], KindFunction
+			}
+		}
+		return "", KindCode
+	})
+	for i := range units {
+		if units[i].kind != KindPackage {
+			units[i].header = header
+		}
+	}
+	return units
+}
+
+func isGoComment(line string) bool {
+	t := strings.TrimSpace(line)
+	return strings.HasPrefix(t, "//") || strings.HasPrefix(t, "/*") || strings.HasPrefix(t, "*")
+}
+
+func (goSyntax) members(unit) []unit {
+	return nil
+}
+
+func (goSyntax) separators() []func(line string) bool {
+	return []func(string) bool{goStmtRe.MatchString}
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package code
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pythonSyntax splits Python source at the statements in column 0. Chunks
+// holding the members of a split class are prefixed with its signature.
+type pythonSyntax struct{}
+
+var (
+	pyDefRe    = regexp.MustCompile(`^\s*(?:async\s+)?def\s+(\w+)`)
+	pyClassRe  = regexp.MustCompile(`^\s*class\s+(\w+)`)
+	pyImportRe = regexp.MustCompile(`^\s*(?:import|from)\s`)
+	pyStmtRe   = regexp.MustCompile(`^\s*(?:if|elif|else|for|while|try|except|finally|with|def|class|return|async\s+(?:def|for|with))\b`)
+)
+
+func (pythonSyntax) units(src string) []unit {
+	lines := strings.Split(src, "\n")
+	starts := pyStatementStarts(lines, 0)
+	if len(starts) == 0 {
+		return []unit{{kind: KindCode, lines: lines}}
+	}
+	return groupUnits(lines, 0, len(lines), starts, func(lines []string) (string, string) {
+		return pyClassify(lines, "")
+	})
+}
+
+func (pythonSyntax) members(u unit) []unit {
+	if u.kind != KindClass {
+		return nil
+	}
+	// Find the class statement below decorators and comments, and the end
+	// of its signature, which may span lines.
+	logical := pyLogicalStarts(u.lines)
+	sigStart := -1
+	for i, l := range u.lines {
+		if logical[i] && pyClassRe.MatchString(l) {
+			sigStart = i
+			break
+		}
+	}
+	if sigStart < 0 {
+		return nil
+	}
+	sigEnd := sigStart + 1
+	for sigEnd < len(u.lines) && !logical[sigEnd] {
+		sigEnd++
+	}
+	bodyIndent := -1
+	for i := sigEnd; i < len(u.lines); i++ {
+		if logical[i] && !isBlank(u.lines[i]) && !isPyComment(u.lines[i]) {
+			bodyIndent = indentOf(u.lines[i])
+			break
+		}
+	}
+	if bodyIndent <= indentOf(u.lines[sigStart]) {
+		// a one-line class such as "class A: pass"
+		return nil
+	}
+
+	// The class statement, its docstring and attributes before the first
+	// member stay together; each member starts a new unit.
+	class := pyClassRe.FindStringSubmatch(u.lines[sigStart])[1]
+	starts := []int{0}
+	for _, s := range pyStatementStarts(u.lines[sigEnd:], bodyIndent) {
+		if _, kind := pyClassify(u.lines[sigEnd+s:], class); len(starts) > 1 || kind != KindCode {
+			starts = append(starts, sigEnd+s)
+		}
+	}
+	if u.symbol != "" {
+		class = u.symbol
+	}
+	signature := strings.Join(u.lines[sigStart:sigEnd], "\n")
+	if u.header != "" {
+		signature = u.header + "\n" + signature
+	}
+
+	units := groupUnits(u.lines, 0, len(u.lines), starts, func(lines []string) (string, string) {
+		return pyClassify(lines, class)
+	})
+	for i := range units {
+		units[i].start += u.start
+		if i == 0 {
+			units[i].symbol, units[i].kind, units[i].header = class, KindClass, u.header
+			continue
+		}
+		units[i].header = signature
+		if units[i].kind == KindCode {
+			units[i].symbol = class
+		}
+	}
+	return units
+}
+
+func (pythonSyntax) separators() []func(line string) bool {
+	return []func(string) bool{pyStmtRe.MatchString}
+}
+
+// pyClassify returns the symbol and kind of the statement in lines, which
+// may be preceded by comments and decorators. The symbol of a member is
+// qualified with its class.
+func pyClassify(lines []string, class string) (symbol, kind string) {
+	qualify := func(name string) string {
+		if class == "" {
+			return name
+		}
+		return class + "." + name
+	}
+	for _, l := range lines {
+		t := strings.TrimSpace(l)
+		if t == "" || strings.HasPrefix(t, "#") || strings.HasPrefix(t, "@") {
+			continue
+		}
+		if m := pyDefRe.FindStringSubmatch(l); m != nil {
+			if class != "" {
+				return qualify(m[1]), KindMethod
+			}
+			return m[1], KindFunction
+		}
+		if m := pyClassRe.FindStringSubmatch(l); m != nil {
+			return qualify(m[1]), KindClass
+		}
+		if pyImportRe.MatchString(l) {
+			return "", KindImport
+		}
+		break
+	}
+	return "", KindCode
+}
+
+// pyStatementStarts returns the lines starting a statement at the given
+// indentation. Decorators are kept with the definition they decorate, and
+// comments directly above a statement with the statement.
+func pyStatementStarts(lines []string, indent int) []int {
+	logical := pyLogicalStarts(lines)
+	var (
+		starts     []int
+		decorating bool
+	)
+	for i, l := range lines {
+		if !logical[i] || isBlank(l) || isPyComment(l) || indentOf(l) != indent {
+			continue
+		}
+		decorator := strings.HasPrefix(strings.TrimSpace(l), "@")
+		if !decorating {
+			starts = append(starts, withLeadingComments(lines, i, isPyComment))
+		}
+		decorating = decorator
+	}
+	return starts
+}
+
+func isPyComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+// pyLogicalStarts reports for every line whether it starts a logical line,
+// i.e. it does not continue a string, a bracketed expression or a line
+// ending with a backslash.
+func pyLogicalStarts(lines []string) []bool {
+	starts := make([]bool, len(lines))
+	var (
+		depth int
+		quote string // the open string delimiter, if any
+	)
+	continued := false
+	for i, l := range lines {
+		starts[i] = depth == 0 && quote == "" && !continued
+		continued = false
+		for j := 0; j < len(l); j++ {
+			c := l[j]
+			if quote != "" {
+				switch {
+				case c == '\\':
+					j++
+				case strings.HasPrefix(l[j:], quote):
+					j += len(quote) - 1
+					quote = ""
+				}
+				continue
+			}
+			switch c {
+			case '#':
+				j = len(l)
+			case '\'', '"':
+				quote = string(c)
+				if strings.HasPrefix(l[j:], strings.Repeat(quote, 3)) {
+					quote = strings.Repeat(quote, 3)
+					j += 2
+				}
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				if depth > 0 {
+					depth--
+				}
+			case '\\':
+				if j == len(l)-1 {
+					continued = true
+				}
+			}
+		}
+		if len(quote) == 1 && !continued && !strings.HasSuffix(l, "\\") {
+			// an unterminated single-quoted string ends with the line
+			quote = ""
+		}
+	}
+	return starts
+}
@@ -0,0 +1,297 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package code
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tsSyntax splits TypeScript and JavaScript source at the statements in
+// column 0 outside of any bracket. Chunks holding the members of a split
+// class are prefixed with its signature.
+type tsSyntax struct{}
+
+var (
+	tsDeclRe   = regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?(?:async\s+)?(function|class|interface|type|enum|namespace|module|const|let|var)\b\s*\*?\s*([A-Za-z_$][\w$]*)?`)
+	tsMemberRe = regexp.MustCompile(`^\s*(?:(?:public|private|protected|static|readonly|abstract|async|override|declare|get|set)\s+)*\*?\s*(#?[A-Za-z_$][\w$]*)\s*([?!]?)\s*([(<:=;]|$)`)
+	tsStmtRe   = regexp.MustCompile(`^\s*(?:if|for|while|do|switch|try|return|const|let|var|function)\b`)
+	tsArrowRe  = regexp.MustCompile(`=\s*(?:async\s*)?(?:function\b|(?:\([^)]*\)|[A-Za-z_$][\w$]*)\s*(?::[^=]+)?=>)`)
+)
+
+func (tsSyntax) units(src string) []unit {
+	lines := strings.Split(src, "\n")
+	starts := tsStatementStarts(lines, tsLex(lines), 0, 0)
+	if len(starts) == 0 {
+		return []unit{{kind: KindCode, lines: lines}}
+	}
+	return groupUnits(lines, 0, len(lines), starts, tsClassify)
+}
+
+func (tsSyntax) members(u unit) []unit {
+	if u.kind != KindClass {
+		return nil
+	}
+	// The signature runs from the class keyword to the line opening the
+	// class body.
+	lex := tsLex(u.lines)
+	sigStart, sigEnd := -1, -1
+	for i, l := range u.lines {
+		if sigStart < 0 && lex[i].code && tsDeclRe.MatchString(l) {
+			sigStart = i
+		}
+		if sigStart >= 0 && i+1 < len(u.lines) && lex[i+1].depth > 0 {
+			sigEnd = i + 1
+			break
+		}
+	}
+	if sigStart < 0 || sigEnd < 0 {
+		return nil
+	}
+
+	starts := append([]int{0}, tsStatementStarts(u.lines, lex, sigEnd, 1)...)
+	class := u.symbol
+	signature := strings.Join(u.lines[sigStart:sigEnd], "\n")
+	if u.header != "" {
+		signature = u.header + "\n" + signature
+	}
+
+	units := groupUnits(u.lines, 0, len(u.lines), starts, func(lines []string) (string, string) {
+		for _, l := range tsSkipDecorators(lines) {
+			m := tsMemberRe.FindStringSubmatch(l)
+			if m == nil {
+				break
+			}
+			if m[3] == "(" || m[3] == "<" || tsArrowRe.MatchString(l) {
+				return class + "." + m[1], KindMethod
+			}
+			return class + "." + m[1], KindVariable
+		}
+		return class, KindCode
+	})
+	for i := range units {
+		units[i].start += u.start
+		if i == 0 {
+			units[i].symbol, units[i].kind, units[i].header = class, KindClass, u.header
+			continue
+		}
+		units[i].header = signature
+	}
+	return units
+}
+
+func (tsSyntax) separators() []func(line string) bool {
+	return []func(string) bool{tsStmtRe.MatchString}
+}
+
+// tsClassify returns the symbol and kind of the statement in lines, which
+// may be preceded by comments and decorators.
+func tsClassify(lines []string) (symbol, kind string) {
+	for _, l := range tsSkipDecorators(lines) {
+		t := strings.TrimSpace(l)
+		if strings.HasPrefix(t, "import ") || strings.HasPrefix(t, "import{") || strings.HasPrefix(t, "import\"") || strings.HasPrefix(t, "import'") {
+			return "", KindImport
+		}
+		m := tsDeclRe.FindStringSubmatch(l)
+		if m == nil {
+			break
+		}
+		name := m[2]
+		if name == "" || name == "extends" || name == "implements" {
+			name = "default"
+		}
+		switch m[1] {
+		case "function":
+			return name, KindFunction
+		case "class":
+			return name, KindClass
+		case "interface":
+			return name, KindInterface
+		case "type":
+			return name, KindType
+		case "enum":
+			return name, KindEnum
+		case "namespace", "module":
+			return name, KindNamespace
+		default:
+			if tsArrowRe.MatchString(l) {
+				return name, KindFunction
+			}
+			return name, KindVariable
+		}
+	}
+	return "", KindCode
+}
+
+// tsStatementStarts returns the lines from lines[from] on that start a
+// statement at the given bracket depth. A statement must start in column 0
+// at depth 0; members of a class body may be indented. Decorators are kept
+// with the declaration they decorate, and comments directly above a
+// statement with the statement.
+func tsStatementStarts(lines []string, lex []tsLine, from, depth int) []int {
+	var (
+		starts     []int
+		decorating bool
+	)
+	for i := from; i < len(lines); i++ {
+		l := lines[i]
+		if !lex[i].code || lex[i].depth != depth || isBlank(l) || isTSComment(l) {
+			continue
+		}
+		if depth == 0 && indentOf(l) > 0 {
+			continue
+		}
+		t := strings.TrimSpace(l)
+		if strings.ContainsAny(t[:1], ")]}.,?:+-*/%&|=>") {
+			// the line continues the previous expression
+			continue
+		}
+		if !decorating {
+			starts = append(starts, withLeadingComments(lines, i, isTSComment))
+		}
+		decorating = strings.HasPrefix(t, "@")
+	}
+	return starts
+}
+
+// tsSkipDecorators returns the lines of a statement that are not blank,
+// comments or decorators, which may span lines.
+func tsSkipDecorators(lines []string) []string {
+	lex := tsLex(lines)
+	var ret []string
+	for i, l := range lines {
+		t := strings.TrimSpace(l)
+		if !lex[i].code || lex[i].depth > 0 || t == "" || isTSComment(l) || t[0] == '@' || t[0] == ')' || t[0] == '}' {
+			continue
+		}
+		ret = append(ret, l)
+	}
+	return ret
+}
+
+func isTSComment(line string) bool {
+	t := strings.TrimSpace(line)
+	return strings.HasPrefix(t, "//") || strings.HasPrefix(t, "/*") || strings.HasPrefix(t, "*")
+}
+
+// tsLine is the lexer state at the start of a line.
+type tsLine struct {
+	// code is false if the line starts inside a string, template literal
+	// or block comment.
+	code bool
+	// depth is the number of open brackets.
+	depth int
+}
+
+// tsLex scans lines and returns the state at the start of each. It knows
+// strings, template literals with substitutions, comments and regular
+// expression literals, which it tells apart from division by the token
+// before the slash.
+func tsLex(lines []string) []tsLine {
+	const (
+		stateCode = iota
+		stateString
+		stateTemplate
+		stateBlockComment
+		stateRegexp
+	)
+	var (
+		state int
+		quote byte
+		depth int
+		// templates holds the bracket depth of every open ${ substitution.
+		templates []int
+		// prev is the last significant character in code.
+		prev byte
+	)
+	ret := make([]tsLine, len(lines))
+	for i, l := range lines {
+		ret[i] = tsLine{code: state == stateCode, depth: depth}
+		for j := 0; j < len(l); j++ {
+			c := l[j]
+			switch state {
+			case stateString, stateRegexp:
+				switch {
+				case c == '\\':
+					j++
+				case c == quote:
+					state = stateCode
+					prev = c
+				}
+				continue
+			case stateTemplate:
+				switch {
+				case c == '\\':
+					j++
+				case c == '`':
+					state = stateCode
+					prev = c
+				case c == '$' && j+1 < len(l) && l[j+1] == '{':
+					j++
+					depth++
+					templates = append(templates, depth)
+					state = stateCode
+					prev = '{'
+				}
+				continue
+			case stateBlockComment:
+				if c == '*' && j+1 < len(l) && l[j+1] == '/' {
+					j++
+					state = stateCode
+				}
+				continue
+			}
+
+			switch c {
+			case ' ', '\t', '\r':
+				continue
+			case '\'', '"':
+				state, quote = stateString, c
+			case '`':
+				state = stateTemplate
+			case '/':
+				switch {
+				case j+1 < len(l) && l[j+1] == '/':
+					j = len(l)
+					continue
+				case j+1 < len(l) && l[j+1] == '*':
+					j++
+					state = stateBlockComment
+					continue
+				case prev == 0 || strings.IndexByte("(,=:[!&|?{};+-*%<>~^", prev) >= 0:
+					state, quote = stateRegexp, '/'
+				}
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				if n := len(templates); c == '}' && n > 0 && templates[n-1] == depth {
+					templates = templates[:n-1]
+					state = stateTemplate
+				}
+				if depth > 0 {
+					depth--
+				}
+			}
+			prev = c
+		}
+		if state == stateString || state == stateRegexp {
+			// strings and regular expressions cannot span lines
+			state = stateCode
+		}
+	}
+	return ret
+}
@@ -0,0 +1,256 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// MetaKeyHeadings is the metadata key of the headings enclosing a chunk,
+	// outermost first, as a []string.
+	MetaKeyHeadings = "headings"
+	// MetaKeyBreadcrumb is the metadata key of the headings enclosing a chunk
+	// joined with " > ", e.g. "Guide > Install > Linux".
+	MetaKeyBreadcrumb = "breadcrumb"
+
+	breadcrumbSeparator = " > "
+	blockSeparator      = "\n\n"
+)
+
+type BlockConfig struct {
+	// ChunkSize is the maximum length of a chunk, measured with LenFunc.
+	// Blocks are packed into chunks up to this size. A single code block,
+	// table row, list item or heading longer than ChunkSize is never split
+	// and becomes an oversized chunk on its own.
+	ChunkSize int
+	// LenFunc is used to calculate string length. Use builtin function len() by default.
+	LenFunc func(string) int
+	// IDGenerator is an optional function to generate new IDs for split chunks.
+	// If nil, the original document ID will be used for all splits.
+	IDGenerator IDGenerator
+}
+
+// NewBlockSplitter creates a markdown splitter that parses documents into
+// blocks (headings, paragraphs, lists, tables, code, quotes) and packs them
+// into chunks of up to ChunkSize. Every heading starts a new chunk, and the
+// enclosing headings are recorded under [MetaKeyHeadings] and
+// [MetaKeyBreadcrumb]. Blocks larger than ChunkSize are split between table
+// rows, list items or lines, never inside a code fence, table row or list
+// item; continuation chunks of a table repeat its header row.
+func NewBlockSplitter(ctx context.Context, config *BlockConfig) (document.Transformer, error) {
+	if config.ChunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be greater than zero")
+	}
+	lenFunc := config.LenFunc
+	if lenFunc == nil {
+		lenFunc = func(s string) int { return len(s) }
+	}
+	idGenerator := config.IDGenerator
+	if idGenerator == nil {
+		idGenerator = defaultIDGenerator
+	}
+	return &blockSplitter{
+		chunkSize:   config.ChunkSize,
+		lenFunc:     lenFunc,
+		idGenerator: idGenerator,
+	}, nil
+}
+
+type blockSplitter struct {
+	chunkSize   int
+	lenFunc     func(string) int
+	idGenerator IDGenerator
+}
+
+type blockChunk struct {
+	content  string
+	headings []string
+}
+
+func (s *blockSplitter) Transform(ctx context.Context, docs []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	var ret []*schema.Document
+	for _, doc := range docs {
+		chunks := s.splitText(doc.Content)
+		for i, chunk := range chunks {
+			nDoc := &schema.Document{
+				ID:       s.idGenerator(ctx, doc.ID, i),
+				Content:  chunk.content,
+				MetaData: deepCopyAnyMap(doc.MetaData),
+			}
+			if len(chunk.headings) > 0 {
+				if nDoc.MetaData == nil {
+					nDoc.MetaData = make(map[string]any, 2)
+				}
+				nDoc.MetaData[MetaKeyHeadings] = chunk.headings
+				nDoc.MetaData[MetaKeyBreadcrumb] = strings.Join(chunk.headings, breadcrumbSeparator)
+			}
+			ret = append(ret, nDoc)
+		}
+	}
+	return ret, nil
+}
+
+func (s *blockSplitter) GetType() string {
+	return "MarkdownBlockSplitter"
+}
+
+// packer accumulates pieces of text into chunks of up to chunkSize.
+type packer struct {
+	s      *blockSplitter
+	chunks []blockChunk
+
+	current []string
+	// headingLevels holds the levels of the headings in current; it is
+	// only non-empty while current contains nothing but headings.
+	headingLevels []int
+	hasContent    bool
+	stack         []*block // enclosing headings
+}
+
+func (s *blockSplitter) splitText(text string) []blockChunk {
+	p := &packer{s: s}
+	for _, b := range parseBlocks(text) {
+		if b.kind == blockHeading {
+			p.heading(b)
+			continue
+		}
+		p.hasContent = true
+		text := b.text()
+		if s.lenFunc(text) <= s.chunkSize {
+			p.add(text)
+			continue
+		}
+		for _, piece := range s.splitBlock(b) {
+			p.add(piece)
+		}
+	}
+	p.flush()
+	return p.chunks
+}
+
+// heading starts a new section. Headings directly following each other
+// share a chunk as long as each one is nested in the previous one.
+func (p *packer) heading(b *block) {
+	if p.hasContent || (len(p.headingLevels) > 0 && b.level <= p.headingLevels[len(p.headingLevels)-1]) {
+		p.flush()
+	}
+	for len(p.stack) > 0 && p.stack[len(p.stack)-1].level >= b.level {
+		p.stack = p.stack[:len(p.stack)-1]
+	}
+	p.stack = append(p.stack, b)
+	p.add(b.text())
+	p.headingLevels = append(p.headingLevels, b.level)
+}
+
+func (p *packer) add(piece string) {
+	if len(p.current) > 0 {
+		candidate := strings.Join(p.current, blockSeparator) + blockSeparator + piece
+		if p.s.lenFunc(candidate) > p.s.chunkSize {
+			hasContent := p.hasContent
+			p.flush()
+			p.hasContent = hasContent
+		}
+	}
+	p.current = append(p.current, piece)
+}
+
+func (p *packer) flush() {
+	if len(p.current) > 0 {
+		var headings []string
+		for _, h := range p.stack {
+			headings = append(headings, h.title)
+		}
+		p.chunks = append(p.chunks, blockChunk{
+			content:  strings.Join(p.current, blockSeparator),
+			headings: headings,
+		})
+	}
+	p.current = nil
+	p.headingLevels = nil
+	p.hasContent = false
+}
+
+// splitBlock splits a block longer than the chunk size at the boundaries
+// allowed for its kind.
+func (s *blockSplitter) splitBlock(b *block) []string {
+	switch b.kind {
+	case blockTable:
+		if len(b.lines) <= 2 {
+			// a table without body rows cannot be split, keep the header whole
+			return []string{b.text()}
+		}
+		header := strings.Join(b.lines[:2], "\n")
+		return s.group(b.lines[2:], header, "\n")
+	case blockList:
+		items := make([]string, len(b.items))
+		for i, start := range b.items {
+			end := len(b.lines)
+			if i+1 < len(b.items) {
+				end = b.items[i+1]
+			}
+			items[i] = strings.TrimRight(strings.Join(b.lines[start:end], "\n"), "\n")
+		}
+		return s.group(items, "", "\n")
+	case blockParagraph, blockQuote:
+		var units []string
+		for _, line := range b.lines {
+			if s.lenFunc(line) <= s.chunkSize {
+				units = append(units, line)
+				continue
+			}
+			units = append(units, s.group(strings.Fields(line), "", " ")...)
+		}
+		return s.group(units, "", "\n")
+	default:
+		// code blocks, thematic breaks
+		return []string{b.text()}
+	}
+}
+
+// group joins consecutive units with sep into pieces of up to the chunk
+// size, starting every piece with prefix. A unit that does not fit on its
+// own becomes a piece by itself.
+func (s *blockSplitter) group(units []string, prefix, sep string) []string {
+	var (
+		pieces  []string
+		current []string
+	)
+	join := func(units []string) string {
+		text := strings.Join(units, sep)
+		if prefix != "" {
+			text = prefix + "\n" + text
+		}
+		return text
+	}
+	for _, unit := range units {
+		if len(current) > 0 && s.lenFunc(join(append(current[:len(current):len(current)], unit))) > s.chunkSize {
+			pieces = append(pieces, join(current))
+			current = nil
+		}
+		current = append(current, unit)
+	}
+	if len(current) > 0 {
+		pieces = append(pieces, join(current))
+	}
+	return pieces
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestParseBlocks(t *testing.T) {
+	text := strings.Join([]string{
+		"Title",
+		"=====",
+		"Some *intro*",
+		"continues here.",
+		"- item 1",
+		"  - nested",
+		"lazy line",
+		"",
+		"- item 2",
+		"",
+		"  second paragraph of item 2",
+		"",
+		"| a | b |",
+		"|---|:-:|",
+		"| 1 | 2 |",
+		"",
+		"```go",
+		"# not a heading",
+		"",
+		"| not | a table |",
+		"```",
+		"    indented code",
+		"",
+		"    more code",
+		"> quote",
+		"lazy quote",
+		"***",
+		"## Sub ##",
+		"1) one",
+		"2) two",
+	}, "\n")
+
+	type want struct {
+		kind  blockKind
+		lines int
+		items []int
+		title string
+	}
+	var got []want
+	for _, b := range parseBlocks(text) {
+		got = append(got, want{kind: b.kind, lines: len(b.lines), items: b.items, title: b.title})
+	}
+	expected := []want{
+		{kind: blockHeading, lines: 2, title: "Title"},
+		{kind: blockParagraph, lines: 2},
+		{kind: blockList, lines: 7, items: []int{0, 4}},
+		{kind: blockTable, lines: 3},
+		{kind: blockCode, lines: 5},
+		{kind: blockCode, lines: 3},
+		{kind: blockQuote, lines: 2},
+		{kind: blockThematicBreak, lines: 1},
+		{kind: blockHeading, lines: 1, title: "Sub"},
+		{kind: blockList, lines: 2, items: []int{0, 1}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("parseBlocks() =\n%+v\nwant\n%+v", got, expected)
+	}
+}
+
+func TestBlockSplitter(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		chunkSize int
+		content   string
+		want      []blockChunk
+	}{
+		{
+			name:      "breadcrumb",
+			chunkSize: 1000,
+			content:   "intro\n# Guide\nabout\n## Install\n### Linux\nsteps\n## Usage\nuse it\n# Other\nmore",
+			want: []blockChunk{
+				{content: "intro"},
+				{content: "# Guide\n\nabout", headings: []string{"Guide"}},
+				{content: "## Install\n\n### Linux\n\nsteps", headings: []string{"Guide", "Install", "Linux"}},
+				{content: "## Usage\n\nuse it", headings: []string{"Guide", "Usage"}},
+				{content: "# Other\n\nmore", headings: []string{"Other"}},
+			},
+		},
+		{
+			name:      "sibling headings without content",
+			chunkSize: 1000,
+			content:   "## A\n## B\ntext",
+			want: []blockChunk{
+				{content: "## A", headings: []string{"A"}},
+				{content: "## B\n\ntext", headings: []string{"B"}},
+			},
+		},
+		{
+			name:      "packs blocks up to the chunk size",
+			chunkSize: 20,
+			content:   "# T\naaaa\n\nbbbb\n\ncccccccccc\n\ndddd",
+			want: []blockChunk{
+				{content: "# T\n\naaaa\n\nbbbb", headings: []string{"T"}},
+				{content: "cccccccccc\n\ndddd", headings: []string{"T"}},
+			},
+		},
+		{
+			name:      "table rows with repeated header",
+			chunkSize: 40,
+			content:   "| k | v |\n|---|---|\n| 1 | a |\n| 2 | b |\n| 3 | c |\n| 4 | d |\n| 5 | e |",
+			want: []blockChunk{
+				{content: "| k | v |\n|---|---|\n| 1 | a |\n| 2 | b |"},
+				{content: "| k | v |\n|---|---|\n| 3 | c |\n| 4 | d |"},
+				{content: "| k | v |\n|---|---|\n| 5 | e |"},
+			},
+		},
+		{
+			name:      "table with only a header is kept whole",
+			chunkSize: 20,
+			content:   "# T\n\n| aaaaaaaa | bbbbbbbbbb | cccccccc |\n|---|---|---|\n\nafter",
+			want: []blockChunk{
+				{content: "# T", headings: []string{"T"}},
+				{content: "| aaaaaaaa | bbbbbbbbbb | cccccccc |\n|---|---|---|", headings: []string{"T"}},
+				{content: "after", headings: []string{"T"}},
+			},
+		},
+		{
+			name:      "code fence is never split",
+			chunkSize: 10,
+			content:   "```\nline one\n\n# inside\nline two\n```\nafter",
+			want: []blockChunk{
+				{content: "```\nline one\n\n# inside\nline two\n```"},
+				{content: "after"},
+			},
+		},
+		{
+			name:      "list items are kept whole",
+			chunkSize: 25,
+			content:   "- first item\n  - nested a\n  - nested b\n- second item\n- third",
+			want: []blockChunk{
+				{content: "- first item\n  - nested a\n  - nested b"},
+				{content: "- second item\n- third"},
+			},
+		},
+		{
+			name:      "long paragraph falls back to lines and words",
+			chunkSize: 12,
+			content:   "short line\nthis line is far too long",
+			want: []blockChunk{
+				{content: "short line"},
+				{content: "this line is"},
+				{content: "far too long"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewBlockSplitter(ctx, &BlockConfig{ChunkSize: tt.chunkSize})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := s.(*blockSplitter).splitText(tt.content)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitText() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockSplitter_Transform(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewBlockSplitter(ctx, &BlockConfig{})
+	if err == nil {
+		t.Fatal("expected an error for a zero chunk size")
+	}
+
+	s, err := NewBlockSplitter(ctx, &BlockConfig{
+		ChunkSize: 100,
+		IDGenerator: func(ctx context.Context, originalID string, splitIndex int) string {
+			return fmt.Sprintf("%s_part%d", originalID, splitIndex)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Transform(ctx, []*schema.Document{{
+		ID:       "doc",
+		Content:  "preface\n# A\n## B\ntext",
+		MetaData: map[string]any{"source": "a.md"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*schema.Document{
+		{ID: "doc_part0", Content: "preface", MetaData: map[string]any{"source": "a.md"}},
+		{ID: "doc_part1", Content: "# A\n\n## B\n\ntext", MetaData: map[string]any{
+			"source":          "a.md",
+			MetaKeyHeadings:   []string{"A", "B"},
+			MetaKeyBreadcrumb: "A > B",
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Transform() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,304 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockCode
+	blockTable
+	blockList
+	blockQuote
+	blockThematicBreak
+)
+
+// block is a top-level markdown block. Its lines are kept verbatim.
+type block struct {
+	kind  blockKind
+	lines []string
+
+	// level and title are set for headings.
+	level int
+	title string
+	// items holds the index of the first line of every list item.
+	items []int
+}
+
+func (b *block) text() string {
+	return strings.Join(b.lines, "\n")
+}
+
+var (
+	atxHeadingRe    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	setextH1Re      = regexp.MustCompile(`^ {0,3}=+[ \t]*$`)
+	setextH2Re      = regexp.MustCompile(`^ {0,3}-+[ \t]*$`)
+	thematicBreakRe = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fenceRe         = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})(.*)$")
+	listMarkerRe    = regexp.MustCompile(`^( {0,3})([-+*]|\d{1,9}[.)])(?:([ \t]+)|$)`)
+	quoteRe         = regexp.MustCompile(`^ {0,3}>`)
+	tableDelimRe    = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+)
+
+// parseBlocks splits markdown text into top-level blocks. It follows
+// CommonMark and GFM closely enough to find the boundaries that must not be
+// split: fenced and indented code, tables, list items, block quotes and
+// headings. Inline markup and HTML blocks are not interpreted.
+func parseBlocks(text string) []*block {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var blocks []*block
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		var b *block
+		switch {
+		case isBlank(line):
+			i++
+			continue
+		case fenceRe.MatchString(line) && validFence(line):
+			b, i = parseFence(lines, i)
+		case indentOf(line) >= 4:
+			b, i = parseIndentedCode(lines, i)
+		case atxHeadingRe.MatchString(line):
+			m := atxHeadingRe.FindStringSubmatch(line)
+			b = &block{kind: blockHeading, lines: []string{line}, level: len(m[1]), title: strings.TrimSpace(m[2])}
+			i++
+		case thematicBreakRe.MatchString(line):
+			b = &block{kind: blockThematicBreak, lines: []string{line}}
+			i++
+		case isTableStart(lines, i):
+			b, i = parseTable(lines, i)
+		case listMarkerRe.MatchString(line):
+			b, i = parseList(lines, i)
+		case quoteRe.MatchString(line):
+			b, i = parseQuote(lines, i)
+		default:
+			b, i = parseParagraph(lines, i)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+func parseFence(lines []string, start int) (*block, int) {
+	m := fenceRe.FindStringSubmatch(lines[start])
+	fence := m[2]
+	end := start + 1
+	for ; end < len(lines); end++ {
+		if isClosingFence(lines[end], fence) {
+			end++
+			break
+		}
+	}
+	return &block{kind: blockCode, lines: lines[start:end]}, end
+}
+
+// validFence reports whether a fence line is an opening fence; the info
+// string of a backtick fence cannot contain backticks.
+func validFence(line string) bool {
+	m := fenceRe.FindStringSubmatch(line)
+	return m[2][0] != '`' || !strings.Contains(m[3], "`")
+}
+
+func isClosingFence(line, fence string) bool {
+	if indentOf(line) > 3 {
+		return false
+	}
+	trimmed := strings.TrimSpace(line)
+	run := len(trimmed) - len(strings.TrimLeft(trimmed, fence[:1]))
+	return run >= len(fence) && run == len(trimmed)
+}
+
+func parseIndentedCode(lines []string, start int) (*block, int) {
+	end := start + 1
+	for end < len(lines) {
+		if indentOf(lines[end]) >= 4 && !isBlank(lines[end]) {
+			end++
+			continue
+		}
+		next := nextNonBlank(lines, end)
+		if isBlank(lines[end]) && next < len(lines) && indentOf(lines[next]) >= 4 {
+			end = next
+			continue
+		}
+		break
+	}
+	return &block{kind: blockCode, lines: lines[start:end]}, end
+}
+
+func isTableStart(lines []string, i int) bool {
+	return i+1 < len(lines) && strings.Contains(lines[i], "|") &&
+		tableDelimRe.MatchString(lines[i+1]) && strings.Contains(lines[i+1], "-")
+}
+
+func parseTable(lines []string, start int) (*block, int) {
+	end := start + 2
+	for end < len(lines) && !isBlank(lines[end]) && strings.Contains(lines[end], "|") && !startsBlock(lines[end]) {
+		end++
+	}
+	return &block{kind: blockTable, lines: lines[start:end]}, end
+}
+
+func parseList(lines []string, start int) (*block, int) {
+	b := &block{kind: blockList, items: []int{0}}
+	baseIndent := indentOf(lines[start])
+	contentIndent := listContentIndent(lines[start])
+	end := start + 1
+	for end < len(lines) {
+		line := lines[end]
+		if isBlank(line) {
+			// A blank line continues the list only if the list goes on.
+			next := nextNonBlank(lines, end)
+			if next == len(lines) {
+				break
+			}
+			if indentOf(lines[next]) >= contentIndent || isSiblingItem(lines[next], baseIndent, contentIndent) {
+				end = next
+				continue
+			}
+			break
+		}
+		switch {
+		case isSiblingItem(line, baseIndent, contentIndent):
+			b.items = append(b.items, end-start)
+			contentIndent = listContentIndent(line)
+		case indentOf(line) >= contentIndent:
+			// continuation or nested block of the current item
+		case isBlank(lines[end-1]) || startsBlock(line):
+			b.lines = lines[start:end]
+			return b, end
+		default:
+			// lazy continuation line of a paragraph in the item
+		}
+		end++
+	}
+	b.lines = lines[start:end]
+	return b, end
+}
+
+// isSiblingItem reports whether line starts a new item of a list whose
+// markers are indented by baseIndent.
+func isSiblingItem(line string, baseIndent, contentIndent int) bool {
+	if thematicBreakRe.MatchString(line) || !listMarkerRe.MatchString(line) {
+		return false
+	}
+	indent := indentOf(line)
+	return indent >= baseIndent && indent < contentIndent
+}
+
+// listContentIndent returns the column where the content of the list item
+// started by line begins.
+func listContentIndent(line string) int {
+	m := listMarkerRe.FindStringSubmatch(line)
+	spaces := len(m[3])
+	if spaces == 0 || spaces > 4 {
+		spaces = 1
+	}
+	return len(m[1]) + len(m[2]) + spaces
+}
+
+func parseQuote(lines []string, start int) (*block, int) {
+	end := start + 1
+	for end < len(lines) {
+		line := lines[end]
+		if quoteRe.MatchString(line) {
+			end++
+			continue
+		}
+		// lazy continuation of a quoted paragraph
+		if isBlank(line) || startsBlock(line) || isBlank(lines[end-1]) {
+			break
+		}
+		end++
+	}
+	return &block{kind: blockQuote, lines: lines[start:end]}, end
+}
+
+func parseParagraph(lines []string, start int) (*block, int) {
+	end := start + 1
+	for end < len(lines) {
+		line := lines[end]
+		if setextH1Re.MatchString(line) || setextH2Re.MatchString(line) {
+			level := 1
+			if setextH2Re.MatchString(line) {
+				level = 2
+			}
+			title := make([]string, 0, end-start)
+			for _, l := range lines[start:end] {
+				title = append(title, strings.TrimSpace(l))
+			}
+			return &block{kind: blockHeading, lines: lines[start : end+1], level: level, title: strings.Join(title, " ")}, end + 1
+		}
+		if isBlank(line) || startsBlock(line) || isTableStart(lines, end) || interruptsParagraph(line) {
+			break
+		}
+		end++
+	}
+	return &block{kind: blockParagraph, lines: lines[start:end]}, end
+}
+
+// startsBlock reports whether line opens a block that ends any paragraph,
+// list or table before it.
+func startsBlock(line string) bool {
+	return (fenceRe.MatchString(line) && validFence(line)) || atxHeadingRe.MatchString(line) ||
+		thematicBreakRe.MatchString(line) || quoteRe.MatchString(line)
+}
+
+// interruptsParagraph reports whether a list item starting at line can
+// interrupt a paragraph: it must not be empty, and an ordered list must
+// start at 1.
+func interruptsParagraph(line string) bool {
+	m := listMarkerRe.FindStringSubmatch(line)
+	if m == nil || strings.TrimSpace(line[len(m[0]):]) == "" {
+		return false
+	}
+	marker := m[2]
+	return marker == "-" || marker == "+" || marker == "*" || strings.TrimLeft(marker[:len(marker)-1], "0") == "1"
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// indentOf returns the indentation of line in columns, counting a tab as
+// four.
+func indentOf(line string) int {
+	n := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			n++
+		case '\t':
+			n += 4 - n%4
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+func nextNonBlank(lines []string, i int) int {
+	for i < len(lines) && isBlank(lines[i]) {
+		i++
+	}
+	return i
+}
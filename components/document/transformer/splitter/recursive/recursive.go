@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/schema"
@@ -36,6 +38,24 @@ const (
 	KeepTypeEnd
 )
 
+// Metadata keys recording where every chunk came from. Offsets are
+// half-open ranges into the Content of the source document, so
+// Content[byte_start:byte_end] is the chunk text.
+const (
+	// MetaKeyParentID is the ID of the document the chunk was split from.
+	MetaKeyParentID = "parent_id"
+	// MetaKeyChunkIndex is the zero-based position of the chunk within its document.
+	MetaKeyChunkIndex = "chunk_index"
+	// MetaKeyChunkTotal is the number of chunks the document was split into.
+	MetaKeyChunkTotal = "chunk_total"
+	// MetaKeyByteStart and MetaKeyByteEnd are the byte offsets of the chunk.
+	MetaKeyByteStart = "byte_start"
+	MetaKeyByteEnd   = "byte_end"
+	// MetaKeyRuneStart and MetaKeyRuneEnd are the rune (Unicode code point) offsets of the chunk.
+	MetaKeyRuneStart = "rune_start"
+	MetaKeyRuneEnd   = "rune_end"
+)
+
 // IDGenerator generates new IDs for split chunks
 type IDGenerator func(ctx context.Context, originalID string, splitIndex int) string
 
@@ -54,6 +74,10 @@ type Config struct {
 	Separators []string
 	// LenFunc is used to calculate string length. Use builtin function len() by default.
 	LenFunc func(string) int
+	// Tokenizer, if set, measures length in tokens, so ChunkSize and OverlapSize are token counts.
+	// See [BPETokenizer] for a tokenizer that loads tiktoken vocabularies such as cl100k_base.
+	// Cannot be combined with LenFunc.
+	Tokenizer Tokenizer
 	// KeepType specifies if separator will be kept in split chunks. Discard separator by default.
 	KeepType KeepType
 	// IDGenerator is an optional function to generate new IDs for split chunks.
@@ -70,7 +94,14 @@ func NewSplitter(ctx context.Context, config *Config) (document.Transformer, err
 		return nil, fmt.Errorf("overlap must be greater than or equal to zero")
 	}
 
+	if config.LenFunc != nil && config.Tokenizer != nil {
+		return nil, fmt.Errorf("only one of LenFunc and Tokenizer can be set")
+	}
+
 	lenFunc := config.LenFunc
+	if config.Tokenizer != nil {
+		lenFunc = config.Tokenizer.CountTokens
+	}
 	if lenFunc == nil {
 		lenFunc = func(s string) int { return len(s) }
 	}
@@ -101,23 +132,44 @@ type splitter struct {
 	idGenerator IDGenerator
 }
 
+// span is a piece of the source text and its byte offset in the source.
+type span struct {
+	text  string
+	start int
+}
+
 func (s *splitter) Transform(ctx context.Context, docs []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
 	ret := make([]*schema.Document, 0, len(docs))
 	for _, doc := range docs {
-		splits := s.splitText(ctx, doc.Content, s.separators)
+		splits := s.splitText(ctx, doc.Content, 0, s.separators)
+		runes := runeCounter{text: doc.Content}
 		for i, split := range splits {
+			metaData := deepCopyMap(doc.MetaData)
+			if metaData == nil {
+				metaData = make(map[string]any, 7)
+			}
+			runeStart := runes.at(split.start)
+			metaData[MetaKeyParentID] = doc.ID
+			metaData[MetaKeyChunkIndex] = i
+			metaData[MetaKeyChunkTotal] = len(splits)
+			metaData[MetaKeyByteStart] = split.start
+			metaData[MetaKeyByteEnd] = split.start + len(split.text)
+			metaData[MetaKeyRuneStart] = runeStart
+			metaData[MetaKeyRuneEnd] = runeStart + utf8.RuneCountInString(split.text)
+
 			ret = append(ret, &schema.Document{
 				ID:       s.idGenerator(ctx, doc.ID, i),
-				Content:  split,
-				MetaData: deepCopyMap(doc.MetaData),
+				Content:  split.text,
+				MetaData: metaData,
 			})
 		}
 	}
 	return ret, nil
 }
 
-func (s *splitter) splitText(ctx context.Context, text string, separators []string) (output []string) {
-	finalChunks := make([]string, 0)
+// splitText splits text, which starts at offset in the source, into chunks.
+func (s *splitter) splitText(ctx context.Context, text string, offset int, separators []string) (output []span) {
+	finalChunks := make([]span, 0)
 
 	// find the appropriate separator
 	separator := separators[len(separators)-1]
@@ -130,12 +182,12 @@ func (s *splitter) splitText(ctx context.Context, text string, separators []stri
 		}
 	}
 
-	splits := s.split(text, separator, s.keepType)
-	goodSplits := make([]string, 0)
+	splits := s.split(text, offset, separator, s.keepType)
+	goodSplits := make([]span, 0)
 
 	// merge the splits, recursively splitting larger texts.
 	for _, split := range splits {
-		if s.lenFunc(split) < s.chunkSize {
+		if s.lenFunc(split.text) < s.chunkSize {
 			goodSplits = append(goodSplits, split)
 			continue
 		}
@@ -144,13 +196,13 @@ func (s *splitter) splitText(ctx context.Context, text string, separators []stri
 			mergedText := s.mergeSplits(goodSplits, separator, s.chunkSize, s.lenFunc, s.keepType)
 
 			finalChunks = append(finalChunks, mergedText...)
-			goodSplits = make([]string, 0)
+			goodSplits = make([]span, 0)
 		}
 
 		if len(newSeparators) == 0 {
 			finalChunks = append(finalChunks, split)
 		} else {
-			otherInfo := s.splitText(ctx, split, newSeparators)
+			otherInfo := s.splitText(ctx, split.text, split.start, newSeparators)
 			finalChunks = append(finalChunks, otherInfo...)
 		}
 	}
@@ -163,43 +215,56 @@ func (s *splitter) splitText(ctx context.Context, text string, separators []stri
 	return finalChunks
 }
 
-func (s *splitter) split(text string, separator string, t KeepType) []string {
+// split splits text, which starts at offset in the source, by separator.
+func (s *splitter) split(text string, offset int, separator string, t KeepType) []span {
+	var parts []string
 	switch t {
-	case KeepTypeNone:
-		return strings.Split(text, separator)
+	case KeepTypeNone, KeepTypeStart:
+		parts = strings.Split(text, separator)
 	case KeepTypeEnd:
-		return strings.SplitAfter(text, separator)
-	case KeepTypeStart:
-		splits := strings.Split(text, separator)
-		for i := 1; i < len(splits); i++ {
-			splits[i] = separator + splits[i]
-		}
-		return splits
+		parts = strings.SplitAfter(text, separator)
 	default:
 		panic(fmt.Sprintf("unknown keep type: %v", t))
 	}
+
+	spans := make([]span, len(parts))
+	pos := offset
+	for i, part := range parts {
+		switch {
+		case t == KeepTypeStart && i > 0:
+			spans[i] = span{text: separator + part, start: pos}
+			pos += len(separator) + len(part)
+		case t == KeepTypeNone && i > 0:
+			pos += len(separator)
+			fallthrough
+		default:
+			spans[i] = span{text: part, start: pos}
+			pos += len(part)
+		}
+	}
+	return spans
 }
 
 // mergeSplits merges smaller splits into splits that are closer to the chunkSize.
-func (s *splitter) mergeSplits(splits []string, separator string, chunkSize int, lenFunc func(string) int, t KeepType) []string {
-	docs := make([]string, 0)
-	currentDoc := make([]string, 0)
+func (s *splitter) mergeSplits(splits []span, separator string, chunkSize int, lenFunc func(string) int, t KeepType) []span {
+	docs := make([]span, 0)
+	currentDoc := make([]span, 0)
 	total := 0
 
 	for _, split := range splits {
-		totalWithSplit := total + lenFunc(split)
+		totalWithSplit := total + lenFunc(split.text)
 		if len(currentDoc) != 0 && t == KeepTypeNone {
 			totalWithSplit += lenFunc(separator)
 		}
 
 		if totalWithSplit > chunkSize && len(currentDoc) > 0 {
 			doc := joinDocs(currentDoc, separator, t)
-			if doc != "" {
+			if doc.text != "" {
 				docs = append(docs, doc)
 			}
 
-			for s.shouldPop(total, lenFunc(split), lenFunc(separator), len(currentDoc)) {
-				total -= lenFunc(currentDoc[0])
+			for s.shouldPop(total, lenFunc(split.text), lenFunc(separator), len(currentDoc)) {
+				total -= lenFunc(currentDoc[0].text)
 				if len(currentDoc) > 1 && s.keepType == KeepTypeNone {
 					total -= lenFunc(separator)
 				}
@@ -208,14 +273,14 @@ func (s *splitter) mergeSplits(splits []string, separator string, chunkSize int,
 		}
 
 		currentDoc = append(currentDoc, split)
-		total += lenFunc(split)
+		total += lenFunc(split.text)
 		if len(currentDoc) > 1 && t == KeepTypeNone {
 			total += lenFunc(separator)
 		}
 	}
 
 	doc := joinDocs(currentDoc, separator, t)
-	if doc != "" {
+	if doc.text != "" {
 		docs = append(docs, doc)
 	}
 
@@ -237,11 +302,44 @@ func (s *splitter) GetType() string {
 	return "RecursiveSplitter"
 }
 
-func joinDocs(docs []string, separator string, t KeepType) string {
+// joinDocs joins consecutive splits and trims surrounding whitespace. The
+// splits are adjacent in the source, so the result is a span of it.
+func joinDocs(docs []span, separator string, t KeepType) span {
+	if len(docs) == 0 {
+		return span{}
+	}
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.text
+	}
+	var joined string
 	if t == KeepTypeNone {
-		return strings.TrimSpace(strings.Join(docs, separator))
+		joined = strings.Join(texts, separator)
+	} else {
+		joined = strings.Join(texts, "")
+	}
+	trimmedLeft := strings.TrimLeftFunc(joined, unicode.IsSpace)
+	return span{
+		text:  strings.TrimRightFunc(trimmedLeft, unicode.IsSpace),
+		start: docs[0].start + len(joined) - len(trimmedLeft),
+	}
+}
+
+// runeCounter converts byte offsets into text to rune offsets. It is
+// cheapest when offsets are queried in increasing order.
+type runeCounter struct {
+	text      string
+	lastByte  int
+	lastRunes int
+}
+
+func (c *runeCounter) at(byteOffset int) int {
+	if byteOffset < c.lastByte {
+		c.lastByte, c.lastRunes = 0, 0
 	}
-	return strings.TrimSpace(strings.Join(docs, ""))
+	c.lastRunes += utf8.RuneCountInString(c.text[c.lastByte:byteOffset])
+	c.lastByte = byteOffset
+	return c.lastRunes
 }
 
 func deepCopyMap(m map[string]interface{}) map[string]interface{} {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/cloudwego/eino/schema"
@@ -121,9 +122,132 @@ func TestRecursiveSplitter(t *testing.T) {
 				t.Errorf("Transform error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			checkProvenance(t, tt.args.input, gotOutput)
 			if !reflect.DeepEqual(gotOutput, tt.wantOutput) {
 				t.Errorf("splitText() gotOutput = %v, want %v", gotOutput, tt.wantOutput)
 			}
 		})
 	}
 }
+
+// checkProvenance verifies the provenance metadata of chunks split from docs
+// and removes it, leaving the metadata inherited from the source documents.
+func checkProvenance(t *testing.T, docs []*schema.Document, chunks []*schema.Document) {
+	t.Helper()
+	sources := make(map[string]*schema.Document, len(docs))
+	for _, doc := range docs {
+		sources[doc.ID] = doc
+	}
+	totals := make(map[string]int)
+	for _, chunk := range chunks {
+		parentID, _ := chunk.MetaData[MetaKeyParentID].(string)
+		totals[parentID]++
+	}
+
+	seen := make(map[string]int)
+	for _, chunk := range chunks {
+		m := chunk.MetaData
+		parentID, _ := m[MetaKeyParentID].(string)
+		source, ok := sources[parentID]
+		if !ok {
+			t.Fatalf("chunk %q: unknown parent %q", chunk.Content, parentID)
+		}
+		if m[MetaKeyChunkIndex] != seen[parentID] || m[MetaKeyChunkTotal] != totals[parentID] {
+			t.Errorf("chunk %q: index/total = %v/%v, want %d/%d", chunk.Content,
+				m[MetaKeyChunkIndex], m[MetaKeyChunkTotal], seen[parentID], totals[parentID])
+		}
+		seen[parentID]++
+
+		byteStart, byteEnd := m[MetaKeyByteStart].(int), m[MetaKeyByteEnd].(int)
+		if got := source.Content[byteStart:byteEnd]; got != chunk.Content {
+			t.Errorf("chunk %q: byte range [%d, %d) is %q", chunk.Content, byteStart, byteEnd, got)
+		}
+		runes := []rune(source.Content)
+		runeStart, runeEnd := m[MetaKeyRuneStart].(int), m[MetaKeyRuneEnd].(int)
+		if got := string(runes[runeStart:runeEnd]); got != chunk.Content {
+			t.Errorf("chunk %q: rune range [%d, %d) is %q", chunk.Content, runeStart, runeEnd, got)
+		}
+
+		for _, key := range []string{MetaKeyParentID, MetaKeyChunkIndex, MetaKeyChunkTotal,
+			MetaKeyByteStart, MetaKeyByteEnd, MetaKeyRuneStart, MetaKeyRuneEnd} {
+			delete(m, key)
+		}
+		if len(m) == 0 && source.MetaData == nil {
+			chunk.MetaData = nil
+		}
+	}
+}
+
+func TestRecursiveSplitter_Provenance(t *testing.T) {
+	ctx := context.Background()
+	docs := []*schema.Document{
+		{ID: "doc1", Content: "  Grüße aus Köln.\n\n日本語のテキスト。  Ein zweiter Satz!\nNoch einer?  ", MetaData: map[string]any{"source": "a.txt"}},
+		{ID: "doc2", Content: "short"},
+		{ID: "doc3", Content: "abab abab abab abab"},
+	}
+	for _, keepType := range []KeepType{KeepTypeNone, KeepTypeStart, KeepTypeEnd} {
+		for _, overlap := range []int{0, 6} {
+			t.Run(fmt.Sprintf("keep%d_overlap%d", keepType, overlap), func(t *testing.T) {
+				s, err := NewSplitter(ctx, &Config{
+					ChunkSize:   12,
+					OverlapSize: overlap,
+					Separators:  []string{"\n\n", "\n", ".", " ", ""},
+					KeepType:    keepType,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				chunks, err := s.Transform(ctx, docs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(chunks) < 4 {
+					t.Fatalf("expected doc1 to be split, got %d chunks", len(chunks))
+				}
+				checkProvenance(t, docs, chunks)
+				for _, chunk := range chunks {
+					if chunk.MetaData != nil && chunk.MetaData["source"] != "a.txt" {
+						t.Errorf("chunk %q lost the source metadata: %v", chunk.Content, chunk.MetaData)
+					}
+				}
+			})
+		}
+	}
+
+	// The source document's metadata is not modified.
+	if len(docs[0].MetaData) != 1 {
+		t.Errorf("source metadata was modified: %v", docs[0].MetaData)
+	}
+}
+
+func TestRecursiveSplitter_Tokenizer(t *testing.T) {
+	ctx := context.Background()
+	if _, err := NewSplitter(ctx, &Config{ChunkSize: 1, LenFunc: func(string) int { return 0 }, Tokenizer: wordTokenizer{}}); err == nil {
+		t.Fatal("expected an error when both LenFunc and Tokenizer are set")
+	}
+
+	s, err := NewSplitter(ctx, &Config{ChunkSize: 4, Separators: []string{" "}, Tokenizer: wordTokenizer{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := s.Transform(ctx, []*schema.Document{{Content: "one two three four five six seven"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, chunk := range chunks {
+		got = append(got, chunk.Content)
+	}
+	// Every word is one token and the separator is another.
+	want := []string{"one two", "three four", "five six", "seven"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// wordTokenizer counts every word and every space as one token.
+type wordTokenizer struct{}
+
+func (wordTokenizer) CountTokens(text string) int {
+	return len(strings.Fields(text)) + strings.Count(text, " ")
+}
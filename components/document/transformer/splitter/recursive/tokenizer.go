@@ -0,0 +1,214 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recursive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokenizer measures text in tokens. When set in [Config], ChunkSize and
+// OverlapSize are token counts.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// Pre-tokenization patterns of the cl100k_base and o200k_base encodings.
+//
+// tiktoken's patterns end with `\s+(?!\S)|\s+`; Go's regexp has no lookahead,
+// so the patterns below use a plain `\s+` and [BPETokenizer] emulates the
+// lookahead by leaving the last whitespace of a run to the following word.
+const (
+	PatternCL100K = `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`
+	PatternO200K  = `[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+`
+)
+
+// BPETokenizer is a byte-level BPE tokenizer compatible with tiktoken
+// vocabularies such as cl100k_base and o200k_base. Special tokens are not
+// recognized; they are counted as ordinary text. It is safe for concurrent
+// use.
+type BPETokenizer struct {
+	ranks   map[string]int
+	pattern *regexp.Regexp
+}
+
+var _ Tokenizer = (*BPETokenizer)(nil)
+
+// LoadBPETokenizer reads a tiktoken vocabulary file, e.g. cl100k_base.tiktoken,
+// and returns a tokenizer using pattern for pre-tokenization. pattern
+// defaults to [PatternCL100K].
+func LoadBPETokenizer(path string, pattern string) (*BPETokenizer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewBPETokenizer(f, pattern)
+}
+
+// NewBPETokenizer reads a tiktoken vocabulary, one base64 encoded token and
+// its rank per line, from r. pattern defaults to [PatternCL100K].
+func NewBPETokenizer(r io.Reader, pattern string) (*BPETokenizer, error) {
+	if pattern == "" {
+		pattern = PatternCL100K
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compile pre-tokenization pattern: %w", err)
+	}
+
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := bytes.Fields(scanner.Bytes())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("vocabulary line %d: want \"<base64 token> <rank>\"", line)
+		}
+		token, err := base64.StdEncoding.DecodeString(string(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("vocabulary line %d: %w", line, err)
+		}
+		rank, err := strconv.Atoi(string(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("vocabulary line %d: %w", line, err)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(ranks) == 0 {
+		return nil, fmt.Errorf("empty vocabulary")
+	}
+	return &BPETokenizer{ranks: ranks, pattern: re}, nil
+}
+
+// CountTokens returns the number of tokens text encodes to.
+func (t *BPETokenizer) CountTokens(text string) int {
+	n := 0
+	t.pieces(text, func(piece string) {
+		if _, ok := t.ranks[piece]; ok {
+			n++
+			return
+		}
+		n += len(t.merge(piece))
+	})
+	return n
+}
+
+// Encode returns the token ranks of text. Bytes missing from an incomplete
+// vocabulary are encoded as -1.
+func (t *BPETokenizer) Encode(text string) []int {
+	var tokens []int
+	t.pieces(text, func(piece string) {
+		if rank, ok := t.ranks[piece]; ok {
+			tokens = append(tokens, rank)
+			return
+		}
+		bounds := t.merge(piece)
+		for i, start := range bounds {
+			end := len(piece)
+			if i+1 < len(bounds) {
+				end = bounds[i+1]
+			}
+			rank, ok := t.ranks[piece[start:end]]
+			if !ok {
+				rank = -1
+			}
+			tokens = append(tokens, rank)
+		}
+	})
+	return tokens
+}
+
+// pieces calls fn with every pre-tokenized piece of text.
+func (t *BPETokenizer) pieces(text string, fn func(piece string)) {
+	for len(text) > 0 {
+		loc := t.pattern.FindStringIndex(text)
+		if loc == nil {
+			fn(text)
+			return
+		}
+		if loc[0] > 0 {
+			fn(text[:loc[0]])
+		}
+		end := loc[1]
+		if end == loc[0] {
+			// Guard against patterns that match the empty string.
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+		} else if end < len(text) && trailingSpaceRun(text[loc[0]:end]) {
+			// Emulate `\s+(?!\S)`: a whitespace run followed by a word leaves
+			// its last character to that word.
+			_, size := utf8.DecodeLastRuneInString(text[loc[0]:end])
+			end -= size
+		}
+		fn(text[loc[0]:end])
+		text = text[end:]
+	}
+}
+
+// trailingSpaceRun reports whether piece is a run of at least two whitespace
+// characters that does not end in a line break.
+func trailingSpaceRun(piece string) bool {
+	last, _ := utf8.DecodeLastRuneInString(piece)
+	if last == '\n' || last == '\r' || utf8.RuneCountInString(piece) < 2 {
+		return false
+	}
+	for _, r := range piece {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// merge runs byte pair merging over piece and returns the start offsets of
+// the resulting tokens.
+func (t *BPETokenizer) merge(piece string) []int {
+	// bounds holds the token boundaries, starting with one token per byte.
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+
+	for len(bounds) > 2 {
+		best, bestRank := -1, math.MaxInt
+		for i := 0; i+2 < len(bounds); i++ {
+			if rank, ok := t.ranks[piece[bounds[i]:bounds[i+2]]]; ok && rank < bestRank {
+				best, bestRank = i, rank
+			}
+		}
+		if best < 0 {
+			break
+		}
+		bounds = append(bounds[:best+1], bounds[best+2:]...)
+	}
+	return bounds[:len(bounds)-1]
+}
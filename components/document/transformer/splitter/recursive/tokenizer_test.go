@@ -0,0 +1,119 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recursive
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testVocab returns a tiktoken style vocabulary with every single byte and a
+// few merges.
+func testVocab() string {
+	var b strings.Builder
+	for i := 0; i < 256; i++ {
+		fmt.Fprintf(&b, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(i)}), i)
+	}
+	for i, token := range []string{"he", "ll", "hell", " w", "or", " wor", "ld", " world"} {
+		fmt.Fprintf(&b, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(token)), 256+i)
+	}
+	return b.String()
+}
+
+func TestBPETokenizer(t *testing.T) {
+	tok, err := NewBPETokenizer(strings.NewReader(testVocab()), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text   string
+		tokens []int
+	}{
+		{text: "hello", tokens: []int{258, 'o'}},
+		{text: " world", tokens: []int{263}},
+		{text: "hello world", tokens: []int{258, 'o', 263}},
+		{text: "", tokens: nil},
+		// The run of spaces leaves its last space to the following word.
+		{text: "hello  world", tokens: []int{258, 'o', ' ', 263}},
+		{text: "it's 2024!\n\n", tokens: []int{'i', 't', '\'', 's', ' ', '2', '0', '2', '4', '!', '\n', '\n'}},
+	}
+	for _, tt := range tests {
+		if got := tok.Encode(tt.text); !reflect.DeepEqual(got, tt.tokens) {
+			t.Errorf("Encode(%q) = %v, want %v", tt.text, got, tt.tokens)
+		}
+		if got := tok.CountTokens(tt.text); got != len(tt.tokens) {
+			t.Errorf("CountTokens(%q) = %d, want %d", tt.text, got, len(tt.tokens))
+		}
+	}
+}
+
+func TestBPETokenizer_Pieces(t *testing.T) {
+	tok, err := NewBPETokenizer(strings.NewReader(testVocab()), PatternCL100K)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pieces []string
+	tok.pieces("Hello  world's 12345\n\n  x  ", func(piece string) { pieces = append(pieces, piece) })
+	want := []string{"Hello", " ", " world", "'s", " ", "123", "45", "\n\n", " ", " x", "  "}
+	if !reflect.DeepEqual(pieces, want) {
+		t.Errorf("pieces = %q, want %q", pieces, want)
+	}
+
+	o200k, err := NewBPETokenizer(strings.NewReader(testVocab()), PatternO200K)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pieces = nil
+	o200k.pieces("HelloWorld's path/to", func(piece string) { pieces = append(pieces, piece) })
+	want = []string{"Hello", "World's", " path", "/to"}
+	if !reflect.DeepEqual(pieces, want) {
+		t.Errorf("o200k pieces = %q, want %q", pieces, want)
+	}
+}
+
+func TestLoadBPETokenizer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.tiktoken")
+	if err := os.WriteFile(path, []byte(testVocab()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tok, err := LoadBPETokenizer(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tok.CountTokens("hello world"); got != 3 {
+		t.Errorf("CountTokens = %d, want 3", got)
+	}
+
+	if _, err := LoadBPETokenizer(filepath.Join(t.TempDir(), "missing"), ""); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	for _, vocab := range []string{"", "aGVsbG8=\n", "!!! 1\n", "aGVsbG8= x\n"} {
+		if _, err := NewBPETokenizer(bytes.NewBufferString(vocab), ""); err == nil {
+			t.Errorf("expected an error for vocabulary %q", vocab)
+		}
+	}
+	if _, err := NewBPETokenizer(strings.NewReader(testVocab()), "("); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semantic
+
+import (
+	"math"
+	"sort"
+)
+
+// BreakpointType decides where chunks are split, based on the cosine distances (1 - similarity) between adjacent chunks.
+type BreakpointType string
+
+const (
+	// BreakpointPercentile splits where the distance is at or above the Percentile of all distances.
+	BreakpointPercentile BreakpointType = "percentile"
+	// BreakpointStandardDeviation splits where the distance is at or above the mean plus BreakpointThreshold standard deviations.
+	BreakpointStandardDeviation BreakpointType = "standard_deviation"
+	// BreakpointInterquartile splits where the distance is at or above the mean plus BreakpointThreshold times the interquartile range.
+	BreakpointInterquartile BreakpointType = "interquartile"
+	// BreakpointGradient splits where the change of distance is at or above the BreakpointThreshold percentile of all changes. It suits
+	// text whose topics drift gradually, e.g. legal or medical documents, where distances are uniformly high.
+	BreakpointGradient BreakpointType = "gradient"
+	// BreakpointAbsolute splits where the similarity is at or below BreakpointThreshold, regardless of the other distances.
+	BreakpointAbsolute BreakpointType = "absolute"
+)
+
+var defaultBreakpointThresholds = map[BreakpointType]float64{
+	BreakpointStandardDeviation: 3,
+	BreakpointInterquartile:     1.5,
+	BreakpointGradient:          0.95,
+}
+
+// breakpoints returns the indexes of the texts that start a new chunk, given the distances between adjacent texts,
+// i.e. distances[i] is the distance between texts i and i+1.
+func (s *splitter) breakpoints(distances []float64) []int {
+	scores := distances
+	var threshold float64
+	switch s.breakpointType {
+	case BreakpointStandardDeviation:
+		mean, std := meanStd(distances)
+		threshold = mean + s.threshold*std
+	case BreakpointInterquartile:
+		mean, _ := meanStd(distances)
+		threshold = mean + s.threshold*(calThreshold(distances, 0.75)-calThreshold(distances, 0.25))
+	case BreakpointGradient:
+		scores = gradient(distances)
+		threshold = calThreshold(scores, s.threshold)
+	case BreakpointAbsolute:
+		threshold = 1 - s.threshold
+	default:
+		threshold = calThreshold(distances, s.threshold)
+	}
+
+	var splitIndexes []int
+	for i, score := range scores {
+		if score >= threshold {
+			splitIndexes = append(splitIndexes, i+1)
+		}
+	}
+	return splitIndexes
+}
+
+func calThreshold(distances []float64, percentile float64) float64 {
+	sorted := make([]float64, len(distances))
+	copy(sorted, distances)
+	sort.Float64s(sorted)
+	idx := int(percentile * float64(len(sorted)))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+func meanStd(values []float64) (mean, std float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		std += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(values)))
+}
+
+// gradient returns the rate of change of values, using central differences inside and one-sided differences at the ends.
+func gradient(values []float64) []float64 {
+	n := len(values)
+	ret := make([]float64, n)
+	if n < 2 {
+		return ret
+	}
+	ret[0] = values[1] - values[0]
+	ret[n-1] = values[n-1] - values[n-2]
+	for i := 1; i < n-1; i++ {
+		ret[i] = (values[i+1] - values[i-1]) / 2
+	}
+	return ret
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"sort"
 	"strings"
 
 	"github.com/cloudwego/eino/components/document"
@@ -48,12 +47,33 @@ type Config struct {
 	// LenFunc is used to calculate string length. Use builtin function len() by default.
 	LenFunc func(s string) int
 	// Percentile specifies the number of splitting. If the difference between two chunks is greater than X percentile, these two chunks will be split.
+	// Only used by BreakpointPercentile.
 	Percentile float64
+	// BreakpointType selects how the difference threshold is derived from the differences between adjacent chunks. BreakpointPercentile by default.
+	BreakpointType BreakpointType
+	// BreakpointThreshold is the parameter of BreakpointType: the number of standard deviations for BreakpointStandardDeviation (3 by default),
+	// the interquartile range multiplier for BreakpointInterquartile (1.5 by default), the percentile of the gradient for BreakpointGradient
+	// (0.95 by default), and the similarity below which chunks are split for BreakpointAbsolute (required).
+	BreakpointThreshold float64
+	// MaxChunkSize specifies the maximum chunk's size. Larger chunks are split again at their weakest boundary until they fit, and single
+	// sentences that are still too large are split at spaces, then characters. No limit by default.
+	MaxChunkSize int
+	// BatchSize specifies the maximum number of texts sent to Embedding in one call. All texts of a document are sent in one call by default.
+	BatchSize int
 	// IDGenerator is an optional function to generate new IDs for split chunks.
 	// If nil, the original document ID will be used for all splits.
 	IDGenerator IDGenerator
 }
 
+// Metadata keys recording the similarity scores of each chunk, for tuning the breakpoint threshold.
+const (
+	// MetaKeySimilarities is the cosine similarity between every pair of adjacent sentences inside the chunk, as []float64.
+	MetaKeySimilarities = "similarities"
+	// MetaKeyBoundarySimilarity is the cosine similarity between the first sentence of the chunk and the last sentence of the previous chunk,
+	// as float64. It is absent on the first chunk, and on chunks that were split from a single sentence.
+	MetaKeyBoundarySimilarity = "boundary_similarity"
+)
+
 func NewSplitter(ctx context.Context, config *Config) (document.Transformer, error) {
 	if config.Embedding == nil {
 		return nil, fmt.Errorf("embedding should not be nil")
@@ -70,29 +90,69 @@ func NewSplitter(ctx context.Context, config *Config) (document.Transformer, err
 	if percentile == 0 {
 		percentile = 0.9
 	}
+	breakpointType := config.BreakpointType
+	if breakpointType == "" {
+		breakpointType = BreakpointPercentile
+	}
+	threshold := config.BreakpointThreshold
+	switch breakpointType {
+	case BreakpointPercentile:
+		threshold = percentile
+	case BreakpointStandardDeviation, BreakpointInterquartile, BreakpointGradient:
+		if threshold == 0 {
+			threshold = defaultBreakpointThresholds[breakpointType]
+		}
+	case BreakpointAbsolute:
+		if threshold == 0 {
+			return nil, fmt.Errorf("breakpoint threshold is required for breakpoint type %s", breakpointType)
+		}
+	default:
+		return nil, fmt.Errorf("unknown breakpoint type: %s", breakpointType)
+	}
+	if config.MaxChunkSize > 0 && config.MaxChunkSize < config.MinChunkSize {
+		return nil, fmt.Errorf("max chunk size %d is smaller than min chunk size %d", config.MaxChunkSize, config.MinChunkSize)
+	}
+	if config.BatchSize < 0 {
+		return nil, fmt.Errorf("batch size should not be negative")
+	}
 	idGenerator := config.IDGenerator
 	if idGenerator == nil {
 		idGenerator = defaultIDGenerator
 	}
 	return &splitter{
-		embedding:    config.Embedding,
-		bufferSize:   config.BufferSize,
-		minChunkSize: config.MinChunkSize,
-		separators:   seps,
-		lenFunc:      lenFunc,
-		percentile:   percentile,
-		idGenerator:  idGenerator,
+		embedding:      config.Embedding,
+		bufferSize:     config.BufferSize,
+		minChunkSize:   config.MinChunkSize,
+		maxChunkSize:   config.MaxChunkSize,
+		batchSize:      config.BatchSize,
+		separators:     seps,
+		lenFunc:        lenFunc,
+		breakpointType: breakpointType,
+		threshold:      threshold,
+		idGenerator:    idGenerator,
 	}, nil
 }
 
 type splitter struct {
-	embedding    embedding.Embedder
-	bufferSize   int
-	minChunkSize int
-	separators   []string
-	lenFunc      func(s string) int
-	percentile   float64
-	idGenerator  IDGenerator
+	embedding      embedding.Embedder
+	bufferSize     int
+	minChunkSize   int
+	maxChunkSize   int
+	batchSize      int
+	separators     []string
+	lenFunc        func(s string) int
+	breakpointType BreakpointType
+	threshold      float64
+	idGenerator    IDGenerator
+}
+
+// chunk is a split of a document with the similarity scores around it.
+type chunk struct {
+	content      string
+	similarities []float64
+	// boundary is the similarity to the previous chunk, valid if hasBoundary is set.
+	boundary    float64
+	hasBoundary bool
 }
 
 func (s *splitter) Transform(ctx context.Context, docs []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
@@ -103,80 +163,171 @@ func (s *splitter) Transform(ctx context.Context, docs []*schema.Document, opts
 			return nil, fmt.Errorf("split document[%s] fail: %w", doc.ID, err)
 		}
 		for i, split := range splits {
+			metaData := deepCopyMap(doc.MetaData)
+			if metaData == nil {
+				metaData = make(map[string]interface{}, 2)
+			}
+			metaData[MetaKeySimilarities] = split.similarities
+			if split.hasBoundary {
+				metaData[MetaKeyBoundarySimilarity] = split.boundary
+			}
 			ret = append(ret, &schema.Document{
 				ID:       s.idGenerator(ctx, doc.ID, i),
-				Content:  split,
-				MetaData: deepCopyMap(doc.MetaData),
+				Content:  split.content,
+				MetaData: metaData,
 			})
 		}
 	}
 	return ret, nil
 }
 
-func (s *splitter) splitText(ctx context.Context, text string, separators []string) ([]string, error) {
+func (s *splitter) splitText(ctx context.Context, text string, separators []string) ([]chunk, error) {
 	texts := []string{text}
 	// split
 	for i := range s.separators {
 		texts = splitTexts(texts, separators[i])
 	}
 
-	sentencesLength := make([]int, len(texts))
-	for i := range texts {
-		sentencesLength[i] = s.lenFunc(texts[i])
+	// distances[i] is the cosine distance between texts[i-1] and texts[i]
+	distances := make([]float64, len(texts))
+	ranges := [][2]int{{0, len(texts)}}
+	if len(texts) > 1 {
+		// combine
+		combinedSentences := make([]string, len(texts))
+		for i := range texts {
+			combinedSentence := texts[i]
+			for j := 1; j <= s.bufferSize && i+j < len(texts); j++ {
+				combinedSentence = combinedSentence + texts[i+j]
+			}
+			for j := 1; j <= s.bufferSize && i-j >= 0; j++ {
+				combinedSentence = texts[i-j] + combinedSentence
+			}
+			combinedSentences[i] = combinedSentence
+		}
+
+		// embedding
+		vectors, err := s.embed(ctx, combinedSentences)
+		if err != nil {
+			return nil, err
+		}
+
+		// cosine distances
+		for i := 1; i < len(texts); i++ {
+			distances[i] = 1 - cosine(vectors[i-1], vectors[i])
+		}
+
+		ranges = ranges[:0]
+		var startIndex int
+		for _, splitIndex := range s.breakpoints(distances[1:]) {
+			if s.lenFunc(strings.Join(texts[startIndex:splitIndex], "")) < s.minChunkSize {
+				continue
+			}
+			ranges = append(ranges, [2]int{startIndex, splitIndex})
+			startIndex = splitIndex
+		}
+		ranges = append(ranges, [2]int{startIndex, len(texts)})
 	}
 
-	if len(texts) == 1 {
-		return texts, nil
+	var ret []chunk
+	for _, r := range ranges {
+		for _, r := range s.limit(texts, distances, r[0], r[1]) {
+			c := chunk{content: strings.Join(texts[r[0]:r[1]], "")}
+			c.similarities = make([]float64, 0, r[1]-r[0]-1)
+			for i := r[0] + 1; i < r[1]; i++ {
+				c.similarities = append(c.similarities, 1-distances[i])
+			}
+			if r[0] > 0 {
+				c.boundary, c.hasBoundary = 1-distances[r[0]], true
+			}
+			if s.maxChunkSize <= 0 || s.lenFunc(c.content) <= s.maxChunkSize {
+				ret = append(ret, c)
+				continue
+			}
+			// a single sentence larger than maxChunkSize
+			for i, piece := range s.splitBySize(c.content, []string{" ", ""}) {
+				p := chunk{content: piece, similarities: []float64{}}
+				if i == 0 {
+					p.boundary, p.hasBoundary = c.boundary, c.hasBoundary
+				}
+				ret = append(ret, p)
+			}
+		}
 	}
+	return ret, nil
+}
 
-	// combine
-	combinedSentences := make([]string, len(texts))
-	for i := range texts {
-		combinedSentence := texts[i]
-		for j := 1; j <= s.bufferSize && i+j < len(texts); j++ {
-			combinedSentence = combinedSentence + texts[i+j]
+// embed embeds texts in batches of up to batchSize.
+func (s *splitter) embed(ctx context.Context, texts []string) ([][]float64, error) {
+	batchSize := s.batchSize
+	if batchSize <= 0 {
+		batchSize = len(texts)
+	}
+	vectors := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); start += batchSize {
+		end := start + batchSize
+		if end > len(texts) {
+			end = len(texts)
 		}
-		for j := 1; j <= s.bufferSize && i-j >= 0; j++ {
-			combinedSentence = texts[i-j] + combinedSentence
+		v, err := s.embedding.EmbedStrings(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(v) != end-start {
+			return nil, fmt.Errorf("embedding returned %d vectors for %d texts", len(v), end-start)
 		}
-		combinedSentences[i] = combinedSentence
+		vectors = append(vectors, v...)
 	}
+	return vectors, nil
+}
 
-	// embedding
-	var vectors [][]float64
-	v, err := s.embedding.EmbedStrings(ctx, combinedSentences)
-	if err != nil {
-		return nil, err
+// limit splits texts[lo:hi] at its largest distance until every part fits maxChunkSize or is a single text.
+// Ties are broken in favor of the most even split.
+func (s *splitter) limit(texts []string, distances []float64, lo, hi int) [][2]int {
+	if s.maxChunkSize <= 0 || hi-lo <= 1 || s.lenFunc(strings.Join(texts[lo:hi], "")) <= s.maxChunkSize {
+		return [][2]int{{lo, hi}}
 	}
-	for i := range v {
-		vectors = append(vectors, v[i])
+	mid := (lo + hi) / 2
+	at := lo + 1
+	for i := lo + 2; i < hi; i++ {
+		if distances[i] > distances[at] || (distances[i] == distances[at] && abs(i-mid) < abs(at-mid)) {
+			at = i
+		}
 	}
+	return append(s.limit(texts, distances, lo, at), s.limit(texts, distances, at, hi)...)
+}
 
-	// cosine distances
-	distances := make([]float64, len(texts))
-	for i := 1; i < len(texts); i++ {
-		distances[i] = 1 - cosine(vectors[i-1], vectors[i])
+// splitBySize splits text into pieces of up to maxChunkSize, at the first separator that makes every piece fit.
+// The empty separator splits between characters.
+func (s *splitter) splitBySize(text string, separators []string) []string {
+	if s.lenFunc(text) <= s.maxChunkSize || len(separators) == 0 {
+		return []string{text}
 	}
-
-	threshold := calThreshold(distances[1:], s.percentile)
-	var splitIndexes []int
-	for i := 1; i < len(distances); i++ {
-		if distances[i] >= threshold {
-			splitIndexes = append(splitIndexes, i)
+	var parts []string
+	if separators[0] == "" {
+		for _, r := range text {
+			parts = append(parts, string(r))
 		}
+	} else {
+		parts = strings.SplitAfter(text, separators[0])
 	}
+
 	var ret []string
-	var startIndex int
-	for i := range splitIndexes {
-		chunk := strings.Join(texts[startIndex:splitIndexes[i]], "")
-		if s.lenFunc(chunk) < s.minChunkSize {
+	var cur string
+	for _, part := range parts {
+		if cur != "" && s.lenFunc(cur+part) > s.maxChunkSize {
+			ret = append(ret, cur)
+			cur = ""
+		}
+		if s.lenFunc(part) > s.maxChunkSize {
+			ret = append(ret, s.splitBySize(part, separators[1:])...)
 			continue
 		}
-		ret = append(ret, chunk)
-		startIndex = splitIndexes[i]
+		cur += part
 	}
-	ret = append(ret, strings.Join(texts[startIndex:], ""))
-	return ret, nil
+	if cur != "" {
+		ret = append(ret, cur)
+	}
+	return ret
 }
 
 func (s *splitter) GetType() string {
@@ -190,6 +341,13 @@ func cosine(vec1, vec2 []float64) float64 {
 	return dotProduct / (normVec1 * normVec2)
 }
 
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
 func dot(x, y []float64) float64 {
 	var sum float64
 	for i, v := range x {
@@ -206,17 +364,6 @@ func splitTexts(texts []string, sep string) []string {
 	return ret
 }
 
-func calThreshold(distances []float64, percentile float64) float64 {
-	sorted := make([]float64, len(distances))
-	copy(sorted, distances)
-	sort.Float64s(sorted)
-	idx := int(percentile * float64(len(sorted)))
-	if idx >= len(sorted) {
-		idx = len(sorted) - 1
-	}
-	return sorted[idx]
-}
-
 func deepCopyMap(m map[string]interface{}) map[string]interface{} {
 	if m == nil {
 		return nil
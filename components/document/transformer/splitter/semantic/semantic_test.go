@@ -138,3 +138,115 @@ func TestSemanticSplitter(t *testing.T) {
 		})
 	}
 }
+
+// topicEmbedding embeds texts by their first letter, so that texts starting with the same letter are identical.
+type topicEmbedding struct {
+	batches []int
+}
+
+func (e *topicEmbedding) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	e.batches = append(e.batches, len(texts))
+	var ret [][]float64
+	for _, text := range texts {
+		vec := make([]float64, 26)
+		vec[text[0]-'a'] = 1
+		ret = append(ret, vec)
+	}
+	return ret, nil
+}
+
+func TestBreakpoints(t *testing.T) {
+	tests := []struct {
+		breakpointType BreakpointType
+		threshold      float64
+		distances      []float64
+		want           []int
+	}{
+		{BreakpointPercentile, 0.8, []float64{0.1, 0.2, 0.3, 0.4, 0.5}, []int{5}},
+		{BreakpointStandardDeviation, 1, []float64{0.1, 0.1, 0.1, 0.1, 0.9}, []int{5}},
+		{BreakpointStandardDeviation, 3, []float64{0.1, 0.1, 0.1, 0.1, 0.9}, nil},
+		{BreakpointInterquartile, 1.5, []float64{0.1, 0.1, 0.1, 0.1, 0.9}, []int{5}},
+		{BreakpointGradient, 0.95, []float64{0.1, 0.1, 0.5, 0.1, 0.1}, []int{2}},
+		{BreakpointAbsolute, 0.5, []float64{0.1, 0.1, 0.5, 0.1, 0.9}, []int{3, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.breakpointType), func(t *testing.T) {
+			s := &splitter{breakpointType: tt.breakpointType, threshold: tt.threshold}
+			if got := s.breakpoints(tt.distances); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("breakpoints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSemanticSplitter_Options(t *testing.T) {
+	ctx := context.Background()
+	split := func(t *testing.T, config *Config, content string) []*schema.Document {
+		t.Helper()
+		config.Separators = []string{"."}
+		s, err := NewSplitter(ctx, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.Transform(ctx, []*schema.Document{{ID: "doc", Content: content}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	t.Run("similarities in metadata", func(t *testing.T) {
+		got := split(t, &Config{
+			Embedding:           &topicEmbedding{},
+			BreakpointType:      BreakpointAbsolute,
+			BreakpointThreshold: 0.5,
+		}, "a1.a2.b1.b2")
+		want := []*schema.Document{
+			{ID: "doc", Content: "a1.a2.", MetaData: map[string]any{MetaKeySimilarities: []float64{1}}},
+			{ID: "doc", Content: "b1.b2", MetaData: map[string]any{MetaKeySimilarities: []float64{1}, MetaKeyBoundarySimilarity: float64(0)}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Transform() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("max chunk size", func(t *testing.T) {
+		var contents []string
+		for _, doc := range split(t, &Config{
+			Embedding:           &topicEmbedding{},
+			BreakpointType:      BreakpointAbsolute,
+			BreakpointThreshold: 0.5,
+			MaxChunkSize:        10,
+		}, "aaaa.aaaa.aaaa.aaaa.bbbb bbbb bbbb bbbb.cccccccccccc") {
+			contents = append(contents, doc.Content)
+		}
+		want := []string{"aaaa.aaaa.", "aaaa.aaaa.", "bbbb bbbb ", "bbbb bbbb.", "cccccccccc", "cc"}
+		if !reflect.DeepEqual(contents, want) {
+			t.Errorf("Transform() = %q, want %q", contents, want)
+		}
+	})
+
+	t.Run("batch size", func(t *testing.T) {
+		e := &topicEmbedding{}
+		got := split(t, &Config{Embedding: e, BatchSize: 2}, "a.b.c.d.e")
+		if !reflect.DeepEqual(e.batches, []int{2, 2, 1}) {
+			t.Errorf("batches = %v, want [2 2 1]", e.batches)
+		}
+		if len(got) != 5 {
+			t.Errorf("Transform() got %d chunks, want 5", len(got))
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		for _, config := range []*Config{
+			{Embedding: &topicEmbedding{}, BreakpointType: "unknown"},
+			{Embedding: &topicEmbedding{}, BreakpointType: BreakpointAbsolute},
+			{Embedding: &topicEmbedding{}, MinChunkSize: 10, MaxChunkSize: 5},
+			{Embedding: &topicEmbedding{}, BatchSize: -1},
+		} {
+			if _, err := NewSplitter(ctx, config); err == nil {
+				t.Errorf("NewSplitter(%+v) expected an error", config)
+			}
+		}
+	})
+}
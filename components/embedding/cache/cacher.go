@@ -18,6 +18,7 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -31,3 +32,50 @@ type Cacher interface {
 	// If the value is not of type []float64, it returns an error.
 	Get(ctx context.Context, key string) ([]float64, bool, error)
 }
+
+// BatchCacher is an optional extension of [Cacher] for backends that can read
+// and write many keys in one round trip. [Embedder] uses it when the
+// configured Cacher implements it.
+type BatchCacher interface {
+	Cacher
+
+	// MGet retrieves the values of keys. values and found have the same
+	// length as keys; found[i] reports whether values[i] was in the cache.
+	MGet(ctx context.Context, keys []string) (values [][]float64, found []bool, err error)
+
+	// MSet stores values[i] under keys[i] for every i.
+	MSet(ctx context.Context, keys []string, values [][]float64, expire time.Duration) error
+}
+
+// getMany looks keys up in c, in one call when c is a [BatchCacher].
+func getMany(ctx context.Context, c Cacher, keys []string) ([][]float64, []bool, error) {
+	if bc, ok := c.(BatchCacher); ok {
+		return bc.MGet(ctx, keys)
+	}
+	values := make([][]float64, len(keys))
+	found := make([]bool, len(keys))
+	for i, key := range keys {
+		value, ok, err := c.Get(ctx, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		values[i], found[i] = value, ok
+	}
+	return values, found, nil
+}
+
+// setMany stores values under keys in c, in one call when c is a
+// [BatchCacher]. Without batching, every key is attempted and the errors are
+// joined.
+func setMany(ctx context.Context, c Cacher, keys []string, values [][]float64, expire time.Duration) error {
+	if bc, ok := c.(BatchCacher); ok {
+		return bc.MSet(ctx, keys, values, expire)
+	}
+	var errs []error
+	for i, key := range keys {
+		if err := c.Set(ctx, key, values[i], expire); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -19,6 +19,7 @@ package cache
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/cloudwego/eino/components/embedding"
@@ -34,6 +35,7 @@ type Embedder struct {
 	cacher     Cacher
 	generator  Generator
 	expiration time.Duration
+	flights    *flightGroup
 }
 
 type Option interface {
@@ -67,6 +69,20 @@ func WithExpiration(expiration time.Duration) Option {
 	})
 }
 
+// WithSingleflight returns an [Option] that controls whether concurrent
+// EmbedStrings calls share in-flight embeddings. When enabled, a text whose
+// cache key is already being embedded by another call is waited for rather
+// than sent to the underlying embedder again. Enabled by default.
+func WithSingleflight(enabled bool) Option {
+	return optionFunc(func(e *Embedder) {
+		if enabled {
+			e.flights = &flightGroup{}
+		} else {
+			e.flights = nil
+		}
+	})
+}
+
 var _ embedding.Embedder = (*Embedder)(nil)
 
 // NewEmbedder creates a new [Embedder] instance with cache support.
@@ -74,6 +90,7 @@ func NewEmbedder(embedder embedding.Embedder, opts ...Option) (*Embedder, error)
 	e := &Embedder{
 		embedder:   embedder,
 		expiration: time.Hour * 2,
+		flights:    &flightGroup{},
 	}
 	for _, opt := range opts {
 		opt.apply(e)
@@ -90,13 +107,14 @@ func NewEmbedder(embedder embedding.Embedder, opts ...Option) (*Embedder, error)
 	return e, nil
 }
 
+// EmbedStrings returns the embeddings of texts, looking them up in the cache
+// first and embedding only the misses. Texts that map to the same cache key
+// are looked up and embedded once. When the [Cacher] implements
+// [BatchCacher], lookups and stores are issued as one batch each. Unless
+// disabled with [WithSingleflight], texts already being embedded by a
+// concurrent call are waited for instead of being embedded again.
 func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
-	var (
-		embeddingsByKey = make(map[int][]float64)
-		embeddingOpts   = embedding.GetCommonOptions(nil, opts...)
-		uncached        []int
-		uncachedTexts   []string
-	)
+	embeddingOpts := embedding.GetCommonOptions(nil, opts...)
 
 	// generate options for the generator
 	var generatorOpt GeneratorOption
@@ -104,47 +122,162 @@ func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...emb
 		generatorOpt.Model = *embeddingOpts.Model
 	}
 
-	// Get cached embeddings and find uncached texts
+	// Collapse the texts to unique keys, remembering which key each index maps to
+	var (
+		keys     []string
+		keyTexts []string
+		keyIndex = make(map[string]int, len(texts))
+		keyOf    = make([]int, len(texts))
+	)
 	for idx, text := range texts {
 		key := e.generator.Generate(ctx, text, generatorOpt)
-		emb, ok, err := e.cacher.Get(ctx, key)
-		if err != nil {
-			return nil, err
-		} else if ok {
-			embeddingsByKey[idx] = emb
-		} else {
-			// If the key is not found, we consider it as uncached
-			uncached = append(uncached, idx)
-			uncachedTexts = append(uncachedTexts, text)
+		k, ok := keyIndex[key]
+		if !ok {
+			k = len(keys)
+			keyIndex[key] = k
+			keys = append(keys, key)
+			keyTexts = append(keyTexts, text)
 		}
+		keyOf[idx] = k
 	}
 
-	// Embed the uncached texts
-	if len(uncachedTexts) > 0 {
-		uncachedEmbeddings, err := e.embedder.EmbedStrings(ctx, uncachedTexts, opts...)
+	embeddings := make([][]float64, len(keys))
+	if len(keys) > 0 {
+		// Get cached embeddings and find uncached keys
+		cached, found, err := getMany(ctx, e.cacher, keys)
 		if err != nil {
 			return nil, err
 		}
 
-		// Cache the uncachedEmbeddings
-		for i, idx := range uncached {
-			key := e.generator.Generate(ctx, texts[idx], generatorOpt)
-			if err := e.cacher.Set(ctx, key, uncachedEmbeddings[i], e.expiration); err != nil {
-				_ = err // skip caching if there's an error
+		var (
+			uncachedKeys  []string
+			uncachedIdx   []int
+			uncachedTexts []string
+		)
+		for k := range keys {
+			if found[k] {
+				embeddings[k] = cached[k]
+				continue
+			}
+			uncachedKeys = append(uncachedKeys, keys[k])
+			uncachedIdx = append(uncachedIdx, k)
+			uncachedTexts = append(uncachedTexts, keyTexts[k])
+		}
+
+		// Embed the uncached texts
+		if len(uncachedTexts) > 0 {
+			uncachedEmbeddings, err := e.embedUncached(ctx, uncachedKeys, uncachedTexts, opts)
+			if err != nil {
+				return nil, err
+			}
+			for i, k := range uncachedIdx {
+				embeddings[k] = uncachedEmbeddings[i]
 			}
-			embeddingsByKey[idx] = uncachedEmbeddings[i]
 		}
 	}
 
-	// Convert the map to a slice
 	result := make([][]float64, len(texts))
-	for i := range texts {
-		if emb, ok := embeddingsByKey[i]; ok {
-			result[i] = emb
+	for idx, k := range keyOf {
+		result[idx] = embeddings[k]
+	}
+	return result, nil
+}
+
+// embedUncached embeds texts whose keys missed the cache and stores the
+// results. With singleflight enabled, keys already being embedded by a
+// concurrent call are waited for instead of being embedded again.
+func (e *Embedder) embedUncached(ctx context.Context, keys, texts []string, opts []embedding.Option) ([][]float64, error) {
+	if e.flights == nil {
+		return e.embedAndStore(ctx, keys, texts, opts)
+	}
+
+	calls, lead := e.flights.claim(keys)
+	var (
+		leadKeys  []string
+		leadTexts []string
+		leadCalls []*flightCall
+	)
+	for i := range keys {
+		if lead[i] {
+			leadKeys = append(leadKeys, keys[i])
+			leadTexts = append(leadTexts, texts[i])
+			leadCalls = append(leadCalls, calls[i])
+		}
+	}
+	if len(leadKeys) > 0 {
+		if err := e.lead(ctx, leadKeys, leadTexts, leadCalls, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([][]float64, len(keys))
+	var retryIdx []int
+	for i, c := range calls {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if c.err != nil {
+			// The leader failed, possibly because its own context was
+			// canceled; embed the text in this call instead.
+			retryIdx = append(retryIdx, i)
+			continue
+		}
+		if lead[i] {
+			result[i] = c.value
 		} else {
-			result[i] = nil // it seems that such a case should not happen
+			// The value is shared with the leader; give each caller its own copy.
+			result[i] = append([]float64(nil), c.value...)
 		}
 	}
 
+	if len(retryIdx) > 0 {
+		retryKeys := make([]string, len(retryIdx))
+		retryTexts := make([]string, len(retryIdx))
+		for j, i := range retryIdx {
+			retryKeys[j], retryTexts[j] = keys[i], texts[i]
+		}
+		retried, err := e.embedAndStore(ctx, retryKeys, retryTexts, opts)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range retryIdx {
+			result[i] = retried[j]
+		}
+	}
 	return result, nil
 }
+
+// lead embeds the texts of the calls claimed by this goroutine and releases
+// their waiters, even if the embedder panics.
+func (e *Embedder) lead(ctx context.Context, keys, texts []string, calls []*flightCall, opts []embedding.Option) error {
+	finished := false
+	defer func() {
+		if !finished {
+			e.flights.finish(keys, calls, nil, errFlightAbandoned)
+		}
+	}()
+
+	values, err := e.embedAndStore(ctx, keys, texts, opts)
+	finished = true
+	e.flights.finish(keys, calls, values, err)
+	return err
+}
+
+// embedAndStore embeds texts with the underlying embedder and caches the
+// results under keys. Cache errors are ignored.
+func (e *Embedder) embedAndStore(ctx context.Context, keys, texts []string, opts []embedding.Option) ([][]float64, error) {
+	embeddings, err := e.embedder.EmbedStrings(ctx, texts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("embedding/cache: embedder returned %d embeddings for %d texts",
+			len(embeddings), len(texts))
+	}
+
+	// Cache the embeddings, skipping caching if there's an error
+	_ = setMany(ctx, e.cacher, keys, embeddings, e.expiration)
+	return embeddings, nil
+}
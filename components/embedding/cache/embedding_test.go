@@ -198,4 +198,84 @@ func TestEmbedder_EmbedStrings(t *testing.T) {
 		mc.AssertExpectations(t)
 		me.AssertExpectations(t)
 	})
+
+	t.Run("duplicate texts are embedded once", func(t *testing.T) {
+		mc := new(mockCacher)
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(mc), WithGenerator(NewSimpleGenerator()), WithExpiration(expiration))
+		require.NoError(t, err)
+
+		key0 := e.generator.Generate(ctx, texts[0], generatorOpt)
+		key1 := e.generator.Generate(ctx, texts[1], generatorOpt)
+
+		mc.On("Get", mock.Anything, key0).Return(nil, false, nil).Once()
+		mc.On("Get", mock.Anything, key1).Return(embeddings[1], true, nil).Once()
+		me.On("EmbedStrings", mock.Anything, []string{texts[0]}, mock.Anything).Return([][]float64{embeddings[0]}, nil).Once()
+		mc.On("Set", mock.Anything, key0, embeddings[0], expiration).Return(nil).Once()
+
+		result, err := e.EmbedStrings(ctx, []string{texts[0], texts[1], texts[0], texts[1], texts[0]})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{embeddings[0], embeddings[1], embeddings[0], embeddings[1], embeddings[0]}, result)
+		mc.AssertExpectations(t)
+		me.AssertExpectations(t)
+	})
+
+	t.Run("batch cacher", func(t *testing.T) {
+		mc := &mockBatchCacher{MemoryCacher: NewMemoryCacher(nil)}
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(mc), WithGenerator(NewSimpleGenerator()), WithExpiration(expiration))
+		require.NoError(t, err)
+
+		key1 := e.generator.Generate(ctx, texts[1], generatorOpt)
+		require.NoError(t, mc.MemoryCacher.Set(ctx, key1, embeddings[1], 0))
+		me.On("EmbedStrings", mock.Anything, []string{texts[0]}, mock.Anything).Return([][]float64{embeddings[0]}, nil).Once()
+
+		result, err := e.EmbedStrings(ctx, []string{texts[0], texts[1], texts[0]})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{embeddings[0], embeddings[1], embeddings[0]}, result)
+		assert.Equal(t, 1, mc.mgets)
+		assert.Equal(t, 1, mc.msets)
+
+		result, err = e.EmbedStrings(ctx, texts)
+		assert.NoError(t, err)
+		assert.Equal(t, embeddings, result)
+		assert.Equal(t, 2, mc.mgets)
+		assert.Equal(t, 1, mc.msets, "nothing to store when every text is cached")
+		me.AssertExpectations(t)
+	})
+
+	t.Run("embedder returns wrong count", func(t *testing.T) {
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(NewMemoryCacher(nil)), WithGenerator(NewSimpleGenerator()))
+		require.NoError(t, err)
+		me.On("EmbedStrings", mock.Anything, texts, mock.Anything).Return([][]float64{embeddings[0]}, nil)
+
+		_, err = e.EmbedStrings(ctx, texts)
+		assert.Error(t, err)
+	})
+}
+
+// mockBatchCacher counts the batch calls made to a MemoryCacher. Get and Set
+// panic so the test fails if the per-key path is used.
+type mockBatchCacher struct {
+	*MemoryCacher
+	mgets, msets int
+}
+
+func (m *mockBatchCacher) Get(context.Context, string) ([]float64, bool, error) {
+	panic("unexpected Get")
+}
+
+func (m *mockBatchCacher) Set(context.Context, string, []float64, time.Duration) error {
+	panic("unexpected Set")
+}
+
+func (m *mockBatchCacher) MGet(ctx context.Context, keys []string) ([][]float64, []bool, error) {
+	m.mgets++
+	return m.MemoryCacher.MGet(ctx, keys)
+}
+
+func (m *mockBatchCacher) MSet(ctx context.Context, keys []string, values [][]float64, expire time.Duration) error {
+	m.msets++
+	return m.MemoryCacher.MSet(ctx, keys, values, expire)
 }
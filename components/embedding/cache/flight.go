@@ -0,0 +1,83 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"errors"
+	"sync"
+)
+
+var errFlightAbandoned = errors.New("embedding/cache: in-flight embedding was abandoned")
+
+// flightGroup tracks the cache keys currently being embedded, so concurrent
+// EmbedStrings calls wait for the call that is already embedding a key
+// instead of sending the same text upstream again. Unlike
+// golang.org/x/sync/singleflight it works on a batch of keys at once, which
+// lets a leader embed all of its keys in a single upstream request.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done  chan struct{}
+	value []float64
+	err   error
+}
+
+// claim registers the calling goroutine as the leader of every key that is
+// not in flight yet. For each key it returns the call to lead (lead[i] is
+// true) or the call to wait for.
+func (g *flightGroup) claim(keys []string) (calls []*flightCall, lead []bool) {
+	calls = make([]*flightCall, len(keys))
+	lead = make([]bool, len(keys))
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	for i, key := range keys {
+		if c, ok := g.calls[key]; ok {
+			calls[i] = c
+			continue
+		}
+		c := &flightCall{done: make(chan struct{})}
+		g.calls[key] = c
+		calls[i], lead[i] = c, true
+	}
+	return calls, lead
+}
+
+// finish publishes the result of the calls led for keys and releases their
+// waiters. values is ignored when err is non-nil.
+func (g *flightGroup) finish(keys []string, calls []*flightCall, values [][]float64, err error) {
+	g.mu.Lock()
+	for _, key := range keys {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+
+	for i, c := range calls {
+		if err != nil {
+			c.err = err
+		} else {
+			c.value = values[i]
+		}
+		close(c.done)
+	}
+}
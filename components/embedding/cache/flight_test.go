@@ -0,0 +1,196 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightGroup(t *testing.T) {
+	var g flightGroup
+
+	calls, lead := g.claim([]string{"a", "b"})
+	assert.Equal(t, []bool{true, true}, lead)
+
+	waiting, lead := g.claim([]string{"b", "c"})
+	assert.Equal(t, []bool{false, true}, lead)
+	assert.Same(t, calls[1], waiting[0])
+
+	g.finish([]string{"a", "b"}, calls, [][]float64{{1}, {2}}, nil)
+	<-waiting[0].done
+	assert.Equal(t, []float64{2}, waiting[0].value)
+
+	// Finished keys can be claimed again.
+	_, lead = g.claim([]string{"a"})
+	assert.Equal(t, []bool{true}, lead)
+
+	g.finish([]string{"c"}, waiting[1:], nil, errors.New("boom"))
+	<-waiting[1].done
+	assert.EqualError(t, waiting[1].err, "boom")
+}
+
+func TestEmbedder_Singleflight(t *testing.T) {
+	ctx := context.Background()
+	generatorOpt := GeneratorOption{}
+
+	// inflight marks key as being embedded by another call and returns the
+	// call so the test can complete it.
+	inflight := func(e *Embedder, key string) *flightCall {
+		calls, lead := e.flights.claim([]string{key})
+		require.True(t, lead[0])
+		return calls[0]
+	}
+	// complete publishes the result of a call returned by inflight without
+	// removing its key from the group, so a caller waits for it whether it
+	// claims the key before or after, and the test only has to check the
+	// embedder calls.
+	complete := func(call *flightCall, value []float64, err error) {
+		call.value, call.err = value, err
+		close(call.done)
+	}
+
+	t.Run("waits for the in-flight call", func(t *testing.T) {
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(NewMemoryCacher(nil)), WithGenerator(NewSimpleGenerator()))
+		require.NoError(t, err)
+		key := e.generator.Generate(ctx, "hot", generatorOpt)
+		call := inflight(e, key)
+
+		done := make(chan [][]float64)
+		go func() {
+			result, err := e.EmbedStrings(ctx, []string{"hot"})
+			assert.NoError(t, err)
+			done <- result
+		}()
+		complete(call, []float64{1, 2}, nil)
+
+		assert.Equal(t, [][]float64{{1, 2}}, <-done)
+		me.AssertNotCalled(t, "EmbedStrings", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("embeds itself when the leader fails", func(t *testing.T) {
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(NewMemoryCacher(nil)), WithGenerator(NewSimpleGenerator()))
+		require.NoError(t, err)
+		key := e.generator.Generate(ctx, "hot", generatorOpt)
+		call := inflight(e, key)
+		me.On("EmbedStrings", mock.Anything, []string{"hot"}, mock.Anything).Return([][]float64{{3}}, nil).Once()
+
+		done := make(chan [][]float64)
+		go func() {
+			result, err := e.EmbedStrings(ctx, []string{"hot"})
+			assert.NoError(t, err)
+			done <- result
+		}()
+		complete(call, nil, context.Canceled)
+
+		assert.Equal(t, [][]float64{{3}}, <-done)
+		me.AssertExpectations(t)
+	})
+
+	t.Run("context canceled while waiting", func(t *testing.T) {
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(NewMemoryCacher(nil)), WithGenerator(NewSimpleGenerator()))
+		require.NoError(t, err)
+		inflight(e, e.generator.Generate(ctx, "hot", generatorOpt))
+
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = e.EmbedStrings(cctx, []string{"hot"})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(NewMemoryCacher(nil)), WithGenerator(NewSimpleGenerator()), WithSingleflight(false))
+		require.NoError(t, err)
+		assert.Nil(t, e.flights)
+		me.On("EmbedStrings", mock.Anything, []string{"hot"}, mock.Anything).Return([][]float64{{1}}, nil).Once()
+
+		result, err := e.EmbedStrings(ctx, []string{"hot"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]float64{{1}}, result)
+		me.AssertExpectations(t)
+	})
+
+	t.Run("concurrent callers", func(t *testing.T) {
+		upstream := &countingEmbedder{release: make(chan struct{})}
+		e, err := NewEmbedder(upstream, WithCacher(NewMemoryCacher(nil)), WithGenerator(NewSimpleGenerator()))
+		require.NoError(t, err)
+
+		const callers = 16
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := e.EmbedStrings(ctx, []string{"hot", "query"})
+				assert.NoError(t, err)
+				assert.Equal(t, [][]float64{{3}, {5}}, result)
+			}()
+		}
+		close(upstream.release)
+		wg.Wait()
+		// Callers that arrive after the first embedding was cached hit the
+		// cache, and those that arrive while it is in flight wait for it;
+		// only callers racing past both can embed again.
+		assert.LessOrEqual(t, upstream.calls.Load(), int32(callers))
+		assert.GreaterOrEqual(t, upstream.calls.Load(), int32(1))
+	})
+
+	t.Run("leader panic releases waiters", func(t *testing.T) {
+		e, err := NewEmbedder(panicEmbedder{}, WithCacher(NewMemoryCacher(nil)), WithGenerator(NewSimpleGenerator()))
+		require.NoError(t, err)
+		key := e.generator.Generate(ctx, "hot", generatorOpt)
+
+		assert.Panics(t, func() { _, _ = e.EmbedStrings(ctx, []string{"hot"}) })
+		calls, lead := e.flights.claim([]string{key})
+		assert.True(t, lead[0], "the panicking call must not stay in flight")
+		e.flights.finish([]string{key}, calls, nil, errFlightAbandoned)
+	})
+}
+
+// countingEmbedder embeds a text as its length once release is closed.
+type countingEmbedder struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (c *countingEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	c.calls.Add(1)
+	<-c.release
+	result := make([][]float64, len(texts))
+	for i, text := range texts {
+		result[i] = []float64{float64(len(text))}
+	}
+	return result, nil
+}
+
+type panicEmbedder struct{}
+
+func (panicEmbedder) EmbedStrings(context.Context, []string, ...embedding.Option) ([][]float64, error) {
+	panic("boom")
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultMemoryMaxEntries = 10000
+
+// MemoryCacherConfig configures a [MemoryCacher].
+type MemoryCacherConfig struct {
+	// MaxEntries bounds the number of cached embeddings. When the cache is
+	// full, the least recently used entry is evicted.
+	// Optional. Default: 10000.
+	MaxEntries int
+
+	// TTL caps how long an entry is kept, regardless of the expiration
+	// passed to Set. Zero means entries only expire as requested by Set.
+	// Optional.
+	TTL time.Duration
+}
+
+// MemoryCacher is an in-process [Cacher] with LRU eviction and per-entry
+// expiration. It is safe for concurrent use and implements [BatchCacher].
+type MemoryCacher struct {
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key      string
+	value    []float64
+	expireAt time.Time // zero means no expiration
+}
+
+var _ BatchCacher = (*MemoryCacher)(nil)
+
+// NewMemoryCacher creates a [MemoryCacher]. config may be nil.
+func NewMemoryCacher(config *MemoryCacherConfig) *MemoryCacher {
+	if config == nil {
+		config = &MemoryCacherConfig{}
+	}
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultMemoryMaxEntries
+	}
+	return &MemoryCacher{
+		maxEntries: maxEntries,
+		ttl:        config.TTL,
+		now:        time.Now,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Set stores value under key. A non-positive expire means no expiration,
+// subject to [MemoryCacherConfig.TTL].
+func (c *MemoryCacher) Set(ctx context.Context, key string, value []float64, expire time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value, c.expireAt(expire))
+	return nil
+}
+
+// Get returns the value stored under key if it is present and not expired.
+func (c *MemoryCacher) Get(ctx context.Context, key string) ([]float64, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.get(key, c.now())
+	return value, ok, nil
+}
+
+// MGet implements [BatchCacher].
+func (c *MemoryCacher) MGet(ctx context.Context, keys []string) ([][]float64, []bool, error) {
+	values := make([][]float64, len(keys))
+	found := make([]bool, len(keys))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for i, key := range keys {
+		values[i], found[i] = c.get(key, now)
+	}
+	return values, found, nil
+}
+
+// MSet implements [BatchCacher].
+func (c *MemoryCacher) MSet(ctx context.Context, keys []string, values [][]float64, expire time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expireAt := c.expireAt(expire)
+	for i, key := range keys {
+		c.set(key, values[i], expireAt)
+	}
+	return nil
+}
+
+// Len returns the number of entries currently held, including expired
+// entries that have not been evicted yet.
+func (c *MemoryCacher) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *MemoryCacher) expireAt(expire time.Duration) time.Time {
+	if c.ttl > 0 && (expire <= 0 || expire > c.ttl) {
+		expire = c.ttl
+	}
+	if expire <= 0 {
+		return time.Time{}
+	}
+	return c.now().Add(expire)
+}
+
+func (c *MemoryCacher) get(key string, now time.Time) ([]float64, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expireAt.IsZero() && !now.Before(entry.expireAt) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.ll.MoveToFront(elem)
+	// Callers own the returned slice; copy so they cannot mutate the cache.
+	return append([]float64(nil), entry.value...), true
+}
+
+func (c *MemoryCacher) set(key string, value []float64, expireAt time.Time) {
+	value = append([]float64(nil), value...)
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value, entry.expireAt = value, expireAt
+		c.ll.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&memoryEntry{key: key, value: value, expireAt: expireAt})
+	for c.ll.Len() > c.maxEntries {
+		c.remove(c.ll.Back())
+	}
+}
+
+func (c *MemoryCacher) remove(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.items, elem.Value.(*memoryEntry).key)
+}
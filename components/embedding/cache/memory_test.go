@@ -0,0 +1,118 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCacher(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("get and set", func(t *testing.T) {
+		c := NewMemoryCacher(nil)
+		require.NoError(t, c.Set(ctx, "a", []float64{1, 2}, 0))
+
+		value, ok, err := c.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []float64{1, 2}, value)
+
+		// The returned slice is a copy.
+		value[0] = 42
+		value, _, _ = c.Get(ctx, "a")
+		assert.Equal(t, []float64{1, 2}, value)
+
+		_, ok, err = c.Get(ctx, "b")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("lru eviction", func(t *testing.T) {
+		c := NewMemoryCacher(&MemoryCacherConfig{MaxEntries: 2})
+		require.NoError(t, c.Set(ctx, "a", []float64{1}, 0))
+		require.NoError(t, c.Set(ctx, "b", []float64{2}, 0))
+		_, ok, _ := c.Get(ctx, "a") // a is now the most recently used
+		require.True(t, ok)
+		require.NoError(t, c.Set(ctx, "c", []float64{3}, 0))
+
+		assert.Equal(t, 2, c.Len())
+		_, ok, _ = c.Get(ctx, "b")
+		assert.False(t, ok, "b should have been evicted")
+		_, ok, _ = c.Get(ctx, "a")
+		assert.True(t, ok)
+		_, ok, _ = c.Get(ctx, "c")
+		assert.True(t, ok)
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		now := time.Unix(1000, 0)
+		c := NewMemoryCacher(&MemoryCacherConfig{TTL: time.Minute})
+		c.now = func() time.Time { return now }
+
+		require.NoError(t, c.Set(ctx, "short", []float64{1}, time.Second))
+		require.NoError(t, c.Set(ctx, "capped", []float64{2}, time.Hour))
+		require.NoError(t, c.Set(ctx, "forever", []float64{3}, 0))
+
+		now = now.Add(2 * time.Second)
+		_, ok, _ := c.Get(ctx, "short")
+		assert.False(t, ok)
+		_, ok, _ = c.Get(ctx, "capped")
+		assert.True(t, ok)
+
+		now = now.Add(time.Minute)
+		_, ok, _ = c.Get(ctx, "capped")
+		assert.False(t, ok, "TTL caps the requested expiration")
+		_, ok, _ = c.Get(ctx, "forever")
+		assert.False(t, ok, "TTL applies to entries without expiration")
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		c := NewMemoryCacher(nil)
+		require.NoError(t, c.MSet(ctx, []string{"a", "b"}, [][]float64{{1}, {2}}, 0))
+
+		values, found, err := c.MGet(ctx, []string{"b", "x", "a"})
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false, true}, found)
+		assert.Equal(t, [][]float64{{2}, nil, {1}}, values)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		c := NewMemoryCacher(&MemoryCacherConfig{MaxEntries: 8})
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					key := string(rune('a' + (i+j)%16))
+					_ = c.Set(ctx, key, []float64{float64(j)}, 0)
+					_, _, _ = c.Get(ctx, key)
+				}
+			}(i)
+		}
+		wg.Wait()
+		assert.LessOrEqual(t, c.Len(), 8)
+	})
+}
@@ -19,6 +19,7 @@ package redis
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -48,7 +49,23 @@ func WithPrefix(prefix string) Option {
 	})
 }
 
-var _ cache.Cacher = (*Cacher)(nil)
+// WithEncoding returns an [Option] that sets the [Encoding] used to write
+// vectors. Entries are decoded according to the format recorded in them,
+// regardless of this setting.
+func WithEncoding(encoding Encoding) Option {
+	return optionFunc(func(c *Cacher) {
+		switch encoding {
+		case EncodingFloat32:
+			c.codec = &float32Codec{}
+		case EncodingInt8:
+			c.codec = &int8Codec{}
+		default:
+			c.codec = defaultCodec
+		}
+	})
+}
+
+var _ cache.BatchCacher = (*Cacher)(nil)
 
 func NewCacher(rdb redis.UniversalClient, opts ...Option) *Cacher {
 	cacher := &Cacher{
@@ -85,3 +102,63 @@ func (c *Cacher) Get(ctx context.Context, key string) ([]float64, bool, error) {
 	}
 	return value, true, nil
 }
+
+// MGet implements [cache.BatchCacher] with a single MGET command.
+func (c *Cacher) MGet(ctx context.Context, keys []string) ([][]float64, []bool, error) {
+	values := make([][]float64, len(keys))
+	found := make([]bool, len(keys))
+	if len(keys) == 0 {
+		return values, found, nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	results, err := c.rdb.MGet(ctx, prefixed...).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(results) != len(keys) {
+		return nil, nil, fmt.Errorf("redis: MGET returned %d values for %d keys", len(results), len(keys))
+	}
+
+	for i, result := range results {
+		if result == nil {
+			continue
+		}
+		data, ok := result.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("redis: unexpected MGET value type %T for key %q", result, keys[i])
+		}
+		if err := c.codec.Unmarshal([]byte(data), &values[i]); err != nil {
+			return nil, nil, err
+		}
+		found[i] = true
+	}
+	return values, found, nil
+}
+
+// MSet implements [cache.BatchCacher] by pipelining one SET per key, so every
+// key keeps its own expiration in a single round trip.
+func (c *Cacher) MSet(ctx context.Context, keys []string, values [][]float64, expire time.Duration) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	payloads := make([][]byte, len(keys))
+	for i := range keys {
+		data, err := c.codec.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+		payloads[i] = data
+	}
+	_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			pipe.Set(ctx, c.prefix+key, payloads[i], expire)
+		}
+		return nil
+	})
+	return err
+}
@@ -69,6 +69,42 @@ func (m *mockRedisClient) Get(ctx context.Context, key string) *redis.StringCmd
 	return cmd
 }
 
+func (m *mockRedisClient) MGet(ctx context.Context, keys ...string) *redis.SliceCmd {
+	args := m.Called(ctx, keys)
+	cmd := redis.NewSliceCmd(ctx)
+	if v := args.Get(0); v != nil {
+		cmd.SetVal(v.([]any))
+	}
+	cmd.SetErr(args.Error(1))
+	return cmd
+}
+
+func (m *mockRedisClient) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	pipe := &mockPipeliner{}
+	if err := fn(pipe); err != nil {
+		return nil, err
+	}
+	args := m.Called(ctx, pipe.sets)
+	return nil, args.Error(0)
+}
+
+// mockPipeliner records the SET commands queued on it.
+type mockPipeliner struct {
+	redis.Pipeliner
+	sets []pipelinedSet
+}
+
+type pipelinedSet struct {
+	key    string
+	value  any
+	expire time.Duration
+}
+
+func (p *mockPipeliner) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	p.sets = append(p.sets, pipelinedSet{key: key, value: value, expire: expiration})
+	return redis.NewStatusCmd(ctx)
+}
+
 func TestCacher(t *testing.T) {
 	ctx := context.Background()
 	key := "test_key"
@@ -159,6 +195,93 @@ func TestCacher(t *testing.T) {
 	})
 }
 
+func TestCacher_Batch(t *testing.T) {
+	ctx := context.Background()
+	expire := time.Minute
+	v1, err := defaultCodec.Marshal([]float64{1.1})
+	require.NoError(t, err)
+	v3, err := defaultCodec.Marshal([]float64{3.3})
+	require.NoError(t, err)
+
+	t.Run("MGet", func(t *testing.T) {
+		mockRdb := new(mockRedisClient)
+		c := NewCacher(mockRdb)
+		mockRdb.On("MGet", mock.Anything, []string{"eino:k1", "eino:k2", "eino:k3"}).
+			Return([]any{string(v1), nil, string(v3)}, nil)
+
+		values, found, err := c.MGet(ctx, []string{"k1", "k2", "k3"})
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false, true}, found)
+		assert.Equal(t, [][]float64{{1.1}, nil, {3.3}}, values)
+		mockRdb.AssertExpectations(t)
+	})
+
+	t.Run("MGet error", func(t *testing.T) {
+		mockRdb := new(mockRedisClient)
+		c := NewCacher(mockRdb)
+		mockRdb.On("MGet", mock.Anything, mock.Anything).Return(nil, errors.New("mget error"))
+
+		_, _, err := c.MGet(ctx, []string{"k1"})
+		assert.EqualError(t, err, "mget error")
+	})
+
+	t.Run("MGet no keys", func(t *testing.T) {
+		mockRdb := new(mockRedisClient)
+		values, found, err := NewCacher(mockRdb).MGet(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, values)
+		assert.Empty(t, found)
+		mockRdb.AssertNotCalled(t, "MGet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("MSet pipelines every key", func(t *testing.T) {
+		mockRdb := new(mockRedisClient)
+		c := NewCacher(mockRdb, WithPrefix("emb"))
+		mockRdb.On("Pipelined", mock.Anything, []pipelinedSet{
+			{key: "emb:k1", value: v1, expire: expire},
+			{key: "emb:k3", value: v3, expire: expire},
+		}).Return(nil)
+
+		err := c.MSet(ctx, []string{"k1", "k3"}, [][]float64{{1.1}, {3.3}}, expire)
+		require.NoError(t, err)
+		mockRdb.AssertExpectations(t)
+	})
+
+	t.Run("MSet error", func(t *testing.T) {
+		mockRdb := new(mockRedisClient)
+		c := NewCacher(mockRdb)
+		mockRdb.On("Pipelined", mock.Anything, mock.Anything).Return(errors.New("pipeline error"))
+
+		err := c.MSet(ctx, []string{"k1"}, [][]float64{{1.1}}, expire)
+		assert.EqualError(t, err, "pipeline error")
+	})
+}
+
+func TestCacher_EncodingCompatibility(t *testing.T) {
+	ctx := context.Background()
+	value := []float64{0.5, -1}
+
+	// An entry written as JSON by an older version is still readable after
+	// switching to a binary encoding.
+	legacy, err := defaultCodec.Marshal(value)
+	require.NoError(t, err)
+	mockRdb := new(mockRedisClient)
+	c := NewCacher(mockRdb, WithEncoding(EncodingInt8))
+	mockRdb.On("Get", mock.Anything, "eino:k").Return(string(legacy), nil)
+
+	got, ok, err := c.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, value, got)
+
+	// New entries are written in the configured encoding.
+	mockRdb.On("Set", mock.Anything, "eino:k", mock.MatchedBy(func(data []byte) bool {
+		return len(data) > 1 && data[0] == binaryMagic && data[1] == formatInt8
+	}), time.Minute).Return("OK", nil)
+	require.NoError(t, c.Set(ctx, "k", value, time.Minute))
+	mockRdb.AssertExpectations(t)
+}
+
 func TestWithPrefix(t *testing.T) {
 	assert.Equal(t, "eino:", NewCacher(nil).prefix)
 	assert.Equal(t, "custom:", NewCacher(nil, WithPrefix("custom:")).prefix)
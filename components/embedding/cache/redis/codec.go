@@ -16,7 +16,33 @@
 
 package redis
 
-import "github.com/bytedance/sonic"
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/bytedance/sonic"
+)
+
+// Encoding selects how a [Cacher] stores vectors in Redis. Every encoding
+// can read entries written with any other, so the encoding of an existing
+// cache can be changed without flushing it.
+type Encoding int
+
+const (
+	// EncodingJSON stores vectors as JSON arrays of float64. It is the
+	// default and the only format written by earlier versions.
+	EncodingJSON Encoding = iota
+	// EncodingFloat32 stores vectors as little-endian float32 values,
+	// 4 bytes per dimension. Precision drops to that of float32, which is
+	// what most embedding models produce anyway.
+	EncodingFloat32
+	// EncodingInt8 quantizes each vector to int8 with a per-vector scale,
+	// 1 byte per dimension plus a 4-byte scale. It is lossy: every value is
+	// rounded to one of 255 levels between -max|v| and +max|v|, which is
+	// usually acceptable for similarity search but not for exact reuse.
+	EncodingInt8
+)
 
 var defaultCodec codec = &sonicCodec{}
 
@@ -25,6 +51,16 @@ type codec interface {
 	Unmarshal(data []byte, v any) error
 }
 
+// Binary entries start with binaryMagic, which can never begin a JSON
+// document, followed by a byte naming the format.
+const (
+	binaryMagic   byte = 0x00
+	formatFloat32 byte = 0x01
+	formatInt8    byte = 0x02
+
+	binaryHeaderLen = 2
+)
+
 type sonicCodec struct{}
 
 func (*sonicCodec) Marshal(v any) ([]byte, error) {
@@ -32,5 +68,107 @@ func (*sonicCodec) Marshal(v any) ([]byte, error) {
 }
 
 func (*sonicCodec) Unmarshal(data []byte, v any) error {
-	return sonic.Unmarshal(data, v)
+	return unmarshalVector(data, v)
+}
+
+type float32Codec struct{}
+
+func (*float32Codec) Marshal(v any) ([]byte, error) {
+	vec, err := vectorOf(v)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, binaryHeaderLen+4*len(vec))
+	data[0], data[1] = binaryMagic, formatFloat32
+	for i, f := range vec {
+		binary.LittleEndian.PutUint32(data[binaryHeaderLen+4*i:], math.Float32bits(float32(f)))
+	}
+	return data, nil
+}
+
+func (*float32Codec) Unmarshal(data []byte, v any) error {
+	return unmarshalVector(data, v)
+}
+
+type int8Codec struct{}
+
+func (*int8Codec) Marshal(v any) ([]byte, error) {
+	vec, err := vectorOf(v)
+	if err != nil {
+		return nil, err
+	}
+	var maxAbs float64
+	for _, f := range vec {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("redis: cannot quantize non-finite value %v", f)
+		}
+		maxAbs = math.Max(maxAbs, math.Abs(f))
+	}
+	scale := float32(maxAbs / math.MaxInt8)
+
+	data := make([]byte, binaryHeaderLen+4+len(vec))
+	data[0], data[1] = binaryMagic, formatInt8
+	binary.LittleEndian.PutUint32(data[binaryHeaderLen:], math.Float32bits(scale))
+	for i, f := range vec {
+		var q float64
+		if scale > 0 {
+			q = math.Max(-math.MaxInt8, math.Min(math.MaxInt8, math.Round(f/float64(scale))))
+		}
+		data[binaryHeaderLen+4+i] = byte(int8(q))
+	}
+	return data, nil
+}
+
+func (*int8Codec) Unmarshal(data []byte, v any) error {
+	return unmarshalVector(data, v)
+}
+
+func vectorOf(v any) ([]float64, error) {
+	vec, ok := v.([]float64)
+	if !ok {
+		return nil, fmt.Errorf("redis: cannot encode %T as a vector", v)
+	}
+	return vec, nil
+}
+
+// unmarshalVector decodes data written by any of the codecs. Binary entries
+// are recognized by their header; anything else is treated as JSON.
+func unmarshalVector(data []byte, v any) error {
+	if len(data) == 0 || data[0] != binaryMagic {
+		return sonic.Unmarshal(data, v)
+	}
+	out, ok := v.(*[]float64)
+	if !ok {
+		return fmt.Errorf("redis: cannot decode a vector into %T", v)
+	}
+	if len(data) < binaryHeaderLen {
+		return fmt.Errorf("redis: truncated vector header")
+	}
+
+	payload := data[binaryHeaderLen:]
+	switch data[1] {
+	case formatFloat32:
+		if len(payload)%4 != 0 {
+			return fmt.Errorf("redis: float32 vector payload of %d bytes is not a multiple of 4", len(payload))
+		}
+		vec := make([]float64, len(payload)/4)
+		for i := range vec {
+			vec[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(payload[4*i:])))
+		}
+		*out = vec
+	case formatInt8:
+		if len(payload) < 4 {
+			return fmt.Errorf("redis: truncated int8 vector scale")
+		}
+		scale := float64(math.Float32frombits(binary.LittleEndian.Uint32(payload)))
+		quantized := payload[4:]
+		vec := make([]float64, len(quantized))
+		for i, q := range quantized {
+			vec[i] = float64(int8(q)) * scale
+		}
+		*out = vec
+	default:
+		return fmt.Errorf("redis: unknown vector format %#x", data[1])
+	}
+	return nil
 }
@@ -17,6 +17,7 @@
 package redis
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,3 +43,72 @@ func TestCodec_Sonic(t *testing.T) {
 func TestCodec_Default(t *testing.T) {
 	assert.Equal(t, &sonicCodec{}, defaultCodec)
 }
+
+func TestCodec_Binary(t *testing.T) {
+	v := []float64{0.5, -0.25, 1.5, 0, -2}
+
+	t.Run("float32", func(t *testing.T) {
+		data, err := (&float32Codec{}).Marshal(v)
+		require.NoError(t, err)
+		assert.Len(t, data, binaryHeaderLen+4*len(v))
+
+		var out []float64
+		require.NoError(t, (&float32Codec{}).Unmarshal(data, &out))
+		assert.Equal(t, v, out, "values exactly representable as float32 round-trip")
+	})
+
+	t.Run("int8", func(t *testing.T) {
+		data, err := (&int8Codec{}).Marshal(v)
+		require.NoError(t, err)
+		assert.Len(t, data, binaryHeaderLen+4+len(v))
+
+		var out []float64
+		require.NoError(t, (&int8Codec{}).Unmarshal(data, &out))
+		require.Len(t, out, len(v))
+		for i := range v {
+			assert.InDelta(t, v[i], out[i], 2.0/127, "index %d", i)
+		}
+		assert.InDelta(t, -2, out[4], 1e-6, "the largest magnitude is exact")
+
+		zeros, err := (&int8Codec{}).Marshal([]float64{0, 0})
+		require.NoError(t, err)
+		require.NoError(t, (&int8Codec{}).Unmarshal(zeros, &out))
+		assert.Equal(t, []float64{0, 0}, out)
+
+		_, err = (&int8Codec{}).Marshal([]float64{math.NaN()})
+		assert.Error(t, err)
+	})
+
+	t.Run("every codec decodes every format", func(t *testing.T) {
+		codecs := []codec{&sonicCodec{}, &float32Codec{}, &int8Codec{}}
+		for _, enc := range codecs {
+			data, err := enc.Marshal(v)
+			require.NoError(t, err)
+			for _, dec := range codecs {
+				var out []float64
+				require.NoError(t, dec.Unmarshal(data, &out), "%T -> %T", enc, dec)
+				assert.Len(t, out, len(v))
+			}
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		var out []float64
+		assert.Error(t, unmarshalVector([]byte{binaryMagic}, &out))
+		assert.Error(t, unmarshalVector([]byte{binaryMagic, formatFloat32, 1, 2, 3}, &out))
+		assert.Error(t, unmarshalVector([]byte{binaryMagic, formatInt8, 1}, &out))
+		assert.Error(t, unmarshalVector([]byte{binaryMagic, 0x7f}, &out))
+
+		var s string
+		assert.Error(t, unmarshalVector([]byte{binaryMagic, formatFloat32}, &s))
+		_, err := (&float32Codec{}).Marshal("not a vector")
+		assert.Error(t, err)
+	})
+}
+
+func TestWithEncoding(t *testing.T) {
+	assert.Equal(t, defaultCodec, NewCacher(nil).codec)
+	assert.Equal(t, defaultCodec, NewCacher(nil, WithEncoding(EncodingJSON)).codec)
+	assert.Equal(t, &float32Codec{}, NewCacher(nil, WithEncoding(EncodingFloat32)).codec)
+	assert.Equal(t, &int8Codec{}, NewCacher(nil, WithEncoding(EncodingInt8)).codec)
+}
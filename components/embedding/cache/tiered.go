@@ -0,0 +1,164 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TieredCacherConfig configures a [TieredCacher].
+type TieredCacherConfig struct {
+	// Tiers are consulted in order, fastest first, e.g. a [MemoryCacher]
+	// in front of a Redis cacher.
+	// Required.
+	Tiers []Cacher
+
+	// BackfillExpiration is the expiration used when a value found in a
+	// lower tier is copied into the tiers above it. The remaining lifetime
+	// of the lower-tier entry is not known, so keep this short enough for
+	// the upper tiers to pick up changes.
+	// Optional. Default: no expiration beyond the tier's own limits.
+	BackfillExpiration time.Duration
+}
+
+// TieredCacher chains several [Cacher]s. Lookups go through the tiers in
+// order and values found in a lower tier are backfilled into the tiers above
+// it; writes go to every tier. It implements [BatchCacher] and batches the
+// calls to every tier that supports it.
+type TieredCacher struct {
+	tiers          []Cacher
+	backfillExpire time.Duration
+}
+
+var _ BatchCacher = (*TieredCacher)(nil)
+
+// NewTieredCacher creates a [TieredCacher].
+func NewTieredCacher(config *TieredCacherConfig) (*TieredCacher, error) {
+	if config == nil || len(config.Tiers) == 0 {
+		return nil, errors.New("embedding/cache: at least one tier is required")
+	}
+	for _, tier := range config.Tiers {
+		if tier == nil {
+			return nil, errors.New("embedding/cache: tier must not be nil")
+		}
+	}
+	return &TieredCacher{
+		tiers:          append([]Cacher(nil), config.Tiers...),
+		backfillExpire: config.BackfillExpiration,
+	}, nil
+}
+
+// Set stores value in every tier. Errors from individual tiers are joined.
+func (c *TieredCacher) Set(ctx context.Context, key string, value []float64, expire time.Duration) error {
+	var errs []error
+	for _, tier := range c.tiers {
+		if err := tier.Set(ctx, key, value, expire); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Get returns the value from the first tier that has it, backfilling the
+// tiers above. A tier that fails counts as a miss, so the tiers below it can
+// still serve the key; the errors are joined and returned only when no tier
+// has the value. Backfill errors are ignored.
+func (c *TieredCacher) Get(ctx context.Context, key string) ([]float64, bool, error) {
+	var errs []error
+	for i, tier := range c.tiers {
+		value, ok, err := tier.Get(ctx, key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		for _, upper := range c.tiers[:i] {
+			_ = upper.Set(ctx, key, value, c.backfillExpire)
+		}
+		return value, true, nil
+	}
+	return nil, false, errors.Join(errs...)
+}
+
+// MGet implements [BatchCacher]. Each tier is only asked for the keys the
+// tiers above it missed, and a tier that fails misses all of them. The errors
+// are joined and returned, along with the values found, only when some keys
+// are found in no tier.
+func (c *TieredCacher) MGet(ctx context.Context, keys []string) ([][]float64, []bool, error) {
+	values := make([][]float64, len(keys))
+	found := make([]bool, len(keys))
+
+	pending := make([]int, len(keys))
+	for i := range keys {
+		pending[i] = i
+	}
+	var errs []error
+	for t, tier := range c.tiers {
+		if len(pending) == 0 {
+			break
+		}
+		tierKeys := make([]string, len(pending))
+		for i, idx := range pending {
+			tierKeys[i] = keys[idx]
+		}
+		tierValues, tierFound, err := getMany(ctx, tier, tierKeys)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var (
+			missed    []int
+			hitKeys   []string
+			hitValues [][]float64
+		)
+		for i, idx := range pending {
+			if !tierFound[i] {
+				missed = append(missed, idx)
+				continue
+			}
+			values[idx], found[idx] = tierValues[i], true
+			hitKeys = append(hitKeys, keys[idx])
+			hitValues = append(hitValues, tierValues[i])
+		}
+		if len(hitKeys) > 0 {
+			for _, upper := range c.tiers[:t] {
+				_ = setMany(ctx, upper, hitKeys, hitValues, c.backfillExpire)
+			}
+		}
+		pending = missed
+	}
+	if len(pending) == 0 {
+		return values, found, nil
+	}
+	return values, found, errors.Join(errs...)
+}
+
+// MSet implements [BatchCacher]. Errors from individual tiers are joined.
+func (c *TieredCacher) MSet(ctx context.Context, keys []string, values [][]float64, expire time.Duration) error {
+	var errs []error
+	for _, tier := range c.tiers {
+		if err := setMany(ctx, tier, keys, values, expire); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
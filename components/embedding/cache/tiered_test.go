@@ -0,0 +1,143 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTieredCacher(t *testing.T) {
+	_, err := NewTieredCacher(nil)
+	assert.Error(t, err)
+	_, err = NewTieredCacher(&TieredCacherConfig{Tiers: []Cacher{NewMemoryCacher(nil), nil}})
+	assert.Error(t, err)
+}
+
+func TestTieredCacher(t *testing.T) {
+	ctx := context.Background()
+	backfill := time.Minute
+
+	t.Run("get backfills upper tiers", func(t *testing.T) {
+		front := NewMemoryCacher(nil)
+		back := new(mockCacher)
+		c, err := NewTieredCacher(&TieredCacherConfig{Tiers: []Cacher{front, back}, BackfillExpiration: backfill})
+		require.NoError(t, err)
+
+		back.On("Get", mock.Anything, "a").Return([]float64{1}, true, nil).Once()
+
+		value, ok, err := c.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []float64{1}, value)
+
+		// The second lookup is served by the front tier.
+		value, ok, err = c.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []float64{1}, value)
+		back.AssertExpectations(t)
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		back := new(mockCacher)
+		c, err := NewTieredCacher(&TieredCacherConfig{Tiers: []Cacher{NewMemoryCacher(nil), back}})
+		require.NoError(t, err)
+		back.On("Get", mock.Anything, "a").Return(nil, false, errors.New("get error"))
+
+		_, _, err = c.Get(ctx, "a")
+		assert.EqualError(t, err, "get error")
+	})
+
+	t.Run("a failing tier counts as a miss", func(t *testing.T) {
+		front, back := new(mockCacher), NewMemoryCacher(nil)
+		c, err := NewTieredCacher(&TieredCacherConfig{Tiers: []Cacher{front, back}})
+		require.NoError(t, err)
+		require.NoError(t, back.Set(ctx, "a", []float64{1}, 0))
+		front.On("Get", mock.Anything, mock.Anything).Return(nil, false, errors.New("get error"))
+		front.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		value, ok, err := c.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []float64{1}, value)
+
+		values, found, err := c.MGet(ctx, []string{"a"})
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true}, found)
+		assert.Equal(t, [][]float64{{1}}, values)
+
+		// Keys found nowhere report the read errors.
+		values, found, err = c.MGet(ctx, []string{"a", "b"})
+		assert.EqualError(t, err, "get error")
+		assert.Equal(t, []bool{true, false}, found)
+		assert.Equal(t, [][]float64{{1}, nil}, values)
+	})
+
+	t.Run("set writes every tier", func(t *testing.T) {
+		front := NewMemoryCacher(nil)
+		back := new(mockCacher)
+		c, err := NewTieredCacher(&TieredCacherConfig{Tiers: []Cacher{front, back}})
+		require.NoError(t, err)
+		back.On("Set", mock.Anything, "a", []float64{1}, time.Hour).Return(errors.New("set error"))
+
+		err = c.Set(ctx, "a", []float64{1}, time.Hour)
+		assert.EqualError(t, err, "set error")
+		_, ok, _ := front.Get(ctx, "a")
+		assert.True(t, ok, "a failing tier must not prevent the others from being written")
+		back.AssertExpectations(t)
+	})
+
+	t.Run("mget asks lower tiers only for misses", func(t *testing.T) {
+		front := NewMemoryCacher(nil)
+		back := new(mockCacher)
+		c, err := NewTieredCacher(&TieredCacherConfig{Tiers: []Cacher{front, back}, BackfillExpiration: backfill})
+		require.NoError(t, err)
+		require.NoError(t, front.Set(ctx, "a", []float64{1}, 0))
+
+		back.On("Get", mock.Anything, "b").Return([]float64{2}, true, nil).Once()
+		back.On("Get", mock.Anything, "c").Return(nil, false, nil).Once()
+
+		values, found, err := c.MGet(ctx, []string{"a", "b", "c"})
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, true, false}, found)
+		assert.Equal(t, [][]float64{{1}, {2}, nil}, values)
+		back.AssertExpectations(t)
+
+		_, ok, _ := front.Get(ctx, "b")
+		assert.True(t, ok, "b should have been backfilled")
+	})
+
+	t.Run("mset", func(t *testing.T) {
+		front, back := NewMemoryCacher(nil), NewMemoryCacher(nil)
+		c, err := NewTieredCacher(&TieredCacherConfig{Tiers: []Cacher{front, back}})
+		require.NoError(t, err)
+
+		require.NoError(t, c.MSet(ctx, []string{"a", "b"}, [][]float64{{1}, {2}}, 0))
+		for _, tier := range []*MemoryCacher{front, back} {
+			_, found, err := tier.MGet(ctx, []string{"a", "b"})
+			require.NoError(t, err)
+			assert.Equal(t, []bool{true, true}, found)
+		}
+	})
+}
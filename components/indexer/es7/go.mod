@@ -2,9 +2,12 @@ module github.com/cloudwego/eino-ext/components/indexer/es7
 
 go 1.18.0
 
+replace github.com/cloudwego/eino-ext/components/indexer/lifecycle => ../lifecycle
+
 require (
 	github.com/bytedance/mockey v1.2.13
 	github.com/cloudwego/eino v0.9.2
+	github.com/cloudwego/eino-ext/components/indexer/lifecycle v0.1.0
 	github.com/elastic/go-elasticsearch/v7 v7.17.10
 	github.com/smartystreets/goconvey v1.8.1
 )
@@ -2,9 +2,12 @@ module github.com/cloudwego/eino-ext/components/indexer/es9
 
 go 1.23.0
 
+replace github.com/cloudwego/eino-ext/components/indexer/lifecycle => ../lifecycle
+
 require (
 	github.com/bytedance/mockey v1.2.13
 	github.com/cloudwego/eino v0.9.2
+	github.com/cloudwego/eino-ext/components/indexer/lifecycle v0.1.0
 	github.com/elastic/go-elasticsearch/v9 v9.0.0
 	github.com/smartystreets/goconvey v1.8.1
 )
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino-ext/components/indexer/lifecycle"
+	"github.com/cloudwego/eino-ext/components/indexer/lifecycle/esdoc"
+	"github.com/cloudwego/eino/schema"
+)
+
+var _ lifecycle.DocumentManager = (*Indexer)(nil)
+
+// DeleteByIDs deletes the documents with the given IDs from the index.
+func (i *Indexer) DeleteByIDs(ctx context.Context, ids []string) error {
+	return i.documents().DeleteByIDs(ctx, ids)
+}
+
+// DeleteByMetaData deletes the documents whose fields equal every value of the filter.
+// Keys of filter are field names as produced by DocumentToFields. Values are matched by term queries,
+// so fields should be mapped as keyword, numeric or boolean types.
+func (i *Indexer) DeleteByMetaData(ctx context.Context, filter map[string]any) error {
+	return i.documents().DeleteByMetaData(ctx, filter)
+}
+
+// GetByIDs returns the documents with the given IDs, converted from their source fields by FieldsToDocument.
+func (i *Indexer) GetByIDs(ctx context.Context, ids []string) ([]*schema.Document, error) {
+	return i.documents().GetByIDs(ctx, ids)
+}
+
+// UpdateMetaData sets the given fields of the document with a partial update, other fields and vectors are kept.
+// Keys of metaData are field names as produced by DocumentToFields.
+func (i *Indexer) UpdateMetaData(ctx context.Context, id string, metaData map[string]any) error {
+	return i.documents().UpdateMetaData(ctx, id, metaData)
+}
+
+func (i *Indexer) documents() *esdoc.Manager {
+	return &esdoc.Manager{
+		Transport:        i.client,
+		Index:            i.config.Index,
+		FieldsToDocument: i.config.FieldsToDocument,
+	}
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+// Package esdoc implements [lifecycle.DocumentManager] over the document APIs
+// which Elasticsearch and OpenSearch share, so that the indexers of every
+// client version manage their documents the same way.
+package esdoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/cloudwego/eino-ext/components/indexer/lifecycle"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Transport sends the requests to the cluster. It is implemented by the
+// clients of go-elasticsearch and opensearch-go.
+type Transport interface {
+	Perform(req *http.Request) (*http.Response, error)
+}
+
+// Manager manages the documents of one index.
+type Manager struct {
+	// Transport sends the requests, e.g. an *elasticsearch.Client.
+	Transport Transport
+	// Index is the name of the index the documents are stored in.
+	Index string
+	// FieldsToDocument converts the source fields of a stored document to an Eino document.
+	// Default puts all the source fields into MetaData.
+	FieldsToDocument func(ctx context.Context, id string, fields map[string]any) (*schema.Document, error)
+}
+
+var _ lifecycle.DocumentManager = (*Manager)(nil)
+
+// DeleteByIDs deletes the documents with the given IDs from the index.
+func (m *Manager) DeleteByIDs(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return m.deleteByQuery(ctx, map[string]any{
+		"ids": map[string]any{"values": ids},
+	})
+}
+
+// DeleteByMetaData deletes the documents whose fields equal every value of the filter.
+// Values are matched by term queries, so fields should be mapped as keyword, numeric or boolean types.
+func (m *Manager) DeleteByMetaData(ctx context.Context, filter map[string]any) error {
+	if len(filter) == 0 {
+		return fmt.Errorf("[DeleteByMetaData] filter is empty")
+	}
+
+	return m.deleteByQuery(ctx, termsQuery(filter))
+}
+
+// GetByIDs returns the documents with the given IDs, converted from their source fields by FieldsToDocument.
+func (m *Manager) GetByIDs(ctx context.Context, ids []string) ([]*schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	res, err := m.post(ctx, "_mget", nil, map[string]any{"ids": ids})
+	if err != nil {
+		return nil, fmt.Errorf("[GetByIDs] mget failed, %w", err)
+	}
+	defer res.Body.Close()
+
+	if isError(res) {
+		return nil, fmt.Errorf("[GetByIDs] mget failed, response: %s", responseString(res))
+	}
+
+	var resp mgetResponse
+	if err = json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("[GetByIDs] decode response failed, %w", err)
+	}
+
+	fieldsToDocument := m.FieldsToDocument
+	if fieldsToDocument == nil {
+		fieldsToDocument = defaultFieldsToDocument
+	}
+
+	docs := make([]*schema.Document, 0, len(resp.Docs))
+	for _, hit := range resp.Docs {
+		if !hit.Found {
+			continue
+		}
+
+		doc, err := fieldsToDocument(ctx, hit.ID, hit.Source)
+		if err != nil {
+			return nil, fmt.Errorf("[GetByIDs] FieldsToDocument failed, id=%s, %w", hit.ID, err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// UpdateMetaData sets the given fields of the document with a partial update, other fields and vectors are kept.
+func (m *Manager) UpdateMetaData(ctx context.Context, id string, metaData map[string]any) error {
+	if metaData == nil {
+		metaData = map[string]any{}
+	}
+
+	res, err := m.post(ctx, "_update/"+url.PathEscape(id), url.Values{"refresh": {"true"}},
+		map[string]any{"doc": metaData})
+	if err != nil {
+		return fmt.Errorf("[UpdateMetaData] update failed, %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("[UpdateMetaData] document not found, id=%s", id)
+	}
+	if isError(res) {
+		return fmt.Errorf("[UpdateMetaData] update failed, response: %s", responseString(res))
+	}
+
+	return nil
+}
+
+func (m *Manager) deleteByQuery(ctx context.Context, query map[string]any) error {
+	res, err := m.post(ctx, "_delete_by_query", url.Values{"conflicts": {"proceed"}, "refresh": {"true"}},
+		map[string]any{"query": query})
+	if err != nil {
+		return fmt.Errorf("[deleteByQuery] delete by query failed, %w", err)
+	}
+	defer res.Body.Close()
+
+	if isError(res) {
+		return fmt.Errorf("[deleteByQuery] delete by query failed, response: %s", responseString(res))
+	}
+
+	return nil
+}
+
+// post sends body as JSON to the endpoint of the index.
+func (m *Manager) post(ctx context.Context, endpoint string, params url.Values, body any) (*http.Response, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request failed, %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/"+url.PathEscape(m.Index)+"/"+endpoint, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Content-Type", "application/json")
+
+	return m.Transport.Perform(req)
+}
+
+type mgetResponse struct {
+	Docs []struct {
+		ID     string         `json:"_id"`
+		Found  bool           `json:"found"`
+		Source map[string]any `json:"_source"`
+	} `json:"docs"`
+}
+
+func isError(res *http.Response) bool {
+	return res.StatusCode > 299
+}
+
+// responseString formats the response like the String method of the responses of the client APIs.
+func responseString(res *http.Response) string {
+	body, _ := io.ReadAll(res.Body)
+	return fmt.Sprintf("[%d %s] %s", res.StatusCode, http.StatusText(res.StatusCode), body)
+}
+
+// termsQuery builds a bool query which filters by a term query for each key - value pair.
+func termsQuery(filter map[string]any) map[string]any {
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	terms := make([]any, 0, len(keys))
+	for _, k := range keys {
+		terms = append(terms, map[string]any{
+			"term": map[string]any{k: filter[k]},
+		})
+	}
+
+	return map[string]any{
+		"bool": map[string]any{"filter": terms},
+	}
+}
+
+func defaultFieldsToDocument(ctx context.Context, id string, fields map[string]any) (*schema.Document, error) {
+	return &schema.Document{
+		ID:       id,
+		MetaData: fields,
+	}, nil
+}
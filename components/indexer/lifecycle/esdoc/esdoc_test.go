@@ -0,0 +1,136 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+package esdoc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+type fakeTransport struct {
+	reqs []*http.Request
+	body []string
+	resp []*http.Response
+}
+
+func (f *fakeTransport) Perform(req *http.Request) (*http.Response, error) {
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.reqs = append(f.reqs, req)
+	f.body = append(f.body, string(b))
+	if len(f.resp) == 0 {
+		return nil, fmt.Errorf("unexpected request %s", req.URL)
+	}
+	res := f.resp[0]
+	f.resp = f.resp[1:]
+	return res, nil
+}
+
+func response(code int, body string) *http.Response {
+	return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestManager(t *testing.T) {
+	convey.Convey("test Manager", t, func() {
+		ctx := context.Background()
+		tr := &fakeTransport{}
+		m := &Manager{Transport: tr, Index: "eino"}
+
+		convey.Convey("test empty input", func() {
+			convey.So(m.DeleteByIDs(ctx, nil), convey.ShouldBeNil)
+			convey.So(m.DeleteByMetaData(ctx, nil), convey.ShouldBeError)
+			docs, err := m.GetByIDs(ctx, nil)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldBeEmpty)
+			convey.So(tr.reqs, convey.ShouldBeEmpty)
+		})
+
+		convey.Convey("test DeleteByIDs", func() {
+			tr.resp = []*http.Response{response(http.StatusOK, `{"deleted":2}`)}
+			convey.So(m.DeleteByIDs(ctx, []string{"1", "2"}), convey.ShouldBeNil)
+			convey.So(tr.reqs[0].Method, convey.ShouldEqual, http.MethodPost)
+			convey.So(tr.reqs[0].URL.String(), convey.ShouldEqual, "/eino/_delete_by_query?conflicts=proceed&refresh=true")
+			convey.So(tr.reqs[0].Header.Get("Content-Type"), convey.ShouldEqual, "application/json")
+			convey.So(tr.body[0], convey.ShouldEqual, `{"query":{"ids":{"values":["1","2"]}}}`)
+		})
+
+		convey.Convey("test DeleteByMetaData", func() {
+			tr.resp = []*http.Response{response(http.StatusOK, `{"deleted":1}`)}
+			convey.So(m.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md", "chunk": 3}), convey.ShouldBeNil)
+			convey.So(tr.reqs[0].URL.Path, convey.ShouldEqual, "/eino/_delete_by_query")
+			convey.So(tr.body[0], convey.ShouldEqual,
+				`{"query":{"bool":{"filter":[{"term":{"chunk":3}},{"term":{"parent_id":"a.md"}}]}}}`)
+
+			tr.resp = []*http.Response{response(http.StatusBadRequest, `{"error":"bad query"}`)}
+			err := m.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `[400 Bad Request] {"error":"bad query"}`)
+		})
+
+		convey.Convey("test GetByIDs", func() {
+			tr.resp = []*http.Response{response(http.StatusOK, `{"docs":[
+				{"_id":"2","found":true,"_source":{"content":"qwe"}},
+				{"_id":"3","found":false},
+				{"_id":"1","found":true,"_source":{"content":"asd"}}
+			]}`)}
+			docs, err := m.GetByIDs(ctx, []string{"2", "3", "1"})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldResemble, []*schema.Document{
+				{ID: "2", MetaData: map[string]any{"content": "qwe"}},
+				{ID: "1", MetaData: map[string]any{"content": "asd"}},
+			})
+			convey.So(tr.reqs[0].URL.String(), convey.ShouldEqual, "/eino/_mget")
+			convey.So(tr.body[0], convey.ShouldEqual, `{"ids":["2","3","1"]}`)
+
+			m.FieldsToDocument = func(ctx context.Context, id string, fields map[string]any) (*schema.Document, error) {
+				return &schema.Document{ID: id, Content: fields["content"].(string)}, nil
+			}
+			tr.resp = []*http.Response{response(http.StatusOK, `{"docs":[{"_id":"1","found":true,"_source":{"content":"asd"}}]}`)}
+			docs, err = m.GetByIDs(ctx, []string{"1"})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldResemble, []*schema.Document{{ID: "1", Content: "asd"}})
+
+			tr.resp = []*http.Response{response(http.StatusNotFound, `{"error":"index_not_found_exception"}`)}
+			_, err = m.GetByIDs(ctx, []string{"1"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "index_not_found_exception")
+		})
+
+		convey.Convey("test UpdateMetaData", func() {
+			tr.resp = []*http.Response{response(http.StatusOK, `{"result":"updated"}`)}
+			convey.So(m.UpdateMetaData(ctx, "a/1", map[string]any{"tag": "x"}), convey.ShouldBeNil)
+			convey.So(tr.reqs[0].URL.String(), convey.ShouldEqual, "/eino/_update/a%2F1?refresh=true")
+			convey.So(tr.body[0], convey.ShouldEqual, `{"doc":{"tag":"x"}}`)
+
+			tr.resp = []*http.Response{response(http.StatusNotFound, `{"error":"document_missing_exception"}`)}
+			err := m.UpdateMetaData(ctx, "2", nil)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldEqual, "[UpdateMetaData] document not found, id=2")
+			convey.So(tr.body[1], convey.ShouldEqual, `{"doc":{}}`)
+		})
+	})
+}
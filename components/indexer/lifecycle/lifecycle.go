@@ -0,0 +1,75 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lifecycle defines optional interfaces for managing the documents
+// stored by an indexer after they have been indexed.
+//
+// The indexers under components/indexer implement these interfaces, and
+// callers can discover the capability with a type assertion on any
+// [indexer.Indexer]:
+//
+//	if m, ok := idx.(lifecycle.DocumentManager); ok {
+//		err = m.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"})
+//	}
+package lifecycle
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Deleter deletes stored documents.
+type Deleter interface {
+	// DeleteByIDs deletes the documents with the given IDs. IDs which are
+	// not stored are ignored.
+	DeleteByIDs(ctx context.Context, ids []string) error
+	// DeleteByMetaData deletes the documents whose metadata matches every
+	// key - value pair of the filter, e.g. all the chunks of a parent
+	// document. An empty filter is an error rather than deleting everything.
+	DeleteByMetaData(ctx context.Context, filter map[string]any) error
+}
+
+// Getter fetches stored documents.
+type Getter interface {
+	// GetByIDs returns the documents with the given IDs, in the order of the
+	// IDs. IDs which are not stored are skipped.
+	GetByIDs(ctx context.Context, ids []string) ([]*schema.Document, error)
+}
+
+// MetaDataUpdater updates the metadata of stored documents.
+type MetaDataUpdater interface {
+	// UpdateMetaData merges metaData into the metadata of the document with
+	// the given ID, without embedding the document again. Keys which are
+	// not in metaData are kept. It returns an error if the document is not
+	// stored.
+	UpdateMetaData(ctx context.Context, id string, metaData map[string]any) error
+}
+
+// DocumentManager is implemented by indexers which support the whole
+// document lifecycle.
+type DocumentManager interface {
+	Deleter
+	Getter
+	MetaDataUpdater
+}
+
+// ManagedIndexer is an indexer which supports the whole document lifecycle.
+type ManagedIndexer interface {
+	indexer.Indexer
+	DocumentManager
+}
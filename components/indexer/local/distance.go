@@ -0,0 +1,129 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// distFunc returns the distance between two vectors, smaller is closer.
+type distFunc func(a, b []float32) float32
+
+var distFuncs = map[Metric]distFunc{
+	// Vectors are normalized when stored and queried.
+	MetricCosine: func(a, b []float32) float32 { return 1 - dot(a, b) },
+	MetricDot:    func(a, b []float32) float32 { return -dot(a, b) },
+	MetricL2:     squaredL2,
+}
+
+func dot(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func squaredL2(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+func toFloat32(v []float64) []float32 {
+	ret := make([]float32, len(v))
+	for i, x := range v {
+		ret[i] = float32(x)
+	}
+	return ret
+}
+
+// normalize scales the vector to unit length, leaving zero vectors as is.
+func normalize(v []float32) {
+	norm := math.Sqrt(float64(dot(v, v)))
+	if norm == 0 {
+		return
+	}
+	for i := range v {
+		v[i] = float32(float64(v[i]) / norm)
+	}
+}
+
+// candidate is a search result, node is -1 for flat scans.
+type candidate struct {
+	node int32
+	id   string
+	dist float32
+}
+
+// minHeap pops the closest candidate first.
+type minHeap []candidate
+
+func (h minHeap) Len() int           { return len(h) }
+func (h minHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h minHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x any)        { *h = append(*h, x.(candidate)) }
+func (h *minHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// maxHeap pops the farthest candidate first, keeping the closest k ones with
+// pushBounded.
+type maxHeap []candidate
+
+func (h maxHeap) Len() int           { return len(h) }
+func (h maxHeap) Less(i, j int) bool { return h[i].dist > h[j].dist }
+func (h maxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x any)        { *h = append(*h, x.(candidate)) }
+func (h *maxHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+func (h *maxHeap) pushBounded(c candidate, k int) {
+	if h.Len() < k {
+		heap.Push(h, c)
+		return
+	}
+	if c.dist < (*h)[0].dist {
+		(*h)[0] = c
+		heap.Fix(h, 0)
+	}
+}
+
+// sorted returns the candidates closest first.
+func (h maxHeap) sorted() []candidate {
+	ret := make([]candidate, len(h))
+	copy(ret, h)
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].dist != ret[j].dist {
+			return ret[i].dist < ret[j].dist
+		}
+		return ret[i].id < ret[j].id
+	})
+	return ret
+}
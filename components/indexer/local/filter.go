@@ -0,0 +1,77 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import "reflect"
+
+// Filter reports whether a document with the metadata passes. The metadata
+// must not be modified.
+type Filter func(metaData map[string]any) bool
+
+// MatchMetaData returns a Filter passing the documents whose metadata has all
+// the key - value pairs. Numbers of different types are equal if their values
+// are, since numbers loaded from a snapshot are float64.
+func MatchMetaData(conds map[string]any) Filter {
+	return func(metaData map[string]any) bool {
+		for k, want := range conds {
+			got, ok := metaData[k]
+			if !ok || !valueEqual(got, want) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func valueEqual(a, b any) bool {
+	if x, ok := toFloat64(a); ok {
+		y, ok := toFloat64(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
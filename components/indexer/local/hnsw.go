@@ -0,0 +1,244 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// hnsw is a hierarchical navigable small world graph, see
+// https://arxiv.org/abs/1603.09320. Removed nodes are kept as tombstones, which
+// still route searches but are never returned.
+type hnsw struct {
+	m              int
+	m0             int
+	efConstruction int
+	levelMult      float64
+	dist           distFunc
+	rng            *rand.Rand
+
+	nodes    []*hnswNode
+	entry    int32
+	maxLevel int
+	deleted  int
+}
+
+type hnswNode struct {
+	id      string
+	vector  []float32
+	links   [][]int32
+	deleted bool
+}
+
+func newHNSW(m, efConstruction int, dist distFunc, rng *rand.Rand) *hnsw {
+	return &hnsw{
+		m:              m,
+		m0:             2 * m,
+		efConstruction: efConstruction,
+		levelMult:      1 / math.Log(float64(m)),
+		dist:           dist,
+		rng:            rng,
+		entry:          -1,
+	}
+}
+
+func (h *hnsw) randomLevel() int {
+	return int(-math.Log(1-h.rng.Float64()) * h.levelMult)
+}
+
+func (h *hnsw) maxLinks(level int) int {
+	if level == 0 {
+		return h.m0
+	}
+	return h.m
+}
+
+// insert adds a node of the vector and returns its index.
+func (h *hnsw) insert(id string, vector []float32) int32 {
+	level := h.randomLevel()
+	idx := int32(len(h.nodes))
+	n := &hnswNode{id: id, vector: vector, links: make([][]int32, level+1)}
+	h.nodes = append(h.nodes, n)
+
+	if h.entry < 0 {
+		h.entry, h.maxLevel = idx, level
+		return idx
+	}
+
+	ep := candidate{node: h.entry, dist: h.dist(vector, h.nodes[h.entry].vector)}
+	for l := h.maxLevel; l > level; l-- {
+		ep = h.greedy(vector, ep, l)
+	}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		cands := h.searchLayer(vector, ep, h.efConstruction, l, nil)
+		neighbors := h.selectNeighbors(cands, h.m)
+		n.links[l] = make([]int32, 0, len(neighbors))
+		for _, nb := range neighbors {
+			n.links[l] = append(n.links[l], nb.node)
+			h.link(nb.node, idx, l)
+		}
+		ep = cands[0]
+	}
+
+	if level > h.maxLevel {
+		h.entry, h.maxLevel = idx, level
+	}
+	return idx
+}
+
+// link adds an edge from the node to the target on the level, pruning the
+// edges of the node if there are too many.
+func (h *hnsw) link(node, target int32, level int) {
+	n := h.nodes[node]
+	n.links[level] = append(n.links[level], target)
+	if len(n.links[level]) <= h.maxLinks(level) {
+		return
+	}
+
+	cands := make([]candidate, 0, len(n.links[level]))
+	for _, l := range n.links[level] {
+		cands = append(cands, candidate{node: l, dist: h.dist(n.vector, h.nodes[l].vector)})
+	}
+	sort.Slice(cands, func(i, j int) bool { return cands[i].dist < cands[j].dist })
+
+	selected := h.selectNeighbors(cands, h.maxLinks(level))
+	links := make([]int32, 0, len(selected))
+	for _, c := range selected {
+		links = append(links, c.node)
+	}
+	n.links[level] = links
+}
+
+// selectNeighbors picks up to m of the candidates sorted closest first, which
+// are closer to the base than to the picked ones, so that the edges spread in
+// different directions. Pruned candidates fill the rest.
+func (h *hnsw) selectNeighbors(cands []candidate, m int) []candidate {
+	if len(cands) <= m {
+		return cands
+	}
+
+	selected := make([]candidate, 0, m)
+	pruned := make([]candidate, 0, len(cands))
+	for _, c := range cands {
+		if len(selected) == m {
+			break
+		}
+		good := true
+		for _, s := range selected {
+			if h.dist(h.nodes[c.node].vector, h.nodes[s.node].vector) < c.dist {
+				good = false
+				break
+			}
+		}
+		if good {
+			selected = append(selected, c)
+		} else {
+			pruned = append(pruned, c)
+		}
+	}
+	for i := 0; len(selected) < m && i < len(pruned); i++ {
+		selected = append(selected, pruned[i])
+	}
+	return selected
+}
+
+// greedy walks from the entry point to the closest node on the level.
+func (h *hnsw) greedy(vector []float32, ep candidate, level int) candidate {
+	for changed := true; changed; {
+		changed = false
+		for _, nb := range h.nodes[ep.node].links[level] {
+			if d := h.dist(vector, h.nodes[nb].vector); d < ep.dist {
+				ep = candidate{node: nb, dist: d}
+				changed = true
+			}
+		}
+	}
+	return ep
+}
+
+// searchLayer returns up to ef nodes closest to the vector on the level,
+// closest first. If accept is set, only the accepted nodes are returned, but
+// all the nodes are traversed, so that the search keeps going until ef
+// accepted nodes are found or the graph is exhausted.
+func (h *hnsw) searchLayer(vector []float32, ep candidate, ef, level int, accept func(node int32) bool) []candidate {
+	visited := map[int32]struct{}{ep.node: {}}
+	cands := &minHeap{ep}
+	results := &maxHeap{}
+	if accept == nil || accept(ep.node) {
+		heap.Push(results, ep)
+	}
+
+	for cands.Len() > 0 {
+		c := heap.Pop(cands).(candidate)
+		if results.Len() >= ef && c.dist > (*results)[0].dist {
+			break
+		}
+		for _, nb := range h.nodes[c.node].links[level] {
+			if _, ok := visited[nb]; ok {
+				continue
+			}
+			visited[nb] = struct{}{}
+
+			d := h.dist(vector, h.nodes[nb].vector)
+			if results.Len() < ef || d < (*results)[0].dist {
+				next := candidate{node: nb, dist: d}
+				heap.Push(cands, next)
+				if accept == nil || accept(nb) {
+					results.pushBounded(next, ef)
+				}
+			}
+		}
+	}
+
+	ret := results.sorted()
+	for i := range ret {
+		ret[i].id = h.nodes[ret[i].node].id
+	}
+	return ret
+}
+
+// search returns up to k live nodes closest to the vector, closest first,
+// skipping the nodes whose IDs are not accepted if accept is set.
+func (h *hnsw) search(vector []float32, k, ef int, accept func(id string) bool) []candidate {
+	if h.entry < 0 {
+		return nil
+	}
+
+	ep := candidate{node: h.entry, dist: h.dist(vector, h.nodes[h.entry].vector)}
+	for l := h.maxLevel; l > 0; l-- {
+		ep = h.greedy(vector, ep, l)
+	}
+	results := h.searchLayer(vector, ep, ef, 0, func(node int32) bool {
+		n := h.nodes[node]
+		return !n.deleted && (accept == nil || accept(n.id))
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// remove marks the node as a tombstone.
+func (h *hnsw) remove(node int32) {
+	if n := h.nodes[node]; !n.deleted {
+		n.deleted = true
+		h.deleted++
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+)
+
+type IndexerConfig struct {
+	// Store where documents are written, it could be shared with retrievers.
+	// Use NewStore to create an empty one, or LoadStore to load a snapshot.
+	Store *Store
+	// BatchSize controls embedding texts size.
+	// Default 10.
+	BatchSize int `json:"batch_size"`
+	// Embedding vectorization method for document contents.
+	// Documents which already have dense vectors, see schema.Document.WithDenseVector, are stored as is.
+	Embedding embedding.Embedder
+}
+
+type Indexer struct {
+	config *IndexerConfig
+}
+
+func NewIndexer(ctx context.Context, config *IndexerConfig) (*Indexer, error) {
+	if config.Store == nil {
+		return nil, fmt.Errorf("[NewIndexer] store not provided")
+	}
+
+	if config.BatchSize == 0 {
+		config.BatchSize = 10
+	}
+
+	if config.BatchSize < 0 {
+		return nil, fmt.Errorf("[NewIndexer] invalid batch size: %d", config.BatchSize)
+	}
+
+	return &Indexer{
+		config: config,
+	}, nil
+}
+
+func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
+	options := indexer.GetCommonOptions(&indexer.Options{
+		Embedding: i.config.Embedding,
+	}, opts...)
+
+	ctx = callbacks.EnsureRunInfo(ctx, i.GetType(), components.ComponentOfIndexer)
+	ctx = callbacks.OnStart(ctx, &indexer.CallbackInput{Docs: docs})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	vectors, err := i.vectors(ctx, docs, options.Embedding)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = i.config.Store.Upsert(docs, vectors); err != nil {
+		return nil, err
+	}
+
+	ids = make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+
+	callbacks.OnEnd(ctx, &indexer.CallbackOutput{IDs: ids})
+
+	return ids, nil
+}
+
+// vectors returns the vectors of the documents, embedding the contents of the
+// documents without dense vectors in batches.
+func (i *Indexer) vectors(ctx context.Context, docs []*schema.Document, emb embedding.Embedder) ([][]float64, error) {
+	vectors := make([][]float64, len(docs))
+	var idxs []int
+	for idx, doc := range docs {
+		if v := doc.DenseVector(); len(v) > 0 {
+			vectors[idx] = v
+		} else {
+			idxs = append(idxs, idx)
+		}
+	}
+	if len(idxs) == 0 {
+		return vectors, nil
+	}
+
+	if emb == nil {
+		return nil, fmt.Errorf("[vectors] embedding method not provided")
+	}
+
+	for start := 0; start < len(idxs); start += i.config.BatchSize {
+		batch := idxs[start:min(start+i.config.BatchSize, len(idxs))]
+		texts := make([]string, 0, len(batch))
+		for _, idx := range batch {
+			texts = append(texts, docs[idx].Content)
+		}
+
+		embedded, err := emb.EmbedStrings(i.makeEmbeddingCtx(ctx, emb), texts)
+		if err != nil {
+			return nil, fmt.Errorf("[vectors] embedding failed, %w", err)
+		}
+
+		if len(embedded) != len(texts) {
+			return nil, fmt.Errorf("[vectors] invalid vector length, expected=%d, got=%d", len(texts), len(embedded))
+		}
+
+		for j, idx := range batch {
+			vectors[idx] = embedded[j]
+		}
+	}
+
+	return vectors, nil
+}
+
+func (i *Indexer) makeEmbeddingCtx(ctx context.Context, emb embedding.Embedder) context.Context {
+	runInfo := &callbacks.RunInfo{
+		Component: components.ComponentOfEmbedding,
+	}
+
+	if embType, ok := components.GetType(emb); ok {
+		runInfo.Type = embType
+	}
+
+	runInfo.Name = runInfo.Type + string(runInfo.Component)
+
+	return callbacks.ReuseHandlers(ctx, runInfo)
+}
+
+const typ = "Local"
+
+func (i *Indexer) GetType() string {
+	return typ
+}
+
+func (i *Indexer) IsCallbacksEnabled() bool {
+	return true
+}
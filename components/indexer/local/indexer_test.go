@@ -0,0 +1,131 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestIndexer(t *testing.T) {
+	convey.Convey("test indexer", t, func() {
+		ctx := context.Background()
+		store, err := NewStore(&StoreConfig{Seed: 1})
+		convey.So(err, convey.ShouldBeNil)
+
+		convey.Convey("test NewIndexer", func() {
+			_, err := NewIndexer(ctx, &IndexerConfig{})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewIndexer(ctx, &IndexerConfig{Store: store, BatchSize: -1})
+			convey.So(err, convey.ShouldNotBeNil)
+
+			i, err := NewIndexer(ctx, &IndexerConfig{Store: store})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(i.config.BatchSize, convey.ShouldEqual, 10)
+			convey.So(i.GetType(), convey.ShouldEqual, typ)
+			convey.So(i.IsCallbacksEnabled(), convey.ShouldBeTrue)
+		})
+
+		convey.Convey("test Store", func() {
+			emb := &mockEmbedding{}
+			i, err := NewIndexer(ctx, &IndexerConfig{Store: store, Embedding: emb, BatchSize: 2})
+			convey.So(err, convey.ShouldBeNil)
+
+			docs := []*schema.Document{
+				{ID: "1", Content: "a"},
+				{ID: "2", Content: "bb"},
+				{ID: "3", Content: "ccc"},
+				(&schema.Document{ID: "4", Content: "dddd"}).WithDenseVector([]float64{0, 0, 1}),
+			}
+			ids, err := i.Store(ctx, docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids, convey.ShouldResemble, []string{"1", "2", "3", "4"})
+			convey.So(emb.calls, convey.ShouldResemble, [][]string{{"a", "bb"}, {"ccc"}})
+
+			got, err := store.Search(&SearchRequest{Vector: []float64{0, 0, 1}, TopK: 1})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(got[0].ID, convey.ShouldEqual, "4")
+
+			convey.Convey("test embedding errors", func() {
+				_, err = i.Store(ctx, docs[:1], indexer.WithEmbedding(&mockEmbedding{err: fmt.Errorf("mock err")}))
+				convey.So(err, convey.ShouldNotBeNil)
+				_, err = i.Store(ctx, docs[:1], indexer.WithEmbedding(&mockEmbedding{size: 2}))
+				convey.So(err, convey.ShouldNotBeNil)
+
+				i.config.Embedding = nil
+				_, err = i.Store(ctx, docs[:1])
+				convey.So(err, convey.ShouldNotBeNil)
+				_, err = i.Store(ctx, docs[3:])
+				convey.So(err, convey.ShouldBeNil)
+			})
+
+			convey.Convey("test lifecycle", func() {
+				convey.So(i.UpdateMetaData(ctx, "1", map[string]any{"lang": "en"}), convey.ShouldBeNil)
+				convey.So(i.UpdateMetaData(ctx, "2", map[string]any{"lang": "zh"}), convey.ShouldBeNil)
+				convey.So(i.UpdateMetaData(ctx, "9", map[string]any{"lang": "zh"}), convey.ShouldNotBeNil)
+
+				got, err := i.GetByIDs(ctx, []string{"2", "9", "1"})
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(got, convey.ShouldResemble, []*schema.Document{
+					{ID: "2", Content: "bb", MetaData: map[string]any{"lang": "zh"}},
+					{ID: "1", Content: "a", MetaData: map[string]any{"lang": "en"}},
+				})
+
+				convey.So(i.DeleteByMetaData(ctx, nil), convey.ShouldNotBeNil)
+				convey.So(i.DeleteByMetaData(ctx, map[string]any{"lang": "zh"}), convey.ShouldBeNil)
+				convey.So(i.DeleteByIDs(ctx, []string{"3", "9"}), convey.ShouldBeNil)
+				got, err = i.GetByIDs(ctx, []string{"1", "2", "3", "4"})
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(docIDs(got), convey.ShouldResemble, []string{"1", "4"})
+			})
+		})
+	})
+}
+
+// mockEmbedding embeds a text to a vector of its length, size overrides the
+// number of the returned vectors.
+type mockEmbedding struct {
+	calls [][]string
+	size  int
+	err   error
+}
+
+func (m *mockEmbedding) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.calls = append(m.calls, texts)
+
+	size := len(texts)
+	if m.size > 0 {
+		size = m.size
+	}
+	vectors := make([][]float64, size)
+	for i := range vectors {
+		vectors[i] = []float64{1, float64(i), 0}
+		if i < len(texts) {
+			vectors[i] = []float64{1, float64(len(texts[i])), 0}
+		}
+	}
+	return vectors, nil
+}
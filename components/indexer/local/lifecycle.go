@@ -0,0 +1,54 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino-ext/components/indexer/lifecycle"
+	"github.com/cloudwego/eino/schema"
+)
+
+var _ lifecycle.DocumentManager = (*Indexer)(nil)
+
+// DeleteByIDs deletes the documents with the given IDs, ignoring the missing ones.
+func (i *Indexer) DeleteByIDs(ctx context.Context, ids []string) error {
+	i.config.Store.Delete(ids...)
+	return nil
+}
+
+// DeleteByMetaData deletes the documents whose metadata equals every value of the filter.
+func (i *Indexer) DeleteByMetaData(ctx context.Context, filter map[string]any) error {
+	if len(filter) == 0 {
+		return fmt.Errorf("[DeleteByMetaData] filter is empty")
+	}
+
+	i.config.Store.DeleteByFilter(MatchMetaData(filter))
+	return nil
+}
+
+// GetByIDs returns the documents with the given IDs, skipping the missing ones.
+func (i *Indexer) GetByIDs(ctx context.Context, ids []string) ([]*schema.Document, error) {
+	return i.config.Store.Get(ids...), nil
+}
+
+// UpdateMetaData merges metaData into the metadata of the document with the given ID,
+// keys with nil values are deleted.
+func (i *Indexer) UpdateMetaData(ctx context.Context, id string, metaData map[string]any) error {
+	return i.config.Store.UpdateMetaData(id, metaData)
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const snapshotVersion = 1
+
+// snapshot is the gob encoded content of a snapshot file. Metadata is encoded
+// as json, since gob requires the concrete types of interface values to be
+// registered.
+type snapshot struct {
+	Version   int
+	Config    StoreConfig
+	Dimension int
+	Records   []snapshotRecord
+	// Graph is nil for IndexFlat.
+	Graph *snapshotGraph
+}
+
+type snapshotRecord struct {
+	ID       string
+	Content  string
+	MetaData []byte
+	Vector   []float32
+	Node     int32
+}
+
+type snapshotGraph struct {
+	Nodes    []snapshotNode
+	Entry    int32
+	MaxLevel int
+}
+
+type snapshotNode struct {
+	Links   [][]int32
+	Deleted bool
+	// Vector of a tombstone, the vectors of live nodes are in the records.
+	Vector []float32
+	ID     string
+}
+
+// Save writes a snapshot of the store to the file, replacing it atomically.
+// Reads are served while saving, writes wait until it's done.
+func (s *Store) Save(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("[Save] create snapshot file failed, path=%s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = s.SaveTo(tmp); err == nil {
+		err = tmp.Close()
+	} else {
+		_ = tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("[Save] write snapshot failed, path=%s: %w", path, err)
+	}
+	return nil
+}
+
+// SaveTo writes a snapshot of the store to the writer.
+func (s *Store) SaveTo(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := &snapshot{
+		Version:   snapshotVersion,
+		Config:    s.config,
+		Dimension: s.dim,
+		Records:   make([]snapshotRecord, 0, len(s.records)),
+	}
+	for _, id := range sortedKeys(s.records) {
+		r := s.records[id]
+		metaData, err := json.Marshal(r.metaData)
+		if err != nil {
+			return fmt.Errorf("[SaveTo] marshal metadata failed, id=%s: %w", id, err)
+		}
+		snap.Records = append(snap.Records, snapshotRecord{
+			ID:       r.id,
+			Content:  r.content,
+			MetaData: metaData,
+			Vector:   r.vector,
+			Node:     r.node,
+		})
+	}
+	if g := s.graph; g != nil {
+		snap.Graph = &snapshotGraph{
+			Nodes:    make([]snapshotNode, 0, len(g.nodes)),
+			Entry:    g.entry,
+			MaxLevel: g.maxLevel,
+		}
+		for _, n := range g.nodes {
+			sn := snapshotNode{Links: n.links, Deleted: n.deleted}
+			if n.deleted {
+				sn.ID, sn.Vector = n.id, n.vector
+			}
+			snap.Graph.Nodes = append(snap.Graph.Nodes, sn)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := gob.NewEncoder(bw).Encode(snap); err != nil {
+		return fmt.Errorf("[SaveTo] encode snapshot failed: %w", err)
+	}
+	return bw.Flush()
+}
+
+// LoadStore reads a Store from a snapshot file written by Store.Save.
+// Numbers in metadata are loaded as float64.
+func LoadStore(path string) (*Store, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("[LoadStore] open snapshot failed, path=%s: %w", path, err)
+	}
+	defer f.Close()
+
+	s, err := LoadStoreFrom(f)
+	if err != nil {
+		return nil, fmt.Errorf("[LoadStore] path=%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// LoadStoreFrom reads a Store from a snapshot written by Store.SaveTo.
+func LoadStoreFrom(r io.Reader) (*Store, error) {
+	snap := &snapshot{}
+	if err := gob.NewDecoder(bufio.NewReader(r)).Decode(snap); err != nil {
+		return nil, fmt.Errorf("[LoadStoreFrom] decode snapshot failed: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return nil, fmt.Errorf("[LoadStoreFrom] unsupported snapshot version: %d", snap.Version)
+	}
+
+	s, err := NewStore(&snap.Config)
+	if err != nil {
+		return nil, err
+	}
+	s.dim = snap.Dimension
+
+	if (s.graph == nil) != (snap.Graph == nil) {
+		return nil, fmt.Errorf("[LoadStoreFrom] graph of snapshot mismatches index type: %s", s.config.IndexType)
+	}
+	if snap.Graph != nil {
+		s.graph.entry, s.graph.maxLevel = snap.Graph.Entry, snap.Graph.MaxLevel
+		s.graph.nodes = make([]*hnswNode, 0, len(snap.Graph.Nodes))
+		for _, sn := range snap.Graph.Nodes {
+			s.graph.nodes = append(s.graph.nodes, &hnswNode{
+				id:      sn.ID,
+				vector:  sn.Vector,
+				links:   sn.Links,
+				deleted: sn.Deleted,
+			})
+			if sn.Deleted {
+				s.graph.deleted++
+			}
+		}
+	}
+
+	for _, sr := range snap.Records {
+		if len(sr.Vector) != s.dim {
+			return nil, fmt.Errorf("[LoadStoreFrom] vector dimension mismatch, id=%s, expected=%d, got=%d", sr.ID, s.dim, len(sr.Vector))
+		}
+		r := &record{
+			id:      sr.ID,
+			content: sr.Content,
+			vector:  sr.Vector,
+			node:    sr.Node,
+		}
+		if err = json.Unmarshal(sr.MetaData, &r.metaData); err != nil {
+			return nil, fmt.Errorf("[LoadStoreFrom] unmarshal metadata failed, id=%s: %w", sr.ID, err)
+		}
+		if s.graph != nil {
+			if sr.Node < 0 || int(sr.Node) >= len(s.graph.nodes) || s.graph.nodes[sr.Node].deleted {
+				return nil, fmt.Errorf("[LoadStoreFrom] invalid graph node of record, id=%s, node=%d", sr.ID, sr.Node)
+			}
+			n := s.graph.nodes[sr.Node]
+			n.id, n.vector = sr.ID, sr.Vector
+		}
+		s.records[sr.ID] = r
+	}
+	if s.graph != nil {
+		for i, n := range s.graph.nodes {
+			if n.vector == nil {
+				return nil, fmt.Errorf("[LoadStoreFrom] graph node without vector: %d", i)
+			}
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,435 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Metric is the similarity metric of the vectors in a Store.
+type Metric string
+
+const (
+	// MetricCosine scores by cosine similarity, in [-1, 1].
+	MetricCosine Metric = "cosine"
+	// MetricDot scores by inner product.
+	MetricDot Metric = "dot"
+	// MetricL2 scores by 1 / (1 + euclidean distance), in (0, 1].
+	MetricL2 Metric = "l2"
+)
+
+// IndexType is the search algorithm of a Store.
+type IndexType string
+
+const (
+	// IndexHNSW searches an approximate nearest neighbor graph.
+	IndexHNSW IndexType = "hnsw"
+	// IndexFlat scans all the vectors, which is exact but linear in the number of documents.
+	IndexFlat IndexType = "flat"
+)
+
+type StoreConfig struct {
+	// Metric similarity metric of the vectors.
+	// Default MetricCosine.
+	Metric Metric `json:"metric"`
+	// IndexType search algorithm.
+	// Default IndexHNSW.
+	IndexType IndexType `json:"index_type"`
+	// Dimension of the vectors, inferred from the first stored vector if not set.
+	Dimension int `json:"dimension"`
+	// M max number of neighbors of a node on each layer of the HNSW graph, 2*M on the bottom layer.
+	// Default 16.
+	M int `json:"m"`
+	// EfConstruction size of the candidate list when inserting into the HNSW graph.
+	// Default 200.
+	EfConstruction int `json:"ef_construction"`
+	// EfSearch size of the candidate list when searching the HNSW graph, raised to topK if smaller.
+	// Default 64.
+	EfSearch int `json:"ef_search"`
+	// Seed of the random levels of the HNSW graph, for reproducible graphs.
+	// Default current time.
+	Seed int64 `json:"seed"`
+}
+
+// Store is an in-process vector store of documents. Reads are safe to run
+// concurrently with each other and with writes.
+//
+// Updates and deletes leave tombstones in the HNSW graph, which is rebuilt once
+// tombstones outnumber live documents.
+type Store struct {
+	mu sync.RWMutex
+
+	config  StoreConfig
+	dim     int
+	records map[string]*record
+	// graph is nil for IndexFlat.
+	graph *hnsw
+}
+
+type record struct {
+	id       string
+	content  string
+	metaData map[string]any
+	vector   []float32
+	// node of the record in graph, -1 for IndexFlat.
+	node int32
+}
+
+// NewStore creates an empty Store.
+func NewStore(config *StoreConfig) (*Store, error) {
+	if config == nil {
+		config = &StoreConfig{}
+	}
+	conf := *config
+
+	if conf.Metric == "" {
+		conf.Metric = MetricCosine
+	}
+	if conf.IndexType == "" {
+		conf.IndexType = IndexHNSW
+	}
+	if conf.M == 0 {
+		conf.M = 16
+	}
+	if conf.EfConstruction == 0 {
+		conf.EfConstruction = 200
+	}
+	if conf.EfSearch == 0 {
+		conf.EfSearch = 64
+	}
+	if conf.Seed == 0 {
+		conf.Seed = time.Now().UnixNano()
+	}
+
+	if _, ok := distFuncs[conf.Metric]; !ok {
+		return nil, fmt.Errorf("[NewStore] unknown metric: %s", conf.Metric)
+	}
+	if conf.IndexType != IndexHNSW && conf.IndexType != IndexFlat {
+		return nil, fmt.Errorf("[NewStore] unknown index type: %s", conf.IndexType)
+	}
+	if conf.Dimension < 0 || conf.M < 2 || conf.EfConstruction < 1 || conf.EfSearch < 1 {
+		return nil, fmt.Errorf("[NewStore] invalid config: dimension=%d, m=%d, ef_construction=%d, ef_search=%d",
+			conf.Dimension, conf.M, conf.EfConstruction, conf.EfSearch)
+	}
+
+	s := &Store{
+		config:  conf,
+		dim:     conf.Dimension,
+		records: make(map[string]*record),
+	}
+	s.graph = s.newGraph()
+	return s, nil
+}
+
+func (s *Store) newGraph() *hnsw {
+	if s.config.IndexType != IndexHNSW {
+		return nil
+	}
+	return newHNSW(s.config.M, s.config.EfConstruction, distFuncs[s.config.Metric], rand.New(rand.NewSource(s.config.Seed)))
+}
+
+// Config returns the config of the store with defaults applied.
+func (s *Store) Config() StoreConfig {
+	return s.config
+}
+
+// Len returns the number of documents in the store.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.records)
+}
+
+// Upsert adds the documents with their vectors, replacing the documents with
+// the same IDs. vectors[i] is the vector of docs[i].
+func (s *Store) Upsert(docs []*schema.Document, vectors [][]float64) error {
+	if len(docs) != len(vectors) {
+		return fmt.Errorf("[Upsert] docs and vectors length mismatch, docs=%d, vectors=%d", len(docs), len(vectors))
+	}
+
+	dim := s.Dimension()
+	records := make([]*record, 0, len(docs))
+	for i, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("[Upsert] doc id not set, index=%d", i)
+		}
+		if len(vectors[i]) == 0 {
+			return fmt.Errorf("[Upsert] vector is empty, id=%s", doc.ID)
+		}
+		if dim == 0 {
+			dim = len(vectors[i])
+		}
+		if len(vectors[i]) != dim {
+			return fmt.Errorf("[Upsert] vector dimension mismatch, id=%s, expected=%d, got=%d", doc.ID, dim, len(vectors[i]))
+		}
+		records = append(records, &record{
+			id:       doc.ID,
+			content:  doc.Content,
+			metaData: copyMetaData(doc.MetaData),
+			vector:   s.prepare(vectors[i]),
+			node:     -1,
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The dimension may be set by a concurrent upsert since it was read.
+	if s.dim != 0 && s.dim != dim {
+		return fmt.Errorf("[Upsert] vector dimension mismatch, expected=%d, got=%d", s.dim, dim)
+	}
+	s.dim = dim
+	for _, r := range records {
+		s.removeLocked(r.id)
+		if s.graph != nil {
+			r.node = s.graph.insert(r.id, r.vector)
+		}
+		s.records[r.id] = r
+	}
+	s.compactLocked()
+	return nil
+}
+
+// Dimension returns the dimension of the vectors, 0 if it's not known yet.
+func (s *Store) Dimension() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dim
+}
+
+// prepare converts the vector to float32, normalizing it for MetricCosine.
+func (s *Store) prepare(vector []float64) []float32 {
+	v := toFloat32(vector)
+	if s.config.Metric == MetricCosine {
+		normalize(v)
+	}
+	return v
+}
+
+// Delete removes the documents with the IDs, ignoring the missing ones, and
+// returns the number of the removed documents.
+func (s *Store) Delete(ids ...string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int
+	for _, id := range ids {
+		if s.removeLocked(id) {
+			n++
+		}
+	}
+	s.compactLocked()
+	return n
+}
+
+// DeleteByFilter removes the documents passing the filter and returns the
+// number of the removed documents.
+func (s *Store) DeleteByFilter(filter Filter) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int
+	for id, r := range s.records {
+		if filter == nil || filter(r.metaData) {
+			s.removeLocked(id)
+			n++
+		}
+	}
+	s.compactLocked()
+	return n
+}
+
+func (s *Store) removeLocked(id string) bool {
+	r, ok := s.records[id]
+	if !ok {
+		return false
+	}
+	delete(s.records, id)
+	if s.graph != nil {
+		s.graph.remove(r.node)
+	}
+	return true
+}
+
+// compactLocked rebuilds the graph without tombstones once they outnumber the
+// live nodes.
+func (s *Store) compactLocked() {
+	if s.graph == nil || s.graph.deleted <= len(s.records) {
+		return
+	}
+	s.graph = s.newGraph()
+	for _, id := range sortedKeys(s.records) {
+		r := s.records[id]
+		r.node = s.graph.insert(id, r.vector)
+	}
+}
+
+// Get returns the documents with the IDs, skipping the missing ones, in the
+// order of the IDs.
+func (s *Store) Get(ids ...string) []*schema.Document {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	docs := make([]*schema.Document, 0, len(ids))
+	for _, id := range ids {
+		if r, ok := s.records[id]; ok {
+			docs = append(docs, r.document())
+		}
+	}
+	return docs
+}
+
+// UpdateMetaData merges the metadata into the metadata of the document with
+// the ID, deleting the keys with nil values.
+func (s *Store) UpdateMetaData(id string, metaData map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return fmt.Errorf("[UpdateMetaData] document not found, id=%s", id)
+	}
+	// Readers may hold the old map, so it's replaced instead of modified.
+	merged := copyMetaData(r.metaData)
+	if merged == nil {
+		merged = make(map[string]any, len(metaData))
+	}
+	for k, v := range metaData {
+		if v == nil {
+			delete(merged, k)
+		} else {
+			merged[k] = v
+		}
+	}
+	r.metaData = merged
+	return nil
+}
+
+// SearchRequest is a similarity search on a Store.
+type SearchRequest struct {
+	// Vector query vector.
+	Vector []float64
+	// TopK max number of the returned documents.
+	TopK int
+	// ScoreThreshold if set, documents scored lower are dropped.
+	ScoreThreshold *float64
+	// Filter if set, only documents passing it are returned.
+	Filter Filter
+}
+
+// Search returns the documents most similar to the vector, highest score
+// first, with the scores set by schema.Document.WithScore.
+func (s *Store) Search(req *SearchRequest) ([]*schema.Document, error) {
+	if req.TopK <= 0 {
+		return nil, fmt.Errorf("[Search] topK must be positive, got=%d", req.TopK)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.records) == 0 {
+		return []*schema.Document{}, nil
+	}
+	if len(req.Vector) != s.dim {
+		return nil, fmt.Errorf("[Search] vector dimension mismatch, expected=%d, got=%d", s.dim, len(req.Vector))
+	}
+
+	query := s.prepare(req.Vector)
+	var results []candidate
+	if s.graph != nil {
+		var accept func(id string) bool
+		if req.Filter != nil {
+			accept = func(id string) bool {
+				return req.Filter(s.records[id].metaData)
+			}
+		}
+		results = s.graph.search(query, req.TopK, max(s.config.EfSearch, req.TopK), accept)
+	} else {
+		results = s.scan(query, req.TopK, req.Filter)
+	}
+
+	docs := make([]*schema.Document, 0, len(results))
+	for _, c := range results {
+		score := s.score(c.dist)
+		if req.ScoreThreshold != nil && score < *req.ScoreThreshold {
+			// Results are sorted, so the rest are scored lower.
+			break
+		}
+		docs = append(docs, s.records[c.id].document().WithScore(score))
+	}
+	return docs, nil
+}
+
+// scan searches all the records.
+func (s *Store) scan(query []float32, k int, filter Filter) []candidate {
+	dist := distFuncs[s.config.Metric]
+	h := &maxHeap{}
+	for id, r := range s.records {
+		if filter != nil && !filter(r.metaData) {
+			continue
+		}
+		h.pushBounded(candidate{node: -1, id: id, dist: dist(query, r.vector)}, k)
+	}
+	return h.sorted()
+}
+
+func (s *Store) score(dist float32) float64 {
+	switch s.config.Metric {
+	case MetricCosine:
+		return 1 - float64(dist)
+	case MetricDot:
+		return -float64(dist)
+	default:
+		// dist is the squared euclidean distance.
+		return 1 / (1 + math.Sqrt(float64(dist)))
+	}
+}
+
+func (r *record) document() *schema.Document {
+	return &schema.Document{
+		ID:       r.id,
+		Content:  r.content,
+		MetaData: copyMetaData(r.metaData),
+	}
+}
+
+func copyMetaData(metaData map[string]any) map[string]any {
+	if metaData == nil {
+		return nil
+	}
+	ret := make(map[string]any, len(metaData))
+	for k, v := range metaData {
+		ret[k] = v
+	}
+	return ret
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
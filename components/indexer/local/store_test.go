@@ -0,0 +1,313 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func randomVectors(rng *rand.Rand, n, dim int) [][]float64 {
+	vectors := make([][]float64, n)
+	for i := range vectors {
+		vectors[i] = make([]float64, dim)
+		for j := range vectors[i] {
+			vectors[i][j] = rng.NormFloat64()
+		}
+	}
+	return vectors
+}
+
+func randomDocs(n int) []*schema.Document {
+	docs := make([]*schema.Document, n)
+	for i := range docs {
+		docs[i] = &schema.Document{
+			ID:       fmt.Sprintf("doc-%d", i),
+			Content:  fmt.Sprintf("content %d", i),
+			MetaData: map[string]any{"group": i % 4},
+		}
+	}
+	return docs
+}
+
+func docIDs(docs []*schema.Document) []string {
+	ret := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ret = append(ret, doc.ID)
+	}
+	return ret
+}
+
+func TestStore(t *testing.T) {
+	convey.Convey("test store", t, func() {
+		convey.Convey("test invalid config", func() {
+			_, err := NewStore(&StoreConfig{Metric: "hamming"})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewStore(&StoreConfig{IndexType: "ivf"})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewStore(&StoreConfig{M: 1})
+			convey.So(err, convey.ShouldNotBeNil)
+
+			s, err := NewStore(nil)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(s.Config().Metric, convey.ShouldEqual, MetricCosine)
+			convey.So(s.Config().IndexType, convey.ShouldEqual, IndexHNSW)
+		})
+
+		convey.Convey("test scores", func() {
+			docs := []*schema.Document{{ID: "x"}, {ID: "y"}}
+			vectors := [][]float64{{3, 0}, {0, 2}}
+			query := []float64{1, 1}
+
+			for _, c := range []struct {
+				metric Metric
+				scores []float64
+			}{
+				{MetricCosine, []float64{0.7071, 0.7071}},
+				{MetricDot, []float64{3, 2}},
+				{MetricL2, []float64{1 / (1 + 2.2361), 1 / (1 + 1.4142)}},
+			} {
+				for _, indexType := range []IndexType{IndexHNSW, IndexFlat} {
+					s, err := NewStore(&StoreConfig{Metric: c.metric, IndexType: indexType})
+					convey.So(err, convey.ShouldBeNil)
+					convey.So(s.Upsert(docs, vectors), convey.ShouldBeNil)
+
+					got, err := s.Search(&SearchRequest{Vector: query, TopK: 2})
+					convey.So(err, convey.ShouldBeNil)
+					convey.So(got, convey.ShouldHaveLength, 2)
+					scores := map[string]float64{}
+					for _, doc := range got {
+						scores[doc.ID] = doc.Score()
+					}
+					convey.So(scores["x"], convey.ShouldAlmostEqual, c.scores[0], 1e-4)
+					convey.So(scores["y"], convey.ShouldAlmostEqual, c.scores[1], 1e-4)
+					convey.So(got[0].Score(), convey.ShouldBeGreaterThanOrEqualTo, got[1].Score())
+				}
+			}
+		})
+
+		convey.Convey("test hnsw recall", func() {
+			rng := rand.New(rand.NewSource(1))
+			docs, vectors := randomDocs(2000), randomVectors(rng, 2000, 16)
+
+			hnswStore, err := NewStore(&StoreConfig{Seed: 1})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(hnswStore.Upsert(docs, vectors), convey.ShouldBeNil)
+			flatStore, err := NewStore(&StoreConfig{IndexType: IndexFlat})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(flatStore.Upsert(docs, vectors), convey.ShouldBeNil)
+
+			var hits, total int
+			for _, query := range randomVectors(rng, 50, 16) {
+				for _, filter := range []Filter{nil, MatchMetaData(map[string]any{"group": 1})} {
+					req := &SearchRequest{Vector: query, TopK: 10, Filter: filter}
+					expected, err := flatStore.Search(req)
+					convey.So(err, convey.ShouldBeNil)
+					got, err := hnswStore.Search(req)
+					convey.So(err, convey.ShouldBeNil)
+					convey.So(got, convey.ShouldHaveLength, 10)
+
+					want := map[string]bool{}
+					for _, id := range docIDs(expected) {
+						want[id] = true
+					}
+					for _, doc := range got {
+						if filter != nil {
+							convey.So(doc.MetaData["group"], convey.ShouldEqual, 1)
+						}
+						if want[doc.ID] {
+							hits++
+						}
+					}
+					total += len(expected)
+				}
+			}
+			convey.So(float64(hits)/float64(total), convey.ShouldBeGreaterThan, 0.95)
+		})
+
+		convey.Convey("test filter and threshold", func() {
+			s, err := NewStore(&StoreConfig{Seed: 1})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(s.Upsert(randomDocs(8), [][]float64{
+				{1, 0}, {0.9, 0.1}, {0.8, 0.2}, {0.7, 0.3}, {0.5, 0.5}, {0.3, 0.7}, {0.1, 0.9}, {0, 1},
+			}), convey.ShouldBeNil)
+
+			got, err := s.Search(&SearchRequest{Vector: []float64{1, 0}, TopK: 3})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docIDs(got), convey.ShouldResemble, []string{"doc-0", "doc-1", "doc-2"})
+
+			got, err = s.Search(&SearchRequest{Vector: []float64{1, 0}, TopK: 3, Filter: MatchMetaData(map[string]any{"group": int64(3)})})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docIDs(got), convey.ShouldResemble, []string{"doc-3", "doc-7"})
+
+			threshold := 0.95
+			got, err = s.Search(&SearchRequest{Vector: []float64{1, 0}, TopK: 8, ScoreThreshold: &threshold})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docIDs(got), convey.ShouldResemble, []string{"doc-0", "doc-1", "doc-2"})
+
+			_, err = s.Search(&SearchRequest{Vector: []float64{1, 0, 0}, TopK: 3})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = s.Search(&SearchRequest{Vector: []float64{1, 0}})
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test upsert, delete and update", func() {
+			s, err := NewStore(&StoreConfig{Seed: 1})
+			convey.So(err, convey.ShouldBeNil)
+
+			convey.So(s.Upsert([]*schema.Document{{}}, [][]float64{{1}}), convey.ShouldNotBeNil)
+			convey.So(s.Upsert([]*schema.Document{{ID: "a"}}, nil), convey.ShouldNotBeNil)
+			convey.So(s.Upsert([]*schema.Document{{ID: "a"}, {ID: "b"}}, [][]float64{{1, 0}, {1}}), convey.ShouldNotBeNil)
+			convey.So(s.Len(), convey.ShouldEqual, 0)
+
+			convey.So(s.Upsert(randomDocs(3), [][]float64{{1, 0}, {0, 1}, {-1, 0}}), convey.ShouldBeNil)
+			convey.So(s.Dimension(), convey.ShouldEqual, 2)
+			convey.So(s.Upsert([]*schema.Document{{ID: "doc-2", Content: "moved"}}, [][]float64{{0.9, 0.1}}), convey.ShouldBeNil)
+			convey.So(s.Len(), convey.ShouldEqual, 3)
+
+			got, err := s.Search(&SearchRequest{Vector: []float64{1, 0}, TopK: 2})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docIDs(got), convey.ShouldResemble, []string{"doc-0", "doc-2"})
+			convey.So(got[1].Content, convey.ShouldEqual, "moved")
+
+			convey.So(s.UpdateMetaData("doc-0", map[string]any{"group": nil, "tag": "x"}), convey.ShouldBeNil)
+			convey.So(s.UpdateMetaData("missing", map[string]any{"tag": "x"}), convey.ShouldNotBeNil)
+			convey.So(s.Get("doc-0", "missing"), convey.ShouldResemble, []*schema.Document{
+				{ID: "doc-0", Content: "content 0", MetaData: map[string]any{"tag": "x"}},
+			})
+
+			// Returned documents are copies.
+			got[0].MetaData["tag"] = "y"
+			convey.So(s.Get("doc-0")[0].MetaData["tag"], convey.ShouldEqual, "x")
+
+			convey.So(s.Delete("doc-0", "missing"), convey.ShouldEqual, 1)
+			convey.So(s.DeleteByFilter(MatchMetaData(map[string]any{"group": 1})), convey.ShouldEqual, 1)
+			got, err = s.Search(&SearchRequest{Vector: []float64{1, 0}, TopK: 3})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docIDs(got), convey.ShouldResemble, []string{"doc-2"})
+		})
+
+		convey.Convey("test compaction", func() {
+			s, err := NewStore(&StoreConfig{Seed: 1})
+			convey.So(err, convey.ShouldBeNil)
+			rng := rand.New(rand.NewSource(2))
+			docs := randomDocs(100)
+			for round := 0; round < 5; round++ {
+				convey.So(s.Upsert(docs, randomVectors(rng, 100, 4)), convey.ShouldBeNil)
+				convey.So(s.graph.deleted, convey.ShouldBeLessThanOrEqualTo, s.Len())
+			}
+			convey.So(s.Len(), convey.ShouldEqual, 100)
+
+			got, err := s.Search(&SearchRequest{Vector: []float64{1, 1, 1, 1}, TopK: 100})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(got, convey.ShouldHaveLength, 100)
+		})
+
+		convey.Convey("test concurrent reads and writes", func() {
+			s, err := NewStore(&StoreConfig{Seed: 1})
+			convey.So(err, convey.ShouldBeNil)
+			docs, vectors := randomDocs(200), randomVectors(rand.New(rand.NewSource(3)), 200, 8)
+			convey.So(s.Upsert(docs[:100], vectors[:100]), convey.ShouldBeNil)
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 100)
+			for w := 0; w < 4; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 100 + w; i < 200; i += 4 {
+						if err := s.Upsert(docs[i:i+1], vectors[i:i+1]); err != nil {
+							errs <- err
+						}
+						s.Delete(docs[i-100].ID)
+					}
+				}(w)
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := w; i < 200; i += 4 {
+						if _, err := s.Search(&SearchRequest{Vector: vectors[i], TopK: 5}); err != nil {
+							errs <- err
+						}
+						_ = s.SaveTo(&bytes.Buffer{})
+					}
+				}(w)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				convey.So(err, convey.ShouldBeNil)
+			}
+			convey.So(s.Len(), convey.ShouldEqual, 100)
+		})
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	convey.Convey("test snapshot", t, func() {
+		rng := rand.New(rand.NewSource(4))
+		docs, vectors := randomDocs(300), randomVectors(rng, 300, 8)
+		queries := randomVectors(rng, 10, 8)
+
+		for _, indexType := range []IndexType{IndexHNSW, IndexFlat} {
+			s, err := NewStore(&StoreConfig{IndexType: indexType, Metric: MetricL2, Seed: 1})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(s.Upsert(docs, vectors), convey.ShouldBeNil)
+			convey.So(s.Delete("doc-1", "doc-2"), convey.ShouldEqual, 2)
+			convey.So(s.UpdateMetaData("doc-3", map[string]any{"tags": []string{"a"}}), convey.ShouldBeNil)
+
+			path := filepath.Join(t.TempDir(), "store.snapshot")
+			convey.So(s.Save(path), convey.ShouldBeNil)
+			loaded, err := LoadStore(path)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(loaded.Config(), convey.ShouldResemble, s.Config())
+			convey.So(loaded.Len(), convey.ShouldEqual, 298)
+			convey.So(loaded.Get("doc-3")[0].MetaData, convey.ShouldResemble, map[string]any{
+				"group": float64(3),
+				"tags":  []any{"a"},
+			})
+
+			filter := MatchMetaData(map[string]any{"group": 2})
+			for _, query := range queries {
+				req := &SearchRequest{Vector: query, TopK: 5, Filter: filter}
+				expected, err := s.Search(req)
+				convey.So(err, convey.ShouldBeNil)
+				got, err := loaded.Search(req)
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(docIDs(got), convey.ShouldResemble, docIDs(expected))
+			}
+
+			// The loaded store keeps accepting writes.
+			convey.So(loaded.Upsert(docs[1:2], vectors[1:2]), convey.ShouldBeNil)
+			got, err := loaded.Search(&SearchRequest{Vector: vectors[1], TopK: 1})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docIDs(got), convey.ShouldResemble, []string{"doc-1"})
+		}
+
+		_, err := LoadStore(filepath.Join(t.TempDir(), "missing"))
+		convey.So(err, convey.ShouldNotBeNil)
+		_, err = LoadStoreFrom(bytes.NewReader([]byte("not a snapshot")))
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
@@ -2,10 +2,13 @@ module github.com/cloudwego/eino-ext/components/indexer/milvus
 
 go 1.23.0
 
+replace github.com/cloudwego/eino-ext/components/indexer/lifecycle => ../lifecycle
+
 require (
 	github.com/bytedance/mockey v1.2.12
 	github.com/bytedance/sonic v1.14.1
 	github.com/cloudwego/eino v0.6.0
+	github.com/cloudwego/eino-ext/components/indexer/lifecycle v0.1.0
 	github.com/milvus-io/milvus-sdk-go/v2 v2.4.2
 	github.com/smartystreets/goconvey v1.8.1
 )
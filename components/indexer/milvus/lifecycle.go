@@ -0,0 +1,224 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino-ext/components/indexer/lifecycle"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+var _ lifecycle.DocumentManager = (*Indexer)(nil)
+
+// The lifecycle methods rely on the default fields: id, content, vector and metadata.
+
+// DeleteByIDs deletes the documents with the given IDs.
+func (i *Indexer) DeleteByIDs(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := i.config.Client.DeleteByPks(ctx, i.config.Collection, "", entity.NewColumnVarChar(defaultCollectionID, ids)); err != nil {
+		return fmt.Errorf("[Indexer.DeleteByIDs] failed to delete documents: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByMetaData deletes the documents whose metadata equals every value of the filter.
+// Values of filter must be strings, numbers or booleans.
+func (i *Indexer) DeleteByMetaData(ctx context.Context, filter map[string]any) error {
+	expr, err := metadataExpr(filter)
+	if err != nil {
+		return fmt.Errorf("[Indexer.DeleteByMetaData] %w", err)
+	}
+
+	if err := i.config.Client.Delete(ctx, i.config.Collection, "", expr); err != nil {
+		return fmt.Errorf("[Indexer.DeleteByMetaData] failed to delete documents: %w", err)
+	}
+
+	return nil
+}
+
+// GetByIDs returns the documents with the given IDs.
+func (i *Indexer) GetByIDs(ctx context.Context, ids []string) ([]*schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	results, err := i.config.Client.QueryByPks(ctx, i.config.Collection, nil,
+		entity.NewColumnVarChar(defaultCollectionID, ids),
+		[]string{defaultCollectionID, defaultCollectionContent, defaultCollectionMetadata})
+	if err != nil {
+		return nil, fmt.Errorf("[Indexer.GetByIDs] failed to query documents: %w", err)
+	}
+
+	found, err := resultsToDocuments(results)
+	if err != nil {
+		return nil, fmt.Errorf("[Indexer.GetByIDs] %w", err)
+	}
+
+	docs := make([]*schema.Document, 0, len(found))
+	for _, id := range ids {
+		if doc, ok := found[id]; ok {
+			docs = append(docs, doc)
+		}
+	}
+
+	return docs, nil
+}
+
+// UpdateMetaData merges metaData into the metadata of the document.
+// The stored vector is written back as it is, so the document is not embedded again.
+func (i *Indexer) UpdateMetaData(ctx context.Context, id string, metaData map[string]any) error {
+	results, err := i.config.Client.QueryByPks(ctx, i.config.Collection, nil,
+		entity.NewColumnVarChar(defaultCollectionID, []string{id}),
+		[]string{defaultCollectionID, defaultCollectionContent, defaultCollectionVector, defaultCollectionMetadata})
+	if err != nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] failed to query document: %w", err)
+	}
+	if results.Len() == 0 {
+		return fmt.Errorf("[Indexer.UpdateMetaData] document not found, id: %s", id)
+	}
+
+	found, err := resultsToDocuments(results)
+	if err != nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] %w", err)
+	}
+	doc := found[id]
+	if doc == nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] document not found, id: %s", id)
+	}
+	for k, v := range metaData {
+		doc.MetaData[k] = v
+	}
+
+	vector := results.GetColumn(defaultCollectionVector)
+	if vector == nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] vector field not found")
+	}
+	metadata, err := sonic.Marshal(doc.MetaData)
+	if err != nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] failed to marshal metadata: %w", err)
+	}
+
+	if _, err = i.config.Client.Upsert(ctx, i.config.Collection, i.config.PartitionName,
+		entity.NewColumnVarChar(defaultCollectionID, []string{doc.ID}),
+		entity.NewColumnVarChar(defaultCollectionContent, []string{doc.Content}),
+		vector,
+		entity.NewColumnJSONBytes(defaultCollectionMetadata, [][]byte{metadata}),
+	); err != nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] failed to upsert document: %w", err)
+	}
+
+	if err = i.config.Client.Flush(ctx, i.config.Collection, false); err != nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] failed to flush collection: %w", err)
+	}
+
+	return nil
+}
+
+// resultsToDocuments converts the queried rows to documents keyed by ID.
+func resultsToDocuments(results client.ResultSet) (map[string]*schema.Document, error) {
+	ids := results.GetColumn(defaultCollectionID)
+	if ids == nil {
+		return nil, nil
+	}
+
+	docs := make(map[string]*schema.Document, ids.Len())
+	for idx := 0; idx < ids.Len(); idx++ {
+		id, err := ids.GetAsString(idx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get id: %w", err)
+		}
+		doc := &schema.Document{
+			ID:       id,
+			MetaData: make(map[string]any),
+		}
+
+		if content := results.GetColumn(defaultCollectionContent); content != nil {
+			if doc.Content, err = content.GetAsString(idx); err != nil {
+				return nil, fmt.Errorf("failed to get content: %w", err)
+			}
+		}
+
+		if metadata := results.GetColumn(defaultCollectionMetadata); metadata != nil {
+			b, err := metadata.Get(idx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get metadata: %w", err)
+			}
+			bytes, ok := b.([]byte)
+			if !ok {
+				return nil, fmt.Errorf("unexpected metadata type %T, id: %s", b, id)
+			}
+			if len(bytes) > 0 {
+				if err := sonic.Unmarshal(bytes, &doc.MetaData); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+				}
+			}
+			if doc.MetaData == nil {
+				doc.MetaData = make(map[string]any)
+			}
+		}
+
+		docs[id] = doc
+	}
+
+	return docs, nil
+}
+
+// metadataExpr builds the boolean expression matching every key - value pair of the filter
+// against the metadata field.
+func metadataExpr(filter map[string]any) (string, error) {
+	if len(filter) == 0 {
+		return "", fmt.Errorf("filter is empty")
+	}
+
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	conds := make([]string, 0, len(keys))
+	for _, k := range keys {
+		switch filter[k].(type) {
+		case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		default:
+			return "", fmt.Errorf("unsupported filter value type %T, key: %s", filter[k], k)
+		}
+
+		key, err := sonic.MarshalString(k)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal filter key: %w", err)
+		}
+		val, err := sonic.MarshalString(filter[k])
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal filter value: %w", err)
+		}
+		conds = append(conds, fmt.Sprintf("%s[%s] == %s", defaultCollectionMetadata, key, val))
+	}
+
+	return strings.Join(conds, " and "), nil
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetadataExpr(t *testing.T) {
+	convey.Convey("test metadataExpr", t, func() {
+		expr, err := metadataExpr(map[string]any{
+			"parent_id": `a "b".md`,
+			"chunk":     3,
+			"final":     true,
+		})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(expr, convey.ShouldEqual,
+			`metadata["chunk"] == 3 and metadata["final"] == true and metadata["parent_id"] == "a \"b\".md"`)
+
+		_, err = metadataExpr(nil)
+		convey.So(err, convey.ShouldNotBeNil)
+
+		_, err = metadataExpr(map[string]any{"tags": []string{"a"}})
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
+
+func TestResultsToDocuments(t *testing.T) {
+	convey.Convey("test resultsToDocuments", t, func() {
+		docs, err := resultsToDocuments(client.ResultSet{
+			entity.NewColumnVarChar(defaultCollectionID, []string{"1", "2"}),
+			entity.NewColumnVarChar(defaultCollectionContent, []string{"asd", "qwe"}),
+			entity.NewColumnJSONBytes(defaultCollectionMetadata, [][]byte{[]byte(`{"parent_id":"a.md"}`), []byte(`{}`)}),
+		})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(docs, convey.ShouldResemble, map[string]*schema.Document{
+			"1": {ID: "1", Content: "asd", MetaData: map[string]any{"parent_id": "a.md"}},
+			"2": {ID: "2", Content: "qwe", MetaData: map[string]any{}},
+		})
+
+		docs, err = resultsToDocuments(client.ResultSet{})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(docs, convey.ShouldBeEmpty)
+
+		_, err = resultsToDocuments(client.ResultSet{
+			entity.NewColumnVarChar(defaultCollectionID, []string{"1"}),
+			entity.NewColumnVarChar(defaultCollectionMetadata, []string{`{}`}),
+		})
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldEqual, "unexpected metadata type string, id: 1")
+
+		_, err = resultsToDocuments(client.ResultSet{
+			entity.NewColumnVarChar(defaultCollectionID, []string{"1", "2"}),
+			entity.NewColumnJSONBytes(defaultCollectionMetadata, [][]byte{[]byte(`{}`)}),
+		})
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldStartWith, "failed to get metadata: ")
+	})
+}
+
+func TestLifecycle(t *testing.T) {
+	PatchConvey("test lifecycle", t, func() {
+		ctx := context.Background()
+		Mock(client.NewClient).Return(&client.GrpcClient{}, nil).Build()
+		mockClient, _ := client.NewClient(ctx, client.Config{})
+		i := &Indexer{config: IndexerConfig{Client: mockClient, Collection: defaultCollection}}
+
+		PatchConvey("test DeleteByIDs", func() {
+			var pks []string
+			Mock(GetMethod(mockClient, "DeleteByPks")).To(func(ctx context.Context, collName string, partitionName string, ids entity.Column) error {
+				convey.So(collName, convey.ShouldEqual, defaultCollection)
+				convey.So(ids.Name(), convey.ShouldEqual, defaultCollectionID)
+				pks = ids.(*entity.ColumnVarChar).Data()
+				return nil
+			}).Build()
+
+			convey.So(i.DeleteByIDs(ctx, nil), convey.ShouldBeNil)
+			convey.So(pks, convey.ShouldBeNil)
+			convey.So(i.DeleteByIDs(ctx, []string{"1", "2"}), convey.ShouldBeNil)
+			convey.So(pks, convey.ShouldResemble, []string{"1", "2"})
+		})
+
+		PatchConvey("test DeleteByMetaData", func() {
+			var got string
+			Mock(GetMethod(mockClient, "Delete")).To(func(ctx context.Context, collName string, partitionName string, expr string) error {
+				got = expr
+				return nil
+			}).Build()
+
+			convey.So(i.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"}), convey.ShouldBeNil)
+			convey.So(got, convey.ShouldEqual, `metadata["parent_id"] == "a.md"`)
+			convey.So(i.DeleteByMetaData(ctx, nil), convey.ShouldBeError)
+		})
+
+		PatchConvey("test GetByIDs", func() {
+			Mock(GetMethod(mockClient, "QueryByPks")).To(func(ctx context.Context, collectionName string, partitionNames []string, ids entity.Column,
+				outputFields []string, opts ...client.SearchQueryOptionFunc) (client.ResultSet, error) {
+				convey.So(ids.(*entity.ColumnVarChar).Data(), convey.ShouldResemble, []string{"2", "3", "1"})
+				convey.So(outputFields, convey.ShouldResemble, []string{defaultCollectionID, defaultCollectionContent, defaultCollectionMetadata})
+				// rows are not returned in the order of the IDs and missing IDs are absent
+				return client.ResultSet{
+					entity.NewColumnVarChar(defaultCollectionID, []string{"1", "2"}),
+					entity.NewColumnVarChar(defaultCollectionContent, []string{"asd", "qwe"}),
+					entity.NewColumnJSONBytes(defaultCollectionMetadata, [][]byte{[]byte(`{}`), []byte(`{"parent_id":"a.md"}`)}),
+				}, nil
+			}).Build()
+
+			docs, err := i.GetByIDs(ctx, []string{"2", "3", "1"})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldResemble, []*schema.Document{
+				{ID: "2", Content: "qwe", MetaData: map[string]any{"parent_id": "a.md"}},
+				{ID: "1", Content: "asd", MetaData: map[string]any{}},
+			})
+		})
+
+		PatchConvey("test UpdateMetaData", func() {
+			vector := entity.NewColumnBinaryVector(defaultCollectionVector, 8, [][]byte{{1}})
+
+			PatchConvey("test document not found", func() {
+				Mock(GetMethod(mockClient, "QueryByPks")).Return(client.ResultSet{}, nil).Build()
+				err := i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"})
+				convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.UpdateMetaData] document not found, id: 1"))
+			})
+
+			PatchConvey("test upsert with the stored vector", func() {
+				Mock(GetMethod(mockClient, "QueryByPks")).Return(client.ResultSet{
+					entity.NewColumnVarChar(defaultCollectionID, []string{"1"}),
+					entity.NewColumnVarChar(defaultCollectionContent, []string{"asd"}),
+					vector,
+					entity.NewColumnJSONBytes(defaultCollectionMetadata, [][]byte{[]byte(`{"parent_id":"a.md","tag":"y"}`)}),
+				}, nil).Build()
+
+				var upserted []entity.Column
+				Mock(GetMethod(mockClient, "Upsert")).To(func(ctx context.Context, collName string, partitionName string, columns ...entity.Column) (entity.Column, error) {
+					upserted = columns
+					return columns[0], nil
+				}).Build()
+				flush := Mock(GetMethod(mockClient, "Flush")).Return(nil).Build()
+
+				convey.So(i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"}), convey.ShouldBeNil)
+				convey.So(len(upserted), convey.ShouldEqual, 4)
+				convey.So(upserted[0].(*entity.ColumnVarChar).Data(), convey.ShouldResemble, []string{"1"})
+				convey.So(upserted[1].(*entity.ColumnVarChar).Data(), convey.ShouldResemble, []string{"asd"})
+				convey.So(upserted[2], convey.ShouldEqual, vector)
+				var metadata map[string]any
+				convey.So(sonic.Unmarshal(upserted[3].(*entity.ColumnJSONBytes).Data()[0], &metadata), convey.ShouldBeNil)
+				convey.So(metadata, convey.ShouldResemble, map[string]any{"parent_id": "a.md", "tag": "x"})
+				convey.So(flush.Times(), convey.ShouldEqual, 1)
+			})
+
+			PatchConvey("test upsert failed", func() {
+				Mock(GetMethod(mockClient, "QueryByPks")).Return(client.ResultSet{
+					entity.NewColumnVarChar(defaultCollectionID, []string{"1"}),
+					entity.NewColumnVarChar(defaultCollectionContent, []string{"asd"}),
+					vector,
+					entity.NewColumnJSONBytes(defaultCollectionMetadata, [][]byte{[]byte(`{}`)}),
+				}, nil).Build()
+				Mock(GetMethod(mockClient, "Upsert")).Return(nil, fmt.Errorf("upsert error")).Build()
+
+				err := i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"})
+				convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.UpdateMetaData] failed to upsert document: upsert error"))
+			})
+		})
+	})
+}
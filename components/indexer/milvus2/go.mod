@@ -2,10 +2,13 @@ module github.com/cloudwego/eino-ext/components/indexer/milvus2
 
 go 1.24.6
 
+replace github.com/cloudwego/eino-ext/components/indexer/lifecycle => ../lifecycle
+
 require (
 	github.com/bytedance/mockey v1.4.0
 	github.com/bytedance/sonic v1.14.1
 	github.com/cloudwego/eino v0.6.0
+	github.com/cloudwego/eino-ext/components/indexer/lifecycle v0.1.0
 	github.com/milvus-io/milvus/client/v2 v2.6.1
 	github.com/smartystreets/goconvey v1.8.1
 )
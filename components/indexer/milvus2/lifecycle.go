@@ -0,0 +1,263 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino-ext/components/indexer/lifecycle"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+var _ lifecycle.DocumentManager = (*Indexer)(nil)
+
+// DeleteByIDs deletes the documents with the given IDs.
+func (i *Indexer) DeleteByIDs(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	deleteOpt := milvusclient.NewDeleteOption(i.config.Collection).
+		WithStringIDs(defaultIDField, ids)
+	if _, err := i.client.Delete(ctx, deleteOpt); err != nil {
+		return fmt.Errorf("[Indexer.DeleteByIDs] failed to delete documents: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByMetaData deletes the documents whose metadata equals every value of the filter.
+// Values of filter must be strings, numbers or booleans.
+func (i *Indexer) DeleteByMetaData(ctx context.Context, filter map[string]any) error {
+	expr, err := metadataExpr(filter)
+	if err != nil {
+		return fmt.Errorf("[Indexer.DeleteByMetaData] %w", err)
+	}
+
+	deleteOpt := milvusclient.NewDeleteOption(i.config.Collection).WithExpr(expr)
+	if _, err := i.client.Delete(ctx, deleteOpt); err != nil {
+		return fmt.Errorf("[Indexer.DeleteByMetaData] failed to delete documents: %w", err)
+	}
+
+	return nil
+}
+
+// GetByIDs returns the documents with the given IDs.
+func (i *Indexer) GetByIDs(ctx context.Context, ids []string) ([]*schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	result, err := i.queryByIDs(ctx, ids, defaultIDField, defaultContentField, defaultMetadataField)
+	if err != nil {
+		return nil, fmt.Errorf("[Indexer.GetByIDs] %w", err)
+	}
+
+	found, err := resultToDocuments(result)
+	if err != nil {
+		return nil, fmt.Errorf("[Indexer.GetByIDs] %w", err)
+	}
+
+	docs := make([]*schema.Document, 0, len(found))
+	for _, id := range ids {
+		if doc, ok := found[id]; ok {
+			docs = append(docs, doc)
+		}
+	}
+
+	return docs, nil
+}
+
+// UpdateMetaData merges metaData into the metadata of the document.
+// The stored vectors are written back as they are, so the document is not embedded again.
+// It relies on the default fields, as written by the default DocumentConverter.
+func (i *Indexer) UpdateMetaData(ctx context.Context, id string, metaData map[string]any) error {
+	outputFields := []string{defaultIDField, defaultContentField, defaultMetadataField}
+	var vectorFields []string
+	if i.config.Vector != nil {
+		vectorFields = append(vectorFields, i.config.Vector.VectorField)
+	}
+	// Sparse vectors generated by functions are computed again by Milvus.
+	if i.config.Sparse != nil && i.config.Sparse.Method == SparseMethodPrecomputed {
+		vectorFields = append(vectorFields, i.config.Sparse.VectorField)
+	}
+	outputFields = append(outputFields, vectorFields...)
+
+	result, err := i.queryByIDs(ctx, []string{id}, outputFields...)
+	if err != nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] %w", err)
+	}
+
+	found, err := resultToDocuments(result)
+	if err != nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] %w", err)
+	}
+	doc, ok := found[id]
+	if !ok {
+		return fmt.Errorf("[Indexer.UpdateMetaData] document not found, id: %s", id)
+	}
+	for k, v := range metaData {
+		doc.MetaData[k] = v
+	}
+
+	metadata, err := sonic.Marshal(doc.MetaData)
+	if err != nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] failed to marshal metadata: %w", err)
+	}
+
+	upsertOpt := milvusclient.NewColumnBasedInsertOption(i.config.Collection).
+		WithColumns(
+			column.NewColumnVarChar(defaultIDField, []string{doc.ID}),
+			column.NewColumnVarChar(defaultContentField, []string{doc.Content}),
+			column.NewColumnJSONBytes(defaultMetadataField, [][]byte{metadata}),
+		)
+	if i.config.PartitionName != "" {
+		upsertOpt = upsertOpt.WithPartition(i.config.PartitionName)
+	}
+	for _, field := range vectorFields {
+		col := resultColumn(result, field)
+		if col == nil {
+			return fmt.Errorf("[Indexer.UpdateMetaData] vector field %s not found", field)
+		}
+		upsertOpt = upsertOpt.WithColumns(col)
+	}
+
+	if _, err = i.client.Upsert(ctx, upsertOpt); err != nil {
+		return fmt.Errorf("[Indexer.UpdateMetaData] failed to upsert document: %w", err)
+	}
+
+	return nil
+}
+
+func (i *Indexer) queryByIDs(ctx context.Context, ids []string, outputFields ...string) (milvusclient.ResultSet, error) {
+	expr, err := sonic.MarshalString(ids)
+	if err != nil {
+		return milvusclient.ResultSet{}, fmt.Errorf("failed to marshal ids: %w", err)
+	}
+
+	queryOpt := milvusclient.NewQueryOption(i.config.Collection).
+		WithFilter(defaultIDField + " in " + expr).
+		WithOutputFields(outputFields...)
+	if i.config.ConsistencyLevel != ConsistencyLevelDefault {
+		queryOpt = queryOpt.WithConsistencyLevel(i.config.ConsistencyLevel.ToEntity())
+	}
+
+	result, err := i.client.Query(ctx, queryOpt)
+	if err != nil {
+		return milvusclient.ResultSet{}, fmt.Errorf("failed to query documents: %w", err)
+	}
+
+	return result, nil
+}
+
+// resultToDocuments converts the queried rows to documents keyed by ID.
+func resultToDocuments(result milvusclient.ResultSet) (map[string]*schema.Document, error) {
+	docs := make(map[string]*schema.Document, result.ResultCount)
+
+	ids := resultColumn(result, defaultIDField)
+	if ids == nil {
+		return docs, nil
+	}
+	content := resultColumn(result, defaultContentField)
+	metadata := resultColumn(result, defaultMetadataField)
+
+	for idx := 0; idx < ids.Len(); idx++ {
+		id, err := ids.GetAsString(idx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get id: %w", err)
+		}
+		doc := &schema.Document{
+			ID:       id,
+			MetaData: make(map[string]any),
+		}
+
+		if content != nil {
+			if doc.Content, err = content.GetAsString(idx); err != nil {
+				return nil, fmt.Errorf("failed to get content: %w", err)
+			}
+		}
+
+		if metadata != nil {
+			val, err := metadata.Get(idx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get metadata: %w", err)
+			}
+			if metaBytes, ok := val.([]byte); ok && len(metaBytes) > 0 {
+				if err := sonic.Unmarshal(metaBytes, &doc.MetaData); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+				}
+			}
+			if doc.MetaData == nil {
+				doc.MetaData = make(map[string]any)
+			}
+		}
+
+		docs[id] = doc
+	}
+
+	return docs, nil
+}
+
+func resultColumn(result milvusclient.ResultSet, name string) column.Column {
+	for _, field := range result.Fields {
+		if field.Name() == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// metadataExpr builds the boolean expression matching every key-value pair of the filter
+// against the metadata field.
+func metadataExpr(filter map[string]any) (string, error) {
+	if len(filter) == 0 {
+		return "", fmt.Errorf("filter is empty")
+	}
+
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	conds := make([]string, 0, len(keys))
+	for _, k := range keys {
+		switch filter[k].(type) {
+		case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		default:
+			return "", fmt.Errorf("unsupported filter value type %T, key: %s", filter[k], k)
+		}
+
+		key, err := sonic.MarshalString(k)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal filter key: %w", err)
+		}
+		val, err := sonic.MarshalString(filter[k])
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal filter value: %w", err)
+		}
+		conds = append(conds, fmt.Sprintf("%s[%s] == %s", defaultMetadataField, key, val))
+	}
+
+	return strings.Join(conds, " and "), nil
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetadataExpr(t *testing.T) {
+	convey.Convey("test metadataExpr", t, func() {
+		expr, err := metadataExpr(map[string]any{
+			"parent_id": `a "b".md`,
+			"chunk":     3,
+			"final":     true,
+		})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(expr, convey.ShouldEqual,
+			`metadata["chunk"] == 3 and metadata["final"] == true and metadata["parent_id"] == "a \"b\".md"`)
+
+		_, err = metadataExpr(nil)
+		convey.So(err, convey.ShouldNotBeNil)
+
+		_, err = metadataExpr(map[string]any{"tags": []string{"a"}})
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
+
+func TestResultToDocuments(t *testing.T) {
+	convey.Convey("test resultToDocuments", t, func() {
+		result := milvusclient.ResultSet{
+			ResultCount: 2,
+			Fields: []column.Column{
+				column.NewColumnVarChar(defaultIDField, []string{"1", "2"}),
+				column.NewColumnVarChar(defaultContentField, []string{"asd", "qwe"}),
+				column.NewColumnJSONBytes(defaultMetadataField, [][]byte{[]byte(`{"parent_id":"a.md"}`), []byte(`{}`)}),
+			},
+		}
+		docs, err := resultToDocuments(result)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(docs, convey.ShouldResemble, map[string]*schema.Document{
+			"1": {ID: "1", Content: "asd", MetaData: map[string]any{"parent_id": "a.md"}},
+			"2": {ID: "2", Content: "qwe", MetaData: map[string]any{}},
+		})
+		convey.So(resultColumn(result, defaultContentField), convey.ShouldNotBeNil)
+		convey.So(resultColumn(result, defaultVectorField), convey.ShouldBeNil)
+
+		docs, err = resultToDocuments(milvusclient.ResultSet{})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(docs, convey.ShouldBeEmpty)
+	})
+}
+
+func TestLifecycle(t *testing.T) {
+	PatchConvey("test lifecycle", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		i := &Indexer{
+			client: mockClient,
+			config: &IndexerConfig{
+				Collection: "test_collection",
+				Vector:     &VectorConfig{VectorField: defaultVectorField},
+			},
+		}
+
+		PatchConvey("test DeleteByIDs", func() {
+			del := Mock(GetMethod(mockClient, "Delete")).Return(milvusclient.DeleteResult{}, nil).Build()
+			convey.So(i.DeleteByIDs(ctx, nil), convey.ShouldBeNil)
+			convey.So(del.Times(), convey.ShouldEqual, 0)
+			convey.So(i.DeleteByIDs(ctx, []string{"1", "2"}), convey.ShouldBeNil)
+			convey.So(del.Times(), convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test DeleteByMetaData", func() {
+			Mock(GetMethod(mockClient, "Delete")).Return(milvusclient.DeleteResult{}, fmt.Errorf("delete error")).Build()
+			err := i.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.DeleteByMetaData] failed to delete documents: delete error"))
+			convey.So(i.DeleteByMetaData(ctx, nil), convey.ShouldBeError)
+		})
+
+		PatchConvey("test GetByIDs", func() {
+			// rows are not returned in the order of the IDs and missing IDs are absent
+			Mock(GetMethod(mockClient, "Query")).Return(milvusclient.ResultSet{
+				ResultCount: 2,
+				Fields: []column.Column{
+					column.NewColumnVarChar(defaultIDField, []string{"1", "2"}),
+					column.NewColumnVarChar(defaultContentField, []string{"asd", "qwe"}),
+					column.NewColumnJSONBytes(defaultMetadataField, [][]byte{[]byte(`{}`), []byte(`{"parent_id":"a.md"}`)}),
+				},
+			}, nil).Build()
+
+			docs, err := i.GetByIDs(ctx, []string{"2", "3", "1"})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldResemble, []*schema.Document{
+				{ID: "2", Content: "qwe", MetaData: map[string]any{"parent_id": "a.md"}},
+				{ID: "1", Content: "asd", MetaData: map[string]any{}},
+			})
+		})
+
+		PatchConvey("test UpdateMetaData", func() {
+			PatchConvey("test document not found", func() {
+				Mock(GetMethod(mockClient, "Query")).Return(milvusclient.ResultSet{}, nil).Build()
+				upsert := Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{}, nil).Build()
+				err := i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"})
+				convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.UpdateMetaData] document not found, id: 1"))
+				convey.So(upsert.Times(), convey.ShouldEqual, 0)
+			})
+
+			PatchConvey("test vector not returned", func() {
+				Mock(GetMethod(mockClient, "Query")).Return(milvusclient.ResultSet{
+					ResultCount: 1,
+					Fields: []column.Column{
+						column.NewColumnVarChar(defaultIDField, []string{"1"}),
+						column.NewColumnVarChar(defaultContentField, []string{"asd"}),
+						column.NewColumnJSONBytes(defaultMetadataField, [][]byte{[]byte(`{}`)}),
+					},
+				}, nil).Build()
+				upsert := Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{}, nil).Build()
+				err := i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"})
+				convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.UpdateMetaData] vector field %s not found", defaultVectorField))
+				convey.So(upsert.Times(), convey.ShouldEqual, 0)
+			})
+
+			PatchConvey("test upsert", func() {
+				Mock(GetMethod(mockClient, "Query")).Return(milvusclient.ResultSet{
+					ResultCount: 1,
+					Fields: []column.Column{
+						column.NewColumnVarChar(defaultIDField, []string{"1"}),
+						column.NewColumnVarChar(defaultContentField, []string{"asd"}),
+						column.NewColumnJSONBytes(defaultMetadataField, [][]byte{[]byte(`{}`)}),
+						column.NewColumnFloatVector(defaultVectorField, 2, [][]float32{{0.1, 0.2}}),
+					},
+				}, nil).Build()
+
+				PatchConvey("test success", func() {
+					upsert := Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{}, nil).Build()
+					convey.So(i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"}), convey.ShouldBeNil)
+					convey.So(upsert.Times(), convey.ShouldEqual, 1)
+				})
+
+				PatchConvey("test upsert failed", func() {
+					Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{}, fmt.Errorf("upsert error")).Build()
+					err := i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"})
+					convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.UpdateMetaData] failed to upsert document: upsert error"))
+				})
+			})
+		})
+	})
+}
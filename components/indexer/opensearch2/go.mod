@@ -2,9 +2,12 @@ module github.com/cloudwego/eino-ext/components/indexer/opensearch2
 
 go 1.18
 
+replace github.com/cloudwego/eino-ext/components/indexer/lifecycle => ../lifecycle
+
 require (
 	github.com/bytedance/mockey v1.3.2
 	github.com/cloudwego/eino v0.9.2
+	github.com/cloudwego/eino-ext/components/indexer/lifecycle v0.1.0
 	github.com/opensearch-project/opensearch-go/v2 v2.3.0
 	github.com/smartystreets/goconvey v1.8.1
 )
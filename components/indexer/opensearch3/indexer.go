@@ -48,6 +48,9 @@ type IndexerConfig struct {
 	// DocumentToFields maps an Eino document to OpenSearch fields.
 	// It allows customization of how documents are stored and vectored.
 	DocumentToFields func(ctx context.Context, doc *schema.Document) (field2Value map[string]FieldValue, err error)
+	// FieldsToDocument converts the source fields of a stored OpenSearch document back to an Eino document.
+	// It is used by GetByIDs. Default puts all the source fields into MetaData.
+	FieldsToDocument func(ctx context.Context, id string, fields map[string]any) (*schema.Document, error)
 	// Embedding is the embedding model used for vectorization.
 	// It is required if any field provided by DocumentToFields requires vectorization (specifically, if FieldValue.EmbedKey is not empty).
 	// This typically applies when:
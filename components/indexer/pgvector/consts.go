@@ -0,0 +1,62 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pgvector
+
+const typ = "PGVector"
+
+const (
+	defaultBatchSize = 5
+
+	// default column names, as created by TableSpec and written by the default DocumentToFields.
+	defaultIDColumn         = "id"
+	defaultContentColumn    = "content"
+	defaultMetaDataColumn   = "metadata"
+	defaultVectorColumn     = "embedding"
+	defaultTextSearchColumn = "content_tsv"
+
+	defaultTextSearchConfig = "simple"
+)
+
+// Distance is the distance operator class of the vector index.
+type Distance string
+
+const (
+	// DistanceCosine indexes by cosine distance, operator <=>.
+	DistanceCosine Distance = "cosine"
+	// DistanceL2 indexes by euclidean distance, operator <->.
+	DistanceL2 Distance = "l2"
+	// DistanceInnerProduct indexes by negative inner product, operator <#>.
+	DistanceInnerProduct Distance = "inner_product"
+)
+
+var distanceOpClasses = map[Distance]string{
+	DistanceCosine:       "vector_cosine_ops",
+	DistanceL2:           "vector_l2_ops",
+	DistanceInnerProduct: "vector_ip_ops",
+}
+
+// IndexType is the type of the vector index.
+type IndexType string
+
+const (
+	// IndexHNSW builds an HNSW index, slower to build but faster and more accurate to search.
+	IndexHNSW IndexType = "hnsw"
+	// IndexIVFFlat builds an IVFFlat index, which should be created after the table has data.
+	IndexIVFFlat IndexType = "ivfflat"
+	// IndexNone builds no vector index, searches scan the table.
+	IndexNone IndexType = "none"
+)
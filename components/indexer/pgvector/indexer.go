@@ -0,0 +1,384 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pgvector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+	"github.com/jackc/pgx/v5"
+)
+
+// IndexerConfig contains configuration for the pgvector indexer.
+type IndexerConfig struct {
+	// Client executes the statements, e.g. a *pgxpool.Pool.
+	Client Querier
+	// Table is the name of the table, which may be qualified by a schema, e.g. public.documents.
+	Table string `json:"table"`
+	// TableSpec, if provided, creates the table and its indexes if they do not exist.
+	TableSpec *TableSpec `json:"table_spec"`
+	// IDColumn is the primary key column, which resolves conflicts when storing documents with existing IDs.
+	// Default "id".
+	IDColumn string `json:"id_column"`
+	// MetaDataColumn is the JSONB column of metadata, used by DeleteByMetaData and UpdateMetaData.
+	// Default "metadata".
+	MetaDataColumn string `json:"metadata_column"`
+	// BatchSize specifies the maximum number of documents to embed in a single batch.
+	// Default is 5.
+	BatchSize int `json:"batch_size"`
+	// DocumentToFields maps an Eino document to columns.
+	// Default defaultDocumentToFields, which writes the columns created by TableSpec:
+	// id, content, metadata, and embedding of the content, or the dense vector of the document if it has one.
+	DocumentToFields func(ctx context.Context, doc *schema.Document) (field2Value map[string]FieldValue, err error)
+	// FieldsToDocument converts the columns of a stored row back to an Eino document, used by GetByIDs.
+	// Default defaultFieldsToDocument, which reads the columns written by defaultDocumentToFields.
+	FieldsToDocument func(ctx context.Context, id string, fields map[string]any) (*schema.Document, error)
+	// Embedding is the embedding model used for vectorization.
+	// It is required if any field provided by DocumentToFields requires vectorization (specifically, if FieldValue.EmbedKey is not empty).
+	Embedding embedding.Embedder
+}
+
+// FieldValue represents the value of a column.
+type FieldValue struct {
+	// Value is the actual data to be stored.
+	// []float64 and []float32 are written as vectors, maps and slices of other types as json.
+	Value any
+	// EmbedKey, if set, causes the Value to be vectorized and stored in the vector column named by EmbedKey.
+	// If Stringify method is provided, Embedding input text will be Stringify(Value).
+	// If Stringify method not set, retriever will try to assert Value as string.
+	EmbedKey string
+	// Stringify converts the Value to a string for embedding.
+	Stringify func(val any) (string, error)
+}
+
+// Indexer implements the [indexer.Indexer] interface for PostgreSQL with the pgvector extension.
+type Indexer struct {
+	client Querier
+	config *IndexerConfig
+}
+
+// NewIndexer creates a new pgvector indexer with the provided configuration.
+// It creates the table if TableSpec is provided.
+func NewIndexer(ctx context.Context, conf *IndexerConfig) (*Indexer, error) {
+	if conf.Client == nil {
+		return nil, fmt.Errorf("[NewIndexer] postgres client not provided")
+	}
+
+	if conf.Table == "" {
+		return nil, fmt.Errorf("[NewIndexer] table not provided")
+	}
+
+	if conf.BatchSize == 0 {
+		conf.BatchSize = defaultBatchSize
+	}
+
+	if conf.IDColumn == "" {
+		conf.IDColumn = defaultIDColumn
+	}
+
+	if conf.MetaDataColumn == "" {
+		conf.MetaDataColumn = defaultMetaDataColumn
+	}
+
+	if conf.DocumentToFields == nil {
+		conf.DocumentToFields = defaultDocumentToFields
+	}
+
+	if conf.FieldsToDocument == nil {
+		conf.FieldsToDocument = defaultFieldsToDocument
+	}
+
+	if conf.TableSpec != nil {
+		if err := createTable(ctx, conf.Client, conf.Table, conf.TableSpec); err != nil {
+			return nil, fmt.Errorf("[NewIndexer] create table failed, %w", err)
+		}
+	}
+
+	return &Indexer{
+		client: conf.Client,
+		config: conf,
+	}, nil
+}
+
+// Store upserts the provided documents into the table.
+// It returns the list of IDs for the stored documents or an error.
+func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
+	ctx = callbacks.EnsureRunInfo(ctx, i.GetType(), components.ComponentOfIndexer)
+	ctx = callbacks.OnStart(ctx, &indexer.CallbackInput{Docs: docs})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	options := indexer.GetCommonOptions(&indexer.Options{
+		Embedding: i.config.Embedding,
+	}, opts...)
+
+	if err = i.batchUpsert(ctx, docs, options); err != nil {
+		return nil, err
+	}
+
+	ids = iter(docs, func(t *schema.Document) string { return t.ID })
+
+	callbacks.OnEnd(ctx, &indexer.CallbackOutput{IDs: ids})
+
+	return ids, nil
+}
+
+func (i *Indexer) batchUpsert(ctx context.Context, docs []*schema.Document, options *indexer.Options) error {
+	emb := options.Embedding
+
+	var (
+		batch  = &pgx.Batch{}
+		tuples []tuple
+		texts  []string
+	)
+
+	embAndQueue := func() error {
+		var vectors [][]float64
+
+		if len(texts) > 0 {
+			if emb == nil {
+				return fmt.Errorf("[batchUpsert] embedding method not provided")
+			}
+
+			var err error
+			vectors, err = emb.EmbedStrings(i.makeEmbeddingCtx(ctx, emb), texts)
+			if err != nil {
+				return fmt.Errorf("[batchUpsert] embedding failed, %w", err)
+			}
+
+			if len(vectors) != len(texts) {
+				return fmt.Errorf("[batchUpsert] invalid vector length, expected=%d, got=%d", len(texts), len(vectors))
+			}
+		}
+
+		for _, t := range tuples {
+			fields := t.fields
+			for k, idx := range t.key2Idx {
+				fields[k] = vectors[idx]
+			}
+
+			sql, args, err := upsertStatement(i.config.Table, i.config.IDColumn, fields)
+			if err != nil {
+				return fmt.Errorf("[batchUpsert] build upsert statement failed, id=%s, %w", t.id, err)
+			}
+			batch.Queue(sql, args...)
+		}
+
+		tuples = tuples[:0]
+		texts = texts[:0]
+
+		return nil
+	}
+
+	for idx := range docs {
+		doc := docs[idx]
+		fields, err := i.config.DocumentToFields(ctx, doc)
+		if err != nil {
+			return fmt.Errorf("[batchUpsert] FieldMapping failed, %w", err)
+		}
+
+		rawFields := make(map[string]any, len(fields))
+		embSize := 0
+		for k, v := range fields {
+			rawFields[k] = v.Value
+			if v.EmbedKey != "" {
+				embSize++
+			}
+		}
+
+		if embSize > i.config.BatchSize {
+			return fmt.Errorf("[batchUpsert] needEmbeddingFields length over batch size, batch size=%d, got size=%d",
+				i.config.BatchSize, embSize)
+		}
+
+		if len(texts)+embSize > i.config.BatchSize {
+			if err = embAndQueue(); err != nil {
+				return err
+			}
+		}
+
+		key2Idx := make(map[string]int, embSize)
+		for k, v := range fields {
+			if v.EmbedKey != "" {
+				if _, found := fields[v.EmbedKey]; found {
+					return fmt.Errorf("[batchUpsert] duplicate key for origin key, key=%s", k)
+				}
+
+				if _, found := key2Idx[v.EmbedKey]; found {
+					return fmt.Errorf("[batchUpsert] duplicate key from embed_key, key=%s", v.EmbedKey)
+				}
+
+				var text string
+				if v.Stringify != nil {
+					text, err = v.Stringify(v.Value)
+					if err != nil {
+						return err
+					}
+				} else {
+					var ok bool
+					text, ok = v.Value.(string)
+					if !ok {
+						return fmt.Errorf("[batchUpsert] assert value as string failed, key=%s, emb_key=%s", k, v.EmbedKey)
+					}
+				}
+
+				key2Idx[v.EmbedKey] = len(texts)
+				texts = append(texts, text)
+			}
+		}
+
+		tuples = append(tuples, tuple{
+			id:      doc.ID,
+			fields:  rawFields,
+			key2Idx: key2Idx,
+		})
+	}
+
+	if len(tuples) > 0 {
+		if err := embAndQueue(); err != nil {
+			return err
+		}
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	// Statements of a batch run in an implicit transaction, so either all the documents are stored or none.
+	br := i.client.SendBatch(ctx, batch)
+	for n := 0; n < batch.Len(); n++ {
+		if _, err := br.Exec(); err != nil {
+			_ = br.Close()
+			return fmt.Errorf("[batchUpsert] upsert failed, %w", err)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("[batchUpsert] upsert failed, %w", err)
+	}
+
+	return nil
+}
+
+// upsertStatement builds the statement inserting the fields as a row, or
+// updating the row with the same ID.
+func upsertStatement(table, idColumn string, fields map[string]any) (string, []any, error) {
+	if _, ok := fields[idColumn]; !ok {
+		return "", nil, fmt.Errorf("id column %s not in fields", idColumn)
+	}
+
+	columns := sortedKeys(fields)
+	var (
+		quotedColumns = make([]string, 0, len(columns))
+		placeholders  = make([]string, 0, len(columns))
+		updates       []string
+		args          = make([]any, 0, len(columns))
+	)
+	for n, column := range columns {
+		quoted := quoteColumn(column)
+		quotedColumns = append(quotedColumns, quoted)
+
+		placeholder := fmt.Sprintf("$%d", n+1)
+		switch v := fields[column].(type) {
+		case []float64:
+			args = append(args, vectorLiteral(v))
+			placeholder += "::vector"
+		case []float32:
+			args = append(args, vectorLiteral(v))
+			placeholder += "::vector"
+		default:
+			args = append(args, v)
+		}
+		placeholders = append(placeholders, placeholder)
+
+		if column != idColumn {
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", quoted, quoted))
+		}
+	}
+
+	onConflict := "DO NOTHING"
+	if len(updates) > 0 {
+		onConflict = "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) %s",
+		quoteTable(table), strings.Join(quotedColumns, ", "), strings.Join(placeholders, ", "),
+		quoteColumn(idColumn), onConflict)
+	return sql, args, nil
+}
+
+func (i *Indexer) makeEmbeddingCtx(ctx context.Context, emb embedding.Embedder) context.Context {
+	runInfo := &callbacks.RunInfo{
+		Component: components.ComponentOfEmbedding,
+	}
+
+	if embType, ok := components.GetType(emb); ok {
+		runInfo.Type = embType
+	}
+
+	runInfo.Name = runInfo.Type + string(runInfo.Component)
+
+	return callbacks.ReuseHandlers(ctx, runInfo)
+}
+
+// GetType returns the type of the indexer.
+func (i *Indexer) GetType() string {
+	return typ
+}
+
+// IsCallbacksEnabled checks if callbacks are enabled for this indexer.
+func (i *Indexer) IsCallbacksEnabled() bool {
+	return true
+}
+
+type tuple struct {
+	id      string
+	fields  map[string]any
+	key2Idx map[string]int
+}
+
+func defaultDocumentToFields(ctx context.Context, doc *schema.Document) (map[string]FieldValue, error) {
+	if doc.ID == "" {
+		return nil, fmt.Errorf("[defaultDocumentToFields] doc id not set")
+	}
+
+	metaData := doc.MetaData
+	if metaData == nil {
+		metaData = map[string]any{}
+	}
+
+	fields := map[string]FieldValue{
+		defaultIDColumn:       {Value: doc.ID},
+		defaultMetaDataColumn: {Value: metaData},
+	}
+	if vector := doc.DenseVector(); len(vector) > 0 {
+		fields[defaultContentColumn] = FieldValue{Value: doc.Content}
+		fields[defaultVectorColumn] = FieldValue{Value: vector}
+	} else {
+		fields[defaultContentColumn] = FieldValue{Value: doc.Content, EmbedKey: defaultVectorColumn}
+	}
+	return fields, nil
+}
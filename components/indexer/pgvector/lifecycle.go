@@ -0,0 +1,144 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pgvector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino-ext/components/indexer/lifecycle"
+	"github.com/cloudwego/eino/schema"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ lifecycle.DocumentManager = (*Indexer)(nil)
+
+// DeleteByIDs deletes the rows with the given IDs.
+func (i *Indexer) DeleteByIDs(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sql := fmt.Sprintf("DELETE FROM %s WHERE %s = ANY($1)", quoteTable(i.config.Table), quoteColumn(i.config.IDColumn))
+	if _, err := i.client.Exec(ctx, sql, ids); err != nil {
+		return fmt.Errorf("[DeleteByIDs] delete failed, %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByMetaData deletes the rows whose metadata contains every key - value pair of the filter.
+func (i *Indexer) DeleteByMetaData(ctx context.Context, filter map[string]any) error {
+	if len(filter) == 0 {
+		return fmt.Errorf("[DeleteByMetaData] filter is empty")
+	}
+
+	b, err := json.Marshal(filter)
+	if err != nil {
+		return fmt.Errorf("[DeleteByMetaData] marshal filter failed, %w", err)
+	}
+
+	sql := fmt.Sprintf("DELETE FROM %s WHERE %s @> $1::jsonb", quoteTable(i.config.Table), quoteColumn(i.config.MetaDataColumn))
+	if _, err = i.client.Exec(ctx, sql, string(b)); err != nil {
+		return fmt.Errorf("[DeleteByMetaData] delete failed, %w", err)
+	}
+
+	return nil
+}
+
+// GetByIDs returns the documents with the given IDs, in the order of the IDs.
+// Missing IDs are skipped.
+func (i *Indexer) GetByIDs(ctx context.Context, ids []string) ([]*schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM %s WHERE %s = ANY($1)", quoteTable(i.config.Table), quoteColumn(i.config.IDColumn))
+	rows, err := i.client.Query(ctx, sql, ids)
+	if err != nil {
+		return nil, fmt.Errorf("[GetByIDs] select failed, %w", err)
+	}
+
+	records, err := pgx.CollectRows(rows, pgx.RowToMap)
+	if err != nil {
+		return nil, fmt.Errorf("[GetByIDs] scan rows failed, %w", err)
+	}
+
+	id2Fields := make(map[string]map[string]any, len(records))
+	for _, fields := range records {
+		id := fmt.Sprint(fields[i.config.IDColumn])
+		id2Fields[id] = fields
+	}
+
+	docs := make([]*schema.Document, 0, len(records))
+	for _, id := range ids {
+		fields, ok := id2Fields[id]
+		if !ok {
+			continue
+		}
+
+		doc, err := i.config.FieldsToDocument(ctx, id, fields)
+		if err != nil {
+			return nil, fmt.Errorf("[GetByIDs] FieldsToDocument failed, id=%s, %w", id, err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// UpdateMetaData merges metaData into the metadata of the row with the given ID.
+// Keys in metaData overwrite the existing ones, the other keys are kept.
+func (i *Indexer) UpdateMetaData(ctx context.Context, id string, metaData map[string]any) error {
+	b, err := json.Marshal(metaData)
+	if err != nil {
+		return fmt.Errorf("[UpdateMetaData] marshal metadata failed, %w", err)
+	}
+
+	column := quoteColumn(i.config.MetaDataColumn)
+	sql := fmt.Sprintf("UPDATE %s SET %s = coalesce(%s, '{}'::jsonb) || $1::jsonb WHERE %s = $2",
+		quoteTable(i.config.Table), column, column, quoteColumn(i.config.IDColumn))
+	tag, err := i.client.Exec(ctx, sql, string(b), id)
+	if err != nil {
+		return fmt.Errorf("[UpdateMetaData] update failed, %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("[UpdateMetaData] document not found, id=%s", id)
+	}
+
+	return nil
+}
+
+// defaultFieldsToDocument reads the columns written by defaultDocumentToFields.
+func defaultFieldsToDocument(ctx context.Context, id string, fields map[string]any) (*schema.Document, error) {
+	doc := &schema.Document{
+		ID:       id,
+		MetaData: map[string]any{},
+	}
+
+	if content, ok := fields[defaultContentColumn].(string); ok {
+		doc.Content = content
+	}
+
+	if metaData, ok := fields[defaultMetaDataColumn].(map[string]any); ok {
+		doc.MetaData = metaData
+	}
+
+	return doc, nil
+}
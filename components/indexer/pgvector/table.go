@@ -0,0 +1,159 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pgvector
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TableSpec describes the table to create if it does not exist, with the
+// default columns:
+//
+//	id          TEXT PRIMARY KEY
+//	content     TEXT
+//	metadata    JSONB
+//	embedding   vector(Dimension)
+//	content_tsv tsvector, generated from content for full-text search
+//
+// along with a vector index on embedding, a GIN index on content_tsv and a GIN
+// index on metadata. Use ExtraColumns for the other fields of DocumentToFields.
+type TableSpec struct {
+	// Dimension of the embedding column.
+	Dimension int `json:"dimension"`
+	// Distance operator class of the vector index, which must match the Distance of the retriever.
+	// Default DistanceCosine.
+	Distance Distance `json:"distance"`
+	// IndexType of the vector index.
+	// Default IndexHNSW.
+	IndexType IndexType `json:"index_type"`
+	// M max number of connections per layer of HNSW index, 0 uses the pgvector default 16.
+	M int `json:"m"`
+	// EfConstruction size of the candidate list when building HNSW index, 0 uses the pgvector default 64.
+	EfConstruction int `json:"ef_construction"`
+	// Lists number of inverted lists of IVFFlat index.
+	// Default 100.
+	Lists int `json:"lists"`
+	// TextSearchConfig text search configuration of content_tsv, e.g. english, which must match the retriever.
+	// Default simple.
+	TextSearchConfig string `json:"text_search_config"`
+	// ExtraColumns column name - type pairs added to the table, e.g. {"title": "TEXT"}.
+	ExtraColumns map[string]string `json:"extra_columns"`
+}
+
+var indexNameRegexp = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// createTableStatements returns the statements creating the extension, the
+// table and its indexes if they don't exist.
+func createTableStatements(table string, spec *TableSpec) ([]string, error) {
+	if spec.Dimension <= 0 {
+		return nil, fmt.Errorf("invalid dimension: %d", spec.Dimension)
+	}
+
+	distance := spec.Distance
+	if distance == "" {
+		distance = DistanceCosine
+	}
+	opClass, ok := distanceOpClasses[distance]
+	if !ok {
+		return nil, fmt.Errorf("unknown distance: %s", distance)
+	}
+
+	tsConfig := spec.TextSearchConfig
+	if tsConfig == "" {
+		tsConfig = defaultTextSearchConfig
+	}
+	tsConfigLiteral, err := textSearchConfigLiteral(tsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	quoted := quoteTable(table)
+	columns := []string{
+		fmt.Sprintf("%s TEXT PRIMARY KEY", quoteColumn(defaultIDColumn)),
+		fmt.Sprintf("%s TEXT", quoteColumn(defaultContentColumn)),
+		fmt.Sprintf("%s JSONB NOT NULL DEFAULT '{}'", quoteColumn(defaultMetaDataColumn)),
+		fmt.Sprintf("%s vector(%d)", quoteColumn(defaultVectorColumn), spec.Dimension),
+		fmt.Sprintf("%s tsvector GENERATED ALWAYS AS (to_tsvector(%s, coalesce(%s, ''))) STORED",
+			quoteColumn(defaultTextSearchColumn), tsConfigLiteral, quoteColumn(defaultContentColumn)),
+	}
+	for _, name := range sortedKeys(spec.ExtraColumns) {
+		columns = append(columns, fmt.Sprintf("%s %s", quoteColumn(name), spec.ExtraColumns[name]))
+	}
+
+	// Index names are unqualified, they are created in the schema of the table.
+	parts := strings.Split(table, ".")
+	prefix := indexNameRegexp.ReplaceAllString(parts[len(parts)-1], "_")
+	indexName := func(column string) string {
+		return quoteColumn(prefix + "_" + column + "_idx")
+	}
+
+	stmts := []string{
+		"CREATE EXTENSION IF NOT EXISTS vector",
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", quoted, strings.Join(columns, ",\n\t")),
+	}
+
+	switch spec.IndexType {
+	case "", IndexHNSW:
+		var params []string
+		if spec.M > 0 {
+			params = append(params, fmt.Sprintf("m = %d", spec.M))
+		}
+		if spec.EfConstruction > 0 {
+			params = append(params, fmt.Sprintf("ef_construction = %d", spec.EfConstruction))
+		}
+		stmt := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING hnsw (%s %s)",
+			indexName(defaultVectorColumn), quoted, quoteColumn(defaultVectorColumn), opClass)
+		if len(params) > 0 {
+			stmt += " WITH (" + strings.Join(params, ", ") + ")"
+		}
+		stmts = append(stmts, stmt)
+	case IndexIVFFlat:
+		lists := spec.Lists
+		if lists <= 0 {
+			lists = 100
+		}
+		stmts = append(stmts, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING ivfflat (%s %s) WITH (lists = %d)",
+			indexName(defaultVectorColumn), quoted, quoteColumn(defaultVectorColumn), opClass, lists))
+	case IndexNone:
+	default:
+		return nil, fmt.Errorf("unknown index type: %s", spec.IndexType)
+	}
+
+	stmts = append(stmts,
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING gin (%s)",
+			indexName(defaultTextSearchColumn), quoted, quoteColumn(defaultTextSearchColumn)),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING gin (%s jsonb_path_ops)",
+			indexName(defaultMetaDataColumn), quoted, quoteColumn(defaultMetaDataColumn)),
+	)
+	return stmts, nil
+}
+
+func createTable(ctx context.Context, client Querier, table string, spec *TableSpec) error {
+	stmts, err := createTableStatements(table, spec)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err = client.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q failed, %w", stmt, err)
+		}
+	}
+	return nil
+}
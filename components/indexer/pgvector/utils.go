@@ -0,0 +1,95 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pgvector
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Querier is the subset of pgx methods used by the indexer, implemented by
+// *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// GetType returns the type of the indexer.
+func GetType() string {
+	return typ
+}
+
+// quoteTable quotes a table name which may be qualified by a schema, e.g. public.documents.
+func quoteTable(table string) string {
+	return pgx.Identifier(strings.Split(table, ".")).Sanitize()
+}
+
+func quoteColumn(column string) string {
+	return pgx.Identifier{column}.Sanitize()
+}
+
+var textSearchConfigRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// textSearchConfigLiteral returns the text search configuration as a
+// regconfig literal, which is inlined into generated columns.
+func textSearchConfigLiteral(config string) (string, error) {
+	if !textSearchConfigRegexp.MatchString(config) {
+		return "", fmt.Errorf("invalid text search config: %q", config)
+	}
+	return "'" + config + "'::regconfig", nil
+}
+
+// vectorLiteral formats the vector as the text representation of the pgvector
+// type, e.g. [1,2,3], which is cast with ::vector.
+func vectorLiteral[T float32 | float64](vector []T) string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+func iter[T, D any](src []T, fn func(T) D) []D {
+	resp := make([]D, len(src))
+	for i := range src {
+		resp[i] = fn(src[i])
+	}
+
+	return resp
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
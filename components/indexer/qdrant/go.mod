@@ -2,9 +2,12 @@ module github.com/cloudwego/eino-ext/components/indexer/qdrant
 
 go 1.23.0
 
+replace github.com/cloudwego/eino-ext/components/indexer/lifecycle => ../lifecycle
+
 require (
 	github.com/bytedance/mockey v1.2.14
 	github.com/cloudwego/eino v0.6.0
+	github.com/cloudwego/eino-ext/components/indexer/lifecycle v0.1.0
 	github.com/google/uuid v1.6.0
 	github.com/qdrant/go-client v1.15.2
 	github.com/smartystreets/goconvey v1.8.1
@@ -0,0 +1,213 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qdrant
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cloudwego/eino-ext/components/indexer/lifecycle"
+	"github.com/cloudwego/eino/schema"
+	qdrant "github.com/qdrant/go-client/qdrant"
+)
+
+var _ lifecycle.DocumentManager = (*Indexer)(nil)
+
+// DeleteByIDs deletes the points with the given IDs.
+func (i *Indexer) DeleteByIDs(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := i.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: i.collection,
+		Wait:           qdrant.PtrOf(true),
+		Points:         qdrant.NewPointsSelector(pointIDs(ids)...),
+	})
+	if err != nil {
+		return fmt.Errorf("[DeleteByIDs] failed to delete points from qdrant, %w", err)
+	}
+	return nil
+}
+
+// DeleteByMetaData deletes the points whose metadata equals every value of the filter.
+// Values of filter must be strings, integers or booleans.
+func (i *Indexer) DeleteByMetaData(ctx context.Context, filter map[string]any) error {
+	f, err := metadataFilter(filter)
+	if err != nil {
+		return fmt.Errorf("[DeleteByMetaData] %w", err)
+	}
+
+	_, err = i.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: i.collection,
+		Wait:           qdrant.PtrOf(true),
+		Points:         qdrant.NewPointsSelectorFilter(f),
+	})
+	if err != nil {
+		return fmt.Errorf("[DeleteByMetaData] failed to delete points from qdrant, %w", err)
+	}
+	return nil
+}
+
+// GetByIDs returns the documents of the points with the given IDs.
+func (i *Indexer) GetByIDs(ctx context.Context, ids []string) ([]*schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	points, err := i.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: i.collection,
+		Ids:            pointIDs(ids),
+		WithPayload:    qdrant.NewWithPayload(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[GetByIDs] failed to get points from qdrant, %w", err)
+	}
+
+	found := make(map[string]*schema.Document, len(points))
+	for _, pt := range points {
+		doc := pointToDocument(pt)
+		found[doc.ID] = doc
+	}
+
+	docs := make([]*schema.Document, 0, len(found))
+	for _, id := range ids {
+		if doc, ok := found[id]; ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// UpdateMetaData merges metaData into the metadata payload of the point, the vector is kept.
+func (i *Indexer) UpdateMetaData(ctx context.Context, id string, metaData map[string]any) error {
+	points, err := i.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: i.collection,
+		Ids:            pointIDs([]string{id}),
+		WithPayload:    qdrant.NewWithPayload(false),
+	})
+	if err != nil {
+		return fmt.Errorf("[UpdateMetaData] failed to get point from qdrant, %w", err)
+	}
+	if len(points) == 0 {
+		return fmt.Errorf("[UpdateMetaData] point not found, id=%s", id)
+	}
+
+	if len(metaData) == 0 {
+		return nil
+	}
+
+	payload, err := qdrant.TryValueMap(metaData)
+	if err != nil {
+		return fmt.Errorf("[UpdateMetaData] invalid metadata, %w", err)
+	}
+
+	_, err = i.client.SetPayload(ctx, &qdrant.SetPayloadPoints{
+		CollectionName: i.collection,
+		Wait:           qdrant.PtrOf(true),
+		Payload:        payload,
+		PointsSelector: qdrant.NewPointsSelector(pointIDs([]string{id})...),
+		Key:            qdrant.PtrOf(defaultMetadataKey),
+	})
+	if err != nil {
+		return fmt.Errorf("[UpdateMetaData] failed to set payload to qdrant, %w", err)
+	}
+	return nil
+}
+
+func pointIDs(ids []string) []*qdrant.PointId {
+	pids := make([]*qdrant.PointId, len(ids))
+	for idx, id := range ids {
+		pids[idx] = qdrant.NewID(id)
+	}
+	return pids
+}
+
+func pointToDocument(pt *qdrant.RetrievedPoint) *schema.Document {
+	doc := &schema.Document{
+		MetaData: map[string]any{},
+	}
+	if uuid := pt.GetId().GetUuid(); uuid != "" {
+		doc.ID = uuid
+	} else {
+		doc.ID = strconv.FormatUint(pt.GetId().GetNum(), 10)
+	}
+	if val, ok := pt.Payload[defaultContentKey]; ok {
+		doc.Content = val.GetStringValue()
+	}
+	if val, ok := pt.Payload[defaultMetadataKey]; ok {
+		for k, v := range val.GetStructValue().GetFields() {
+			doc.MetaData[k] = valueToAny(v)
+		}
+	}
+	return doc
+}
+
+func valueToAny(v *qdrant.Value) any {
+	switch kind := v.GetKind().(type) {
+	case *qdrant.Value_BoolValue:
+		return kind.BoolValue
+	case *qdrant.Value_IntegerValue:
+		return kind.IntegerValue
+	case *qdrant.Value_DoubleValue:
+		return kind.DoubleValue
+	case *qdrant.Value_StringValue:
+		return kind.StringValue
+	case *qdrant.Value_ListValue:
+		list := make([]any, 0, len(kind.ListValue.GetValues()))
+		for _, item := range kind.ListValue.GetValues() {
+			list = append(list, valueToAny(item))
+		}
+		return list
+	case *qdrant.Value_StructValue:
+		m := make(map[string]any, len(kind.StructValue.GetFields()))
+		for k, item := range kind.StructValue.GetFields() {
+			m[k] = valueToAny(item)
+		}
+		return m
+	default:
+		return nil
+	}
+}
+
+// metadataFilter builds a filter matching every key - value pair against the metadata payload.
+func metadataFilter(filter map[string]any) (*qdrant.Filter, error) {
+	if len(filter) == 0 {
+		return nil, fmt.Errorf("filter is empty")
+	}
+
+	conds := make([]*qdrant.Condition, 0, len(filter))
+	for k, v := range filter {
+		field := defaultMetadataKey + "." + k
+		switch val := v.(type) {
+		case string:
+			conds = append(conds, qdrant.NewMatchKeyword(field, val))
+		case bool:
+			conds = append(conds, qdrant.NewMatchBool(field, val))
+		case int:
+			conds = append(conds, qdrant.NewMatchInt(field, int64(val)))
+		case int32:
+			conds = append(conds, qdrant.NewMatchInt(field, int64(val)))
+		case int64:
+			conds = append(conds, qdrant.NewMatchInt(field, val))
+		default:
+			return nil, fmt.Errorf("unsupported filter value type %T, key=%s", v, k)
+		}
+	}
+	return &qdrant.Filter{Must: conds}, nil
+}
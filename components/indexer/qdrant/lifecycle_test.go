@@ -0,0 +1,159 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qdrant
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/schema"
+	qdrant "github.com/qdrant/go-client/qdrant"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetadataFilter(t *testing.T) {
+	Convey("test metadataFilter", t, func() {
+		f, err := metadataFilter(map[string]any{"parent_id": "a.md"})
+		So(err, ShouldBeNil)
+		So(f.Must, ShouldHaveLength, 1)
+		So(f.Must[0].GetField().GetKey(), ShouldEqual, "metadata.parent_id")
+		So(f.Must[0].GetField().GetMatch().GetKeyword(), ShouldEqual, "a.md")
+
+		f, err = metadataFilter(map[string]any{"chunk": 3, "final": true})
+		So(err, ShouldBeNil)
+		So(f.Must, ShouldHaveLength, 2)
+
+		_, err = metadataFilter(nil)
+		So(err, ShouldNotBeNil)
+
+		_, err = metadataFilter(map[string]any{"score": 0.5})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestPointToDocument(t *testing.T) {
+	Convey("test pointToDocument", t, func() {
+		pt := &qdrant.RetrievedPoint{
+			Id: qdrant.NewID("5c56c793-69f3-4fbf-87e6-c4bf54c28c26"),
+			Payload: qdrant.NewValueMap(map[string]any{
+				defaultContentKey: "asd",
+				defaultMetadataKey: map[string]any{
+					"parent_id": "a.md",
+					"chunk":     3,
+					"tags":      []any{"x", true},
+				},
+			}),
+		}
+		So(pointToDocument(pt), ShouldResemble, &schema.Document{
+			ID:      "5c56c793-69f3-4fbf-87e6-c4bf54c28c26",
+			Content: "asd",
+			MetaData: map[string]any{
+				"parent_id": "a.md",
+				"chunk":     int64(3),
+				"tags":      []any{"x", true},
+			},
+		})
+
+		pt = &qdrant.RetrievedPoint{Id: qdrant.NewIDNum(7)}
+		So(pointToDocument(pt), ShouldResemble, &schema.Document{ID: "7", MetaData: map[string]any{}})
+	})
+}
+
+func TestLifecycle(t *testing.T) {
+	PatchConvey("test lifecycle", t, func() {
+		ctx := context.Background()
+		i := &Indexer{client: &qdrant.Client{}, collection: CollectionName}
+		id := "5c56c793-69f3-4fbf-87e6-c4bf54c28c26"
+
+		PatchConvey("test DeleteByIDs", func() {
+			var req *qdrant.DeletePoints
+			Mock((*qdrant.Client).Delete).To(func(c *qdrant.Client, ctx context.Context, r *qdrant.DeletePoints) (*qdrant.UpdateResult, error) {
+				req = r
+				return &qdrant.UpdateResult{}, nil
+			}).Build()
+
+			So(i.DeleteByIDs(ctx, nil), ShouldBeNil)
+			So(req, ShouldBeNil)
+			So(i.DeleteByIDs(ctx, []string{id}), ShouldBeNil)
+			So(req.GetCollectionName(), ShouldEqual, CollectionName)
+			So(req.GetWait(), ShouldBeTrue)
+			So(req.GetPoints().GetPoints().GetIds(), ShouldHaveLength, 1)
+			So(req.GetPoints().GetPoints().GetIds()[0].GetUuid(), ShouldEqual, id)
+		})
+
+		PatchConvey("test DeleteByMetaData", func() {
+			var req *qdrant.DeletePoints
+			Mock((*qdrant.Client).Delete).To(func(c *qdrant.Client, ctx context.Context, r *qdrant.DeletePoints) (*qdrant.UpdateResult, error) {
+				req = r
+				return nil, fmt.Errorf("delete error")
+			}).Build()
+
+			err := i.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "delete error")
+			must := req.GetPoints().GetFilter().GetMust()
+			So(must, ShouldHaveLength, 1)
+			So(must[0].GetField().GetKey(), ShouldEqual, "metadata.parent_id")
+		})
+
+		PatchConvey("test GetByIDs", func() {
+			var req *qdrant.GetPoints
+			Mock((*qdrant.Client).Get).To(func(c *qdrant.Client, ctx context.Context, r *qdrant.GetPoints) ([]*qdrant.RetrievedPoint, error) {
+				req = r
+				// points are not returned in the order of the IDs and missing IDs are absent
+				return []*qdrant.RetrievedPoint{
+					{Id: qdrant.NewIDNum(1), Payload: qdrant.NewValueMap(map[string]any{defaultContentKey: "asd"})},
+					{Id: qdrant.NewIDNum(2), Payload: qdrant.NewValueMap(map[string]any{defaultContentKey: "qwe"})},
+				}, nil
+			}).Build()
+
+			docs, err := i.GetByIDs(ctx, []string{"2", "3", "1"})
+			So(err, ShouldBeNil)
+			So(docs, ShouldResemble, []*schema.Document{
+				{ID: "2", Content: "qwe", MetaData: map[string]any{}},
+				{ID: "1", Content: "asd", MetaData: map[string]any{}},
+			})
+			So(req.GetIds(), ShouldHaveLength, 3)
+			So(req.GetWithPayload().GetEnable(), ShouldBeTrue)
+		})
+
+		PatchConvey("test UpdateMetaData", func() {
+			var req *qdrant.SetPayloadPoints
+			setPayload := Mock((*qdrant.Client).SetPayload).To(func(c *qdrant.Client, ctx context.Context, r *qdrant.SetPayloadPoints) (*qdrant.UpdateResult, error) {
+				req = r
+				return &qdrant.UpdateResult{}, nil
+			}).Build()
+
+			PatchConvey("test point not found", func() {
+				Mock((*qdrant.Client).Get).Return([]*qdrant.RetrievedPoint{}, nil).Build()
+				err := i.UpdateMetaData(ctx, id, map[string]any{"tag": "x"})
+				So(err, ShouldBeError, fmt.Errorf("[UpdateMetaData] point not found, id=%s", id))
+				So(setPayload.Times(), ShouldEqual, 0)
+			})
+
+			PatchConvey("test set metadata payload", func() {
+				Mock((*qdrant.Client).Get).Return([]*qdrant.RetrievedPoint{{Id: qdrant.NewID(id)}}, nil).Build()
+				So(i.UpdateMetaData(ctx, id, map[string]any{"tag": "x"}), ShouldBeNil)
+				So(req.GetKey(), ShouldEqual, defaultMetadataKey)
+				So(req.GetPayload()["tag"].GetStringValue(), ShouldEqual, "x")
+				So(req.GetPointsSelector().GetPoints().GetIds()[0].GetUuid(), ShouldEqual, id)
+			})
+		})
+	})
+}
@@ -44,6 +44,10 @@ type IndexerConfig struct {
 	// Eventually, command will look like: hset $(KeyPrefix+key) field_1 val_1 field_2 val_2 ...
 	// Default defaultDocumentToFields.
 	DocumentToHashes func(ctx context.Context, doc *schema.Document) (*Hashes, error)
+	// HashesToDocument converts the fields of a stored hash back to a document, used by GetByIDs.
+	// id is the hash key without KeyPrefix.
+	// Default defaultHashesToDocument, which reads content from field "content" and the other fields except "vector_content" as MetaData.
+	HashesToDocument func(ctx context.Context, id string, fields map[string]string) (*schema.Document, error)
 	// BatchSize controls embedding texts size.
 	// Default 10.
 	BatchSize int `json:"batch_size"`
@@ -86,6 +90,10 @@ func NewIndexer(ctx context.Context, config *IndexerConfig) (*Indexer, error) {
 		config.DocumentToHashes = defaultDocumentToFields
 	}
 
+	if config.HashesToDocument == nil {
+		config.HashesToDocument = defaultHashesToDocument
+	}
+
 	if config.BatchSize == 0 {
 		config.BatchSize = 10
 	}
@@ -0,0 +1,254 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cloudwego/eino-ext/components/indexer/lifecycle"
+	"github.com/cloudwego/eino/schema"
+	"github.com/redis/go-redis/v9"
+)
+
+const scanBatchSize = 100
+
+var _ lifecycle.DocumentManager = (*Indexer)(nil)
+
+// DeleteByIDs deletes the hashes of the documents with the given IDs.
+func (i *Indexer) DeleteByIDs(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := i.config.Client.Del(ctx, i.keys(ids)...).Err(); err != nil {
+		return fmt.Errorf("[DeleteByIDs] del failed, %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByMetaData deletes the hashes whose fields equal every value of the filter.
+// Keys of filter are hash fields, as produced by DocumentToHashes.
+// It scans all the keys matching KeyPrefix, so KeyPrefix should be set to limit the scan to this indexer.
+func (i *Indexer) DeleteByMetaData(ctx context.Context, filter map[string]any) error {
+	if len(filter) == 0 {
+		return fmt.Errorf("[DeleteByMetaData] filter is empty")
+	}
+
+	fields := make([]string, 0, len(filter))
+	values := make([]string, 0, len(filter))
+	for k, v := range filter {
+		val, err := hashValue(v)
+		if err != nil {
+			return fmt.Errorf("[DeleteByMetaData] invalid filter value, field=%s, %w", k, err)
+		}
+		fields = append(fields, k)
+		values = append(values, val)
+	}
+
+	deleteMatched := func(keys []string) error {
+		pipeline := i.config.Client.Pipeline()
+		cmds := make([]*redis.SliceCmd, len(keys))
+		for idx, key := range keys {
+			cmds[idx] = pipeline.HMGet(ctx, key, fields...)
+		}
+		if _, err := pipeline.Exec(ctx); err != nil {
+			return fmt.Errorf("[DeleteByMetaData] hmget failed, %w", err)
+		}
+
+		var matched []string
+		for idx, cmd := range cmds {
+			if matchHash(cmd.Val(), values) {
+				matched = append(matched, keys[idx])
+			}
+		}
+		if len(matched) == 0 {
+			return nil
+		}
+
+		if err := i.config.Client.Del(ctx, matched...).Err(); err != nil {
+			return fmt.Errorf("[DeleteByMetaData] del failed, %w", err)
+		}
+		return nil
+	}
+
+	keys := make([]string, 0, scanBatchSize)
+	iter := i.config.Client.ScanType(ctx, 0, i.config.KeyPrefix+"*", scanBatchSize, "hash").Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == scanBatchSize {
+			if err := deleteMatched(keys); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("[DeleteByMetaData] scan failed, %w", err)
+	}
+
+	if len(keys) > 0 {
+		return deleteMatched(keys)
+	}
+
+	return nil
+}
+
+// GetByIDs returns the documents with the given IDs, converted from their hashes by HashesToDocument.
+func (i *Indexer) GetByIDs(ctx context.Context, ids []string) ([]*schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	pipeline := i.config.Client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	for idx, key := range i.keys(ids) {
+		cmds[idx] = pipeline.HGetAll(ctx, key)
+	}
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("[GetByIDs] hgetall failed, %w", err)
+	}
+
+	docs := make([]*schema.Document, 0, len(ids))
+	for idx, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+
+		doc, err := i.config.HashesToDocument(ctx, ids[idx], fields)
+		if err != nil {
+			return nil, fmt.Errorf("[GetByIDs] HashesToDocument failed, id=%s, %w", ids[idx], err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// UpdateMetaData sets the given fields of the hash of the document, other fields and vectors are kept.
+func (i *Indexer) UpdateMetaData(ctx context.Context, id string, metaData map[string]any) error {
+	key := i.config.KeyPrefix + id
+	n, err := i.config.Client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("[UpdateMetaData] exists failed, %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("[UpdateMetaData] document not found, id=%s", id)
+	}
+
+	if len(metaData) == 0 {
+		return nil
+	}
+
+	if err = i.config.Client.HSet(ctx, key, flatten(metaData)...).Err(); err != nil {
+		return fmt.Errorf("[UpdateMetaData] hset failed, %w", err)
+	}
+
+	return nil
+}
+
+func (i *Indexer) keys(ids []string) []string {
+	keys := make([]string, len(ids))
+	for idx, id := range ids {
+		keys[idx] = i.config.KeyPrefix + id
+	}
+	return keys
+}
+
+func defaultHashesToDocument(ctx context.Context, id string, fields map[string]string) (*schema.Document, error) {
+	doc := &schema.Document{
+		ID:       id,
+		Content:  fields[defaultReturnFieldContent],
+		MetaData: make(map[string]any, len(fields)),
+	}
+	for k, v := range fields {
+		if k == defaultReturnFieldContent || k == defaultReturnFieldVectorContent {
+			continue
+		}
+		doc.MetaData[k] = v
+	}
+
+	return doc, nil
+}
+
+func matchHash(got []any, want []string) bool {
+	for idx := range want {
+		s, ok := got[idx].(string)
+		if !ok || s != want[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// hashValue formats v the same way as it is written by hset.
+func hashValue(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case []byte:
+		return string(val), nil
+	case int:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int8:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint64:
+		return strconv.FormatUint(val, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 64), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case bool:
+		if val {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return val.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.FormatInt(val.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		b, err := val.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", v)
+	}
+}
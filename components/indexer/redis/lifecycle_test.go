@@ -0,0 +1,191 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/schema"
+	"github.com/redis/go-redis/v9"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestLifecycle(t *testing.T) {
+	convey.Convey("test lifecycle", t, func() {
+		ctx := context.Background()
+
+		convey.Convey("test hashValue", func() {
+			for v, expected := range map[any]string{
+				nil:                   "",
+				"asd":                 "asd",
+				123:                   "123",
+				int64(-5):             "-5",
+				uint8(7):              "7",
+				1.5:                   "1.5",
+				float32(0.25):         "0.25",
+				true:                  "1",
+				false:                 "0",
+				time.Second:           "1000000000",
+				time.Unix(0, 0).UTC(): "1970-01-01T00:00:00Z",
+			} {
+				got, err := hashValue(v)
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(got, convey.ShouldEqual, expected)
+			}
+
+			_, err := hashValue(map[string]any{})
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test matchHash", func() {
+			convey.So(matchHash([]any{"a", "1"}, []string{"a", "1"}), convey.ShouldBeTrue)
+			convey.So(matchHash([]any{"a", "2"}, []string{"a", "1"}), convey.ShouldBeFalse)
+			convey.So(matchHash([]any{"a", nil}, []string{"a", ""}), convey.ShouldBeFalse)
+		})
+
+		convey.Convey("test defaultHashesToDocument", func() {
+			doc, err := defaultHashesToDocument(ctx, "1", map[string]string{
+				defaultReturnFieldContent:       "asd",
+				defaultReturnFieldVectorContent: "\x00\x00",
+				"parent_id":                     "a.md",
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(doc, convey.ShouldResemble, &schema.Document{
+				ID:       "1",
+				Content:  "asd",
+				MetaData: map[string]any{"parent_id": "a.md"},
+			})
+		})
+
+		convey.Convey("test empty input", func() {
+			i := &Indexer{config: &IndexerConfig{
+				Client:           redis.NewClient(&redis.Options{}),
+				KeyPrefix:        "eino:",
+				HashesToDocument: defaultHashesToDocument,
+			}}
+			convey.So(i.keys([]string{"1", "2"}), convey.ShouldResemble, []string{"eino:1", "eino:2"})
+			convey.So(i.DeleteByIDs(ctx, nil), convey.ShouldBeNil)
+			convey.So(i.DeleteByMetaData(ctx, nil), convey.ShouldBeError)
+			docs, err := i.GetByIDs(ctx, nil)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldBeEmpty)
+		})
+	})
+}
+
+func TestLifecycleCommands(t *testing.T) {
+	PatchConvey("test lifecycle commands", t, func() {
+		ctx := context.Background()
+		mockClient := redis.NewClient(&redis.Options{})
+		i := &Indexer{config: &IndexerConfig{
+			Client:           mockClient,
+			KeyPrefix:        "eino:",
+			HashesToDocument: defaultHashesToDocument,
+		}}
+
+		var deleted []string
+		Mock(GetMethod(mockClient, "Del")).To(func(ctx context.Context, keys ...string) *redis.IntCmd {
+			deleted = append(deleted, keys...)
+			return redis.NewIntResult(int64(len(keys)), nil)
+		}).Build()
+
+		pl := &redis.Pipeline{}
+		Mock(GetMethod(mockClient, "Pipeline")).Return(pl).Build()
+		Mock(GetMethod(pl, "Exec")).Return(nil, nil).Build()
+
+		PatchConvey("test DeleteByIDs", func() {
+			convey.So(i.DeleteByIDs(ctx, []string{"1", "2"}), convey.ShouldBeNil)
+			convey.So(deleted, convey.ShouldResemble, []string{"eino:1", "eino:2"})
+		})
+
+		PatchConvey("test DeleteByMetaData", func() {
+			var scanned string
+			Mock(GetMethod(mockClient, "ScanType")).To(func(ctx context.Context, cursor uint64, match string, count int64, keyType string) *redis.ScanCmd {
+				scanned = match
+				return redis.NewScanCmdResult([]string{"eino:1", "eino:2", "eino:3"}, 0, nil)
+			}).Build()
+			hashes := map[string][]any{
+				"eino:1": {"a.md", "1"},
+				"eino:2": {"b.md", "1"},
+				"eino:3": {"a.md", nil},
+			}
+			Mock(GetMethod(pl, "HMGet")).To(func(ctx context.Context, key string, fields ...string) *redis.SliceCmd {
+				convey.So(fields, convey.ShouldHaveLength, 2)
+				vals := hashes[key]
+				if fields[0] != "parent_id" {
+					vals = []any{vals[1], vals[0]}
+				}
+				return redis.NewSliceResult(vals, nil)
+			}).Build()
+
+			convey.So(i.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md", "final": true}), convey.ShouldBeNil)
+			convey.So(scanned, convey.ShouldEqual, "eino:*")
+			convey.So(deleted, convey.ShouldResemble, []string{"eino:1"})
+		})
+
+		PatchConvey("test DeleteByMetaData scan failed", func() {
+			Mock(GetMethod(mockClient, "ScanType")).Return(redis.NewScanCmdResult(nil, 0, fmt.Errorf("scan error"))).Build()
+			err := i.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[DeleteByMetaData] scan failed, scan error"))
+			convey.So(deleted, convey.ShouldBeEmpty)
+		})
+
+		PatchConvey("test GetByIDs", func() {
+			Mock(GetMethod(pl, "HGetAll")).To(func(ctx context.Context, key string) *redis.MapStringStringCmd {
+				if key == "eino:3" {
+					return redis.NewMapStringStringResult(map[string]string{}, nil)
+				}
+				return redis.NewMapStringStringResult(map[string]string{
+					defaultReturnFieldContent: "content of " + key,
+					"parent_id":               "a.md",
+				}, nil)
+			}).Build()
+
+			docs, err := i.GetByIDs(ctx, []string{"2", "3", "1"})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldResemble, []*schema.Document{
+				{ID: "2", Content: "content of eino:2", MetaData: map[string]any{"parent_id": "a.md"}},
+				{ID: "1", Content: "content of eino:1", MetaData: map[string]any{"parent_id": "a.md"}},
+			})
+		})
+
+		PatchConvey("test UpdateMetaData", func() {
+			var hset []any
+			hsetMocker := Mock(GetMethod(mockClient, "HSet")).To(func(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+				hset = append([]any{key}, values...)
+				return redis.NewIntResult(1, nil)
+			}).Build()
+
+			PatchConvey("test document not found", func() {
+				Mock(GetMethod(mockClient, "Exists")).Return(redis.NewIntResult(0, nil)).Build()
+				err := i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"})
+				convey.So(err, convey.ShouldBeError, fmt.Errorf("[UpdateMetaData] document not found, id=1"))
+				convey.So(hsetMocker.Times(), convey.ShouldEqual, 0)
+			})
+
+			PatchConvey("test hset", func() {
+				Mock(GetMethod(mockClient, "Exists")).Return(redis.NewIntResult(1, nil)).Build()
+				convey.So(i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"}), convey.ShouldBeNil)
+				convey.So(hset, convey.ShouldResemble, []any{"eino:1", "tag", "x"})
+			})
+		})
+	})
+}
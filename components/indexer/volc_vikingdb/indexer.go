@@ -42,6 +42,9 @@ type IndexerConfig struct {
 	ConnectionTimeout int64  `json:"connection_timeout"` // second
 
 	Collection string `json:"collection"`
+	// Index 数据集上的索引名称，用于 DeleteByMetaData 按标量字段检索待删除的数据
+	// 可选，不配置时 DeleteByMetaData 会返回错误
+	Index string `json:"index"`
 
 	// WithMultiModal 如果数据集在平台向量化，需要配置此字段为true，无需再配置EmbeddingConfig
 	WithMultiModal  bool            `json:"with_multi_modal"`
@@ -72,6 +75,7 @@ type Indexer struct {
 	config     *IndexerConfig
 	service    *vikingdb.VikingDBService
 	collection *vikingdb.Collection
+	index      *vikingdb.Index
 	embModel   *vikingdb.EmbModel
 }
 
@@ -102,9 +106,17 @@ func NewIndexer(ctx context.Context, config *IndexerConfig) (*Indexer, error) {
 		config:     config,
 		service:    service,
 		collection: collection,
+		index:      nil,
 		embModel:   nil,
 	}
 
+	if config.Index != "" {
+		i.index, err = service.GetIndex(config.Collection, config.Index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if config.EmbeddingConfig.UseBuiltin {
 		i.embModel = &vikingdb.EmbModel{
 			ModelName: config.EmbeddingConfig.ModelName,
@@ -0,0 +1,205 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package volc_vikingdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/volcengine/volc-sdk-golang/service/vikingdb"
+
+	"github.com/cloudwego/eino-ext/components/indexer/lifecycle"
+	"github.com/cloudwego/eino/schema"
+)
+
+const deleteBatchSize = 100
+
+var _ lifecycle.DocumentManager = (*Indexer)(nil)
+
+// DeleteByIDs deletes the data with the given IDs.
+func (i *Indexer) DeleteByIDs(ctx context.Context, ids []string) error {
+	for _, sub := range chunk(ids, deleteBatchSize) {
+		if err := i.collection.DeleteData(sub); err != nil {
+			return fmt.Errorf("[DeleteByIDs] DeleteData failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByMetaData deletes the data whose scalar fields equal every value of the filter.
+// Keys of filter are fields set by SetExtraDataFields. The data is searched by the index
+// named by IndexerConfig.Index, so the fields must be scalar index fields of it.
+func (i *Indexer) DeleteByMetaData(ctx context.Context, filter map[string]any) error {
+	if len(filter) == 0 {
+		return fmt.Errorf("[DeleteByMetaData] filter is empty")
+	}
+	if i.index == nil {
+		return fmt.Errorf("[DeleteByMetaData] index not provided")
+	}
+
+	dsl := filterDSL(filter)
+	deleted := make(map[string]bool)
+	for {
+		result, err := i.index.Search(nil, vikingdb.NewSearchOptions().
+			SetFilter(dsl).
+			SetLimit(deleteBatchSize))
+		if err != nil {
+			return fmt.Errorf("[DeleteByMetaData] Search failed: %w", err)
+		}
+
+		// The index is updated asynchronously, deleted data may be returned again.
+		ids := make([]string, 0, len(result))
+		for _, data := range result {
+			if id := dataID(data); !deleted[id] {
+				deleted[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			if len(result) < deleteBatchSize {
+				return nil
+			}
+			// A whole page of deleted data may hide matching data which is not seen yet.
+			return fmt.Errorf("[DeleteByMetaData] delete incomplete, search returned only deleted data, deleted=%d", len(deleted))
+		}
+
+		if err = i.collection.DeleteData(ids); err != nil {
+			return fmt.Errorf("[DeleteByMetaData] DeleteData failed: %w", err)
+		}
+	}
+}
+
+// GetByIDs returns the documents of the data with the given IDs.
+// Fields of the data are returned in MetaData as set by SetExtraDataFields.
+func (i *Indexer) GetByIDs(ctx context.Context, ids []string) ([]*schema.Document, error) {
+	found := make(map[string]*schema.Document, len(ids))
+	for _, sub := range chunk(ids, deleteBatchSize) {
+		result, err := i.collection.FetchData(sub)
+		if err != nil {
+			return nil, fmt.Errorf("[GetByIDs] FetchData failed: %w", err)
+		}
+
+		for _, data := range result {
+			if data == nil || len(data.Fields) == 0 {
+				continue
+			}
+			doc := data2Document(data)
+			found[doc.ID] = doc
+		}
+	}
+
+	docs := make([]*schema.Document, 0, len(found))
+	for _, id := range ids {
+		if doc, ok := found[id]; ok {
+			docs = append(docs, doc)
+		}
+	}
+
+	return docs, nil
+}
+
+// UpdateMetaData sets the given scalar fields of the data. The data is fetched and upserted
+// with its vectors and ttl, so it is not embedded again.
+func (i *Indexer) UpdateMetaData(ctx context.Context, id string, metaData map[string]any) error {
+	result, err := i.collection.FetchData(id)
+	if err != nil {
+		return fmt.Errorf("[UpdateMetaData] FetchData failed: %w", err)
+	}
+	if len(result) == 0 || result[0] == nil || len(result[0].Fields) == 0 {
+		return fmt.Errorf("[UpdateMetaData] data not found, id=%s", id)
+	}
+
+	if err = i.collection.UpsertData([]vikingdb.Data{mergeData(result[0], metaData)}); err != nil {
+		return fmt.Errorf("[UpdateMetaData] UpsertData failed: %w", err)
+	}
+
+	return nil
+}
+
+// mergeData returns the data to upsert, with the fields of metaData set and the other fields and ttl kept.
+func mergeData(data *vikingdb.Data, metaData map[string]any) vikingdb.Data {
+	fields := make(map[string]interface{}, len(data.Fields)+len(metaData))
+	for k, v := range data.Fields {
+		fields[k] = v
+	}
+	for k, v := range metaData {
+		fields[k] = v
+	}
+
+	return vikingdb.Data{Fields: fields, TTL: data.TTL}
+}
+
+func dataID(data *vikingdb.Data) string {
+	switch id := data.Id.(type) {
+	case string:
+		return id
+	case int:
+		return strconv.FormatInt(int64(id), 10)
+	case int64:
+		return strconv.FormatInt(id, 10)
+	default:
+		if id, ok := data.Fields[defaultFieldID].(string); ok {
+			return id
+		}
+		return fmt.Sprint(data.Id)
+	}
+}
+
+func data2Document(data *vikingdb.Data) *schema.Document {
+	doc := &schema.Document{
+		ID:       dataID(data),
+		MetaData: map[string]any{},
+	}
+	doc.Content, _ = data.Fields[defaultFieldContent].(string)
+	doc.MetaData[extraKeyVikingDBFields] = data.Fields
+	if data.TTL != 0 {
+		doc.MetaData[extraKeyVikingDBTTL] = data.TTL
+	}
+
+	return doc
+}
+
+// filterDSL builds the filter which requires every field to equal its value.
+// see: https://www.volcengine.com/docs/84313/1254609
+func filterDSL(filter map[string]any) map[string]interface{} {
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	conds := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		conds = append(conds, map[string]interface{}{
+			"op":    "must",
+			"field": k,
+			"conds": []interface{}{filter[k]},
+		})
+	}
+
+	if len(conds) == 1 {
+		return conds[0].(map[string]interface{})
+	}
+
+	return map[string]interface{}{
+		"op":    "and",
+		"conds": conds,
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package volc_vikingdb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/volcengine/volc-sdk-golang/service/vikingdb"
+)
+
+func TestFilterDSL(t *testing.T) {
+	PatchConvey("test filterDSL", t, func() {
+		PatchConvey("test single field", func() {
+			convey.So(filterDSL(map[string]any{"parent_id": "a.md"}), convey.ShouldResemble, map[string]interface{}{
+				"op":    "must",
+				"field": "parent_id",
+				"conds": []interface{}{"a.md"},
+			})
+		})
+
+		PatchConvey("test multiple fields", func() {
+			convey.So(filterDSL(map[string]any{"parent_id": "a.md", "chunk": 3}), convey.ShouldResemble, map[string]interface{}{
+				"op": "and",
+				"conds": []interface{}{
+					map[string]interface{}{"op": "must", "field": "chunk", "conds": []interface{}{3}},
+					map[string]interface{}{"op": "must", "field": "parent_id", "conds": []interface{}{"a.md"}},
+				},
+			})
+		})
+	})
+}
+
+func TestData2Document(t *testing.T) {
+	PatchConvey("test data2Document", t, func() {
+		fields := map[string]interface{}{
+			defaultFieldID:      "1",
+			defaultFieldContent: "asd",
+			"parent_id":         "a.md",
+		}
+		doc := data2Document(&vikingdb.Data{Id: "1", Fields: fields, TTL: 10})
+		convey.So(doc.ID, convey.ShouldEqual, "1")
+		convey.So(doc.Content, convey.ShouldEqual, "asd")
+		convey.So(doc.MetaData[extraKeyVikingDBFields], convey.ShouldResemble, fields)
+		convey.So(doc.MetaData[extraKeyVikingDBTTL], convey.ShouldEqual, int64(10))
+
+		doc = data2Document(&vikingdb.Data{Id: int64(2), Fields: map[string]interface{}{}})
+		convey.So(doc.ID, convey.ShouldEqual, "2")
+	})
+}
+
+func TestDeleteByMetaData(t *testing.T) {
+	PatchConvey("test DeleteByMetaData", t, func() {
+		ctx := context.Background()
+		i := &Indexer{config: &IndexerConfig{}}
+
+		PatchConvey("test empty filter", func() {
+			convey.So(i.DeleteByMetaData(ctx, nil), convey.ShouldBeError)
+		})
+
+		PatchConvey("test index not provided", func() {
+			convey.So(i.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"}), convey.ShouldBeError)
+		})
+
+		i.collection = &vikingdb.Collection{}
+		i.index = &vikingdb.Index{}
+		deleteData := Mock(GetMethod(i.collection, "DeleteData")).Return(nil).Build()
+
+		PatchConvey("test delete until no data returned", func() {
+			full := dataPage(0, deleteBatchSize)
+			Mock(GetMethod(i.index, "Search")).Return(Sequence(full, nil).Then(dataPage(deleteBatchSize, 3), nil)).Build()
+			convey.So(i.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"}), convey.ShouldBeNil)
+			convey.So(deleteData.Times(), convey.ShouldEqual, 2)
+		})
+
+		PatchConvey("test stale data returned again", func() {
+			Mock(GetMethod(i.index, "Search")).Return(Sequence(dataPage(0, 2), nil).Then(dataPage(0, 2), nil)).Build()
+			convey.So(i.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"}), convey.ShouldBeNil)
+			convey.So(deleteData.Times(), convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test page full of stale data", func() {
+			full := dataPage(0, deleteBatchSize)
+			Mock(GetMethod(i.index, "Search")).Return(Sequence(full, nil).Then(full, nil)).Build()
+			err := i.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "delete incomplete")
+			convey.So(deleteData.Times(), convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test search failed", func() {
+			Mock(GetMethod(i.index, "Search")).Return(nil, fmt.Errorf("mock err")).Build()
+			err := i.DeleteByMetaData(ctx, map[string]any{"parent_id": "a.md"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "mock err")
+		})
+	})
+}
+
+func dataPage(from, n int) []*vikingdb.Data {
+	page := make([]*vikingdb.Data, 0, n)
+	for id := from; id < from+n; id++ {
+		page = append(page, &vikingdb.Data{Id: strconv.Itoa(id), Fields: map[string]interface{}{}})
+	}
+	return page
+}
+
+func TestDeleteByIDs(t *testing.T) {
+	PatchConvey("test DeleteByIDs", t, func() {
+		ctx := context.Background()
+		i := &Indexer{config: &IndexerConfig{}, collection: &vikingdb.Collection{}}
+		ids := make([]string, 0, 2*deleteBatchSize+1)
+		for _, data := range dataPage(0, 2*deleteBatchSize+1) {
+			ids = append(ids, dataID(data))
+		}
+
+		PatchConvey("test delete in batches", func() {
+			deleteData := Mock(GetMethod(i.collection, "DeleteData")).Return(nil).Build()
+			convey.So(i.DeleteByIDs(ctx, ids), convey.ShouldBeNil)
+			convey.So(deleteData.Times(), convey.ShouldEqual, 3)
+		})
+
+		PatchConvey("test delete failed", func() {
+			Mock(GetMethod(i.collection, "DeleteData")).Return(fmt.Errorf("mock err")).Build()
+			err := i.DeleteByIDs(ctx, ids)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "mock err")
+		})
+	})
+}
+
+func TestGetByIDs(t *testing.T) {
+	PatchConvey("test GetByIDs", t, func() {
+		ctx := context.Background()
+		i := &Indexer{config: &IndexerConfig{}, collection: &vikingdb.Collection{}}
+
+		// data are not returned in the order of the IDs, missing IDs are returned without fields
+		Mock(GetMethod(i.collection, "FetchData")).Return([]*vikingdb.Data{
+			{Id: "1", Fields: map[string]interface{}{defaultFieldContent: "asd"}},
+			{Id: "3"},
+			{Id: "2", Fields: map[string]interface{}{defaultFieldContent: "qwe"}},
+		}, nil).Build()
+
+		docs, err := i.GetByIDs(ctx, []string{"2", "3", "1"})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(len(docs), convey.ShouldEqual, 2)
+		convey.So(docs[0].ID, convey.ShouldEqual, "2")
+		convey.So(docs[0].Content, convey.ShouldEqual, "qwe")
+		convey.So(docs[1].ID, convey.ShouldEqual, "1")
+		convey.So(docs[1].Content, convey.ShouldEqual, "asd")
+	})
+}
+
+func TestUpdateMetaData(t *testing.T) {
+	PatchConvey("test UpdateMetaData", t, func() {
+		ctx := context.Background()
+		i := &Indexer{config: &IndexerConfig{}, collection: &vikingdb.Collection{}}
+		upsertData := Mock(GetMethod(i.collection, "UpsertData")).Return(nil).Build()
+
+		PatchConvey("test data not found", func() {
+			Mock(GetMethod(i.collection, "FetchData")).Return([]*vikingdb.Data{{Id: "1"}}, nil).Build()
+			err := i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "data not found, id=1")
+			convey.So(upsertData.Times(), convey.ShouldEqual, 0)
+		})
+
+		PatchConvey("test upsert", func() {
+			Mock(GetMethod(i.collection, "FetchData")).Return([]*vikingdb.Data{
+				{Id: "1", Fields: map[string]interface{}{defaultFieldID: "1", "tag": "y"}},
+			}, nil).Build()
+			convey.So(i.UpdateMetaData(ctx, "1", map[string]any{"tag": "x"}), convey.ShouldBeNil)
+			convey.So(upsertData.Times(), convey.ShouldEqual, 1)
+		})
+	})
+}
+
+func TestMergeData(t *testing.T) {
+	PatchConvey("test mergeData", t, func() {
+		data := &vikingdb.Data{
+			Id:     "1",
+			Fields: map[string]interface{}{defaultFieldID: "1", defaultFieldContent: "asd", "tag": "y"},
+			TTL:    10,
+		}
+		convey.So(mergeData(data, map[string]any{"tag": "x", "parent_id": "a.md"}), convey.ShouldResemble, vikingdb.Data{
+			Fields: map[string]interface{}{defaultFieldID: "1", defaultFieldContent: "asd", "tag": "x", "parent_id": "a.md"},
+			TTL:    10,
+		})
+		convey.So(data.Fields["tag"], convey.ShouldEqual, "y")
+	})
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ensemble
+
+const typ = "Ensemble"
+
+const (
+	defaultRRFK   = 60
+	defaultWeight = 1
+)
+
+// MetaDataKeySources is the metadata key of the names of the sub-retrievers
+// which returned a fused document, in the order of Config.Retrievers.
+// Read it with GetSources.
+const MetaDataKeySources = "_ensemble_sources"
+
+// FusionMode decides how the results of the sub-retrievers are fused.
+type FusionMode string
+
+const (
+	// FusionRRF scores a document by reciprocal rank fusion, the sum of
+	// Weight / (RRFK + rank) over the sub-retrievers returning it, rank
+	// starting from 1. It only relies on ranks, so it suits sub-retrievers
+	// whose scores are not comparable, e.g. BM25 and cosine similarity.
+	FusionRRF FusionMode = "rrf"
+	// FusionWeighted scores a document by the weighted sum of its scores,
+	// which are min-max normalized to [0, 1] per sub-retriever.
+	FusionWeighted FusionMode = "weighted"
+)
+
+// DedupMode decides which documents of different sub-retrievers are the same.
+type DedupMode string
+
+const (
+	// DedupByID treats documents with the same ID as the same, or documents
+	// with the same content if they have no ID.
+	DedupByID DedupMode = "id"
+	// DedupByContent treats documents with the same content as the same,
+	// which suits sub-retrievers with different ID schemes over the same corpus.
+	DedupByContent DedupMode = "content"
+)
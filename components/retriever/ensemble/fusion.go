@@ -0,0 +1,154 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ensemble
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// result is the documents returned by a sub-retriever.
+type result struct {
+	name   string
+	weight float64
+	docs   []*schema.Document
+}
+
+// fused is a document returned by one or more sub-retrievers.
+type fused struct {
+	doc     *schema.Document
+	score   float64
+	sources []string
+}
+
+// fuse merges the results in order, deduplicating documents by the key of
+// dedup, and returns the documents with the highest fused scores first.
+// Documents with equal scores keep the order in which they were first seen.
+func fuse(results []*result, mode FusionMode, rrfK int, dedup DedupMode) []*schema.Document {
+	var (
+		key2Fused = make(map[string]*fused)
+		all       []*fused
+	)
+
+	for _, res := range results {
+		normalize := minMaxNormalizer(res.docs)
+		seen := make(map[string]bool, len(res.docs))
+		rank := 0
+		for _, doc := range res.docs {
+			if doc == nil {
+				continue
+			}
+			key := dedupKey(doc, dedup)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			rank++
+
+			var score float64
+			switch mode {
+			case FusionWeighted:
+				score = res.weight * normalize(doc.Score())
+			default:
+				score = res.weight / float64(rrfK+rank)
+			}
+
+			f, ok := key2Fused[key]
+			if !ok {
+				f = &fused{doc: doc}
+				key2Fused[key] = f
+				all = append(all, f)
+			}
+			f.score += score
+			f.sources = append(f.sources, res.name)
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].score > all[j].score
+	})
+
+	docs := make([]*schema.Document, 0, len(all))
+	for _, f := range all {
+		docs = append(docs, f.toDocument())
+	}
+	return docs
+}
+
+// toDocument copies the document, so that the documents of the sub-retrievers
+// are not modified, and sets the fused score and the sources.
+func (f *fused) toDocument() *schema.Document {
+	metaData := make(map[string]any, len(f.doc.MetaData)+2)
+	for k, v := range f.doc.MetaData {
+		metaData[k] = v
+	}
+	metaData[MetaDataKeySources] = f.sources
+
+	doc := &schema.Document{
+		ID:       f.doc.ID,
+		Content:  f.doc.Content,
+		MetaData: metaData,
+	}
+	return doc.WithScore(f.score)
+}
+
+// minMaxNormalizer returns a function scaling the scores of the documents to
+// [0, 1]. All scores are 1 if they are equal.
+func minMaxNormalizer(docs []*schema.Document) func(float64) float64 {
+	first := true
+	var lo, hi float64
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		s := doc.Score()
+		if first || s < lo {
+			lo = s
+		}
+		if first || s > hi {
+			hi = s
+		}
+		first = false
+	}
+
+	if hi == lo {
+		return func(float64) float64 { return 1 }
+	}
+	return func(s float64) float64 {
+		return (s - lo) / (hi - lo)
+	}
+}
+
+func dedupKey(doc *schema.Document, dedup DedupMode) string {
+	if dedup == DedupByID && doc.ID != "" {
+		return "id:" + doc.ID
+	}
+	sum := sha256.Sum256([]byte(doc.Content))
+	return "content:" + hex.EncodeToString(sum[:])
+}
+
+// GetSources returns the names of the sub-retrievers which returned the fused document.
+func GetSources(doc *schema.Document) []string {
+	if doc == nil || doc.MetaData == nil {
+		return nil
+	}
+	sources, _ := doc.MetaData[MetaDataKeySources].([]string)
+	return sources
+}
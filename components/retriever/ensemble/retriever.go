@@ -0,0 +1,281 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ensemble
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Config contains configuration for the ensemble retriever.
+type Config struct {
+	// Retrievers are the sub-retrievers the query is sent to concurrently.
+	Retrievers []*SubRetriever
+	// Fusion decides how the results of the sub-retrievers are fused.
+	// Default FusionRRF.
+	Fusion FusionMode
+	// RRFK is the constant k of FusionRRF, larger values flatten the differences between ranks.
+	// Default 60.
+	RRFK int
+	// Dedup decides which documents of different sub-retrievers are the same.
+	// Default DedupByID.
+	Dedup DedupMode
+	// Timeout of each sub-retriever without its own Timeout, 0 means no timeout.
+	Timeout time.Duration
+	// IgnoreErrors skips the sub-retrievers which fail or time out, and only
+	// fails if all of them do. By default, any failure fails the retrieval.
+	IgnoreErrors bool
+	// TopK is the number of fused documents to return, 0 returns all of them.
+	TopK int
+}
+
+// SubRetriever is a retriever of the ensemble.
+type SubRetriever struct {
+	// Name identifies the retriever in the sources of fused documents, which must be unique.
+	// Default the type of Retriever, e.g. Qdrant, or retriever_<index> if it has no type.
+	Name string
+	// Retriever is the retriever to send the query to.
+	Retriever retriever.Retriever
+	// Weight scales the scores the retriever contributes, which must not be negative.
+	// Default 1.
+	Weight float64
+	// Timeout of the retriever, overriding Config.Timeout.
+	Timeout time.Duration
+}
+
+// Retriever implements the [retriever.Retriever] interface. It sends the query
+// to all sub-retrievers concurrently and fuses their results.
+type Retriever struct {
+	retrievers   []*SubRetriever
+	fusion       FusionMode
+	rrfK         int
+	dedup        DedupMode
+	ignoreErrors bool
+	topK         int
+}
+
+// NewRetriever creates a new ensemble retriever with the provided configuration.
+func NewRetriever(ctx context.Context, config *Config) (*Retriever, error) {
+	if config == nil {
+		return nil, fmt.Errorf("[NewRetriever] config is nil")
+	}
+	if len(config.Retrievers) == 0 {
+		return nil, fmt.Errorf("[NewRetriever] retrievers not provided")
+	}
+
+	fusion := config.Fusion
+	if fusion == "" {
+		fusion = FusionRRF
+	}
+	if fusion != FusionRRF && fusion != FusionWeighted {
+		return nil, fmt.Errorf("[NewRetriever] unknown fusion mode: %s", fusion)
+	}
+
+	dedup := config.Dedup
+	if dedup == "" {
+		dedup = DedupByID
+	}
+	if dedup != DedupByID && dedup != DedupByContent {
+		return nil, fmt.Errorf("[NewRetriever] unknown dedup mode: %s", dedup)
+	}
+
+	rrfK := config.RRFK
+	if rrfK == 0 {
+		rrfK = defaultRRFK
+	}
+
+	names := make(map[string]bool, len(config.Retrievers))
+	retrievers := make([]*SubRetriever, 0, len(config.Retrievers))
+	for i, sub := range config.Retrievers {
+		if sub == nil || sub.Retriever == nil {
+			return nil, fmt.Errorf("[NewRetriever] retriever %d not provided", i)
+		}
+		if sub.Weight < 0 {
+			return nil, fmt.Errorf("[NewRetriever] weight of retriever %d is negative", i)
+		}
+
+		s := *sub
+		if s.Name == "" {
+			if t, ok := components.GetType(s.Retriever); ok && t != "" {
+				s.Name = t
+			} else {
+				s.Name = fmt.Sprintf("retriever_%d", i)
+			}
+		}
+		if names[s.Name] {
+			return nil, fmt.Errorf("[NewRetriever] duplicate retriever name: %s", s.Name)
+		}
+		names[s.Name] = true
+
+		if s.Weight == 0 {
+			s.Weight = defaultWeight
+		}
+		if s.Timeout == 0 {
+			s.Timeout = config.Timeout
+		}
+		retrievers = append(retrievers, &s)
+	}
+
+	return &Retriever{
+		retrievers:   retrievers,
+		fusion:       fusion,
+		rrfK:         rrfK,
+		dedup:        dedup,
+		ignoreErrors: config.IgnoreErrors,
+		topK:         config.TopK,
+	}, nil
+}
+
+// Retrieve sends the query to all sub-retrievers concurrently and returns the
+// fused documents. The options are passed to every sub-retriever, and TopK
+// also limits the fused documents.
+func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	co := retriever.GetCommonOptions(&retriever.Options{
+		TopK: &r.topK,
+	}, opts...)
+
+	ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfRetriever)
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
+		Query: query,
+		TopK:  *co.TopK,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	var (
+		wg      sync.WaitGroup
+		results = make([]*result, len(r.retrievers))
+		errs    = make([]error, len(r.retrievers))
+	)
+	for i, sub := range r.retrievers {
+		wg.Add(1)
+		go func(i int, sub *SubRetriever) {
+			defer wg.Done()
+			subDocs, err := retrieve(ctx, sub, query, opts)
+			if err != nil {
+				errs[i] = fmt.Errorf("[ensemble retriever] retriever %s failed: %w", sub.Name, err)
+				return
+			}
+			results[i] = &result{name: sub.Name, weight: sub.Weight, docs: subDocs}
+		}(i, sub)
+	}
+	wg.Wait()
+
+	succeeded := make([]*result, 0, len(results))
+	for i, res := range results {
+		if errs[i] != nil {
+			if !r.ignoreErrors {
+				return nil, errs[i]
+			}
+			continue
+		}
+		succeeded = append(succeeded, res)
+	}
+	if len(succeeded) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	docs = fuse(succeeded, r.fusion, r.rrfK, r.dedup)
+	if *co.TopK > 0 && len(docs) > *co.TopK {
+		docs = docs[:*co.TopK]
+	}
+
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+
+	return docs, nil
+}
+
+// retrieve calls the sub-retriever, and stops waiting for it once its timeout
+// expires or ctx is canceled, even if it ignores the context. Callbacks are
+// triggered on its behalf if it does not trigger them itself.
+func retrieve(ctx context.Context, sub *SubRetriever, query string, opts []retriever.Option) (docs []*schema.Document, err error) {
+	if sub.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sub.Timeout)
+		defer cancel()
+	}
+
+	ctx = ctxWithRetrieverRunInfo(ctx, sub.Retriever)
+	if !components.IsCallbacksEnabled(sub.Retriever) {
+		ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{Query: query})
+		defer func() {
+			if err != nil {
+				callbacks.OnError(ctx, err)
+				return
+			}
+			callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+		}()
+	}
+
+	type output struct {
+		docs []*schema.Document
+		err  error
+	}
+	// buffered, so that a retriever ignoring the canceled context does not block forever
+	ch := make(chan output, 1)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				ch <- output{err: fmt.Errorf("panic: %v", e)}
+			}
+		}()
+		d, e := sub.Retriever.Retrieve(ctx, query, opts...)
+		ch <- output{docs: d, err: e}
+	}()
+
+	select {
+	case out := <-ch:
+		return out.docs, out.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func ctxWithRetrieverRunInfo(ctx context.Context, r retriever.Retriever) context.Context {
+	runInfo := &callbacks.RunInfo{
+		Component: components.ComponentOfRetriever,
+	}
+
+	if t, ok := components.GetType(r); ok {
+		runInfo.Type = t
+	}
+
+	runInfo.Name = runInfo.Type + string(runInfo.Component)
+
+	return callbacks.ReuseHandlers(ctx, runInfo)
+}
+
+func (r *Retriever) GetType() string {
+	return typ
+}
+
+func (r *Retriever) IsCallbacksEnabled() bool {
+	return true
+}
+
+var _ retriever.Retriever = &Retriever{}
@@ -0,0 +1,266 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+package ensemble
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestFuse(t *testing.T) {
+	convey.Convey("test fuse", t, func() {
+		doc := func(id, content string, score float64) *schema.Document {
+			return (&schema.Document{ID: id, Content: content, MetaData: map[string]any{"origin": id}}).WithScore(score)
+		}
+		ids := func(docs []*schema.Document) []string {
+			var ret []string
+			for _, d := range docs {
+				ret = append(ret, d.ID)
+			}
+			return ret
+		}
+
+		lexical := &result{name: "bm25", weight: 1, docs: []*schema.Document{
+			doc("a", "apple", 12), doc("b", "banana", 8), doc("c", "cherry", 2),
+		}}
+		dense := &result{name: "dense", weight: 1, docs: []*schema.Document{
+			doc("c", "cherry", 0.9), doc("b", "banana", 0.85), doc("d", "durian", 0.1),
+		}}
+
+		convey.Convey("test rrf", func() {
+			docs := fuse([]*result{lexical, dense}, FusionRRF, 60, DedupByID)
+			// c ranks 3rd and 1st, which beats b ranking 2nd twice
+			convey.So(ids(docs), convey.ShouldResemble, []string{"c", "b", "a", "d"})
+			convey.So(docs[0].Score(), convey.ShouldAlmostEqual, 1.0/63+1.0/61)
+			convey.So(docs[1].Score(), convey.ShouldAlmostEqual, 1.0/62+1.0/62)
+			convey.So(GetSources(docs[0]), convey.ShouldResemble, []string{"bm25", "dense"})
+			convey.So(GetSources(docs[2]), convey.ShouldResemble, []string{"bm25"})
+			convey.So(GetSources(docs[3]), convey.ShouldResemble, []string{"dense"})
+			convey.So(docs[3].MetaData["origin"], convey.ShouldEqual, "d")
+
+			// the documents of the sub-retrievers are not modified
+			convey.So(lexical.docs[1].MetaData, convey.ShouldNotContainKey, MetaDataKeySources)
+			convey.So(lexical.docs[1].Score(), convey.ShouldEqual, 8)
+
+			// weighting bm25 up puts b, ranking 2nd in it, ahead of c ranking 3rd
+			lexical.weight = 3
+			docs = fuse([]*result{lexical, dense}, FusionRRF, 60, DedupByID)
+			convey.So(ids(docs), convey.ShouldResemble, []string{"b", "c", "a", "d"})
+		})
+
+		convey.Convey("test weighted", func() {
+			docs := fuse([]*result{lexical, dense}, FusionWeighted, 60, DedupByID)
+			// bm25: a=1, b=0.6, c=0; dense: c=1, b=0.9375, d=0
+			convey.So(ids(docs), convey.ShouldResemble, []string{"b", "a", "c", "d"})
+			convey.So(docs[0].Score(), convey.ShouldAlmostEqual, 0.6+0.9375)
+			convey.So(docs[3].Score(), convey.ShouldEqual, 0)
+
+			// equal scores are all normalized to 1
+			single := &result{name: "s", weight: 0.5, docs: []*schema.Document{doc("x", "x", 3), doc("y", "y", 3)}}
+			docs = fuse([]*result{single}, FusionWeighted, 60, DedupByID)
+			convey.So(ids(docs), convey.ShouldResemble, []string{"x", "y"})
+			convey.So(docs[1].Score(), convey.ShouldEqual, 0.5)
+		})
+
+		convey.Convey("test dedup", func() {
+			other := &result{name: "other", weight: 1, docs: []*schema.Document{
+				doc("other-1", "banana", 1), doc("other-1", "banana", 1), nil, doc("", "apple", 1),
+			}}
+			docs := fuse([]*result{lexical, other}, FusionRRF, 60, DedupByID)
+			convey.So(ids(docs), convey.ShouldResemble, []string{"a", "other-1", "b", "", "c"})
+			convey.So(GetSources(docs[1]), convey.ShouldResemble, []string{"other"})
+
+			docs = fuse([]*result{lexical, other}, FusionRRF, 60, DedupByContent)
+			convey.So(ids(docs), convey.ShouldResemble, []string{"a", "b", "c"})
+			convey.So(GetSources(docs[0]), convey.ShouldResemble, []string{"bm25", "other"})
+			convey.So(GetSources(docs[1]), convey.ShouldResemble, []string{"bm25", "other"})
+		})
+
+		convey.So(GetSources(nil), convey.ShouldBeNil)
+		convey.So(GetSources(&schema.Document{}), convey.ShouldBeNil)
+	})
+}
+
+func TestRetriever(t *testing.T) {
+	convey.Convey("test retriever", t, func() {
+		ctx := context.Background()
+		lexical := &mockRetriever{typ: "ES8", docs: []*schema.Document{
+			{ID: "a", Content: "apple"}, {ID: "b", Content: "banana"},
+		}}
+		dense := &mockRetriever{typ: "Qdrant", docs: []*schema.Document{
+			{ID: "b", Content: "banana"}, {ID: "c", Content: "cherry"},
+		}}
+
+		convey.Convey("test NewRetriever", func() {
+			_, err := NewRetriever(ctx, nil)
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{Retrievers: []*SubRetriever{{}}})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{Retrievers: []*SubRetriever{{Retriever: lexical, Weight: -1}}})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{Retrievers: []*SubRetriever{{Retriever: lexical}, {Retriever: lexical}}})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{Retrievers: []*SubRetriever{{Retriever: lexical}}, Fusion: "max"})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{Retrievers: []*SubRetriever{{Retriever: lexical}}, Dedup: "title"})
+			convey.So(err, convey.ShouldNotBeNil)
+
+			subs := []*SubRetriever{{Retriever: lexical}, {Retriever: &mockRetriever{}, Weight: 2, Timeout: time.Second}}
+			r, err := NewRetriever(ctx, &Config{Retrievers: subs, Timeout: time.Minute})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(r.fusion, convey.ShouldEqual, FusionRRF)
+			convey.So(r.dedup, convey.ShouldEqual, DedupByID)
+			convey.So(r.rrfK, convey.ShouldEqual, 60)
+			convey.So(r.retrievers[0].Name, convey.ShouldEqual, "ES8")
+			convey.So(r.retrievers[0].Weight, convey.ShouldEqual, 1)
+			convey.So(r.retrievers[0].Timeout, convey.ShouldEqual, time.Minute)
+			convey.So(r.retrievers[1].Name, convey.ShouldEqual, "retriever_1")
+			convey.So(r.retrievers[1].Weight, convey.ShouldEqual, 2)
+			convey.So(r.retrievers[1].Timeout, convey.ShouldEqual, time.Second)
+			// the config is not modified
+			convey.So(subs[0].Name, convey.ShouldEqual, "")
+			convey.So(r.GetType(), convey.ShouldEqual, typ)
+			convey.So(r.IsCallbacksEnabled(), convey.ShouldBeTrue)
+		})
+
+		convey.Convey("test Retrieve", func() {
+			r, err := NewRetriever(ctx, &Config{Retrievers: []*SubRetriever{
+				{Name: "lexical", Retriever: lexical},
+				{Name: "dense", Retriever: dense},
+			}})
+			convey.So(err, convey.ShouldBeNil)
+
+			docs, err := r.Retrieve(ctx, "fruit")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldHaveLength, 3)
+			convey.So(docs[0].ID, convey.ShouldEqual, "b")
+			convey.So(GetSources(docs[0]), convey.ShouldResemble, []string{"lexical", "dense"})
+			convey.So(lexical.query, convey.ShouldEqual, "fruit")
+			convey.So(dense.query, convey.ShouldEqual, "fruit")
+
+			docs, err = r.Retrieve(ctx, "fruit", retriever.WithTopK(1))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldHaveLength, 1)
+			// options are passed to the sub-retrievers
+			convey.So(lexical.topK, convey.ShouldEqual, 1)
+		})
+
+		convey.Convey("test Retrieve with callbacks", func() {
+			var started []string
+			handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+				started = append(started, info.Type)
+				return ctx
+			}).Build()
+			ctx := callbacks.InitCallbacks(ctx, nil, handler)
+
+			r, err := NewRetriever(ctx, &Config{Retrievers: []*SubRetriever{{Retriever: lexical}}})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "fruit")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(started, convey.ShouldResemble, []string{"Ensemble", "ES8"})
+		})
+
+		convey.Convey("test Retrieve failure", func() {
+			failing := &mockRetriever{err: fmt.Errorf("mock err")}
+			panicking := &mockRetriever{panic: true}
+			slow := &mockRetriever{delay: time.Second, docs: []*schema.Document{{ID: "z"}}}
+
+			r, err := NewRetriever(ctx, &Config{Retrievers: []*SubRetriever{
+				{Name: "lexical", Retriever: lexical},
+				{Name: "failing", Retriever: failing},
+			}})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "fruit")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "failing")
+
+			r, err = NewRetriever(ctx, &Config{
+				Retrievers: []*SubRetriever{
+					{Name: "lexical", Retriever: lexical},
+					{Name: "failing", Retriever: failing},
+					{Name: "panicking", Retriever: panicking},
+					{Name: "slow", Retriever: slow, Timeout: 10 * time.Millisecond},
+				},
+				IgnoreErrors: true,
+			})
+			convey.So(err, convey.ShouldBeNil)
+			start := time.Now()
+			docs, err := r.Retrieve(ctx, "fruit")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(time.Since(start), convey.ShouldBeLessThan, 500*time.Millisecond)
+			convey.So(docs, convey.ShouldHaveLength, 2)
+			convey.So(GetSources(docs[0]), convey.ShouldResemble, []string{"lexical"})
+
+			r, err = NewRetriever(ctx, &Config{
+				Retrievers: []*SubRetriever{
+					{Name: "failing", Retriever: failing},
+					{Name: "panicking", Retriever: panicking},
+					{Name: "slow", Retriever: slow},
+				},
+				Timeout:      10 * time.Millisecond,
+				IgnoreErrors: true,
+			})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "fruit")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "mock err")
+			convey.So(err.Error(), convey.ShouldContainSubstring, "panic")
+			convey.So(err.Error(), convey.ShouldContainSubstring, context.DeadlineExceeded.Error())
+		})
+	})
+}
+
+// mockRetriever does not trigger callbacks itself, and ignores the context,
+// so a timed out call may still record its query while the next test runs.
+type mockRetriever struct {
+	typ   string
+	docs  []*schema.Document
+	err   error
+	panic bool
+	delay time.Duration
+
+	mu    sync.Mutex
+	query string
+	topK  int
+}
+
+func (m *mockRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	if m.panic {
+		panic("mock panic")
+	}
+	time.Sleep(m.delay)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.query = query
+	m.topK = *retriever.GetCommonOptions(&retriever.Options{TopK: new(int)}, opts...).TopK
+	return m.docs, m.err
+}
+
+func (m *mockRetriever) GetType() string {
+	return m.typ
+}
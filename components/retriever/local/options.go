@@ -0,0 +1,43 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"github.com/cloudwego/eino-ext/components/indexer/local"
+	"github.com/cloudwego/eino/components/retriever"
+)
+
+type implOptions struct {
+	Filter     map[string]any
+	FilterFunc local.Filter
+}
+
+// WithFilter only retrieves documents whose metadata equals every value of the filter.
+// Numbers are compared by value, so that int 1 matches float64 1.
+func WithFilter(filter map[string]any) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *implOptions) {
+		o.Filter = filter
+	})
+}
+
+// WithFilterFunc only retrieves documents whose metadata passes the filter,
+// in addition to WithFilter if both are set.
+func WithFilterFunc(filter local.Filter) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *implOptions) {
+		o.FilterFunc = filter
+	})
+}
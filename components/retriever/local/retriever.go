@@ -0,0 +1,170 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino-ext/components/indexer/local"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+type Config struct {
+	// Store to search, usually shared with a local indexer.
+	// Use local.NewStore to create an empty one, or local.LoadStore to load a snapshot.
+	Store *local.Store
+	// Embedder used to generate vector representations for queries.
+	Embedding embedding.Embedder
+	// Optional minimum score threshold for filtering results.
+	// Scores are cosine similarity, inner product or 1 / (1 + euclidean distance), depending on the metric of Store.
+	ScoreThreshold *float64
+	// Number of top results to retrieve.
+	// Default 5.
+	TopK int
+}
+
+type Retriever struct {
+	store          *local.Store
+	embedding      embedding.Embedder
+	scoreThreshold *float64
+	topK           int
+}
+
+func NewRetriever(ctx context.Context, config *Config) (*Retriever, error) {
+	if config == nil {
+		return nil, fmt.Errorf("[NewRetriever] config is nil")
+	}
+	if config.Store == nil {
+		return nil, fmt.Errorf("[NewRetriever] store not provided")
+	}
+
+	topK := config.TopK
+	if topK == 0 {
+		topK = 5
+	}
+
+	return &Retriever{
+		store:          config.Store,
+		embedding:      config.Embedding,
+		scoreThreshold: config.ScoreThreshold,
+		topK:           topK,
+	}, nil
+}
+
+func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	co := retriever.GetCommonOptions(&retriever.Options{
+		TopK:           &r.topK,
+		ScoreThreshold: r.scoreThreshold,
+		Embedding:      r.embedding,
+	}, opts...)
+	io := retriever.GetImplSpecificOptions(&implOptions{}, opts...)
+
+	ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfRetriever)
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
+		Query:          query,
+		TopK:           *co.TopK,
+		Filter:         tryMarshalJsonString(io.Filter),
+		ScoreThreshold: co.ScoreThreshold,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	emb := co.Embedding
+	if emb == nil {
+		return nil, fmt.Errorf("[local retriever] embedding not provided")
+	}
+	vectors, err := emb.EmbedStrings(r.makeEmbeddingCtx(ctx, emb), []string{query})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("[local retriever] invalid return length of vector, got=%d, expected=1", len(vectors))
+	}
+
+	docs, err = r.store.Search(&local.SearchRequest{
+		Vector:         vectors[0],
+		TopK:           *co.TopK,
+		ScoreThreshold: co.ScoreThreshold,
+		Filter:         makeFilter(io),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[local retriever] search failed: %w", err)
+	}
+
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+
+	return docs, nil
+}
+
+func makeFilter(io *implOptions) local.Filter {
+	switch {
+	case len(io.Filter) == 0:
+		return io.FilterFunc
+	case io.FilterFunc == nil:
+		return local.MatchMetaData(io.Filter)
+	default:
+		match := local.MatchMetaData(io.Filter)
+		return func(metaData map[string]any) bool {
+			return match(metaData) && io.FilterFunc(metaData)
+		}
+	}
+}
+
+func (r *Retriever) makeEmbeddingCtx(ctx context.Context, emb embedding.Embedder) context.Context {
+	runInfo := &callbacks.RunInfo{
+		Component: components.ComponentOfEmbedding,
+	}
+
+	if embType, ok := components.GetType(emb); ok {
+		runInfo.Type = embType
+	}
+
+	runInfo.Name = runInfo.Type + string(runInfo.Component)
+
+	return callbacks.ReuseHandlers(ctx, runInfo)
+}
+
+func tryMarshalJsonString(input map[string]any) string {
+	if len(input) == 0 {
+		return ""
+	}
+	if b, err := json.Marshal(input); err == nil {
+		return string(b)
+	}
+	return ""
+}
+
+const typ = "Local"
+
+func (r *Retriever) GetType() string {
+	return typ
+}
+
+func (r *Retriever) IsCallbacksEnabled() bool {
+	return true
+}
+
+var _ retriever.Retriever = &Retriever{}
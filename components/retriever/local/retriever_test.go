@@ -0,0 +1,155 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/components/indexer/local"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestRetriever(t *testing.T) {
+	convey.Convey("test retriever", t, func() {
+		ctx := context.Background()
+		store, err := local.NewStore(&local.StoreConfig{Seed: 1})
+		convey.So(err, convey.ShouldBeNil)
+
+		emb := &mockEmbedding{vectors: map[string][]float64{
+			"apple":  {1, 0, 0},
+			"banana": {0.9, 0.1, 0},
+			"cherry": {0.7, 0.3, 0},
+			"durian": {0, 1, 0},
+			"fruit":  {1, 0.05, 0},
+		}}
+		idx, err := local.NewIndexer(ctx, &local.IndexerConfig{Store: store, Embedding: emb})
+		convey.So(err, convey.ShouldBeNil)
+		_, err = idx.Store(ctx, []*schema.Document{
+			{ID: "1", Content: "apple", MetaData: map[string]any{"color": "red", "rank": 1}},
+			{ID: "2", Content: "banana", MetaData: map[string]any{"color": "yellow", "rank": 2}},
+			{ID: "3", Content: "cherry", MetaData: map[string]any{"color": "red", "rank": 3}},
+			{ID: "4", Content: "durian", MetaData: map[string]any{"color": "green", "rank": 4}},
+		})
+		convey.So(err, convey.ShouldBeNil)
+
+		contents := func(docs []*schema.Document) []string {
+			var ret []string
+			for _, doc := range docs {
+				ret = append(ret, doc.Content)
+			}
+			return ret
+		}
+
+		convey.Convey("test NewRetriever", func() {
+			_, err := NewRetriever(ctx, nil)
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{})
+			convey.So(err, convey.ShouldNotBeNil)
+
+			r, err := NewRetriever(ctx, &Config{Store: store})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(r.topK, convey.ShouldEqual, 5)
+			convey.So(r.GetType(), convey.ShouldEqual, typ)
+			convey.So(r.IsCallbacksEnabled(), convey.ShouldBeTrue)
+
+			_, err = r.Retrieve(ctx, "fruit")
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test Retrieve", func() {
+			threshold := 0.5
+			r, err := NewRetriever(ctx, &Config{Store: store, Embedding: emb, TopK: 3, ScoreThreshold: &threshold})
+			convey.So(err, convey.ShouldBeNil)
+
+			docs, err := r.Retrieve(ctx, "fruit")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(contents(docs), convey.ShouldResemble, []string{"apple", "banana", "cherry"})
+			convey.So(docs[0].Score(), convey.ShouldBeGreaterThan, docs[1].Score())
+
+			docs, err = r.Retrieve(ctx, "fruit", retriever.WithTopK(1))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(contents(docs), convey.ShouldResemble, []string{"apple"})
+
+			high := 0.99
+			docs, err = r.Retrieve(ctx, "fruit", retriever.WithScoreThreshold(high))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(contents(docs), convey.ShouldResemble, []string{"apple", "banana"})
+
+			docs, err = r.Retrieve(ctx, "fruit", retriever.WithTopK(4), retriever.WithScoreThreshold(0))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(contents(docs), convey.ShouldResemble, []string{"apple", "banana", "cherry", "durian"})
+
+			docs, err = r.Retrieve(ctx, "fruit", WithFilter(map[string]any{"color": "red"}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(contents(docs), convey.ShouldResemble, []string{"apple", "cherry"})
+
+			docs, err = r.Retrieve(ctx, "fruit",
+				WithFilter(map[string]any{"color": "red"}),
+				WithFilterFunc(func(metaData map[string]any) bool {
+					return metaData["rank"].(int) > 1
+				}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(contents(docs), convey.ShouldResemble, []string{"cherry"})
+
+			docs, err = r.Retrieve(ctx, "fruit", WithFilterFunc(func(metaData map[string]any) bool {
+				return metaData["color"] != "red"
+			}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(contents(docs), convey.ShouldResemble, []string{"banana"})
+
+			_, err = r.Retrieve(ctx, "unknown")
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = r.Retrieve(ctx, "fruit", retriever.WithEmbedding(&mockEmbedding{}))
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test Retrieve from snapshot", func() {
+			path := filepath.Join(t.TempDir(), "store.snapshot")
+			convey.So(store.Save(path), convey.ShouldBeNil)
+			loaded, err := local.LoadStore(path)
+			convey.So(err, convey.ShouldBeNil)
+
+			r, err := NewRetriever(ctx, &Config{Store: loaded, Embedding: emb, TopK: 2})
+			convey.So(err, convey.ShouldBeNil)
+			docs, err := r.Retrieve(ctx, "fruit", WithFilter(map[string]any{"rank": 3}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(contents(docs), convey.ShouldResemble, []string{"cherry"})
+		})
+	})
+}
+
+type mockEmbedding struct {
+	vectors map[string][]float64
+}
+
+func (m *mockEmbedding) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	ret := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		v, ok := m.vectors[text]
+		if !ok {
+			return nil, fmt.Errorf("unknown text: %s", text)
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pgvector
+
+const typ = "PGVector"
+
+const (
+	defaultTopK = 5
+
+	defaultIDColumn         = "id"
+	defaultContentColumn    = "content"
+	defaultMetaDataColumn   = "metadata"
+	defaultVectorColumn     = "embedding"
+	defaultTextSearchColumn = "content_tsv"
+
+	defaultTextSearchConfig = "simple"
+	defaultRRFK             = 60
+	defaultCandidateFactor  = 4
+
+	// scoreColumn is the alias of the score selected along with the columns.
+	scoreColumn = "_eino_score"
+)
+
+// SearchMode decides how rows are ranked.
+type SearchMode string
+
+const (
+	// SearchModeVector ranks rows by the distance between their vectors and the query embedding.
+	SearchModeVector SearchMode = "vector"
+	// SearchModeFullText ranks rows matching the query by ts_rank_cd of their tsvector column.
+	SearchModeFullText SearchMode = "full_text"
+	// SearchModeHybrid fuses the vector and full-text rankings by reciprocal rank fusion.
+	SearchModeHybrid SearchMode = "hybrid"
+)
+
+// Distance is the distance operator used for vector search, which must match
+// the operator class of the vector index to use it.
+type Distance string
+
+const (
+	// DistanceCosine searches by cosine distance, operator <=>, score is cosine similarity.
+	DistanceCosine Distance = "cosine"
+	// DistanceL2 searches by euclidean distance, operator <->, score is 1 / (1 + distance).
+	DistanceL2 Distance = "l2"
+	// DistanceInnerProduct searches by negative inner product, operator <#>, score is inner product.
+	DistanceInnerProduct Distance = "inner_product"
+)
+
+var distanceOperators = map[Distance]string{
+	DistanceCosine:       "<=>",
+	DistanceL2:           "<->",
+	DistanceInnerProduct: "<#>",
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pgvector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Filter is a condition on the JSONB metadata column. Build it with Eq, Ne,
+// In, Gt, Gte, Lt, Lte and Exists, and combine them with And, Or and Not:
+//
+//	And(Eq("lang", "en"), Or(Gte("year", 2020), Exists("pinned")))
+type Filter interface {
+	build(b *queryBuilder) (string, error)
+}
+
+type filterFunc func(b *queryBuilder) (string, error)
+
+func (f filterFunc) build(b *queryBuilder) (string, error) {
+	return f(b)
+}
+
+// queryBuilder collects the arguments of a statement.
+type queryBuilder struct {
+	metaDataColumn string
+	args           []any
+}
+
+// arg adds an argument and returns its placeholder.
+func (b *queryBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// jsonArg adds a value encoded as json and returns its placeholder cast to jsonb.
+func (b *queryBuilder) jsonArg(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("marshal filter value failed, %w", err)
+	}
+	return b.arg(string(data)) + "::jsonb", nil
+}
+
+// Eq matches rows whose metadata value of the key equals the value. If the
+// value is a slice, it matches arrays containing all the elements. It's
+// served by the GIN index on metadata.
+func Eq(key string, value any) Filter {
+	return filterFunc(func(b *queryBuilder) (string, error) {
+		p, err := b.jsonArg(map[string]any{key: value})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s @> %s", b.metaDataColumn, p), nil
+	})
+}
+
+// Ne matches rows whose metadata value of the key does not equal the value,
+// including rows without the key.
+func Ne(key string, value any) Filter {
+	return Not(Eq(key, value))
+}
+
+// In matches rows whose metadata value of the key equals any of the values.
+func In(key string, values ...any) Filter {
+	filters := make([]Filter, 0, len(values))
+	for _, v := range values {
+		filters = append(filters, Eq(key, v))
+	}
+	return Or(filters...)
+}
+
+// Gt matches rows whose metadata value of the key is greater than the value.
+// Values of different json types never match, so numbers compare with numbers
+// and strings with strings.
+func Gt(key string, value any) Filter {
+	return compare(key, ">", value)
+}
+
+// Gte matches rows whose metadata value of the key is greater than or equal to the value.
+func Gte(key string, value any) Filter {
+	return compare(key, ">=", value)
+}
+
+// Lt matches rows whose metadata value of the key is less than the value.
+func Lt(key string, value any) Filter {
+	return compare(key, "<", value)
+}
+
+// Lte matches rows whose metadata value of the key is less than or equal to the value.
+func Lte(key string, value any) Filter {
+	return compare(key, "<=", value)
+}
+
+func compare(key, op string, value any) Filter {
+	return filterFunc(func(b *queryBuilder) (string, error) {
+		k := b.arg(key)
+		v, err := b.jsonArg(value)
+		if err != nil {
+			return "", err
+		}
+		field := fmt.Sprintf("%s -> %s", b.metaDataColumn, k)
+		return fmt.Sprintf("(jsonb_typeof(%s) = jsonb_typeof(%s) AND (%s) %s %s)", field, v, field, op, v), nil
+	})
+}
+
+// Exists matches rows whose metadata has the key.
+func Exists(key string) Filter {
+	return filterFunc(func(b *queryBuilder) (string, error) {
+		return fmt.Sprintf("%s ? %s", b.metaDataColumn, b.arg(key)), nil
+	})
+}
+
+// And matches rows matching all the filters, or all rows if there are none.
+func And(filters ...Filter) Filter {
+	return combine(filters, " AND ", "TRUE")
+}
+
+// Or matches rows matching any of the filters, or no rows if there are none.
+func Or(filters ...Filter) Filter {
+	return combine(filters, " OR ", "FALSE")
+}
+
+func combine(filters []Filter, sep, empty string) Filter {
+	return filterFunc(func(b *queryBuilder) (string, error) {
+		if len(filters) == 0 {
+			return empty, nil
+		}
+		conds := make([]string, 0, len(filters))
+		for _, f := range filters {
+			cond, err := f.build(b)
+			if err != nil {
+				return "", err
+			}
+			conds = append(conds, cond)
+		}
+		if len(conds) == 1 {
+			return conds[0], nil
+		}
+		return "(" + strings.Join(conds, sep) + ")", nil
+	})
+}
+
+// Not matches rows not matching the filter.
+func Not(filter Filter) Filter {
+	return filterFunc(func(b *queryBuilder) (string, error) {
+		cond, err := filter.build(b)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("NOT COALESCE(%s, FALSE)", cond), nil
+	})
+}
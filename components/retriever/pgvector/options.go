@@ -0,0 +1,42 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pgvector
+
+import (
+	"github.com/cloudwego/eino/components/retriever"
+)
+
+type implOptions struct {
+	SearchMode SearchMode
+	Filter     Filter
+}
+
+// WithSearchMode overrides Config.SearchMode for the call.
+func WithSearchMode(mode SearchMode) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *implOptions) {
+		o.SearchMode = mode
+	})
+}
+
+// WithFilter only retrieves documents whose metadata matches the filter, e.g.
+//
+//	WithFilter(And(Eq("lang", "en"), Gte("year", 2020)))
+func WithFilter(filter Filter) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *implOptions) {
+		o.Filter = filter
+	})
+}
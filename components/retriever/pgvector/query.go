@@ -0,0 +1,122 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pgvector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// searchRequest is a search built into a statement by buildQuery.
+type searchRequest struct {
+	mode   SearchMode
+	query  string
+	vector []float64
+	topK   int
+	filter Filter
+}
+
+// buildQuery builds the statement of the search, which selects the columns
+// and the score aliased scoreColumn, highest score first.
+func (r *Retriever) buildQuery(req *searchRequest) (string, []any, error) {
+	b := &queryBuilder{metaDataColumn: quoteColumn(r.config.MetaDataColumn)}
+
+	var (
+		vector, tsQuery string
+		where           = "TRUE"
+	)
+	if req.mode != SearchModeFullText {
+		vector = b.arg(vectorLiteral(req.vector)) + "::vector"
+	}
+	if req.mode != SearchModeVector {
+		tsQuery = fmt.Sprintf("websearch_to_tsquery(%s::regconfig, %s)", b.arg(r.config.TextSearchConfig), b.arg(req.query))
+	}
+	if req.filter != nil {
+		cond, err := req.filter.build(b)
+		if err != nil {
+			return "", nil, err
+		}
+		where = cond
+	}
+
+	var (
+		table      = quoteTable(r.config.Table)
+		id         = quoteColumn(r.config.IDColumn)
+		tsv        = quoteColumn(r.config.TextSearchColumn)
+		distance   = fmt.Sprintf("%s %s %s", quoteColumn(r.config.VectorColumn), distanceOperators[r.config.Distance], vector)
+		columns    = make([]string, 0, len(r.config.Columns))
+		docColumns = make([]string, 0, len(r.config.Columns))
+	)
+	for _, c := range r.config.Columns {
+		columns = append(columns, quoteColumn(c))
+		docColumns = append(docColumns, "d."+quoteColumn(c))
+	}
+
+	switch req.mode {
+	case SearchModeVector:
+		limit := b.arg(req.topK)
+		sql := fmt.Sprintf("SELECT %s, %s AS %s FROM %s WHERE %s ORDER BY %s LIMIT %s",
+			strings.Join(columns, ", "), r.vectorScore(distance), scoreColumn, table, where, distance, limit)
+		return sql, b.args, nil
+
+	case SearchModeFullText:
+		limit := b.arg(req.topK)
+		sql := fmt.Sprintf("SELECT %s, ts_rank_cd(%s, q)::float8 AS %s FROM %s, %s q WHERE %s @@ q AND %s ORDER BY %s DESC LIMIT %s",
+			strings.Join(columns, ", "), tsv, scoreColumn, table, tsQuery, tsv, where, scoreColumn, limit)
+		return sql, b.args, nil
+
+	case SearchModeHybrid:
+		candidates := b.arg(req.topK * r.config.Hybrid.CandidateFactor)
+		limit := b.arg(req.topK)
+		k := strconv.Itoa(r.config.Hybrid.RRFK)
+		vectorWeight := strconv.FormatFloat(r.config.Hybrid.VectorWeight, 'g', -1, 64)
+		textWeight := strconv.FormatFloat(r.config.Hybrid.TextWeight, 'g', -1, 64)
+
+		sql := fmt.Sprintf(`WITH vector_search AS (
+	SELECT id, ROW_NUMBER() OVER (ORDER BY distance) AS rank FROM (
+		SELECT %[1]s AS id, %[2]s AS distance FROM %[3]s WHERE %[4]s ORDER BY %[2]s LIMIT %[5]s
+	) candidates
+), text_search AS (
+	SELECT id, ROW_NUMBER() OVER (ORDER BY text_rank DESC) AS rank FROM (
+		SELECT %[1]s AS id, ts_rank_cd(%[6]s, q) AS text_rank FROM %[3]s, %[7]s q WHERE %[6]s @@ q AND %[4]s ORDER BY text_rank DESC LIMIT %[5]s
+	) candidates
+)
+SELECT %[8]s, COALESCE(%[9]s::float8 / (%[10]s + vs.rank), 0) + COALESCE(%[11]s::float8 / (%[10]s + ts.rank), 0) AS %[12]s
+FROM vector_search vs FULL OUTER JOIN text_search ts ON vs.id = ts.id
+JOIN %[3]s d ON d.%[1]s = COALESCE(vs.id, ts.id)
+ORDER BY %[12]s DESC LIMIT %[13]s`,
+			id, distance, table, where, candidates, tsv, tsQuery,
+			strings.Join(docColumns, ", "), vectorWeight, k, textWeight, scoreColumn, limit)
+		return sql, b.args, nil
+
+	default:
+		return "", nil, fmt.Errorf("unknown search mode: %s", req.mode)
+	}
+}
+
+// vectorScore converts the distance to a score, higher is more similar.
+func (r *Retriever) vectorScore(distance string) string {
+	switch r.config.Distance {
+	case DistanceL2:
+		return fmt.Sprintf("1 / (1 + (%s))", distance)
+	case DistanceInnerProduct:
+		return fmt.Sprintf("(%s) * -1", distance)
+	default:
+		return fmt.Sprintf("1 - (%s)", distance)
+	}
+}
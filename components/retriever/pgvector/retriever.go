@@ -0,0 +1,302 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pgvector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/jackc/pgx/v5"
+)
+
+// Config contains configuration for the pgvector retriever.
+type Config struct {
+	// Client executes the queries, e.g. a *pgxpool.Pool.
+	Client Querier
+	// Table is the name of the table, which may be qualified by a schema, e.g. public.documents.
+	Table string `json:"table"`
+	// SearchMode decides how rows are ranked, can be overridden by WithSearchMode.
+	// Default SearchModeVector.
+	SearchMode SearchMode `json:"search_mode"`
+	// Distance is the distance operator of vector search, which should match the operator class of the vector index.
+	// Default DistanceCosine.
+	Distance Distance `json:"distance"`
+	// IDColumn is the primary key column.
+	// Default "id".
+	IDColumn string `json:"id_column"`
+	// MetaDataColumn is the JSONB column of metadata, used by filters.
+	// Default "metadata".
+	MetaDataColumn string `json:"metadata_column"`
+	// VectorColumn is the vector column searched by SearchModeVector and SearchModeHybrid.
+	// Default "embedding".
+	VectorColumn string `json:"vector_column"`
+	// TextSearchColumn is the tsvector column searched by SearchModeFullText and SearchModeHybrid.
+	// Default "content_tsv".
+	TextSearchColumn string `json:"text_search_column"`
+	// TextSearchConfig is the text search configuration which parses the query, e.g. english.
+	// It should match the configuration of TextSearchColumn.
+	// Default "simple".
+	TextSearchConfig string `json:"text_search_config"`
+	// Columns are the columns selected and passed to FieldsToDocument, which must include IDColumn.
+	// Default id, content and metadata.
+	Columns []string `json:"columns"`
+	// Hybrid configures the fusion of SearchModeHybrid.
+	Hybrid *HybridConfig `json:"hybrid"`
+	// FieldsToDocument converts the selected columns of a row to an Eino document.
+	// Default defaultFieldsToDocument, which reads the columns written by the pgvector indexer by default.
+	FieldsToDocument func(ctx context.Context, id string, fields map[string]any) (*schema.Document, error)
+	// Embedding is the embedding model used to vectorize the query.
+	// It's not required by SearchModeFullText.
+	Embedding embedding.Embedder
+	// ScoreThreshold, if set, drops documents scoring less than it.
+	// Scores depend on SearchMode and Distance, see README.
+	ScoreThreshold *float64 `json:"score_threshold"`
+	// TopK is the number of documents to retrieve.
+	// Default 5.
+	TopK int `json:"top_k"`
+}
+
+// HybridConfig configures the reciprocal rank fusion of SearchModeHybrid,
+// which scores a row by VectorWeight / (RRFK + vector rank) + TextWeight / (RRFK + text rank).
+type HybridConfig struct {
+	// VectorWeight is the weight of the vector ranking.
+	// Default 1.
+	VectorWeight float64 `json:"vector_weight"`
+	// TextWeight is the weight of the full-text ranking.
+	// Default 1.
+	TextWeight float64 `json:"text_weight"`
+	// RRFK is the constant k of reciprocal rank fusion, larger values flatten the differences between ranks.
+	// Default 60.
+	RRFK int `json:"rrf_k"`
+	// CandidateFactor decides how many candidates each ranking fetches, which is TopK * CandidateFactor.
+	// Default 4.
+	CandidateFactor int `json:"candidate_factor"`
+}
+
+// Retriever implements the [retriever.Retriever] interface for PostgreSQL with the pgvector extension.
+type Retriever struct {
+	client Querier
+	config *Config
+}
+
+// NewRetriever creates a new pgvector retriever with the provided configuration.
+func NewRetriever(ctx context.Context, conf *Config) (*Retriever, error) {
+	if conf.Client == nil {
+		return nil, fmt.Errorf("[NewRetriever] postgres client not provided")
+	}
+
+	if conf.Table == "" {
+		return nil, fmt.Errorf("[NewRetriever] table not provided")
+	}
+
+	if conf.SearchMode == "" {
+		conf.SearchMode = SearchModeVector
+	}
+
+	if conf.Distance == "" {
+		conf.Distance = DistanceCosine
+	}
+	if _, ok := distanceOperators[conf.Distance]; !ok {
+		return nil, fmt.Errorf("[NewRetriever] unknown distance: %s", conf.Distance)
+	}
+
+	if conf.IDColumn == "" {
+		conf.IDColumn = defaultIDColumn
+	}
+
+	if conf.MetaDataColumn == "" {
+		conf.MetaDataColumn = defaultMetaDataColumn
+	}
+
+	if conf.VectorColumn == "" {
+		conf.VectorColumn = defaultVectorColumn
+	}
+
+	if conf.TextSearchColumn == "" {
+		conf.TextSearchColumn = defaultTextSearchColumn
+	}
+
+	if conf.TextSearchConfig == "" {
+		conf.TextSearchConfig = defaultTextSearchConfig
+	}
+
+	if len(conf.Columns) == 0 {
+		conf.Columns = []string{defaultIDColumn, defaultContentColumn, defaultMetaDataColumn}
+	}
+	if !contains(conf.Columns, conf.IDColumn) {
+		return nil, fmt.Errorf("[NewRetriever] columns must include id column %s", conf.IDColumn)
+	}
+
+	if conf.Hybrid == nil {
+		conf.Hybrid = &HybridConfig{}
+	}
+	if conf.Hybrid.VectorWeight == 0 && conf.Hybrid.TextWeight == 0 {
+		conf.Hybrid.VectorWeight, conf.Hybrid.TextWeight = 1, 1
+	}
+	if conf.Hybrid.RRFK == 0 {
+		conf.Hybrid.RRFK = defaultRRFK
+	}
+	if conf.Hybrid.CandidateFactor == 0 {
+		conf.Hybrid.CandidateFactor = defaultCandidateFactor
+	}
+
+	if conf.FieldsToDocument == nil {
+		conf.FieldsToDocument = defaultFieldsToDocument
+	}
+
+	if conf.TopK == 0 {
+		conf.TopK = defaultTopK
+	}
+
+	return &Retriever{
+		client: conf.Client,
+		config: conf,
+	}, nil
+}
+
+// Retrieve searches the table for the documents most relevant to the query.
+func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	co := retriever.GetCommonOptions(&retriever.Options{
+		TopK:           &r.config.TopK,
+		ScoreThreshold: r.config.ScoreThreshold,
+		Embedding:      r.config.Embedding,
+	}, opts...)
+	io := retriever.GetImplSpecificOptions(&implOptions{
+		SearchMode: r.config.SearchMode,
+	}, opts...)
+
+	ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfRetriever)
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
+		Query:          query,
+		TopK:           *co.TopK,
+		ScoreThreshold: co.ScoreThreshold,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	req := &searchRequest{
+		mode:   io.SearchMode,
+		query:  query,
+		topK:   *co.TopK,
+		filter: io.Filter,
+	}
+
+	if req.mode != SearchModeFullText {
+		emb := co.Embedding
+		if emb == nil {
+			return nil, fmt.Errorf("[pgvector retriever] embedding not provided")
+		}
+
+		vectors, err := emb.EmbedStrings(r.makeEmbeddingCtx(ctx, emb), []string{query})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(vectors) != 1 {
+			return nil, fmt.Errorf("[pgvector retriever] invalid return length of vector, got=%d, expected=1", len(vectors))
+		}
+		req.vector = vectors[0]
+	}
+
+	sql, args, err := r.buildQuery(req)
+	if err != nil {
+		return nil, fmt.Errorf("[pgvector retriever] build query failed, %w", err)
+	}
+
+	rows, err := r.client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[pgvector retriever] query failed, %w", err)
+	}
+
+	records, err := pgx.CollectRows(rows, pgx.RowToMap)
+	if err != nil {
+		return nil, fmt.Errorf("[pgvector retriever] scan rows failed, %w", err)
+	}
+
+	docs = make([]*schema.Document, 0, len(records))
+	for _, fields := range records {
+		score, err := toFloat64(fields[scoreColumn])
+		if err != nil {
+			return nil, fmt.Errorf("[pgvector retriever] %w", err)
+		}
+		if co.ScoreThreshold != nil && score < *co.ScoreThreshold {
+			continue
+		}
+		delete(fields, scoreColumn)
+
+		id := fmt.Sprint(fields[r.config.IDColumn])
+		doc, err := r.config.FieldsToDocument(ctx, id, fields)
+		if err != nil {
+			return nil, fmt.Errorf("[pgvector retriever] FieldsToDocument failed, id=%s, %w", id, err)
+		}
+		docs = append(docs, doc.WithScore(score))
+	}
+
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+
+	return docs, nil
+}
+
+// defaultFieldsToDocument reads the columns written by the pgvector indexer by default.
+func defaultFieldsToDocument(ctx context.Context, id string, fields map[string]any) (*schema.Document, error) {
+	doc := &schema.Document{
+		ID:       id,
+		MetaData: map[string]any{},
+	}
+
+	if content, ok := fields[defaultContentColumn].(string); ok {
+		doc.Content = content
+	}
+
+	if metaData, ok := fields[defaultMetaDataColumn].(map[string]any); ok {
+		doc.MetaData = metaData
+	}
+
+	return doc, nil
+}
+
+func (r *Retriever) makeEmbeddingCtx(ctx context.Context, emb embedding.Embedder) context.Context {
+	runInfo := &callbacks.RunInfo{
+		Component: components.ComponentOfEmbedding,
+	}
+
+	if embType, ok := components.GetType(emb); ok {
+		runInfo.Type = embType
+	}
+
+	runInfo.Name = runInfo.Type + string(runInfo.Component)
+
+	return callbacks.ReuseHandlers(ctx, runInfo)
+}
+
+func (r *Retriever) GetType() string {
+	return typ
+}
+
+func (r *Retriever) IsCallbacksEnabled() bool {
+	return true
+}
+
+var _ retriever.Retriever = &Retriever{}
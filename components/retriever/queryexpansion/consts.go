@@ -0,0 +1,74 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package queryexpansion
+
+import (
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+)
+
+const typ = "QueryExpansion"
+
+const (
+	// expansionType is the type of the run info of query generation in callbacks.
+	expansionType = "QueryGeneration"
+
+	defaultMultiQueryNum = 3
+	defaultHyDENum       = 1
+
+	// CallbackExtraKeyQueries is the key of the generated queries in the Extra of retriever.CallbackOutput.
+	CallbackExtraKeyQueries = "queries"
+)
+
+// Mode decides what the chat model generates from the query.
+type Mode string
+
+const (
+	// ModeMultiQuery generates paraphrases of the query from different
+	// perspectives, which are retrieved along with the query.
+	ModeMultiQuery Mode = "multi_query"
+	// ModeHyDE generates hypothetical documents answering the query, which
+	// are retrieved instead of, or along with, the query. Hypothetical
+	// documents are usually closer to the relevant documents in the embedding
+	// space than short questions are.
+	ModeHyDE Mode = "hyde"
+)
+
+const (
+	// VariableQuery is the variable of the query in prompts.
+	VariableQuery = "query"
+	// VariableNum is the variable of the number of queries to generate in prompts.
+	VariableNum = "num"
+)
+
+const defaultMultiQueryPrompt = `You are an AI assistant helping to retrieve relevant documents from a search engine.
+Generate {num} different versions of the question below, rephrasing it from different perspectives, so that documents missed by the original wording are found.
+Only output the questions, one per line, without numbering or explanation.
+
+Question: {query}`
+
+const defaultHyDEPrompt = `Write a short passage answering the question below, as if it were taken from a document of the knowledge base.
+Only output the passage, without explanation.
+
+Question: {query}`
+
+func defaultPrompt(mode Mode) prompt.ChatTemplate {
+	if mode == ModeHyDE {
+		return prompt.FromMessages(schema.FString, schema.UserMessage(defaultHyDEPrompt))
+	}
+	return prompt.FromMessages(schema.FString, schema.UserMessage(defaultMultiQueryPrompt))
+}
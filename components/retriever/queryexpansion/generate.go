@@ -0,0 +1,130 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package queryexpansion
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// generateQueries generates at most numQueries distinct queries, excluding
+// the original one. The generation is reported to callbacks as a lambda
+// whose input is the query and whose output is the generated queries, so
+// that they show up in traces.
+func (r *Retriever) generateQueries(ctx context.Context, query string) (queries []string, err error) {
+	ctx = callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{
+		Name:      expansionType + string(compose.ComponentOfLambda),
+		Type:      expansionType,
+		Component: compose.ComponentOfLambda,
+	})
+	ctx = callbacks.OnStart(ctx, query)
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	messages, err := r.prompt.Format(makeComponentCtx(ctx, r.prompt, components.ComponentOfPrompt), map[string]any{
+		VariableQuery: query,
+		VariableNum:   r.numQueries,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[query expansion retriever] format prompt failed: %w", err)
+	}
+
+	// ModeMultiQuery generates all queries at once, while ModeHyDE generates a document per call.
+	calls := 1
+	if r.mode == ModeHyDE {
+		calls = r.numQueries
+	}
+
+	var (
+		wg      sync.WaitGroup
+		outputs = make([][]string, calls)
+		errs    = make([]error, calls)
+	)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				if e := recover(); e != nil {
+					errs[i] = fmt.Errorf("panic: %v", e)
+				}
+			}()
+
+			msg, err := r.chatModel.Generate(makeComponentCtx(ctx, r.chatModel, components.ComponentOfChatModel), messages)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			outputs[i], errs[i] = r.outputParser(ctx, msg)
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{query: true}
+	for i := range outputs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("[query expansion retriever] generate queries failed: %w", errs[i])
+		}
+		for _, q := range outputs[i] {
+			q = strings.TrimSpace(q)
+			if q == "" || seen[q] || len(queries) == r.numQueries {
+				continue
+			}
+			seen[q] = true
+			queries = append(queries, q)
+		}
+	}
+
+	callbacks.OnEnd(ctx, queries)
+
+	return queries, nil
+}
+
+var listMarker = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s+`)
+
+// parseLines returns the non-empty lines of the message, without list markers
+// like "- " or "1. ", which models tend to add despite being told not to.
+func parseLines(ctx context.Context, message *schema.Message) ([]string, error) {
+	var queries []string
+	for _, line := range strings.Split(message.Content, "\n") {
+		line = listMarker.ReplaceAllString(strings.TrimSpace(line), "")
+		if line != "" {
+			queries = append(queries, line)
+		}
+	}
+	return queries, nil
+}
+
+// parseContent returns the whole content of the message as a query.
+func parseContent(ctx context.Context, message *schema.Message) ([]string, error) {
+	content := strings.TrimSpace(message.Content)
+	if content == "" {
+		return nil, nil
+	}
+	return []string{content}, nil
+}
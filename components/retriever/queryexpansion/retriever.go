@@ -0,0 +1,285 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package queryexpansion
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Config contains configuration for the query expansion retriever.
+type Config struct {
+	// Retriever is the inner retriever, which the query and the generated queries are sent to concurrently.
+	Retriever retriever.Retriever
+	// ChatModel generates the queries.
+	ChatModel model.BaseChatModel
+	// Mode decides what ChatModel generates.
+	// Default ModeMultiQuery.
+	Mode Mode
+	// NumQueries is the number of paraphrases of ModeMultiQuery, generated by one call of ChatModel,
+	// or the number of hypothetical documents of ModeHyDE, generated by concurrent calls.
+	// Default 3 for ModeMultiQuery and 1 for ModeHyDE.
+	NumQueries int
+	// ExcludeOriginalQuery only retrieves the generated queries.
+	// By default, the original query is retrieved as well.
+	ExcludeOriginalQuery bool
+	// Prompt formats the messages to ChatModel with the variables VariableQuery and VariableNum.
+	// Default a prompt of Mode in schema.FString format.
+	Prompt prompt.ChatTemplate
+	// OutputParser parses the generated queries from a message of ChatModel.
+	// Default each non-empty line without list markers for ModeMultiQuery, and the whole content for ModeHyDE.
+	OutputParser func(ctx context.Context, message *schema.Message) ([]string, error)
+	// TopK is the number of merged documents to return, 0 returns all of them.
+	TopK int
+}
+
+// Retriever implements the [retriever.Retriever] interface. It expands the
+// query with a chat model, retrieves the query and the generated queries by
+// the inner retriever, and merges the results.
+type Retriever struct {
+	retriever    retriever.Retriever
+	chatModel    model.BaseChatModel
+	mode         Mode
+	numQueries   int
+	withOriginal bool
+	prompt       prompt.ChatTemplate
+	outputParser func(ctx context.Context, message *schema.Message) ([]string, error)
+	topK         int
+}
+
+// NewRetriever creates a new query expansion retriever with the provided configuration.
+func NewRetriever(ctx context.Context, config *Config) (*Retriever, error) {
+	if config == nil {
+		return nil, fmt.Errorf("[NewRetriever] config is nil")
+	}
+	if config.Retriever == nil {
+		return nil, fmt.Errorf("[NewRetriever] retriever not provided")
+	}
+	if config.ChatModel == nil {
+		return nil, fmt.Errorf("[NewRetriever] chat model not provided")
+	}
+
+	mode := config.Mode
+	if mode == "" {
+		mode = ModeMultiQuery
+	}
+	if mode != ModeMultiQuery && mode != ModeHyDE {
+		return nil, fmt.Errorf("[NewRetriever] unknown mode: %s", mode)
+	}
+
+	numQueries := config.NumQueries
+	if numQueries < 0 {
+		return nil, fmt.Errorf("[NewRetriever] num queries is negative")
+	}
+	if numQueries == 0 {
+		numQueries = defaultMultiQueryNum
+		if mode == ModeHyDE {
+			numQueries = defaultHyDENum
+		}
+	}
+
+	tpl := config.Prompt
+	if tpl == nil {
+		tpl = defaultPrompt(mode)
+	}
+
+	parser := config.OutputParser
+	if parser == nil {
+		parser = parseLines
+		if mode == ModeHyDE {
+			parser = parseContent
+		}
+	}
+
+	return &Retriever{
+		retriever:    config.Retriever,
+		chatModel:    config.ChatModel,
+		mode:         mode,
+		numQueries:   numQueries,
+		withOriginal: !config.ExcludeOriginalQuery,
+		prompt:       tpl,
+		outputParser: parser,
+		topK:         config.TopK,
+	}, nil
+}
+
+// Retrieve generates queries from the query, retrieves all of them
+// concurrently and returns the merged documents. The options are passed to
+// the inner retriever, and TopK also limits the merged documents.
+func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	co := retriever.GetCommonOptions(&retriever.Options{
+		TopK: &r.topK,
+	}, opts...)
+
+	ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfRetriever)
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
+		Query: query,
+		TopK:  *co.TopK,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	generated, err := r.generateQueries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := generated
+	if r.withOriginal {
+		queries = append([]string{query}, generated...)
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("[query expansion retriever] no query generated")
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results = make([][]*schema.Document, len(queries))
+		errs    = make([]error, len(queries))
+	)
+	for i, q := range queries {
+		wg.Add(1)
+		go func(i int, q string) {
+			defer wg.Done()
+			results[i], errs[i] = r.retrieve(ctx, q, opts)
+		}(i, q)
+	}
+	wg.Wait()
+
+	for i, e := range errs {
+		if e != nil {
+			return nil, fmt.Errorf("[query expansion retriever] retrieve query %q failed: %w", queries[i], e)
+		}
+	}
+
+	docs = merge(results)
+	if *co.TopK > 0 && len(docs) > *co.TopK {
+		docs = docs[:*co.TopK]
+	}
+
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{
+		Docs:  docs,
+		Extra: map[string]any{CallbackExtraKeyQueries: generated},
+	})
+
+	return docs, nil
+}
+
+// retrieve calls the inner retriever, recovering from panics. Callbacks are
+// triggered on its behalf if it does not trigger them itself.
+func (r *Retriever) retrieve(ctx context.Context, query string, opts []retriever.Option) (docs []*schema.Document, err error) {
+	ctx = makeComponentCtx(ctx, r.retriever, components.ComponentOfRetriever)
+	if !components.IsCallbacksEnabled(r.retriever) {
+		ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{Query: query})
+		defer func() {
+			if err != nil {
+				callbacks.OnError(ctx, err)
+				return
+			}
+			callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+		}()
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("panic: %v", e)
+		}
+	}()
+
+	return r.retriever.Retrieve(ctx, query, opts...)
+}
+
+// merge deduplicates the documents by ID, or by content if they have no ID,
+// keeping the occurrence with the highest score, and returns the documents
+// with the highest scores first. Documents with equal scores keep the order
+// in which they were first seen, so the documents of earlier queries come first.
+func merge(results [][]*schema.Document) []*schema.Document {
+	var (
+		key2Idx = make(map[string]int)
+		docs    []*schema.Document
+	)
+	for _, res := range results {
+		for _, doc := range res {
+			if doc == nil {
+				continue
+			}
+			key := dedupKey(doc)
+			idx, ok := key2Idx[key]
+			if !ok {
+				key2Idx[key] = len(docs)
+				docs = append(docs, doc)
+				continue
+			}
+			if doc.Score() > docs[idx].Score() {
+				docs[idx] = doc
+			}
+		}
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].Score() > docs[j].Score()
+	})
+	return docs
+}
+
+func dedupKey(doc *schema.Document) string {
+	if doc.ID != "" {
+		return "id:" + doc.ID
+	}
+	sum := sha256.Sum256([]byte(doc.Content))
+	return "content:" + hex.EncodeToString(sum[:])
+}
+
+// makeComponentCtx sets the run info of the component, so that its callbacks
+// are not reported as those of the query expansion retriever.
+func makeComponentCtx(ctx context.Context, component any, comp components.Component) context.Context {
+	runInfo := &callbacks.RunInfo{
+		Component: comp,
+	}
+
+	if t, ok := components.GetType(component); ok {
+		runInfo.Type = t
+	}
+
+	runInfo.Name = runInfo.Type + string(runInfo.Component)
+
+	return callbacks.ReuseHandlers(ctx, runInfo)
+}
+
+func (r *Retriever) GetType() string {
+	return typ
+}
+
+func (r *Retriever) IsCallbacksEnabled() bool {
+	return true
+}
+
+var _ retriever.Retriever = &Retriever{}
@@ -0,0 +1,306 @@
+/*
+ * Copyright 2026 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package queryexpansion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestParse(t *testing.T) {
+	convey.Convey("test parse", t, func() {
+		ctx := context.Background()
+		queries, err := parseLines(ctx, schema.AssistantMessage("1. what is eino\n\n - eino framework\n2) eino usage\n* go llm framework\n", nil))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(queries, convey.ShouldResemble, []string{"what is eino", "eino framework", "eino usage", "go llm framework"})
+
+		queries, err = parseContent(ctx, schema.AssistantMessage("  Eino is a framework.\n- It is written in Go.  ", nil))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(queries, convey.ShouldResemble, []string{"Eino is a framework.\n- It is written in Go."})
+		queries, err = parseContent(ctx, schema.AssistantMessage(" ", nil))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(queries, convey.ShouldBeEmpty)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	convey.Convey("test merge", t, func() {
+		doc := func(id, content string, score float64) *schema.Document {
+			return (&schema.Document{ID: id, Content: content}).WithScore(score)
+		}
+		docs := merge([][]*schema.Document{
+			{doc("a", "apple", 0.5), doc("b", "banana", 0.4), nil},
+			{doc("b", "banana", 0.9), doc("", "cherry", 0.4), doc("c", "cherry", 0.4)},
+			{doc("", "cherry", 0.1), doc("a", "apple", 0.2)},
+		})
+		var ids []string
+		for _, d := range docs {
+			ids = append(ids, d.ID+":"+d.Content)
+		}
+		convey.So(ids, convey.ShouldResemble, []string{"b:banana", "a:apple", ":cherry", "c:cherry"})
+		convey.So(docs[0].Score(), convey.ShouldEqual, 0.9)
+		convey.So(docs[2].Score(), convey.ShouldEqual, 0.4)
+	})
+}
+
+func TestRetriever(t *testing.T) {
+	convey.Convey("test retriever", t, func() {
+		ctx := context.Background()
+		inner := &mockRetriever{docs: map[string][]*schema.Document{
+			"what is eino": {
+				(&schema.Document{ID: "1", Content: "eino intro"}).WithScore(0.5),
+				(&schema.Document{ID: "2", Content: "go basics"}).WithScore(0.2),
+			},
+			"eino framework": {
+				(&schema.Document{ID: "1", Content: "eino intro"}).WithScore(0.8),
+				(&schema.Document{ID: "3", Content: "eino components"}).WithScore(0.6),
+			},
+			"eino overview": {
+				(&schema.Document{ID: "4", Content: "eino overview"}).WithScore(0.7),
+			},
+			"Eino is a Go framework for LLM applications.": {
+				(&schema.Document{ID: "5", Content: "eino readme"}).WithScore(0.95),
+			},
+		}}
+		cm := &mockChatModel{content: "1. eino framework\n2. what is eino\n3. eino overview\n4. eino usage"}
+
+		ids := func(docs []*schema.Document) []string {
+			var ret []string
+			for _, d := range docs {
+				ret = append(ret, d.ID)
+			}
+			return ret
+		}
+
+		convey.Convey("test NewRetriever", func() {
+			_, err := NewRetriever(ctx, nil)
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{Retriever: inner})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{Retriever: inner, ChatModel: cm, Mode: "step_back"})
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = NewRetriever(ctx, &Config{Retriever: inner, ChatModel: cm, NumQueries: -1})
+			convey.So(err, convey.ShouldNotBeNil)
+
+			r, err := NewRetriever(ctx, &Config{Retriever: inner, ChatModel: cm})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(r.mode, convey.ShouldEqual, ModeMultiQuery)
+			convey.So(r.numQueries, convey.ShouldEqual, 3)
+			convey.So(r.withOriginal, convey.ShouldBeTrue)
+			convey.So(r.GetType(), convey.ShouldEqual, typ)
+			convey.So(r.IsCallbacksEnabled(), convey.ShouldBeTrue)
+
+			r, err = NewRetriever(ctx, &Config{Retriever: inner, ChatModel: cm, Mode: ModeHyDE})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(r.numQueries, convey.ShouldEqual, 1)
+		})
+
+		convey.Convey("test multi-query", func() {
+			r, err := NewRetriever(ctx, &Config{Retriever: inner, ChatModel: cm})
+			convey.So(err, convey.ShouldBeNil)
+
+			docs, err := r.Retrieve(ctx, "what is eino")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids(docs), convey.ShouldResemble, []string{"1", "4", "3", "2"})
+			convey.So(docs[0].Score(), convey.ShouldEqual, 0.8)
+			// the original query is not generated again, and the rest are truncated to 3
+			convey.So(inner.queries(), convey.ShouldResemble, []string{"eino framework", "eino overview", "eino usage", "what is eino"})
+			convey.So(cm.inputs[0][0].Content, convey.ShouldContainSubstring, "Generate 3 different versions")
+			convey.So(cm.inputs[0][0].Content, convey.ShouldContainSubstring, "Question: what is eino")
+
+			docs, err = r.Retrieve(ctx, "what is eino", retriever.WithTopK(2))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids(docs), convey.ShouldResemble, []string{"1", "4"})
+			convey.So(inner.topK, convey.ShouldEqual, 2)
+		})
+
+		convey.Convey("test HyDE", func() {
+			hyde := &mockChatModel{content: "Eino is a Go framework for LLM applications."}
+			r, err := NewRetriever(ctx, &Config{Retriever: inner, ChatModel: hyde, Mode: ModeHyDE, NumQueries: 2, ExcludeOriginalQuery: true})
+			convey.So(err, convey.ShouldBeNil)
+
+			docs, err := r.Retrieve(ctx, "what is eino")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids(docs), convey.ShouldResemble, []string{"5"})
+			// a document per call, deduplicated
+			convey.So(hyde.inputs, convey.ShouldHaveLength, 2)
+			convey.So(inner.queries(), convey.ShouldResemble, []string{"Eino is a Go framework for LLM applications."})
+			convey.So(hyde.inputs[0][0].Content, convey.ShouldContainSubstring, "Write a short passage")
+		})
+
+		convey.Convey("test custom prompt and parser", func() {
+			r, err := NewRetriever(ctx, &Config{
+				Retriever: inner,
+				ChatModel: cm,
+				Prompt: prompt.FromMessages(schema.FString,
+					schema.SystemMessage("Rewrite into {num} keyword queries."),
+					schema.UserMessage("{query}")),
+				OutputParser: func(ctx context.Context, message *schema.Message) ([]string, error) {
+					return []string{"eino overview"}, nil
+				},
+				NumQueries: 5,
+			})
+			convey.So(err, convey.ShouldBeNil)
+
+			docs, err := r.Retrieve(ctx, "what is eino")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids(docs), convey.ShouldResemble, []string{"4", "1", "2"})
+			convey.So(cm.inputs[0], convey.ShouldHaveLength, 2)
+			convey.So(cm.inputs[0][0].Content, convey.ShouldEqual, "Rewrite into 5 keyword queries.")
+			convey.So(cm.inputs[0][1].Content, convey.ShouldEqual, "what is eino")
+		})
+
+		convey.Convey("test callbacks", func() {
+			var (
+				mu        sync.Mutex
+				started   []string
+				generated []string
+				extra     map[string]any
+			)
+			handler := callbacks.NewHandlerBuilder().
+				OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+					mu.Lock()
+					defer mu.Unlock()
+					started = append(started, info.Type+"/"+string(info.Component))
+					return ctx
+				}).
+				OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+					switch info.Type {
+					case expansionType:
+						generated = output.([]string)
+					case typ:
+						extra = retriever.ConvCallbackOutput(output).Extra
+					}
+					return ctx
+				}).Build()
+			ctx := callbacks.InitCallbacks(ctx, nil, handler)
+
+			r, err := NewRetriever(ctx, &Config{Retriever: inner, ChatModel: cm, NumQueries: 1})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "what is eino")
+			convey.So(err, convey.ShouldBeNil)
+
+			sort.Strings(started)
+			convey.So(started, convey.ShouldResemble, []string{
+				"/Retriever", "/Retriever", "Default/ChatTemplate", "QueryExpansion/Retriever", "QueryGeneration/Lambda",
+			})
+			convey.So(generated, convey.ShouldResemble, []string{"eino framework"})
+			convey.So(extra, convey.ShouldResemble, map[string]any{CallbackExtraKeyQueries: []string{"eino framework"}})
+		})
+
+		convey.Convey("test Retrieve error", func() {
+			r, err := NewRetriever(ctx, &Config{Retriever: inner, ChatModel: &mockChatModel{err: fmt.Errorf("mock err")}})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "what is eino")
+			convey.So(err, convey.ShouldNotBeNil)
+
+			r, err = NewRetriever(ctx, &Config{Retriever: inner, ChatModel: &mockChatModel{panic: true}})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "what is eino")
+			convey.So(err, convey.ShouldNotBeNil)
+
+			r, err = NewRetriever(ctx, &Config{Retriever: inner, ChatModel: cm, Prompt: prompt.FromMessages(schema.FString, schema.UserMessage("{unknown}"))})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "what is eino")
+			convey.So(err, convey.ShouldNotBeNil)
+
+			r, err = NewRetriever(ctx, &Config{Retriever: inner, ChatModel: &mockChatModel{content: "what is eino"}, ExcludeOriginalQuery: true})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "what is eino")
+			convey.So(err, convey.ShouldNotBeNil)
+
+			r, err = NewRetriever(ctx, &Config{Retriever: &mockRetriever{err: fmt.Errorf("mock err")}, ChatModel: cm})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "what is eino")
+			convey.So(err, convey.ShouldNotBeNil)
+
+			r, err = NewRetriever(ctx, &Config{Retriever: &mockRetriever{panic: true}, ChatModel: cm})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "what is eino")
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
+
+// mockRetriever returns the documents of the query, and does not trigger callbacks itself.
+type mockRetriever struct {
+	docs  map[string][]*schema.Document
+	err   error
+	panic bool
+
+	mu       sync.Mutex
+	received []string
+	topK     int
+}
+
+func (m *mockRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	if m.panic {
+		panic("mock panic")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.received = append(m.received, query)
+	m.topK = *retriever.GetCommonOptions(&retriever.Options{TopK: new(int)}, opts...).TopK
+	return m.docs[query], m.err
+}
+
+// queries returns the received queries sorted, as they are retrieved concurrently.
+func (m *mockRetriever) queries() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ret := append([]string(nil), m.received...)
+	sort.Strings(ret)
+	return ret
+}
+
+type mockChatModel struct {
+	content string
+	err     error
+	panic   bool
+
+	mu     sync.Mutex
+	inputs [][]*schema.Message
+}
+
+func (m *mockChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if m.panic {
+		panic("mock panic")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputs = append(m.inputs, input)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return schema.AssistantMessage(m.content, nil), nil
+}
+
+func (m *mockChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, fmt.Errorf("not implemented")
+}